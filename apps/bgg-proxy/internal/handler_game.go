@@ -1,13 +1,13 @@
 package internal
 
 import (
-	"bytes"
 	"log/slog"
 	"strconv"
 
 	"github.com/kkjdaniel/gogeek/thing"
 	"github.com/nats-io/nats.go/micro"
 	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/core/service"
 	"go.jetify.com/typeid/v2"
 )
 
@@ -73,14 +73,11 @@ func HandlerGetGameByID() micro.Handler {
 		// For simplicity, return only the first game found
 		game := games[0]
 
-		buf := bytes.NewBuffer(nil)
-		if err := core.EncodeGame(buf, game); err != nil {
+		slog.Info("response", "game", game)
+
+		if err := service.Respond(r, game); err != nil {
 			r.Error("internal_error", "failed to encode game", nil)
 			return
 		}
-
-		slog.Info("response", "game", game)
-
-		r.Respond(buf.Bytes())
 	})
 }
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/apps/match-service/internal"
+	"github.com/ngoldack/dicetrace/package/core/logger"
+	"github.com/ngoldack/dicetrace/package/core/service"
+	"github.com/ngoldack/dicetrace/package/core/stream"
+	"github.com/ngoldack/dicetrace/package/event"
+)
+
+// outboxInterval is how often the domain events of stored changes are
+// published.
+const outboxInterval = time.Second
+
+func main() {
+	if err := Run(context.Background()); err != nil {
+		panic(err)
+	}
+}
+
+func Run(ctx context.Context) error {
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	logger.SetupLogger()
+
+	slog.Info("starting match-service...")
+
+	nc, err := nats.Connect(os.Getenv("NATS_URL"))
+	if err != nil {
+		return fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	defer nc.Close()
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	if err := stream.CreateStreams(ctx, js); err != nil {
+		return err
+	}
+
+	pool, err := pgxpool.New(ctx, os.Getenv("DATABASE_URL"))
+	if err != nil {
+		return fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+	defer pool.Close()
+
+	if err := event.Migrate(ctx, pool); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	history, err := internal.NewJetStreamMatchLog(ctx, js)
+	if err != nil {
+		return err
+	}
+
+	repo := event.NewPostgreSQLEventRepository(pool)
+	games := internal.NewBGGProxyResolver(nc, repo)
+	recorder := internal.NewRecorder(repo, repo, games, history)
+	tournaments := internal.NewTournaments(repo, repo, games, recorder)
+
+	relay := event.NewOutboxRelay(pool, stream.NewJetStreamPublisher(js))
+	go func() {
+		_ = relay.Run(ctx, outboxInterval)
+	}()
+
+	srv, err := service.NewService(ctx, nc, service.Config{
+		Name:    "match-service",
+		Version: "1.0.0",
+		Endpoints: map[string]func() micro.Handler{
+			"match-start":         func() micro.Handler { return internal.HandlerStartMatch(recorder) },
+			"match-get":           func() micro.Handler { return internal.HandlerGetMatch(recorder) },
+			"match-add-player":    func() micro.Handler { return internal.HandlerAddPlayer(recorder) },
+			"match-submit-scores": func() micro.Handler { return internal.HandlerSubmitScores(recorder) },
+			"match-finalize":      func() micro.Handler { return internal.HandlerFinalizeMatch(recorder) },
+			"match-correct-score": func() micro.Handler { return internal.HandlerCorrectScore(recorder) },
+			"match-history":       func() micro.Handler { return internal.HandlerMatchHistory(recorder) },
+
+			"tournament-create":     func() micro.Handler { return internal.HandlerCreateTournament(tournaments) },
+			"tournament-get":        func() micro.Handler { return internal.HandlerGetTournament(tournaments) },
+			"tournament-next-round": func() micro.Handler { return internal.HandlerNextRound(tournaments) },
+			"tournament-standings":  func() micro.Handler { return internal.HandlerStandings(tournaments) },
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+	slog.Info("context cancelled, shutting down match-service...")
+
+	if err := srv.Stop(); err != nil {
+		slog.Error("failed to stop micro service", "error", err)
+	}
+
+	slog.Info("match-service exited gracefully")
+
+	return nil
+}
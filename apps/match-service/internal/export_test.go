@@ -0,0 +1,7 @@
+package internal
+
+import "time"
+
+func SetRecorderClock(r *Recorder, now func() time.Time) {
+	r.now = now
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/core/service"
+	"github.com/ngoldack/dicetrace/package/event"
+)
+
+// SubjectBGGGameByID is the bgg-proxy endpoint resolving a BGG game. The
+// BGG ID is passed in the bgg_id header.
+const SubjectBGGGameByID = "bgg-game-by-id"
+
+// bggErrorGameNotFound is the error code bgg-proxy responds with for unknown
+// games.
+const bggErrorGameNotFound = "bgg_game_not_found"
+
+var ErrGameNotFound = errors.New("game not found")
+
+// GameResolver looks up games by their BoardGameGeek ID.
+type GameResolver interface {
+	ResolveGame(ctx context.Context, bggID int) (*core.Game, error)
+}
+
+// BGGProxyResolver resolves stored games from the repository and games not
+// played before through the bgg-proxy service, which mints a new game ID on
+// every request. The repository stores a BGG game once, so matches of it
+// share the game ID of its first match.
+type BGGProxyResolver struct {
+	nc    *nats.Conn
+	games event.GameRepository
+}
+
+var _ GameResolver = (*BGGProxyResolver)(nil)
+
+func NewBGGProxyResolver(nc *nats.Conn, games event.GameRepository) *BGGProxyResolver {
+	return &BGGProxyResolver{
+		nc:    nc,
+		games: games,
+	}
+}
+
+func (r *BGGProxyResolver) ResolveGame(ctx context.Context, bggID int) (*core.Game, error) {
+	game, err := r.games.GetGameByBGGID(ctx, bggID)
+	if err == nil {
+		return game, nil
+	}
+	if !errors.Is(err, event.ErrGameNotFound) {
+		return nil, err
+	}
+
+	msg := nats.NewMsg(SubjectBGGGameByID)
+	msg.Header.Set("bgg_id", strconv.Itoa(bggID))
+	service.Accept(msg, core.ContentTypeProtobuf)
+
+	resp, err := r.nc.RequestMsgWithContext(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request game %d from bgg-proxy: %w", bggID, err)
+	}
+
+	if code := resp.Header.Get(micro.ErrorCodeHeader); code != "" {
+		if code == bggErrorGameNotFound {
+			return nil, fmt.Errorf("bgg game %d: %w", bggID, ErrGameNotFound)
+		}
+		return nil, fmt.Errorf("bgg-proxy failed to resolve game %d: %s", bggID, resp.Header.Get(micro.ErrorHeader))
+	}
+
+	game, err = service.DecodeResponse[core.Game](resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode game %d: %w", bggID, err)
+	}
+
+	return game, nil
+}
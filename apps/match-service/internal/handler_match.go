@@ -0,0 +1,280 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/core/service"
+	"github.com/ngoldack/dicetrace/package/event"
+)
+
+const (
+	ErrorEventIDMissing    = "event_id_missing"
+	ErrorMatchIDMissing    = "match_id_missing"
+	ErrorUserIDMissing     = "user_id_missing"
+	ErrorActorIDInvalid    = "actor_id_invalid"
+	ErrorRequestInvalid    = "request_invalid"
+	ErrorEventNotFound     = "event_not_found"
+	ErrorEventClosed       = "event_closed"
+	ErrorMatchNotFound     = "match_not_found"
+	ErrorMatchExists       = "match_exists"
+	ErrorMatchFinalized    = "match_finalized"
+	ErrorAlreadyPlaying    = "already_playing"
+	ErrorNotAttending      = "not_attending"
+	ErrorGameNotFound      = "game_not_found"
+	ErrorScoreboardInvalid = "scoreboard_invalid"
+	ErrorMatchNotFinalized = "match_not_finalized"
+	ErrorNotPlaying        = "not_playing"
+	ErrorNoHistory         = "no_history"
+	ErrorVersionConflict   = "version_conflict"
+)
+
+var matchErrorCodes = []service.ErrorCode{
+	{Err: event.ErrEventNotFound, Code: ErrorEventNotFound},
+	{Err: core.ErrEventClosed, Code: ErrorEventClosed},
+	{Err: event.ErrMatchNotFound, Code: ErrorMatchNotFound},
+	{Err: event.ErrMatchExists, Code: ErrorMatchExists},
+	{Err: core.ErrMatchFinalized, Code: ErrorMatchFinalized},
+	{Err: core.ErrAlreadyPlaying, Code: ErrorAlreadyPlaying},
+	{Err: ErrNotAttending, Code: ErrorNotAttending},
+	{Err: ErrGameNotFound, Code: ErrorGameNotFound},
+	{Err: core.ErrMatchNotFinalized, Code: ErrorMatchNotFinalized},
+	{Err: core.ErrNotPlaying, Code: ErrorNotPlaying},
+	{Err: ErrNoHistory, Code: ErrorNoHistory},
+	{Err: core.ErrVersionConflict, Code: ErrorVersionConflict},
+}
+
+// respondMatch responds with the match, or with an internal error if the
+// match cannot be encoded in the requested content type.
+func respondMatch(r micro.Request, match *core.Match) {
+	if err := service.Respond(r, match); err != nil {
+		slog.Error("failed to respond match", slog.String("match_id", match.MatchID.String()), slog.Any("error", err))
+		r.Error(service.ErrorInternal, "failed to encode match", nil)
+	}
+}
+
+// respondChanged responds with the changed match, or with the error if the
+// match was not changed. If only recording the change's history failed, the
+// error is logged as the match itself was changed.
+func respondChanged(r micro.Request, match *core.Match, err error) {
+	if match == nil {
+		service.RespondError(r, err, matchErrorCodes)
+		return
+	}
+	if err != nil {
+		slog.Error("failed to record match history", slog.String("match_id", match.MatchID.String()), slog.Any("error", err))
+	}
+
+	respondMatch(r, match)
+}
+
+// actorContext records changes made by the request as made by the user
+// given by the optional actor_id header. The header is not authenticated:
+// any client allowed to send requests to the service may claim to be any
+// user, so the history shows who changed a match only as far as the NATS
+// accounts allowed to call the service are trusted.
+func actorContext(ctx context.Context, r micro.Request) (context.Context, bool) {
+	if r.Headers().Get("actor_id") == "" {
+		return ctx, true
+	}
+
+	actorID, ok := service.IDFromHeader(r, "actor_id", "user")
+	if !ok {
+		return ctx, false
+	}
+	return WithActor(ctx, actorID), true
+}
+
+// HandlerStartMatch starts a match in the event given by the event_id
+// header. The request body is a StartRequest.
+func HandlerStartMatch(recorder *Recorder) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		ctx, ok := actorContext(ctx, r)
+		if !ok {
+			r.Error(ErrorActorIDInvalid, "actor ID is invalid", nil)
+			return
+		}
+
+		eventID, ok := service.IDFromHeader(r, "event_id", "event")
+		if !ok {
+			r.Error(ErrorEventIDMissing, "event ID is missing or invalid", nil)
+			return
+		}
+
+		var req StartRequest
+		if err := json.Unmarshal(r.Data(), &req); err != nil || req.BGGID <= 0 {
+			r.Error(ErrorRequestInvalid, "failed to decode start request", nil)
+			return
+		}
+
+		match, err := recorder.Start(ctx, eventID, req)
+		respondChanged(r, match, err)
+	})
+}
+
+func HandlerGetMatch(recorder *Recorder) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		matchID, ok := service.IDFromHeader(r, "match_id", "match")
+		if !ok {
+			r.Error(ErrorMatchIDMissing, "match ID is missing or invalid", nil)
+			return
+		}
+
+		match, err := recorder.Get(ctx, matchID)
+		if err != nil {
+			service.RespondError(r, err, matchErrorCodes)
+			return
+		}
+
+		respondMatch(r, match)
+	})
+}
+
+// HandlerAddPlayer adds the attendee given by the user_id header to the
+// match given by the match_id header.
+func HandlerAddPlayer(recorder *Recorder) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		ctx, ok := actorContext(ctx, r)
+		if !ok {
+			r.Error(ErrorActorIDInvalid, "actor ID is invalid", nil)
+			return
+		}
+
+		matchID, ok := service.IDFromHeader(r, "match_id", "match")
+		if !ok {
+			r.Error(ErrorMatchIDMissing, "match ID is missing or invalid", nil)
+			return
+		}
+
+		userID, ok := service.IDFromHeader(r, "user_id", "user")
+		if !ok {
+			r.Error(ErrorUserIDMissing, "user ID is missing or invalid", nil)
+			return
+		}
+
+		match, err := recorder.AddPlayer(ctx, matchID, userID)
+		respondChanged(r, match, err)
+	})
+}
+
+// HandlerSubmitScores replaces the scoreboard of the match given by the
+// match_id header. The request body is the scoreboard, in the content type
+// of the Content-Type header.
+func HandlerSubmitScores(recorder *Recorder) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		ctx, ok := actorContext(ctx, r)
+		if !ok {
+			r.Error(ErrorActorIDInvalid, "actor ID is invalid", nil)
+			return
+		}
+
+		matchID, ok := service.IDFromHeader(r, "match_id", "match")
+		if !ok {
+			r.Error(ErrorMatchIDMissing, "match ID is missing or invalid", nil)
+			return
+		}
+
+		scoreboard, err := service.DecodeRequest[core.Scoreboard](r)
+		if err != nil {
+			r.Error(ErrorScoreboardInvalid, "failed to decode scoreboard", nil)
+			return
+		}
+
+		match, err := recorder.SubmitScores(ctx, matchID, *scoreboard)
+		respondChanged(r, match, err)
+	})
+}
+
+// HandlerFinalizeMatch finalizes the match given by the match_id header.
+func HandlerFinalizeMatch(recorder *Recorder) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		ctx, ok := actorContext(ctx, r)
+		if !ok {
+			r.Error(ErrorActorIDInvalid, "actor ID is invalid", nil)
+			return
+		}
+
+		matchID, ok := service.IDFromHeader(r, "match_id", "match")
+		if !ok {
+			r.Error(ErrorMatchIDMissing, "match ID is missing or invalid", nil)
+			return
+		}
+
+		match, err := recorder.Finalize(ctx, matchID)
+		respondChanged(r, match, err)
+	})
+}
+
+// HandlerCorrectScore corrects a player's score of the finalized match given
+// by the match_id header. The request body is a CorrectionRequest.
+func HandlerCorrectScore(recorder *Recorder) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		ctx, ok := actorContext(ctx, r)
+		if !ok {
+			r.Error(ErrorActorIDInvalid, "actor ID is invalid", nil)
+			return
+		}
+
+		matchID, ok := service.IDFromHeader(r, "match_id", "match")
+		if !ok {
+			r.Error(ErrorMatchIDMissing, "match ID is missing or invalid", nil)
+			return
+		}
+
+		var req CorrectionRequest
+		if err := json.Unmarshal(r.Data(), &req); err != nil {
+			r.Error(ErrorRequestInvalid, "failed to decode correction request", nil)
+			return
+		}
+
+		match, err := recorder.CorrectScore(ctx, matchID, req)
+		respondChanged(r, match, err)
+	})
+}
+
+// HandlerMatchHistory responds with the commands the match given by the
+// match_id header was recorded with, oldest first.
+func HandlerMatchHistory(recorder *Recorder) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		matchID, ok := service.IDFromHeader(r, "match_id", "match")
+		if !ok {
+			r.Error(ErrorMatchIDMissing, "match ID is missing or invalid", nil)
+			return
+		}
+
+		history, err := recorder.History(ctx, matchID)
+		if err != nil {
+			service.RespondError(r, err, matchErrorCodes)
+			return
+		}
+
+		if err := r.RespondJSON(history); err != nil {
+			slog.Error("failed to respond history", slog.String("match_id", matchID.String()), slog.Any("error", err))
+			r.Error(service.ErrorInternal, "failed to encode history", nil)
+		}
+	})
+}
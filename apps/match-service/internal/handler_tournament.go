@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"encoding/json"
+	"log/slog"
+	"slices"
+
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/package/core/service"
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/ngoldack/dicetrace/package/tournament"
+)
+
+const (
+	ErrorTournamentNotFound = "tournament_not_found"
+	ErrorTournamentExists   = "tournament_exists"
+	ErrorRoundIncomplete    = "round_incomplete"
+	ErrorTournamentComplete = "tournament_complete"
+	ErrorNoWinner           = "no_winner"
+)
+
+var tournamentErrorCodes = slices.Concat(matchErrorCodes, []service.ErrorCode{
+	{Err: event.ErrTournamentNotFound, Code: ErrorTournamentNotFound},
+	{Err: event.ErrTournamentExists, Code: ErrorTournamentExists},
+	{Err: tournament.ErrRoundIncomplete, Code: ErrorRoundIncomplete},
+	{Err: tournament.ErrComplete, Code: ErrorTournamentComplete},
+	{Err: tournament.ErrNoWinner, Code: ErrorNoWinner},
+})
+
+// respondTournament responds with the tournament, or with an internal error
+// if it cannot be encoded in the requested content type.
+func respondTournament(r micro.Request, t *tournament.Tournament) {
+	if err := service.Respond(r, t); err != nil {
+		slog.Error("failed to respond tournament", slog.String("event_id", t.EventID.String()), slog.Any("error", err))
+		r.Error(service.ErrorInternal, "failed to encode tournament", nil)
+	}
+}
+
+// HandlerCreateTournament runs the event given by the event_id header as a
+// tournament. The request body is a TournamentRequest.
+func HandlerCreateTournament(tournaments *Tournaments) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		eventID, ok := service.IDFromHeader(r, "event_id", "event")
+		if !ok {
+			r.Error(ErrorEventIDMissing, "event ID is missing or invalid", nil)
+			return
+		}
+
+		var req TournamentRequest
+		if err := json.Unmarshal(r.Data(), &req); err != nil || req.BGGID <= 0 {
+			r.Error(ErrorRequestInvalid, "failed to decode tournament request", nil)
+			return
+		}
+
+		t, err := tournaments.Create(ctx, eventID, req)
+		if err != nil {
+			service.RespondError(r, err, tournamentErrorCodes)
+			return
+		}
+
+		respondTournament(r, t)
+	})
+}
+
+// HandlerGetTournament responds with the tournament of the event given by
+// the event_id header.
+func HandlerGetTournament(tournaments *Tournaments) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		eventID, ok := service.IDFromHeader(r, "event_id", "event")
+		if !ok {
+			r.Error(ErrorEventIDMissing, "event ID is missing or invalid", nil)
+			return
+		}
+
+		t, err := tournaments.Get(ctx, eventID)
+		if err != nil {
+			service.RespondError(r, err, tournamentErrorCodes)
+			return
+		}
+
+		respondTournament(r, t)
+	})
+}
+
+// HandlerNextRound starts the next round of the tournament of the event
+// given by the event_id header and responds with the StartedRound.
+func HandlerNextRound(tournaments *Tournaments) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		ctx, ok := actorContext(ctx, r)
+		if !ok {
+			r.Error(ErrorActorIDInvalid, "actor ID is invalid", nil)
+			return
+		}
+
+		eventID, ok := service.IDFromHeader(r, "event_id", "event")
+		if !ok {
+			r.Error(ErrorEventIDMissing, "event ID is missing or invalid", nil)
+			return
+		}
+
+		round, err := tournaments.NextRound(ctx, eventID)
+		if round == nil {
+			service.RespondError(r, err, tournamentErrorCodes)
+			return
+		}
+		if err != nil {
+			slog.Error("failed to record match history", slog.String("event_id", eventID.String()), slog.Any("error", err))
+		}
+
+		if err := service.Respond(r, round); err != nil {
+			slog.Error("failed to respond round", slog.String("event_id", eventID.String()), slog.Any("error", err))
+			r.Error(service.ErrorInternal, "failed to encode round", nil)
+		}
+	})
+}
+
+// HandlerStandings responds with the standings of the tournament of the
+// event given by the event_id header, best first.
+func HandlerStandings(tournaments *Tournaments) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		eventID, ok := service.IDFromHeader(r, "event_id", "event")
+		if !ok {
+			r.Error(ErrorEventIDMissing, "event ID is missing or invalid", nil)
+			return
+		}
+
+		standings, err := tournaments.Standings(ctx, eventID)
+		if err != nil {
+			service.RespondError(r, err, tournamentErrorCodes)
+			return
+		}
+
+		if err := service.Respond(r, &standings); err != nil {
+			slog.Error("failed to respond standings", slog.String("event_id", eventID.String()), slog.Any("error", err))
+			r.Error(service.ErrorInternal, "failed to encode standings", nil)
+		}
+	})
+}
@@ -0,0 +1,243 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/ngoldack/dicetrace/package/core"
+)
+
+const (
+	historyStream        = "MATCH_HISTORY"
+	historySubjectPrefix = "match.history."
+	snapshotBucket       = "match_snapshots"
+
+	// snapshotInterval is the number of commands between snapshots.
+	snapshotInterval = 20
+)
+
+// ErrNoHistory is returned for matches without recorded commands, such as
+// matches recorded before their history was kept.
+var ErrNoHistory = errors.New("match has no history")
+
+// MatchLog keeps the history of every match as an append-only sequence of
+// commands the match's state is derived from.
+type MatchLog interface {
+	// Append stores the command that changed the match to the aggregate's
+	// state. It returns core.ErrVersionConflict unless the command directly
+	// follows the last stored command of the match.
+	Append(ctx context.Context, agg *core.MatchAggregate, cmd core.MatchCommand) error
+	// Version returns the version of the last stored command of the match,
+	// or 0 if it has no history.
+	Version(ctx context.Context, matchID core.MatchID) (int, error)
+	// Load rebuilds the match from its latest snapshot and the commands
+	// stored after it, e.g. to audit the stored match against its history.
+	Load(ctx context.Context, matchID core.MatchID) (*core.MatchAggregate, error)
+	// History returns all commands of the match in order.
+	History(ctx context.Context, matchID core.MatchID) ([]core.MatchCommand, error)
+}
+
+// JetStreamMatchLog stores the commands of each match on its own subject of
+// the history stream. Every snapshotInterval commands the match is
+// snapshotted to a key value bucket.
+type JetStreamMatchLog struct {
+	js        jetstream.JetStream
+	stream    jetstream.Stream
+	snapshots jetstream.KeyValue
+}
+
+var _ MatchLog = (*JetStreamMatchLog)(nil)
+
+func NewJetStreamMatchLog(ctx context.Context, js jetstream.JetStream) (*JetStreamMatchLog, error) {
+	stream, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:        historyStream,
+		Description: "Commands of every match",
+		Subjects:    []string{historySubjectPrefix + "*"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stream '%s': %w", historyStream, err)
+	}
+
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      snapshotBucket,
+		Description: "Snapshots of match histories",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key value bucket '%s': %w", snapshotBucket, err)
+	}
+
+	return &JetStreamMatchLog{
+		js:        js,
+		stream:    stream,
+		snapshots: kv,
+	}, nil
+}
+
+func historySubject(matchID core.MatchID) string {
+	return historySubjectPrefix + matchID.String()
+}
+
+// snapshot is a stored aggregate and the stream sequence of its last
+// command.
+type snapshot struct {
+	Aggregate core.MatchAggregate `json:"aggregate"`
+	Sequence  uint64              `json:"sequence"`
+}
+
+// Append publishes the command expecting the last command of the match at
+// its stream sequence, so concurrent appends of the same version fail.
+func (l *JetStreamMatchLog) Append(ctx context.Context, agg *core.MatchAggregate, cmd core.MatchCommand) error {
+	matchID := agg.Match.MatchID
+	subject := historySubject(matchID)
+
+	previous, last, err := l.last(ctx, matchID)
+	if err != nil {
+		return err
+	}
+	if last != 0 && previous.Version != cmd.Version-1 {
+		return fmt.Errorf("command %d of match '%s' after %d: %w", cmd.Version, matchID, previous.Version, core.ErrVersionConflict)
+	}
+	if last == 0 && cmd.Version != 1 {
+		return fmt.Errorf("command %d of match '%s' without history: %w", cmd.Version, matchID, core.ErrVersionConflict)
+	}
+
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to marshal command: %w", err)
+	}
+
+	ack, err := l.js.Publish(ctx, subject, data, jetstream.WithExpectLastSequencePerSubject(last))
+	var apiErr *jetstream.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode == jetstream.JSErrCodeStreamWrongLastSequence {
+		return fmt.Errorf("command %d of match '%s': %w", cmd.Version, matchID, core.ErrVersionConflict)
+	} else if err != nil {
+		return fmt.Errorf("failed to append command %d of match '%s': %w", cmd.Version, matchID, err)
+	}
+
+	if cmd.Version%snapshotInterval == 0 {
+		// snapshots only speed up loading, the history stays complete
+		if err := l.saveSnapshot(ctx, snapshot{Aggregate: *agg, Sequence: ack.Sequence}); err != nil {
+			slog.WarnContext(ctx, "failed to snapshot match", slog.String("match_id", matchID.String()), slog.Any("error", err))
+		}
+	}
+
+	return nil
+}
+
+func (l *JetStreamMatchLog) Version(ctx context.Context, matchID core.MatchID) (int, error) {
+	previous, _, err := l.last(ctx, matchID)
+	if err != nil {
+		return 0, err
+	}
+	return previous.Version, nil
+}
+
+// last returns the last command of the match and its stream sequence, or
+// sequence 0 if the match has no history.
+func (l *JetStreamMatchLog) last(ctx context.Context, matchID core.MatchID) (core.MatchCommand, uint64, error) {
+	msg, err := l.stream.GetLastMsgForSubject(ctx, historySubject(matchID))
+	if errors.Is(err, jetstream.ErrMsgNotFound) {
+		return core.MatchCommand{}, 0, nil
+	} else if err != nil {
+		return core.MatchCommand{}, 0, fmt.Errorf("failed to get last command of match '%s': %w", matchID, err)
+	}
+
+	var cmd core.MatchCommand
+	if err := json.Unmarshal(msg.Data, &cmd); err != nil {
+		return core.MatchCommand{}, 0, fmt.Errorf("failed to unmarshal command of match '%s': %w", matchID, err)
+	}
+
+	return cmd, msg.Sequence, nil
+}
+
+func (l *JetStreamMatchLog) Load(ctx context.Context, matchID core.MatchID) (*core.MatchAggregate, error) {
+	snap, err := l.snapshot(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	var from uint64 = 1
+	var base *core.MatchAggregate
+	if snap != nil {
+		from, base = snap.Sequence+1, &snap.Aggregate
+	}
+
+	history, err := l.commands(ctx, matchID, from)
+	if err != nil {
+		return nil, err
+	}
+	if base == nil && len(history) == 0 {
+		return nil, fmt.Errorf("match '%s': %w", matchID, ErrNoHistory)
+	}
+
+	return core.ReplayMatch(base, history)
+}
+
+func (l *JetStreamMatchLog) History(ctx context.Context, matchID core.MatchID) ([]core.MatchCommand, error) {
+	history, err := l.commands(ctx, matchID, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, fmt.Errorf("match '%s': %w", matchID, ErrNoHistory)
+	}
+
+	return history, nil
+}
+
+// commands returns the commands of the match from the stream sequence on.
+func (l *JetStreamMatchLog) commands(ctx context.Context, matchID core.MatchID, from uint64) ([]core.MatchCommand, error) {
+	subject := historySubject(matchID)
+
+	history := make([]core.MatchCommand, 0)
+	for seq := from; ; {
+		msg, err := l.stream.GetMsg(ctx, seq, jetstream.WithGetMsgSubject(subject))
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get commands of match '%s': %w", matchID, err)
+		}
+
+		var cmd core.MatchCommand
+		if err := json.Unmarshal(msg.Data, &cmd); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal command of match '%s': %w", matchID, err)
+		}
+		history = append(history, cmd)
+		seq = msg.Sequence + 1
+	}
+
+	return history, nil
+}
+
+func (l *JetStreamMatchLog) snapshot(ctx context.Context, matchID core.MatchID) (*snapshot, error) {
+	entry, err := l.snapshots.Get(ctx, matchID.String())
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot of match '%s': %w", matchID, err)
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(entry.Value(), &snap); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot of match '%s': %w", matchID, err)
+	}
+
+	return &snap, nil
+}
+
+func (l *JetStreamMatchLog) saveSnapshot(ctx context.Context, snap snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	if _, err := l.snapshots.Put(ctx, snap.Aggregate.Match.MatchID.String(), data); err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,266 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/event"
+)
+
+// ErrNotAttending is returned when a player is not a confirmed attendee of
+// the match's event.
+var ErrNotAttending = errors.New("user is not a confirmed attendee")
+
+// StartRequest starts a match of a BoardGameGeek game.
+type StartRequest struct {
+	BGGID int `json:"bgg_id"`
+	// Scoring overrides the scoring of the resolved game if its unit is set.
+	Scoring core.Scoring `json:"scoring,omitzero"`
+	// PlayerIDs are the initial players; more may join later.
+	PlayerIDs []core.UserID `json:"player_ids,omitempty"`
+}
+
+// Recorder records matches while they are played: a match is started within
+// an event, players join, scores are submitted and finally the result is
+// made final. Results may be corrected afterwards. The match repository
+// announces every change, e.g. finalized and corrected results, as domain
+// events.
+//
+// Every change is a core.MatchCommand appended to the match's history once
+// the change was stored. The match repository is authoritative: commands
+// apply to the stored match, so a change whose command failed to append
+// leaves a gap in the history rather than being lost on the next change.
+type Recorder struct {
+	events  event.EventRepository
+	matches event.MatchRepository
+	games   GameResolver
+	history MatchLog
+	now     func() time.Time
+}
+
+func NewRecorder(events event.EventRepository, matches event.MatchRepository, games GameResolver, history MatchLog) *Recorder {
+	return &Recorder{
+		events:  events,
+		matches: matches,
+		games:   games,
+		history: history,
+		now:     time.Now,
+	}
+}
+
+type actorKey struct{}
+
+// WithActor returns a context in which changes are recorded as made by the
+// user. The user is trusted as given, see actorContext.
+func WithActor(ctx context.Context, userID core.UserID) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+func actorFrom(ctx context.Context) core.UserID {
+	userID, _ := ctx.Value(actorKey{}).(core.UserID)
+	return userID
+}
+
+// command returns a command of the kind made now by the context's actor.
+func (r *Recorder) command(ctx context.Context, kind core.MatchCommandKind) core.MatchCommand {
+	return core.MatchCommand{Kind: kind, By: actorFrom(ctx), At: r.now().UTC()}
+}
+
+// apply applies the command as the next version of the aggregate and
+// returns it with its version.
+func apply(agg *core.MatchAggregate, cmd core.MatchCommand) (core.MatchCommand, error) {
+	cmd.Version = agg.Version + 1
+	return cmd, agg.Apply(cmd)
+}
+
+// record appends the commands of a stored change to the match's history.
+// The change is kept if appending fails, so callers return the error along
+// with the changed match.
+func (r *Recorder) record(ctx context.Context, agg *core.MatchAggregate, commands []core.MatchCommand) error {
+	for _, cmd := range commands {
+		if err := r.history.Append(ctx, agg, cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// change applies the command built within the match's event to the stored
+// match and stores the result. The command is appended to the history only
+// once the change was stored, so the history never holds a change that was
+// rolled back. Appending failures are reported along with the changed
+// match.
+func (r *Recorder) change(ctx context.Context, matchID core.MatchID, build func(evt *core.Event) (core.MatchCommand, error)) (*core.Match, error) {
+	var agg *core.MatchAggregate
+	var commands []core.MatchCommand
+	match, err := r.matches.UpdateMatch(ctx, matchID, func(evt *core.Event, match *core.Match) error {
+		var err error
+		agg, commands, err = r.load(ctx, match)
+		if err != nil {
+			return err
+		}
+
+		cmd, err := build(evt)
+		if err != nil {
+			return err
+		}
+		cmd, err = apply(agg, cmd)
+		if err != nil {
+			return err
+		}
+		commands = append(commands, cmd)
+
+		*match = agg.Match
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return match, r.record(ctx, agg, commands)
+}
+
+// load returns the stored match at the version of its history. Matches
+// recorded before their history was kept start it with their stored state;
+// the started command is returned to be appended with the change.
+func (r *Recorder) load(ctx context.Context, stored *core.Match) (*core.MatchAggregate, []core.MatchCommand, error) {
+	version, err := r.history.Version(ctx, stored.MatchID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if version > 0 {
+		return &core.MatchAggregate{Match: *stored, Version: version}, nil, nil
+	}
+
+	agg := &core.MatchAggregate{}
+	imported := *stored
+	cmd, err := apply(agg, core.MatchCommand{Kind: core.MatchCommandStarted, At: r.now().UTC(), Match: &imported})
+	if err != nil {
+		return nil, nil, err
+	}
+	return agg, []core.MatchCommand{cmd}, nil
+}
+
+// Start resolves the game and starts a match in the event. Players must be
+// confirmed attendees. Failing to start the match's history is reported
+// along with the stored match.
+func (r *Recorder) Start(ctx context.Context, eventID core.EventID, req StartRequest) (*core.Match, error) {
+	evt, err := r.events.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := evt.CheckMutable(); err != nil {
+		return nil, err
+	}
+
+	players := make([]core.User, 0, len(req.PlayerIDs))
+	for _, userID := range req.PlayerIDs {
+		player, err := attendingPlayer(evt, userID)
+		if err != nil {
+			return nil, err
+		}
+		players = append(players, player)
+	}
+
+	game, err := r.games.ResolveGame(ctx, req.BGGID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Scoring.Unit != "" {
+		game.Scoring = req.Scoring
+	}
+
+	match := core.StartMatch(*game, players, r.now().UTC())
+	if err := match.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := r.matches.CreateMatch(ctx, eventID, match); err != nil {
+		return nil, err
+	}
+
+	return match, r.started(ctx, match)
+}
+
+// started starts the history of the stored match. A match left without
+// history starts it with its stored state on the next change, see load.
+func (r *Recorder) started(ctx context.Context, match *core.Match) error {
+	agg := &core.MatchAggregate{}
+	cmd := r.command(ctx, core.MatchCommandStarted)
+	cmd.Match = match
+	cmd, err := apply(agg, cmd)
+	if err != nil {
+		return err
+	}
+
+	return r.record(ctx, agg, []core.MatchCommand{cmd})
+}
+
+func (r *Recorder) Get(ctx context.Context, matchID core.MatchID) (*core.Match, error) {
+	return r.matches.GetMatch(ctx, matchID)
+}
+
+// History returns the commands the match was recorded with.
+func (r *Recorder) History(ctx context.Context, matchID core.MatchID) ([]core.MatchCommand, error) {
+	return r.history.History(ctx, matchID)
+}
+
+// AddPlayer adds a confirmed attendee of the event to the match.
+func (r *Recorder) AddPlayer(ctx context.Context, matchID core.MatchID, userID core.UserID) (*core.Match, error) {
+	return r.change(ctx, matchID, func(evt *core.Event) (core.MatchCommand, error) {
+		player, err := attendingPlayer(evt, userID)
+		if err != nil {
+			return core.MatchCommand{}, err
+		}
+
+		cmd := r.command(ctx, core.MatchCommandPlayerAdded)
+		cmd.Player = &player
+		return cmd, nil
+	})
+}
+
+// SubmitScores replaces the scoreboard of the match.
+func (r *Recorder) SubmitScores(ctx context.Context, matchID core.MatchID, scoreboard core.Scoreboard) (*core.Match, error) {
+	return r.change(ctx, matchID, func(*core.Event) (core.MatchCommand, error) {
+		cmd := r.command(ctx, core.MatchCommandScoreSet)
+		cmd.Scoreboard = &scoreboard
+		return cmd, nil
+	})
+}
+
+// CorrectionRequest corrects a player's score of a finalized match.
+type CorrectionRequest struct {
+	Score  core.Score `json:"score"`
+	Reason string     `json:"reason,omitempty"`
+}
+
+// CorrectScore replaces a player's score of the finalized match. The
+// correction is kept in the match's history along with the reason.
+func (r *Recorder) CorrectScore(ctx context.Context, matchID core.MatchID, req CorrectionRequest) (*core.Match, error) {
+	return r.change(ctx, matchID, func(*core.Event) (core.MatchCommand, error) {
+		cmd := r.command(ctx, core.MatchCommandScoreCorrected)
+		cmd.Score, cmd.Reason = &req.Score, req.Reason
+		return cmd, nil
+	})
+}
+
+// Finalize makes the match's result final.
+func (r *Recorder) Finalize(ctx context.Context, matchID core.MatchID) (*core.Match, error) {
+	return r.change(ctx, matchID, func(*core.Event) (core.MatchCommand, error) {
+		return r.command(ctx, core.MatchCommandFinalized), nil
+	})
+}
+
+// attendingPlayer returns the user of the confirmed attendee.
+func attendingPlayer(evt *core.Event, userID core.UserID) (core.User, error) {
+	for _, a := range evt.Attendees {
+		if a.User.UserID == userID && a.Status == core.AttendeeStatusConfirmed {
+			return a.User, nil
+		}
+	}
+	return core.User{}, fmt.Errorf("user '%s' in event '%s': %w", userID, evt.EventID, ErrNotAttending)
+}
@@ -0,0 +1,494 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/apps/match-service/internal"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2025, time.March, 6, 20, 0, 0, 0, time.UTC)
+
+// mockRepository implements the parts of event.Repository used by the
+// recorder in memory
+type mockRepository struct {
+	event.Repository
+
+	mu      sync.Mutex
+	events  map[string]*core.Event
+	matches map[string]string // match ID to event ID
+}
+
+func newMockRepository(events ...*core.Event) *mockRepository {
+	r := &mockRepository{events: make(map[string]*core.Event), matches: make(map[string]string)}
+	for _, evt := range events {
+		r.events[evt.EventID.String()] = evt
+	}
+	return r
+}
+
+func (r *mockRepository) GetEvent(ctx context.Context, eventID core.EventID) (*core.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	evt, ok := r.events[eventID.String()]
+	if !ok {
+		return nil, event.ErrEventNotFound
+	}
+	return evt, nil
+}
+
+func (r *mockRepository) CreateMatch(ctx context.Context, eventID core.EventID, match *core.Match) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	evt, ok := r.events[eventID.String()]
+	if !ok {
+		return event.ErrEventNotFound
+	}
+	if err := evt.CheckMutable(); err != nil {
+		return err
+	}
+	if _, ok := r.matches[match.MatchID.String()]; ok {
+		return event.ErrMatchExists
+	}
+	evt.Matches = append(evt.Matches, *match)
+	r.matches[match.MatchID.String()] = eventID.String()
+	return nil
+}
+
+func (r *mockRepository) GetMatch(ctx context.Context, matchID core.MatchID) (*core.Match, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	evt, i, err := r.find(matchID)
+	if err != nil {
+		return nil, err
+	}
+	match := evt.Matches[i]
+	return &match, nil
+}
+
+func (r *mockRepository) UpdateMatch(ctx context.Context, matchID core.MatchID, fn func(evt *core.Event, match *core.Match) error) (*core.Match, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	evt, i, err := r.find(matchID)
+	if err != nil {
+		return nil, err
+	}
+	if err := evt.CheckMutable(); err != nil {
+		return nil, err
+	}
+
+	// work on a copy, so failing updates leave the stored match untouched
+	match := evt.Matches[i]
+	match.Players = slices.Clone(match.Players)
+	match.Scoreboard.Scores = slices.Clone(match.Scoreboard.Scores)
+	if err := fn(evt, &match); err != nil {
+		return nil, err
+	}
+	if err := match.Validate(); err != nil {
+		return nil, err
+	}
+	evt.Matches[i] = match
+	return &match, nil
+}
+
+func (r *mockRepository) find(matchID core.MatchID) (*core.Event, int, error) {
+	eventID, ok := r.matches[matchID.String()]
+	if !ok {
+		return nil, 0, event.ErrMatchNotFound
+	}
+	evt := r.events[eventID]
+	i := slices.IndexFunc(evt.Matches, func(m core.Match) bool { return m.MatchID == matchID })
+	return evt, i, nil
+}
+
+// mockGameResolver resolves the games it knows by BGG ID
+type mockGameResolver map[int]core.Game
+
+func (r mockGameResolver) ResolveGame(ctx context.Context, bggID int) (*core.Game, error) {
+	game, ok := r[bggID]
+	if !ok {
+		return nil, internal.ErrGameNotFound
+	}
+	return &game, nil
+}
+
+// mockMatchLog keeps the history of every match in memory
+type mockMatchLog struct {
+	mu       sync.Mutex
+	commands map[string][]core.MatchCommand
+	err      error
+}
+
+func newMockMatchLog() *mockMatchLog {
+	return &mockMatchLog{commands: make(map[string][]core.MatchCommand)}
+}
+
+func (l *mockMatchLog) Append(ctx context.Context, agg *core.MatchAggregate, cmd core.MatchCommand) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return l.err
+	}
+	key := agg.Match.MatchID.String()
+	if len(l.commands[key]) != cmd.Version-1 {
+		return core.ErrVersionConflict
+	}
+	l.commands[key] = append(l.commands[key], cmd)
+	return nil
+}
+
+func (l *mockMatchLog) Version(ctx context.Context, matchID core.MatchID) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.commands[matchID.String()]), nil
+}
+
+func (l *mockMatchLog) Load(ctx context.Context, matchID core.MatchID) (*core.MatchAggregate, error) {
+	history, err := l.History(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+	return core.ReplayMatch(nil, history)
+}
+
+func (l *mockMatchLog) History(ctx context.Context, matchID core.MatchID) ([]core.MatchCommand, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	history := l.commands[matchID.String()]
+	if len(history) == 0 {
+		return nil, internal.ErrNoHistory
+	}
+	return slices.Clone(history), nil
+}
+
+const azulBGGID = 230802
+
+func newTestEvent() *core.Event {
+	attendee := func(name string, status core.AttendeeStatus) core.Attendee {
+		return core.Attendee{User: core.User{UserID: core.NewUserID(), Username: name}, Status: status}
+	}
+	return &core.Event{
+		EventID: core.NewEventID(),
+		Status:  core.EventStatusOngoing,
+		Attendees: []core.Attendee{
+			attendee("alice", core.AttendeeStatusConfirmed),
+			attendee("bob", core.AttendeeStatusConfirmed),
+			attendee("carol", core.AttendeeStatusPending),
+		},
+		Matches: []core.Match{},
+	}
+}
+
+func newTestRecorder(events ...*core.Event) (*internal.Recorder, *mockRepository, *mockMatchLog) {
+	repo := newMockRepository(events...)
+	games := mockGameResolver{azulBGGID: {GameID: core.NewGameID(), BGGID: azulBGGID, Name: "Azul"}}
+	history := newMockMatchLog()
+
+	recorder := internal.NewRecorder(repo, repo, games, history)
+	internal.SetRecorderClock(recorder, func() time.Time { return now })
+
+	return recorder, repo, history
+}
+
+func TestRecorderStart(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	evt := newTestEvent()
+	alice := evt.Attendees[0].User
+	recorder, repo, _ := newTestRecorder(evt)
+
+	match, err := recorder.Start(ctx, evt.EventID, internal.StartRequest{
+		BGGID:     azulBGGID,
+		PlayerIDs: []core.UserID{alice.UserID},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, core.MatchStatusInProgress, match.Status)
+	assert.Equal(t, "Azul", match.Game.Name)
+	assert.Equal(t, []core.User{alice}, match.Players)
+	assert.Equal(t, now, match.StartedAt)
+	assert.Equal(t, core.ScoreUnitPoints, match.Scoreboard.ScoreUnit)
+
+	stored, err := repo.GetMatch(ctx, match.MatchID)
+	require.NoError(t, err)
+	assert.Equal(t, match, stored)
+}
+
+func TestRecorderStartWithScoring(t *testing.T) {
+	t.Parallel()
+	evt := newTestEvent()
+	recorder, _, _ := newTestRecorder(evt)
+
+	match, err := recorder.Start(context.Background(), evt.EventID, internal.StartRequest{
+		BGGID:   azulBGGID,
+		Scoring: core.Scoring{Unit: core.ScoreUnitRank},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, core.ScoreUnitRank, match.Game.Scoring.Unit)
+	assert.Equal(t, core.ScoreUnitRank, match.Scoreboard.ScoreUnit)
+}
+
+func TestRecorderStartErrors(t *testing.T) {
+	t.Parallel()
+	evt := newTestEvent()
+	closed := newTestEvent()
+	closed.Status = core.EventStatusCompleted
+
+	testCases := []struct {
+		name    string
+		eventID core.EventID
+		req     internal.StartRequest
+		err     error
+	}{
+		{
+			name:    "unknown event",
+			eventID: core.NewEventID(),
+			req:     internal.StartRequest{BGGID: azulBGGID},
+			err:     event.ErrEventNotFound,
+		},
+		{
+			name:    "closed event",
+			eventID: closed.EventID,
+			req:     internal.StartRequest{BGGID: azulBGGID},
+			err:     core.ErrEventClosed,
+		},
+		{
+			name:    "unknown game",
+			eventID: evt.EventID,
+			req:     internal.StartRequest{BGGID: 1},
+			err:     internal.ErrGameNotFound,
+		},
+		{
+			name:    "pending attendee",
+			eventID: evt.EventID,
+			req:     internal.StartRequest{BGGID: azulBGGID, PlayerIDs: []core.UserID{evt.Attendees[2].User.UserID}},
+			err:     internal.ErrNotAttending,
+		},
+		{
+			name:    "stranger",
+			eventID: evt.EventID,
+			req:     internal.StartRequest{BGGID: azulBGGID, PlayerIDs: []core.UserID{core.NewUserID()}},
+			err:     internal.ErrNotAttending,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			recorder, _, _ := newTestRecorder(evt, closed)
+
+			match, err := recorder.Start(context.Background(), tc.eventID, tc.req)
+			assert.Nil(t, match)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestRecorderPlay(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	evt := newTestEvent()
+	alice, bob, carol := evt.Attendees[0].User, evt.Attendees[1].User, evt.Attendees[2].User
+	recorder, _, _ := newTestRecorder(evt)
+
+	match, err := recorder.Start(ctx, evt.EventID, internal.StartRequest{BGGID: azulBGGID})
+	require.NoError(t, err)
+
+	match, err = recorder.AddPlayer(ctx, match.MatchID, alice.UserID)
+	require.NoError(t, err)
+	match, err = recorder.AddPlayer(ctx, match.MatchID, bob.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, []core.User{alice, bob}, match.Players)
+
+	_, err = recorder.AddPlayer(ctx, match.MatchID, alice.UserID)
+	require.ErrorIs(t, err, core.ErrAlreadyPlaying)
+	_, err = recorder.AddPlayer(ctx, match.MatchID, carol.UserID)
+	require.ErrorIs(t, err, internal.ErrNotAttending)
+
+	// alice is missing a score
+	_, err = recorder.SubmitScores(ctx, match.MatchID, core.Scoreboard{
+		Scores:    []core.Score{{UserID: bob.UserID, Value: 58}},
+		ScoreUnit: core.ScoreUnitPoints,
+	})
+	var verr *core.ValidationError
+	require.ErrorAs(t, err, &verr)
+
+	match, err = recorder.SubmitScores(ctx, match.MatchID, core.Scoreboard{
+		Scores:    []core.Score{{UserID: alice.UserID, Value: 61}, {UserID: bob.UserID, Value: 58}},
+		ScoreUnit: core.ScoreUnitPoints,
+	})
+	require.NoError(t, err)
+
+	match, err = recorder.Finalize(ctx, match.MatchID)
+	require.NoError(t, err)
+	assert.Equal(t, core.MatchStatusFinalized, match.Status)
+	assert.Equal(t, now, match.EndedAt)
+
+	assert.Equal(t, alice.UserID, match.Placements()[0].UserID)
+	assert.True(t, match.Placements()[0].Won)
+
+	_, err = recorder.Finalize(ctx, match.MatchID)
+	require.ErrorIs(t, err, core.ErrMatchFinalized)
+	_, err = recorder.SubmitScores(ctx, match.MatchID, match.Scoreboard)
+	require.ErrorIs(t, err, core.ErrMatchFinalized)
+
+	stored, err := recorder.Get(ctx, match.MatchID)
+	require.NoError(t, err)
+	assert.Equal(t, match, stored)
+}
+
+func TestRecorderFinalizeHistoryFailure(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	evt := newTestEvent()
+	recorder, _, history := newTestRecorder(evt)
+
+	match, err := recorder.Start(ctx, evt.EventID, internal.StartRequest{
+		BGGID:     azulBGGID,
+		PlayerIDs: []core.UserID{evt.Attendees[0].User.UserID},
+	})
+	require.NoError(t, err)
+	history.err = errors.New("jetstream unavailable")
+
+	finalized, err := recorder.Finalize(ctx, match.MatchID)
+	require.Error(t, err)
+	require.NotNil(t, finalized)
+	assert.True(t, finalized.Finalized())
+
+	stored, err := recorder.Get(ctx, match.MatchID)
+	require.NoError(t, err)
+	assert.True(t, stored.Finalized())
+}
+
+func TestRecorderUnknownMatch(t *testing.T) {
+	t.Parallel()
+	recorder, _, _ := newTestRecorder(newTestEvent())
+
+	_, err := recorder.Get(context.Background(), core.NewMatchID())
+	assert.ErrorIs(t, err, event.ErrMatchNotFound)
+
+	_, err = recorder.Finalize(context.Background(), core.NewMatchID())
+	assert.ErrorIs(t, err, event.ErrMatchNotFound)
+}
+
+func TestRecorderHistory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	evt := newTestEvent()
+	alice, bob := evt.Attendees[0].User, evt.Attendees[1].User
+	recorder, _, _ := newTestRecorder(evt)
+	ctx = internal.WithActor(ctx, alice.UserID)
+
+	match, err := recorder.Start(ctx, evt.EventID, internal.StartRequest{
+		BGGID:     azulBGGID,
+		PlayerIDs: []core.UserID{alice.UserID, bob.UserID},
+	})
+	require.NoError(t, err)
+
+	// corrections are for finalized matches only
+	_, err = recorder.CorrectScore(ctx, match.MatchID, internal.CorrectionRequest{Score: core.Score{UserID: bob.UserID, Value: 64}})
+	require.ErrorIs(t, err, core.ErrMatchNotFinalized)
+
+	_, err = recorder.SubmitScores(ctx, match.MatchID, core.Scoreboard{
+		Scores:    []core.Score{{UserID: alice.UserID, Value: 61}, {UserID: bob.UserID, Value: 58}},
+		ScoreUnit: core.ScoreUnitPoints,
+	})
+	require.NoError(t, err)
+	_, err = recorder.Finalize(ctx, match.MatchID)
+	require.NoError(t, err)
+
+	_, err = recorder.CorrectScore(ctx, match.MatchID, internal.CorrectionRequest{Score: core.Score{UserID: core.NewUserID(), Value: 1}})
+	require.ErrorIs(t, err, core.ErrNotPlaying)
+
+	match, err = recorder.CorrectScore(ctx, match.MatchID, internal.CorrectionRequest{
+		Score:  core.Score{UserID: bob.UserID, Value: 64},
+		Reason: "miscounted bonus",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, bob.UserID, match.Placements()[0].UserID)
+
+	stored, err := recorder.Get(ctx, match.MatchID)
+	require.NoError(t, err)
+	assert.Equal(t, match, stored)
+
+	history, err := recorder.History(ctx, match.MatchID)
+	require.NoError(t, err)
+	kinds := make([]core.MatchCommandKind, 0, len(history))
+	for i, cmd := range history {
+		assert.Equal(t, i+1, cmd.Version)
+		assert.Equal(t, alice.UserID, cmd.By)
+		kinds = append(kinds, cmd.Kind)
+	}
+	assert.Equal(t, []core.MatchCommandKind{
+		core.MatchCommandStarted,
+		core.MatchCommandScoreSet,
+		core.MatchCommandFinalized,
+		core.MatchCommandScoreCorrected,
+	}, kinds)
+	assert.Equal(t, "miscounted bonus", history[3].Reason)
+}
+
+func TestRecorderImportsMatchWithoutHistory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	evt := newTestEvent()
+	alice := evt.Attendees[0].User
+	recorder, repo, history := newTestRecorder(evt)
+
+	// recorded before histories were kept
+	legacy := core.StartMatch(core.Game{GameID: core.NewGameID(), Name: "Azul"}, nil, now)
+	require.NoError(t, repo.CreateMatch(ctx, evt.EventID, legacy))
+	_, err := recorder.History(ctx, legacy.MatchID)
+	require.ErrorIs(t, err, internal.ErrNoHistory)
+
+	match, err := recorder.AddPlayer(ctx, legacy.MatchID, alice.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, []core.User{alice}, match.Players)
+
+	commands, err := history.History(ctx, legacy.MatchID)
+	require.NoError(t, err)
+	require.Len(t, commands, 2)
+	assert.Equal(t, core.MatchCommandStarted, commands[0].Kind)
+	assert.True(t, commands[0].By.IsZero())
+	assert.Equal(t, core.MatchCommandPlayerAdded, commands[1].Kind)
+}
+
+func TestRecorderHistoryFailure(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	evt := newTestEvent()
+	alice, bob := evt.Attendees[0].User, evt.Attendees[1].User
+	recorder, _, history := newTestRecorder(evt)
+
+	match, err := recorder.Start(ctx, evt.EventID, internal.StartRequest{BGGID: azulBGGID, PlayerIDs: []core.UserID{alice.UserID}})
+	require.NoError(t, err)
+
+	// the change is stored even though its command is missing from the history
+	history.err = errors.New("jetstream unavailable")
+	match, err = recorder.AddPlayer(ctx, match.MatchID, bob.UserID)
+	require.Error(t, err)
+	require.NotNil(t, match)
+	assert.Equal(t, []core.User{alice, bob}, match.Players)
+
+	// later changes apply to the stored match
+	history.err = nil
+	match, err = recorder.SubmitScores(ctx, match.MatchID, core.Scoreboard{
+		Scores:    []core.Score{{UserID: alice.UserID, Value: 61}, {UserID: bob.UserID, Value: 58}},
+		ScoreUnit: core.ScoreUnitPoints,
+	})
+	require.NoError(t, err)
+	assert.Len(t, match.Scoreboard.Scores, 2)
+
+	commands, err := recorder.History(ctx, match.MatchID)
+	require.NoError(t, err)
+	require.Len(t, commands, 2)
+	assert.Equal(t, core.MatchCommandScoreSet, commands[1].Kind)
+	assert.Equal(t, 2, commands[1].Version)
+}
@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/ngoldack/dicetrace/package/tournament"
+)
+
+// TournamentRequest runs an event as a tournament of a BoardGameGeek game.
+type TournamentRequest struct {
+	Format tournament.Format `json:"format"`
+	BGGID  int               `json:"bgg_id"`
+	// PlayerIDs are seeded in order, the strongest first.
+	PlayerIDs   []core.UserID `json:"player_ids"`
+	SwissRounds int           `json:"swiss_rounds,omitempty"`
+}
+
+// StartedRound is a round started by a tournament with the matches of its
+// pairings; byes have no match.
+type StartedRound struct {
+	// Number is the 1-based number of the round.
+	Number  int              `json:"number"`
+	Round   tournament.Round `json:"round"`
+	Matches []core.Match     `json:"matches"`
+}
+
+// Tournaments runs events as tournaments of their confirmed attendees. The
+// matches of every round are played like any other match of the event, see
+// Recorder.
+type Tournaments struct {
+	events      event.EventRepository
+	tournaments event.TournamentRepository
+	games       GameResolver
+	recorder    *Recorder
+}
+
+func NewTournaments(events event.EventRepository, tournaments event.TournamentRepository, games GameResolver, recorder *Recorder) *Tournaments {
+	return &Tournaments{
+		events:      events,
+		tournaments: tournaments,
+		games:       games,
+		recorder:    recorder,
+	}
+}
+
+// Create runs the event as a tournament. Players must be confirmed
+// attendees.
+func (t *Tournaments) Create(ctx context.Context, eventID core.EventID, req TournamentRequest) (*tournament.Tournament, error) {
+	evt, err := t.events.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := evt.CheckMutable(); err != nil {
+		return nil, err
+	}
+
+	players := make([]core.User, 0, len(req.PlayerIDs))
+	for _, userID := range req.PlayerIDs {
+		player, err := attendingPlayer(evt, userID)
+		if err != nil {
+			return nil, err
+		}
+		players = append(players, player)
+	}
+
+	game, err := t.games.ResolveGame(ctx, req.BGGID)
+	if err != nil {
+		return nil, err
+	}
+
+	tr, err := tournament.New(eventID, req.Format, *game, players)
+	if err != nil {
+		return nil, err
+	}
+	// the repository validates the number of rounds with the tournament
+	tr.SwissRounds = req.SwissRounds
+
+	if err := t.tournaments.CreateTournament(ctx, tr); err != nil {
+		return nil, err
+	}
+
+	return tr, nil
+}
+
+func (t *Tournaments) Get(ctx context.Context, eventID core.EventID) (*tournament.Tournament, error) {
+	return t.tournaments.GetTournament(ctx, eventID)
+}
+
+// NextRound starts the next round of the event's tournament once every match
+// of the current round is finalized. Failing to start the history of the
+// round's matches is reported along with the stored round.
+func (t *Tournaments) NextRound(ctx context.Context, eventID core.EventID) (*StartedRound, error) {
+	tr, matches, err := t.tournaments.StartTournamentRound(ctx, eventID, t.recorder.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for i := range matches {
+		if err := t.recorder.started(ctx, &matches[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	round := &StartedRound{
+		Number:  len(tr.Rounds),
+		Round:   tr.Rounds[len(tr.Rounds)-1],
+		Matches: matches,
+	}
+	return round, errors.Join(errs...)
+}
+
+// Standings ranks the players of the event's tournament by the rounds
+// completed so far.
+func (t *Tournaments) Standings(ctx context.Context, eventID core.EventID) ([]tournament.Standing, error) {
+	tr, err := t.tournaments.GetTournament(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	evt, err := t.events.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return tr.Standings(evt.Matches)
+}
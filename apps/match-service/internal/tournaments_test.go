@@ -0,0 +1,181 @@
+package internal_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/apps/match-service/internal"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/ngoldack/dicetrace/package/tournament"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTournamentRepository keeps the tournaments of the mock repository's
+// events in memory
+type mockTournamentRepository struct {
+	repo *mockRepository
+
+	mu          sync.Mutex
+	tournaments map[string]tournament.Tournament
+}
+
+func (r *mockTournamentRepository) CreateTournament(ctx context.Context, t *tournament.Tournament) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	if _, ok := r.tournaments[t.EventID.String()]; ok {
+		return event.ErrTournamentExists
+	}
+	r.tournaments[t.EventID.String()] = *t
+	return nil
+}
+
+func (r *mockTournamentRepository) GetTournament(ctx context.Context, eventID core.EventID) (*tournament.Tournament, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tournaments[eventID.String()]
+	if !ok {
+		return nil, event.ErrTournamentNotFound
+	}
+	t.Rounds = slices.Clone(t.Rounds)
+	return &t, nil
+}
+
+func (r *mockTournamentRepository) StartTournamentRound(ctx context.Context, eventID core.EventID, startedAt time.Time) (*tournament.Tournament, []core.Match, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tournaments[eventID.String()]
+	if !ok {
+		return nil, nil, event.ErrTournamentNotFound
+	}
+	evt, err := r.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t.Rounds = slices.Clone(t.Rounds)
+	matches, err := t.NextRound(evt.Matches, startedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range matches {
+		if err := r.repo.CreateMatch(ctx, eventID, &matches[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	r.tournaments[eventID.String()] = t
+	return &t, matches, nil
+}
+
+func newTestTournaments(evt *core.Event) (*internal.Tournaments, *internal.Recorder, *mockMatchLog) {
+	recorder, repo, history := newTestRecorder(evt)
+	games := mockGameResolver{azulBGGID: {GameID: core.NewGameID(), BGGID: azulBGGID, Name: "Azul"}}
+	tournaments := &mockTournamentRepository{repo: repo, tournaments: make(map[string]tournament.Tournament)}
+
+	return internal.NewTournaments(repo, tournaments, games, recorder), recorder, history
+}
+
+func TestTournamentsCreate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	evt := newTestEvent()
+	alice, bob, carol := evt.Attendees[0].User, evt.Attendees[1].User, evt.Attendees[2].User
+	tournaments, _, _ := newTestTournaments(evt)
+
+	_, err := tournaments.Create(ctx, evt.EventID, internal.TournamentRequest{
+		Format:    tournament.FormatSwiss,
+		BGGID:     azulBGGID,
+		PlayerIDs: []core.UserID{alice.UserID, carol.UserID},
+	})
+	require.ErrorIs(t, err, internal.ErrNotAttending)
+
+	_, err = tournaments.Create(ctx, evt.EventID, internal.TournamentRequest{
+		Format:      tournament.FormatRoundRobin,
+		BGGID:       azulBGGID,
+		PlayerIDs:   []core.UserID{alice.UserID, bob.UserID},
+		SwissRounds: 3,
+	})
+	var verr *core.ValidationError
+	require.ErrorAs(t, err, &verr)
+
+	tr, err := tournaments.Create(ctx, evt.EventID, internal.TournamentRequest{
+		Format:      tournament.FormatSwiss,
+		BGGID:       azulBGGID,
+		PlayerIDs:   []core.UserID{bob.UserID, alice.UserID},
+		SwissRounds: 3,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, evt.EventID, tr.EventID)
+	assert.Equal(t, []core.User{bob, alice}, tr.Players)
+	assert.Equal(t, 3, tr.SwissRounds)
+
+	stored, err := tournaments.Get(ctx, evt.EventID)
+	require.NoError(t, err)
+	assert.Equal(t, tr, stored)
+
+	_, err = tournaments.Create(ctx, evt.EventID, internal.TournamentRequest{
+		Format:    tournament.FormatSwiss,
+		BGGID:     azulBGGID,
+		PlayerIDs: []core.UserID{alice.UserID, bob.UserID},
+	})
+	require.ErrorIs(t, err, event.ErrTournamentExists)
+}
+
+func TestTournamentsPlay(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	evt := newTestEvent()
+	alice, bob := evt.Attendees[0].User, evt.Attendees[1].User
+	tournaments, recorder, history := newTestTournaments(evt)
+
+	_, err := tournaments.Create(ctx, evt.EventID, internal.TournamentRequest{
+		Format:    tournament.FormatSingleElimination,
+		BGGID:     azulBGGID,
+		PlayerIDs: []core.UserID{alice.UserID, bob.UserID},
+	})
+	require.NoError(t, err)
+
+	round, err := tournaments.NextRound(ctx, evt.EventID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, round.Number)
+	require.Len(t, round.Matches, 1)
+	match := round.Matches[0]
+	assert.Equal(t, match.MatchID, round.Round.Pairings[0].MatchID)
+	assert.Equal(t, now, match.StartedAt)
+
+	commands, err := history.History(ctx, match.MatchID)
+	require.NoError(t, err)
+	require.Len(t, commands, 1)
+	assert.Equal(t, core.MatchCommandStarted, commands[0].Kind)
+
+	_, err = tournaments.NextRound(ctx, evt.EventID)
+	require.ErrorIs(t, err, tournament.ErrRoundIncomplete)
+
+	_, err = recorder.SubmitScores(ctx, match.MatchID, core.Scoreboard{
+		Scores:    []core.Score{{UserID: alice.UserID, Value: 42}, {UserID: bob.UserID, Value: 47}},
+		ScoreUnit: core.ScoreUnitPoints,
+	})
+	require.NoError(t, err)
+	_, err = recorder.Finalize(ctx, match.MatchID)
+	require.NoError(t, err)
+
+	standings, err := tournaments.Standings(ctx, evt.EventID)
+	require.NoError(t, err)
+	require.Len(t, standings, 2)
+	assert.Equal(t, bob, standings[0].User)
+	assert.Equal(t, 1, standings[0].Wins)
+	assert.Equal(t, 1, standings[1].EliminatedIn)
+
+	_, err = tournaments.NextRound(ctx, evt.EventID)
+	require.ErrorIs(t, err, tournament.ErrComplete)
+
+	_, err = tournaments.Standings(ctx, core.NewEventID())
+	require.ErrorIs(t, err, event.ErrTournamentNotFound)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/apps/planner-service/internal"
+	"github.com/ngoldack/dicetrace/package/core/logger"
+	"github.com/ngoldack/dicetrace/package/core/service"
+	"github.com/ngoldack/dicetrace/package/event"
+)
+
+func main() {
+	if err := Run(context.Background()); err != nil {
+		panic(err)
+	}
+}
+
+func Run(ctx context.Context) error {
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	logger.SetupLogger()
+
+	slog.Info("starting planner-service...")
+
+	nc, err := nats.Connect(os.Getenv("NATS_URL"))
+	if err != nil {
+		return fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	defer nc.Close()
+
+	pool, err := pgxpool.New(ctx, os.Getenv("DATABASE_URL"))
+	if err != nil {
+		return fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+	defer pool.Close()
+
+	if err := event.Migrate(ctx, pool); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	repo := event.NewPostgreSQLEventRepository(pool)
+	tables := internal.NewTables(repo, internal.NewStatsServiceSkill(nc))
+
+	srv, err := service.NewService(ctx, nc, service.Config{
+		Name:    "planner-service",
+		Version: "1.0.0",
+		Endpoints: map[string]func() micro.Handler{
+			"planner-tables": func() micro.Handler { return internal.HandlerAssignTables(tables) },
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+	slog.Info("context cancelled, shutting down planner-service...")
+
+	if err := srv.Stop(); err != nil {
+		slog.Error("failed to stop micro service", "error", err)
+	}
+
+	slog.Info("planner-service exited gracefully")
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"encoding/json"
+
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/package/core/service"
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/ngoldack/dicetrace/package/planner"
+)
+
+const (
+	ErrorEventIDMissing = "event_id_missing"
+	ErrorRequestInvalid = "request_invalid"
+	ErrorEventNotFound  = "event_not_found"
+	ErrorNoSeating      = "no_seating"
+)
+
+var tablesErrorCodes = []service.ErrorCode{
+	{Err: event.ErrEventNotFound, Code: ErrorEventNotFound},
+	{Err: planner.ErrNoSeating, Code: ErrorNoSeating},
+}
+
+// HandlerAssignTables seats the confirmed attendees of the event given by
+// the event_id header at game tables. The request body is a TablesRequest.
+func HandlerAssignTables(tables *Tables) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		eventID, ok := service.IDFromHeader(r, "event_id", "event")
+		if !ok {
+			r.Error(ErrorEventIDMissing, "event ID is missing or invalid", nil)
+			return
+		}
+
+		var req TablesRequest
+		if err := json.Unmarshal(r.Data(), &req); err != nil || req.Tables < 0 {
+			r.Error(ErrorRequestInvalid, "failed to decode tables request", nil)
+			return
+		}
+
+		assigned, err := tables.Assign(ctx, eventID, req)
+		if err != nil {
+			service.RespondError(r, err, tablesErrorCodes)
+			return
+		}
+
+		_ = r.RespondJSON(assigned)
+	})
+}
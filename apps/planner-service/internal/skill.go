@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/rating"
+)
+
+// SubjectRatingPlayers is the stats-service endpoint returning the ratings
+// of the users listed by the request body.
+const SubjectRatingPlayers = "rating-players"
+
+// SkillSource looks up the overall ratings of players. Players without a
+// rating are left out.
+type SkillSource interface {
+	Skill(ctx context.Context, userIDs []core.UserID) (map[core.UserID]float64, error)
+}
+
+// StatsServiceSkill looks up the Glicko-2 ratings the stats-service keeps
+// up to date with every finalized match.
+type StatsServiceSkill struct {
+	nc *nats.Conn
+}
+
+var _ SkillSource = (*StatsServiceSkill)(nil)
+
+func NewStatsServiceSkill(nc *nats.Conn) *StatsServiceSkill {
+	return &StatsServiceSkill{
+		nc: nc,
+	}
+}
+
+func (s *StatsServiceSkill) Skill(ctx context.Context, userIDs []core.UserID) (map[core.UserID]float64, error) {
+	data, err := json.Marshal(struct {
+		UserIDs []core.UserID `json:"user_ids"`
+	}{UserIDs: userIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode ratings request: %w", err)
+	}
+
+	msg := nats.NewMsg(SubjectRatingPlayers)
+	msg.Data = data
+
+	resp, err := s.nc.RequestMsgWithContext(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request ratings from stats-service: %w", err)
+	}
+
+	if code := resp.Header.Get(micro.ErrorCodeHeader); code != "" {
+		return nil, fmt.Errorf("stats-service failed to return ratings: %s", resp.Header.Get(micro.ErrorHeader))
+	}
+
+	var ratings []rating.PlayerRating
+	if err := json.Unmarshal(resp.Data, &ratings); err != nil {
+		return nil, fmt.Errorf("failed to decode ratings: %w", err)
+	}
+
+	skill := make(map[core.UserID]float64, len(ratings))
+	for _, pr := range ratings {
+		skill[pr.User.UserID] = pr.Rating.Rating
+	}
+	return skill, nil
+}
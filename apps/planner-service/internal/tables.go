@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/ngoldack/dicetrace/package/planner"
+)
+
+// TablesRequest lists the games available at an event's tables.
+type TablesRequest struct {
+	Games []planner.TableGame `json:"games"`
+	// Tables fixes the number of tables; zero picks the best number.
+	Tables      int                  `json:"tables,omitempty"`
+	Preferences []planner.Preference `json:"preferences,omitempty"`
+}
+
+// Tables seats the confirmed attendees of events at game tables. Skill is
+// balanced by the attendees' overall ratings and variety by the matches
+// they finished before.
+type Tables struct {
+	events event.Repository
+	skill  SkillSource
+}
+
+func NewTables(events event.Repository, skill SkillSource) *Tables {
+	return &Tables{
+		events: events,
+		skill:  skill,
+	}
+}
+
+func (t *Tables) Assign(ctx context.Context, eventID core.EventID, req TablesRequest) ([]planner.Table, error) {
+	evt, err := t.events.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var players []core.User
+	var userIDs []core.UserID
+	for _, a := range evt.Attendees {
+		if a.Status == core.AttendeeStatusConfirmed {
+			players = append(players, a.User)
+			userIDs = append(userIDs, a.User.UserID)
+		}
+	}
+
+	skill, err := t.skill.Skill(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := t.events.ListFinalizedMatchesOf(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return planner.AssignTables(ctx, planner.TablesRequest{
+		Players:     players,
+		Games:       req.Games,
+		Tables:      req.Tables,
+		Preferences: req.Preferences,
+		Skill:       skill,
+		History:     history,
+	})
+}
@@ -0,0 +1,135 @@
+package internal_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/apps/planner-service/internal"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/ngoldack/dicetrace/package/planner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	azul  = core.Game{GameID: core.NewGameID(), BGGID: 230802, Name: "Azul"}
+	brass = core.Game{GameID: core.NewGameID(), BGGID: 224517, Name: "Brass: Birmingham"}
+	start = time.Date(2025, time.March, 6, 19, 0, 0, 0, time.UTC)
+)
+
+// mockRepository implements the parts of event.Repository used by the
+// planner-service in memory
+type mockRepository struct {
+	event.Repository
+
+	events []*core.Event
+}
+
+func (r *mockRepository) GetEvent(ctx context.Context, eventID core.EventID) (*core.Event, error) {
+	for _, evt := range r.events {
+		if evt.EventID == eventID {
+			return evt, nil
+		}
+	}
+	return nil, event.ErrEventNotFound
+}
+
+func (r *mockRepository) ListFinalizedMatchesOf(ctx context.Context, userIDs []core.UserID) ([]core.Match, error) {
+	var matches []core.Match
+	for _, evt := range r.events {
+		for _, m := range evt.Matches {
+			if slices.ContainsFunc(m.Players, func(p core.User) bool { return slices.Contains(userIDs, p.UserID) }) {
+				matches = append(matches, m)
+			}
+		}
+	}
+	return core.Chronological(matches), nil
+}
+
+// mockSkill returns the ratings of the players it knows and records the
+// players asked for
+type mockSkill struct {
+	ratings map[core.UserID]float64
+	asked   []core.UserID
+}
+
+func (s *mockSkill) Skill(ctx context.Context, userIDs []core.UserID) (map[core.UserID]float64, error) {
+	s.asked = userIDs
+	skill := make(map[core.UserID]float64)
+	for _, id := range userIDs {
+		if r, ok := s.ratings[id]; ok {
+			skill[id] = r
+		}
+	}
+	return skill, nil
+}
+
+func newUsers(names ...string) []core.User {
+	users := make([]core.User, 0, len(names))
+	for _, name := range names {
+		users = append(users, core.User{UserID: core.NewUserID(), Username: name})
+	}
+	return users
+}
+
+func newDuel(endedAt time.Time, winner, loser core.User) core.Match {
+	return core.Match{
+		MatchID:   core.NewMatchID(),
+		Status:    core.MatchStatusFinalized,
+		Game:      azul,
+		Players:   []core.User{winner, loser},
+		StartedAt: endedAt.Add(-time.Hour),
+		EndedAt:   endedAt,
+		Scoreboard: core.Scoreboard{ScoreUnit: core.ScoreUnitPoints, Scores: []core.Score{
+			{UserID: winner.UserID, Value: 2},
+			{UserID: loser.UserID, Value: 1},
+		}},
+	}
+}
+
+func TestTablesAssign(t *testing.T) {
+	t.Parallel()
+	users := newUsers("alice", "bob", "carol", "dave", "erin")
+	alice, bob, carol, dave, erin := users[0], users[1], users[2], users[3], users[4]
+
+	// alice and bob are the strongest players and played together
+	skill := &mockSkill{ratings: map[core.UserID]float64{alice.UserID: 1800, bob.UserID: 1750, carol.UserID: 1400, dave.UserID: 1350}}
+	past := &core.Event{EventID: core.NewEventID(), Title: "Last week", Status: core.EventStatusCompleted}
+	past.Matches = append(past.Matches, newDuel(start, alice, bob), newDuel(start, carol, dave))
+
+	tonight := &core.Event{EventID: core.NewEventID(), Title: "Tonight", Attendees: []core.Attendee{
+		{User: alice, Status: core.AttendeeStatusConfirmed},
+		{User: bob, Status: core.AttendeeStatusConfirmed},
+		{User: carol, Status: core.AttendeeStatusConfirmed},
+		{User: dave, Status: core.AttendeeStatusConfirmed},
+		{User: erin, Status: core.AttendeeStatusDeclined},
+	}}
+
+	tables := internal.NewTables(&mockRepository{events: []*core.Event{past, tonight}}, skill)
+
+	assigned, err := tables.Assign(t.Context(), tonight.EventID, internal.TablesRequest{
+		Games:  []planner.TableGame{{Game: azul, MinPlayers: 2, MaxPlayers: 2}, {Game: brass, MinPlayers: 2, MaxPlayers: 2}},
+		Tables: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, assigned, 2)
+
+	for _, table := range assigned {
+		require.Len(t, table.Players, 2)
+		assert.NotEqual(t, erin, table.Players[0])
+		strong := 0
+		for _, p := range table.Players {
+			if p == alice || p == bob {
+				strong++
+			}
+		}
+		assert.Equal(t, 1, strong, "strong players are split")
+	}
+	assert.Equal(t, []core.UserID{alice.UserID, bob.UserID, carol.UserID, dave.UserID}, skill.asked, "only confirmed attendees are rated")
+
+	_, err = tables.Assign(t.Context(), core.NewEventID(), internal.TablesRequest{})
+	assert.ErrorIs(t, err, event.ErrEventNotFound)
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/apps/scheduler/internal"
+	"github.com/ngoldack/dicetrace/package/core/logger"
+	"github.com/ngoldack/dicetrace/package/core/service"
+	"github.com/ngoldack/dicetrace/package/core/stream"
+	"github.com/ngoldack/dicetrace/package/event"
+	"golang.org/x/sync/errgroup"
+)
+
+func main() {
+	if err := Run(context.Background()); err != nil {
+		panic(err)
+	}
+}
+
+const (
+	defaultHorizon          = 4 * 7 * 24 * time.Hour
+	defaultInterval         = time.Hour
+	defaultReminderOffsets  = "24h,1h"
+	defaultReminderInterval = time.Minute
+	defaultHTTPAddr         = ":8080"
+	outboxInterval          = time.Second
+	shutdownTimeout         = 10 * time.Second
+)
+
+func Run(ctx context.Context) error {
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	logger.SetupLogger()
+
+	slog.Info("starting scheduler...")
+
+	horizon, err := durationFromEnv("SCHEDULER_HORIZON", defaultHorizon)
+	if err != nil {
+		return err
+	}
+
+	interval, err := durationFromEnv("SCHEDULER_INTERVAL", defaultInterval)
+	if err != nil {
+		return err
+	}
+
+	reminderInterval, err := durationFromEnv("SCHEDULER_REMINDER_INTERVAL", defaultReminderInterval)
+	if err != nil {
+		return err
+	}
+
+	reminderOffsets := os.Getenv("SCHEDULER_REMINDER_OFFSETS")
+	if reminderOffsets == "" {
+		reminderOffsets = defaultReminderOffsets
+	}
+	offsets, err := internal.ParseOffsets(reminderOffsets)
+	if err != nil {
+		return err
+	}
+
+	nc, err := nats.Connect(os.Getenv("NATS_URL"))
+	if err != nil {
+		return fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	defer nc.Close()
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	pool, err := pgxpool.New(ctx, os.Getenv("DATABASE_URL"))
+	if err != nil {
+		return fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+	defer pool.Close()
+
+	if err := event.Migrate(ctx, pool); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	templates, err := internal.NewKVTemplateStore(ctx, js)
+	if err != nil {
+		return err
+	}
+
+	occurrences, err := internal.NewKVOccurrenceStore(ctx, js)
+	if err != nil {
+		return err
+	}
+
+	polls, err := internal.NewKVPollStore(ctx, js)
+	if err != nil {
+		return err
+	}
+
+	reminderLog, err := internal.NewKVReminderLog(ctx, js)
+	if err != nil {
+		return err
+	}
+
+	feedTokens, err := internal.NewKVFeedTokenStore(ctx, js)
+	if err != nil {
+		return err
+	}
+
+	notifyStream, err := internal.NewNotifyStream(ctx, js)
+	if err != nil {
+		return err
+	}
+
+	if err := stream.CreateStreams(ctx, js); err != nil {
+		return err
+	}
+
+	events := event.NewPostgreSQLEventRepository(pool)
+	relay := event.NewOutboxRelay(pool, stream.NewJetStreamPublisher(js))
+	materializer := internal.NewMaterializer(templates, occurrences, events, horizon)
+	dispatcher := internal.NewReminderDispatcher(events, reminderLog, internal.NewJetStreamReminderPublisher(js), offsets)
+	rsvp := internal.NewRSVP(events, internal.NewNATSRSVPPublisher(nc))
+	notifier := internal.NewNotifier(notifyStream, reminderLog, sinksFromEnv()...)
+
+	srv, err := service.NewService(ctx, nc, service.Config{
+		Name:    "scheduler",
+		Version: "1.0.0",
+		Endpoints: map[string]func() micro.Handler{
+			"template-create":  func() micro.Handler { return internal.HandlerCreateTemplate(templates, materializer) },
+			"template-get":     func() micro.Handler { return internal.HandlerGetTemplate(templates) },
+			"template-list":    func() micro.Handler { return internal.HandlerListTemplates(templates) },
+			"template-delete":  func() micro.Handler { return internal.HandlerDeleteTemplate(templates) },
+			"template-except":  func() micro.Handler { return internal.HandlerAddException(templates, materializer) },
+			"materialize":      func() micro.Handler { return internal.HandlerMaterialize(materializer) },
+			"poll-create":      func() micro.Handler { return internal.HandlerCreatePoll(polls) },
+			"poll-get":         func() micro.Handler { return internal.HandlerGetPoll(polls) },
+			"poll-vote":        func() micro.Handler { return internal.HandlerVotePoll(polls) },
+			"poll-close":       func() micro.Handler { return internal.HandlerClosePoll(polls, events) },
+			"rsvp-invite":      func() micro.Handler { return internal.HandlerInvite(rsvp) },
+			"rsvp-accept":      func() micro.Handler { return internal.HandlerAccept(rsvp) },
+			"rsvp-decline":     func() micro.Handler { return internal.HandlerDecline(rsvp) },
+			"ical-feed":        func() micro.Handler { return internal.HandlerCalendarFeed(events) },
+			"ical-import":      func() micro.Handler { return internal.HandlerCalendarImport(events) },
+			"ical-feed-token":  func() micro.Handler { return internal.HandlerCalendarFeedToken(feedTokens) },
+			"ical-feed-rotate": func() micro.Handler { return internal.HandlerRotateFeedToken(feedTokens) },
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	httpAddr := os.Getenv("SCHEDULER_HTTP_ADDR")
+	if httpAddr == "" {
+		httpAddr = defaultHTTPAddr
+	}
+	httpSrv := &http.Server{
+		Addr:              httpAddr,
+		Handler:           internal.NewCalendarHandler(events, feedTokens),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errg, ctx := errgroup.WithContext(ctx)
+
+	errg.Go(func() error {
+		slog.Info("serving calendar feeds", slog.String("addr", httpAddr))
+		if err := httpSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("calendar server failed: %w", err)
+		}
+		return nil
+	})
+
+	errg.Go(func() error {
+		return materializer.Run(ctx, interval)
+	})
+
+	errg.Go(func() error {
+		return dispatcher.Run(ctx, reminderInterval)
+	})
+
+	errg.Go(func() error {
+		return notifier.Run(ctx)
+	})
+
+	errg.Go(func() error {
+		return relay.Run(ctx, outboxInterval)
+	})
+
+	// Blocking go-routine to wait for context cancellation
+	errg.Go(func() error {
+		<-ctx.Done()
+		slog.Info("context cancelled, shutting down scheduler...")
+
+		if err := srv.Stop(); err != nil {
+			slog.Error("failed to stop micro service", "error", err)
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := httpSrv.Shutdown(shutdownCtx); err != nil {
+			slog.Error("failed to stop calendar server", "error", err)
+		}
+
+		return nil
+	})
+
+	err = errg.Wait()
+	if err != nil {
+		return fmt.Errorf("scheduler exited with error: %w", err)
+	}
+
+	slog.Info("scheduler exited gracefully")
+
+	return nil
+}
+
+// durationFromEnv parses the environment variable as a time.Duration,
+// falling back to def if it is unset.
+func durationFromEnv(key string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration in %s: %w", key, err)
+	}
+
+	return d, nil
+}
+
+// sinksFromEnv returns the reminder sinks configured in the environment.
+// Reminders are always logged.
+func sinksFromEnv() []internal.Sink {
+	sinks := []internal.Sink{internal.LogSink{}}
+
+	if url := os.Getenv("NOTIFY_WEBHOOK_URL"); url != "" {
+		sinks = append(sinks, internal.NewWebhookSink(url))
+	}
+
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		sinks = append(sinks, internal.NewSMTPSink(internal.SMTPConfig{
+			Addr:     addr,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+			To:       strings.Split(os.Getenv("SMTP_TO"), ","),
+		}))
+	}
+
+	return sinks
+}
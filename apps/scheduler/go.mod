@@ -1,3 +1,12 @@
 module github.com/ngoldack/dicetrace/apps/scheduler
 
 go 1.25.3
+
+require (
+	github.com/jackc/pgx/v5 v5.7.4
+	github.com/nats-io/nats.go v1.47.0
+	github.com/stretchr/testify v1.11.1
+	github.com/teambition/rrule-go v1.8.2
+	go.jetify.com/typeid/v2 v2.0.0-alpha.3
+	golang.org/x/sync v0.17.0
+)
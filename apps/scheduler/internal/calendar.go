@@ -0,0 +1,161 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/core/ical"
+	"github.com/ngoldack/dicetrace/package/event"
+)
+
+const calendarContentType = "text/calendar; charset=utf-8"
+
+// Feed returns the user's upcoming events as a calendar. Events the user
+// declined are left out; cancelled events are kept, so subscribed calendars
+// show the cancellation.
+func Feed(ctx context.Context, events event.EventRepository, userID core.UserID, now time.Time) (*ical.Calendar, error) {
+	attended, err := events.ListEventsByAttendee(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &ical.Calendar{
+		Name:   "dicetrace",
+		Events: make([]*core.Event, 0, len(attended)),
+	}
+
+	for _, evt := range attended {
+		if evt.StartsAt.IsZero() || evt.Status == core.EventStatusCompleted {
+			continue
+		}
+
+		end := evt.EndsAt
+		if end.IsZero() {
+			end = evt.StartsAt
+		}
+		if end.Before(now) {
+			continue
+		}
+
+		if declined(evt, userID) {
+			continue
+		}
+
+		cal.Events = append(cal.Events, evt)
+	}
+
+	return cal, nil
+}
+
+func declined(evt *core.Event, userID core.UserID) bool {
+	for _, a := range evt.Attendees {
+		if a.User.UserID == userID {
+			return a.Status == core.AttendeeStatusDeclined
+		}
+	}
+	return false
+}
+
+// Import creates the events of the calendar. Events that already exist are
+// skipped, so importing the same calendar twice is harmless. It returns the
+// IDs of the created events.
+func Import(ctx context.Context, events event.EventRepository, cal *ical.Calendar) ([]core.EventID, error) {
+	created := make([]core.EventID, 0, len(cal.Events))
+	var errs []error
+
+	for _, evt := range cal.Events {
+		err := events.CreateEvent(ctx, evt)
+		if errors.Is(err, event.ErrEventExists) {
+			continue
+		} else if err != nil {
+			errs = append(errs, fmt.Errorf("event '%s': %w", evt.EventID, err))
+			continue
+		}
+
+		created = append(created, evt.EventID)
+	}
+
+	return created, errors.Join(errs...)
+}
+
+const feedTokenLength = 26
+
+// NewFeedToken returns a new random feed token. Tokens carry 128 bits of
+// randomness in base32.
+func NewFeedToken() string {
+	return rand.Text()
+}
+
+// ValidFeedToken reports whether the token is shaped like the tokens
+// NewFeedToken returns.
+func ValidFeedToken(token string) bool {
+	if len(token) != feedTokenLength {
+		return false
+	}
+	for _, c := range token {
+		if (c < 'A' || c > 'Z') && (c < '2' || c > '7') {
+			return false
+		}
+	}
+	return true
+}
+
+// FeedPath returns the path the feed of the token is served at.
+func FeedPath(token string) string {
+	return "/calendar/" + token + ".ics"
+}
+
+// NewCalendarHandler serves subscribable per-user feeds at
+// GET /calendar/{token}.ics. Calendar apps cannot authenticate, so feeds are
+// identified by a secret token per user instead of the user ID; unknown
+// tokens are not found.
+func NewCalendarHandler(events event.EventRepository, tokens FeedTokenStore) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /calendar/{file}", func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutSuffix(r.PathValue("file"), ".ics")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		userID, ok, err := tokens.Resolve(r.Context(), token)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to resolve calendar feed token", slog.Any("error", err))
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		cal, err := Feed(r.Context(), events, userID, time.Now())
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to build calendar feed", slog.String("user_id", userID.String()), slog.Any("error", err))
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := ical.Encode(&buf, cal); err != nil {
+			slog.ErrorContext(r.Context(), "failed to encode calendar feed", slog.String("user_id", userID.String()), slog.Any("error", err))
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", calendarContentType)
+		w.Header().Set("Cache-Control", "private")
+		_, _ = w.Write(buf.Bytes())
+	})
+
+	return mux
+}
@@ -0,0 +1,170 @@
+package internal_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/apps/scheduler/internal"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/core/ical"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeed(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	now := time.Date(2025, time.June, 5, 12, 0, 0, 0, time.UTC)
+	events := newMockEventRepository()
+
+	upcoming := newScheduledEvent(t, now.Add(24*time.Hour))
+	user := upcoming.Attendees[0].User
+	require.NoError(t, events.CreateEvent(ctx, upcoming))
+
+	past := newScheduledEvent(t, now.Add(-48*time.Hour))
+	past.Attendees[0].User = user
+	require.NoError(t, events.CreateEvent(ctx, past))
+
+	cancelled := newScheduledEvent(t, now.Add(48*time.Hour))
+	cancelled.Attendees[0].User = user
+	require.NoError(t, cancelled.Transition(core.EventTransitionCancel))
+	require.NoError(t, events.CreateEvent(ctx, cancelled))
+
+	declined := newScheduledEvent(t, now.Add(72*time.Hour))
+	declined.Attendees[0] = core.Attendee{User: user, Status: core.AttendeeStatusDeclined}
+	require.NoError(t, events.CreateEvent(ctx, declined))
+
+	cal, err := internal.Feed(ctx, events, user.UserID, now)
+	require.NoError(t, err)
+
+	ids := make([]core.EventID, 0, len(cal.Events))
+	for _, evt := range cal.Events {
+		ids = append(ids, evt.EventID)
+	}
+	assert.Equal(t, []core.EventID{upcoming.EventID, cancelled.EventID}, ids)
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	events := newMockEventRepository()
+
+	existing := newScheduledEvent(t, time.Now().Add(24*time.Hour))
+	require.NoError(t, events.CreateEvent(ctx, existing))
+	imported := newScheduledEvent(t, time.Now().Add(48*time.Hour))
+
+	var buf bytes.Buffer
+	require.NoError(t, ical.Encode(&buf, &ical.Calendar{Events: []*core.Event{existing, imported}}))
+	cal, err := ical.Decode(&buf)
+	require.NoError(t, err)
+
+	created, err := internal.Import(ctx, events, cal)
+	require.NoError(t, err)
+	assert.Equal(t, []core.EventID{imported.EventID}, created)
+
+	stored, err := events.GetEvent(ctx, imported.EventID)
+	require.NoError(t, err)
+	assert.Equal(t, imported.Attendees, stored.Attendees)
+}
+
+// mockFeedTokenStore implements internal.FeedTokenStore in memory
+type mockFeedTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]core.UserID
+	users  map[core.UserID]string
+}
+
+func newMockFeedTokenStore() *mockFeedTokenStore {
+	return &mockFeedTokenStore{tokens: make(map[string]core.UserID), users: make(map[core.UserID]string)}
+}
+
+func (s *mockFeedTokenStore) Token(ctx context.Context, userID core.UserID) (string, error) {
+	s.mu.Lock()
+	token, ok := s.users[userID]
+	s.mu.Unlock()
+	if ok {
+		return token, nil
+	}
+	return s.Rotate(ctx, userID)
+}
+
+func (s *mockFeedTokenStore) Rotate(ctx context.Context, userID core.UserID) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, s.users[userID])
+	token := internal.NewFeedToken()
+	s.tokens[token] = userID
+	s.users[userID] = token
+	return token, nil
+}
+
+func (s *mockFeedTokenStore) Resolve(ctx context.Context, token string) (core.UserID, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	userID, ok := s.tokens[token]
+	return userID, ok, nil
+}
+
+func TestCalendarHandler(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	events := newMockEventRepository()
+	tokens := newMockFeedTokenStore()
+
+	evt := newScheduledEvent(t, time.Now().Add(24*time.Hour))
+	require.NoError(t, events.CreateEvent(ctx, evt))
+	user := evt.Attendees[0].User
+
+	token, err := tokens.Token(ctx, user.UserID)
+	require.NoError(t, err)
+	assert.True(t, internal.ValidFeedToken(token))
+
+	srv := httptest.NewServer(internal.NewCalendarHandler(events, tokens))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + internal.FeedPath(token))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/calendar; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	cal, err := ical.Decode(resp.Body)
+	require.NoError(t, err)
+	require.Len(t, cal.Events, 1)
+	assert.Equal(t, evt.EventID, cal.Events[0].EventID)
+
+	rotated, err := tokens.Rotate(ctx, user.UserID)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		path   string
+		status int
+	}{
+		{internal.FeedPath(rotated), http.StatusOK},
+		// revoked by rotating
+		{internal.FeedPath(token), http.StatusNotFound},
+		// feeds are not served by user ID
+		{"/calendar/" + user.UserID.String() + ".ics", http.StatusNotFound},
+		{"/calendar/" + rotated, http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		resp, err := http.Get(srv.URL + tc.path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, tc.status, resp.StatusCode, tc.path)
+	}
+}
+
+func TestValidFeedToken(t *testing.T) {
+	t.Parallel()
+	assert.True(t, internal.ValidFeedToken(internal.NewFeedToken()))
+	assert.NotEqual(t, internal.NewFeedToken(), internal.NewFeedToken())
+	assert.False(t, internal.ValidFeedToken(""))
+	assert.False(t, internal.ValidFeedToken(core.NewUserID().String()))
+	assert.False(t, internal.ValidFeedToken("../../../../../../../../../"))
+}
@@ -0,0 +1,11 @@
+package internal
+
+import "time"
+
+func SetMaterializerClock(m *Materializer, now func() time.Time) {
+	m.now = now
+}
+
+func SetDispatcherClock(d *ReminderDispatcher, now func() time.Time) {
+	d.now = now
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/core/ical"
+	"github.com/ngoldack/dicetrace/package/core/service"
+	"github.com/ngoldack/dicetrace/package/event"
+)
+
+const ErrorCalendarInvalid = "calendar_invalid"
+
+var calendarErrorCodes = []service.ErrorCode{
+	{Err: ical.ErrInvalid, Code: ErrorCalendarInvalid},
+}
+
+// HandlerCalendarFeed responds with the iCalendar feed of the user in the
+// user_id header.
+func HandlerCalendarFeed(events event.EventRepository) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		userID, ok := service.IDFromHeader(r, "user_id", "user")
+		if !ok {
+			r.Error(ErrorUserIDMissing, "user ID is missing or invalid", nil)
+			return
+		}
+
+		cal, err := Feed(ctx, events, userID, time.Now())
+		if err != nil {
+			service.RespondError(r, err, calendarErrorCodes)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := ical.Encode(&buf, cal); err != nil {
+			service.RespondError(r, err, calendarErrorCodes)
+			return
+		}
+
+		_ = r.Respond(buf.Bytes(), micro.WithHeaders(micro.Headers{"Content-Type": {calendarContentType}}))
+	})
+}
+
+// FeedTokenResponse is the response of the feed token endpoints.
+type FeedTokenResponse struct {
+	Token string `json:"token"`
+	// Path is the path of the feed on the calendar HTTP server.
+	Path string `json:"path"`
+}
+
+// HandlerCalendarFeedToken responds with the feed token of the user in the
+// user_id header, issuing one on first use.
+func HandlerCalendarFeedToken(tokens FeedTokenStore) micro.Handler {
+	return handlerFeedToken(tokens.Token)
+}
+
+// HandlerRotateFeedToken issues a new feed token for the user in the user_id
+// header, so the feed is no longer served at its old URL.
+func HandlerRotateFeedToken(tokens FeedTokenStore) micro.Handler {
+	return handlerFeedToken(tokens.Rotate)
+}
+
+func handlerFeedToken(token func(ctx context.Context, userID core.UserID) (string, error)) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		userID, ok := service.IDFromHeader(r, "user_id", "user")
+		if !ok {
+			r.Error(ErrorUserIDMissing, "user ID is missing or invalid", nil)
+			return
+		}
+
+		t, err := token(ctx, userID)
+		if err != nil {
+			service.RespondError(r, err, calendarErrorCodes)
+			return
+		}
+
+		_ = r.RespondJSON(FeedTokenResponse{Token: t, Path: FeedPath(t)})
+	})
+}
+
+// HandlerCalendarImport creates the events of the iCalendar request body and
+// responds with the IDs of the created events.
+func HandlerCalendarImport(events event.EventRepository) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		cal, err := ical.Decode(bytes.NewReader(r.Data()))
+		if err != nil {
+			r.Error(ErrorCalendarInvalid, service.ErrorDescription(err), nil)
+			return
+		}
+
+		created, err := Import(ctx, events, cal)
+		if err != nil {
+			if len(created) == 0 {
+				service.RespondError(r, err, calendarErrorCodes)
+				return
+			}
+			slog.ErrorContext(ctx, "calendar import partially failed", slog.Any("error", err))
+		}
+
+		_ = r.RespondJSON(map[string][]core.EventID{"created": created})
+	})
+}
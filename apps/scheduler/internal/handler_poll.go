@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/core/service"
+	"github.com/ngoldack/dicetrace/package/event"
+)
+
+const (
+	ErrorPollInvalid   = "poll_invalid"
+	ErrorPollNotFound  = "poll_not_found"
+	ErrorPollIDMissing = "poll_id_missing"
+	ErrorPollClosed    = "poll_closed"
+	ErrorNoQuorum      = "no_quorum"
+)
+
+var pollErrorCodes = []service.ErrorCode{
+	{Err: ErrPollNotFound, Code: ErrorPollNotFound},
+	{Err: ErrPollInvalid, Code: ErrorPollInvalid},
+	{Err: ErrPollClosed, Code: ErrorPollClosed},
+	{Err: ErrNoQuorum, Code: ErrorNoQuorum},
+}
+
+// PollView is a poll together with its current tally.
+type PollView struct {
+	*Poll
+	Tally []SlotTally `json:"tally"`
+}
+
+func HandlerCreatePoll(store PollStore) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		var poll Poll
+		if err := json.Unmarshal(r.Data(), &poll); err != nil {
+			r.Error(ErrorPollInvalid, "failed to decode poll", nil)
+			return
+		}
+
+		poll.PollID = NewPollID()
+		poll.Ballots = []Ballot{}
+		poll.EventID = core.EventID{}
+		if err := poll.Validate(); err != nil {
+			r.Error(ErrorPollInvalid, service.ErrorDescription(err), nil)
+			return
+		}
+
+		if err := store.CreatePoll(ctx, &poll); err != nil {
+			service.RespondError(r, err, pollErrorCodes)
+			return
+		}
+
+		_ = r.RespondJSON(PollView{Poll: &poll, Tally: poll.Tally()})
+	})
+}
+
+func HandlerGetPoll(store PollStore) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		pollID, ok := service.IDFromHeader(r, "poll_id", "poll")
+		if !ok {
+			r.Error(ErrorPollIDMissing, "poll ID is missing or invalid", nil)
+			return
+		}
+
+		poll, err := store.GetPoll(ctx, pollID)
+		if err != nil {
+			service.RespondError(r, err, pollErrorCodes)
+			return
+		}
+
+		_ = r.RespondJSON(PollView{Poll: poll, Tally: poll.Tally()})
+	})
+}
+
+// HandlerVotePoll records a user's votes. The request body is a Ballot.
+func HandlerVotePoll(store PollStore) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		pollID, ok := service.IDFromHeader(r, "poll_id", "poll")
+		if !ok {
+			r.Error(ErrorPollIDMissing, "poll ID is missing or invalid", nil)
+			return
+		}
+
+		var ballot Ballot
+		if err := json.Unmarshal(r.Data(), &ballot); err != nil {
+			r.Error(ErrorPollInvalid, "failed to decode ballot", nil)
+			return
+		}
+
+		poll, err := store.UpdatePoll(ctx, pollID, func(poll *Poll) error {
+			return poll.Vote(ballot)
+		})
+		if err != nil {
+			service.RespondError(r, err, pollErrorCodes)
+			return
+		}
+
+		_ = r.RespondJSON(PollView{Poll: poll, Tally: poll.Tally()})
+	})
+}
+
+// HandlerClosePoll closes the poll and responds with the created event.
+func HandlerClosePoll(store PollStore, events event.EventRepository) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		pollID, ok := service.IDFromHeader(r, "poll_id", "poll")
+		if !ok {
+			r.Error(ErrorPollIDMissing, "poll ID is missing or invalid", nil)
+			return
+		}
+
+		evt, err := ClosePoll(ctx, store, events, pollID)
+		if err != nil {
+			service.RespondError(r, err, pollErrorCodes)
+			return
+		}
+
+		_ = r.RespondJSON(evt)
+	})
+}
+
+// ClosePoll closes the poll and creates the event for its best slot. The
+// poll is marked closed before the event is created, so concurrent calls
+// cannot create the event twice; it is reopened if creating the event fails.
+func ClosePoll(ctx context.Context, store PollStore, events event.EventRepository, pollID PollID) (*core.Event, error) {
+	var evt *core.Event
+	_, err := store.UpdatePoll(ctx, pollID, func(poll *Poll) error {
+		var err error
+		evt, err = poll.Close()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := events.CreateEvent(ctx, evt); err != nil {
+		_, rerr := store.UpdatePoll(ctx, pollID, func(poll *Poll) error {
+			poll.Reopen()
+			return nil
+		})
+		if rerr != nil {
+			err = errors.Join(err, rerr)
+		}
+		return nil, fmt.Errorf("failed to create event for poll '%s': %w", pollID, err)
+	}
+
+	slog.InfoContext(ctx, "closed poll",
+		slog.String("poll_id", pollID.String()),
+		slog.String("event_id", evt.EventID.String()),
+		slog.Time("starts_at", evt.StartsAt),
+	)
+
+	return evt, nil
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/core/service"
+	"github.com/ngoldack/dicetrace/package/event"
+)
+
+const (
+	ErrorEventIDMissing = "event_id_missing"
+	ErrorUserIDMissing  = "user_id_missing"
+	ErrorUserInvalid    = "user_invalid"
+	ErrorEventNotFound  = "event_not_found"
+	ErrorEventClosed    = "event_closed"
+	ErrorAlreadyInvited = "already_invited"
+	ErrorNotInvited     = "not_invited"
+)
+
+var rsvpErrorCodes = []service.ErrorCode{
+	{Err: event.ErrEventNotFound, Code: ErrorEventNotFound},
+	{Err: core.ErrEventClosed, Code: ErrorEventClosed},
+	{Err: core.ErrAlreadyInvited, Code: ErrorAlreadyInvited},
+	{Err: core.ErrNotInvited, Code: ErrorNotInvited},
+}
+
+// HandlerInvite invites a user to the event. The request body is the user.
+func HandlerInvite(rsvp *RSVP) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		eventID, ok := service.IDFromHeader(r, "event_id", "event")
+		if !ok {
+			r.Error(ErrorEventIDMissing, "event ID is missing or invalid", nil)
+			return
+		}
+
+		var user core.User
+		if err := json.Unmarshal(r.Data(), &user); err != nil || user.UserID.IsZero() {
+			r.Error(ErrorUserInvalid, "failed to decode user", nil)
+			return
+		}
+
+		respondRSVP(r, eventID.String(), func() (*core.Event, error) {
+			return rsvp.Invite(ctx, eventID, user)
+		})
+	})
+}
+
+func HandlerAccept(rsvp *RSVP) micro.Handler {
+	return handlerRespond(rsvp.Accept)
+}
+
+func HandlerDecline(rsvp *RSVP) micro.Handler {
+	return handlerRespond(rsvp.Decline)
+}
+
+// handlerRespond handles a user's response to an invitation, identified by
+// the event_id and user_id headers.
+func handlerRespond(respond func(ctx context.Context, eventID core.EventID, userID core.UserID) (*core.Event, error)) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		eventID, ok := service.IDFromHeader(r, "event_id", "event")
+		if !ok {
+			r.Error(ErrorEventIDMissing, "event ID is missing or invalid", nil)
+			return
+		}
+
+		userID, ok := service.IDFromHeader(r, "user_id", "user")
+		if !ok {
+			r.Error(ErrorUserIDMissing, "user ID is missing or invalid", nil)
+			return
+		}
+
+		respondRSVP(r, eventID.String(), func() (*core.Event, error) {
+			return respond(ctx, eventID, userID)
+		})
+	})
+}
+
+// respondRSVP responds with the updated event. If only publishing the
+// changes failed, the error is logged as the update itself succeeded.
+func respondRSVP(r micro.Request, eventID string, update func() (*core.Event, error)) {
+	evt, err := update()
+	if evt == nil {
+		service.RespondError(r, err, rsvpErrorCodes)
+		return
+	}
+	if err != nil {
+		slog.Error("failed to publish rsvp changes", slog.String("event_id", eventID), slog.Any("error", err))
+	}
+
+	_ = r.RespondJSON(evt)
+}
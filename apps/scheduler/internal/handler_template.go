@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/package/core/service"
+)
+
+const (
+	ErrorTemplateInvalid   = "template_invalid"
+	ErrorTemplateNotFound  = "template_not_found"
+	ErrorTemplateIDMissing = "template_id_missing"
+)
+
+var templateErrorCodes = []service.ErrorCode{
+	{Err: ErrTemplateNotFound, Code: ErrorTemplateNotFound},
+	{Err: ErrTemplateInvalid, Code: ErrorTemplateInvalid},
+}
+
+func HandlerCreateTemplate(store TemplateStore, materializer *Materializer) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		var tmpl Template
+		if err := json.Unmarshal(r.Data(), &tmpl); err != nil {
+			r.Error(ErrorTemplateInvalid, "failed to decode template", nil)
+			return
+		}
+
+		tmpl.TemplateID = NewTemplateID()
+		if err := tmpl.Validate(); err != nil {
+			r.Error(ErrorTemplateInvalid, service.ErrorDescription(err), nil)
+			return
+		}
+
+		if err := store.SaveTemplate(ctx, &tmpl); err != nil {
+			service.RespondError(r, err, templateErrorCodes)
+			return
+		}
+
+		// The periodic run retries failed materializations, so the template
+		// is created regardless.
+		if _, err := materializer.Materialize(ctx, &tmpl); err != nil {
+			slog.ErrorContext(ctx, "failed to materialize new template", slog.String("template_id", tmpl.TemplateID.String()), slog.Any("error", err))
+		}
+
+		_ = r.RespondJSON(tmpl)
+	})
+}
+
+func HandlerGetTemplate(store TemplateStore) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		templateID, ok := service.IDFromHeader(r, "template_id", "template")
+		if !ok {
+			r.Error(ErrorTemplateIDMissing, "template ID is missing or invalid", nil)
+			return
+		}
+
+		tmpl, err := store.GetTemplate(ctx, templateID)
+		if err != nil {
+			service.RespondError(r, err, templateErrorCodes)
+			return
+		}
+
+		_ = r.RespondJSON(tmpl)
+	})
+}
+
+func HandlerListTemplates(store TemplateStore) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		templates, err := store.ListTemplates(ctx)
+		if err != nil {
+			service.RespondError(r, err, templateErrorCodes)
+			return
+		}
+
+		_ = r.RespondJSON(templates)
+	})
+}
+
+func HandlerDeleteTemplate(store TemplateStore) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		templateID, ok := service.IDFromHeader(r, "template_id", "template")
+		if !ok {
+			r.Error(ErrorTemplateIDMissing, "template ID is missing or invalid", nil)
+			return
+		}
+
+		if err := store.DeleteTemplate(ctx, templateID); err != nil {
+			service.RespondError(r, err, templateErrorCodes)
+			return
+		}
+
+		_ = r.Respond(nil)
+	})
+}
+
+// HandlerAddException skips or moves a single occurrence of a template. The
+// request body is an Exception.
+func HandlerAddException(store TemplateStore, materializer *Materializer) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		templateID, ok := service.IDFromHeader(r, "template_id", "template")
+		if !ok {
+			r.Error(ErrorTemplateIDMissing, "template ID is missing or invalid", nil)
+			return
+		}
+
+		var ex Exception
+		if err := json.Unmarshal(r.Data(), &ex); err != nil {
+			r.Error(ErrorTemplateInvalid, "failed to decode exception", nil)
+			return
+		}
+
+		tmpl, err := store.GetTemplate(ctx, templateID)
+		if err != nil {
+			service.RespondError(r, err, templateErrorCodes)
+			return
+		}
+
+		if err := materializer.ApplyException(ctx, tmpl, ex); err != nil {
+			service.RespondError(r, err, templateErrorCodes)
+			return
+		}
+
+		_ = r.RespondJSON(tmpl)
+	})
+}
+
+func HandlerMaterialize(materializer *Materializer) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		created, err := materializer.MaterializeAll(ctx)
+		if err != nil {
+			service.RespondError(r, err, templateErrorCodes)
+			return
+		}
+
+		_ = r.RespondJSON(map[string]int{"created": created})
+	})
+}
@@ -0,0 +1,182 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/event"
+)
+
+// Materializer creates the events of upcoming template occurrences.
+type Materializer struct {
+	templates   TemplateStore
+	occurrences OccurrenceStore
+	events      event.EventRepository
+
+	// horizon is how far ahead of now occurrences are materialized.
+	horizon time.Duration
+	now     func() time.Time
+}
+
+func NewMaterializer(templates TemplateStore, occurrences OccurrenceStore, events event.EventRepository, horizon time.Duration) *Materializer {
+	return &Materializer{
+		templates:   templates,
+		occurrences: occurrences,
+		events:      events,
+		horizon:     horizon,
+		now:         time.Now,
+	}
+}
+
+// MaterializeAll materializes the upcoming occurrences of every template and
+// returns the number of created events. Failing templates are logged and
+// skipped.
+func (m *Materializer) MaterializeAll(ctx context.Context) (int, error) {
+	templates, err := m.templates.ListTemplates(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	var errs []error
+	for _, tmpl := range templates {
+		events, err := m.Materialize(ctx, tmpl)
+		created += len(events)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to materialize template", slog.String("template_id", tmpl.TemplateID.String()), slog.Any("error", err))
+			errs = append(errs, err)
+		}
+	}
+
+	return created, errors.Join(errs...)
+}
+
+// Materialize creates events for all occurrences of the template within the
+// horizon that have not been materialized yet.
+func (m *Materializer) Materialize(ctx context.Context, tmpl *Template) ([]*core.Event, error) {
+	now := m.now()
+	occurrences, err := tmpl.Occurrences(now, now.Add(m.horizon))
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]*core.Event, 0)
+	for _, occ := range occurrences {
+		evt, err := tmpl.NewEvent(occ)
+		if err != nil {
+			return created, err
+		}
+
+		claimed, err := m.occurrences.Claim(ctx, tmpl.TemplateID, occ.Original, evt.EventID)
+		if err != nil {
+			return created, err
+		}
+		if !claimed {
+			// A claim without its event is left behind when the scheduler
+			// stopped between claiming and creating; the event is created
+			// under the claimed ID.
+			orphaned, err := m.orphaned(ctx, tmpl.TemplateID, occ.Original)
+			if err != nil {
+				return created, err
+			}
+			if orphaned.IsZero() {
+				continue
+			}
+			evt.EventID = orphaned
+		}
+
+		if err := m.events.CreateEvent(ctx, evt); err != nil {
+			if !claimed && errors.Is(err, event.ErrEventExists) {
+				// created by a concurrent run
+				continue
+			}
+			// Give the occurrence back so the next run retries it, even if
+			// the run was cancelled.
+			if rerr := m.occurrences.Release(context.WithoutCancel(ctx), tmpl.TemplateID, occ.Original); rerr != nil {
+				err = errors.Join(err, rerr)
+			}
+			return created, fmt.Errorf("failed to create event for occurrence at %s: %w", occ.StartsAt, err)
+		}
+
+		slog.InfoContext(ctx, "materialized occurrence",
+			slog.String("template_id", tmpl.TemplateID.String()),
+			slog.String("event_id", evt.EventID.String()),
+			slog.Time("starts_at", evt.StartsAt),
+		)
+		created = append(created, evt)
+	}
+
+	return created, nil
+}
+
+// orphaned returns the event ID of the occurrence's claim if the event has
+// not been created, and the zero ID otherwise.
+func (m *Materializer) orphaned(ctx context.Context, templateID TemplateID, original time.Time) (core.EventID, error) {
+	eventID, ok, err := m.occurrences.Lookup(ctx, templateID, original)
+	if err != nil || !ok {
+		return core.EventID{}, err
+	}
+
+	_, err = m.events.GetEvent(ctx, eventID)
+	switch {
+	case errors.Is(err, event.ErrEventNotFound):
+		return eventID, nil
+	case err != nil:
+		return core.EventID{}, err
+	default:
+		return core.EventID{}, nil
+	}
+}
+
+// ApplyException adds the exception to the template and updates an event
+// already materialized for the occurrence: skipped occurrences are cancelled
+// and moved occurrences are rescheduled.
+func (m *Materializer) ApplyException(ctx context.Context, tmpl *Template, ex Exception) error {
+	tmpl.AddException(ex)
+	if err := tmpl.Validate(); err != nil {
+		return err
+	}
+
+	if err := m.templates.SaveTemplate(ctx, tmpl); err != nil {
+		return err
+	}
+
+	eventID, ok, err := m.occurrences.Lookup(ctx, tmpl.TemplateID, ex.Occurrence)
+	if err != nil || !ok {
+		return err
+	}
+
+	if ex.Skip {
+		_, err = m.events.TransitionEvent(ctx, eventID, core.EventTransitionCancel)
+		return err
+	}
+
+	duration := time.Duration(tmpl.DurationMinutes) * time.Minute
+	_, err = m.events.RescheduleEvent(ctx, eventID, ex.MovedTo, ex.MovedTo.Add(duration))
+	return err
+}
+
+// Run materializes all templates every interval until the context is done.
+func (m *Materializer) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		created, err := m.MaterializeAll(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "materialization run failed", slog.Any("error", err))
+		} else {
+			slog.InfoContext(ctx, "materialization run finished", slog.Int("created", created))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
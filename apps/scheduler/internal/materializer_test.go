@@ -0,0 +1,319 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/apps/scheduler/internal"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTemplateStore implements internal.TemplateStore in memory
+type mockTemplateStore struct {
+	mu        sync.Mutex
+	templates map[string]*internal.Template
+}
+
+func newMockTemplateStore(templates ...*internal.Template) *mockTemplateStore {
+	s := &mockTemplateStore{templates: make(map[string]*internal.Template)}
+	for _, tmpl := range templates {
+		s.templates[tmpl.TemplateID.String()] = tmpl
+	}
+	return s
+}
+
+func (s *mockTemplateStore) SaveTemplate(ctx context.Context, tmpl *internal.Template) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.templates[tmpl.TemplateID.String()] = tmpl
+	return nil
+}
+
+func (s *mockTemplateStore) GetTemplate(ctx context.Context, templateID internal.TemplateID) (*internal.Template, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tmpl, ok := s.templates[templateID.String()]
+	if !ok {
+		return nil, internal.ErrTemplateNotFound
+	}
+	return tmpl, nil
+}
+
+func (s *mockTemplateStore) ListTemplates(ctx context.Context) ([]*internal.Template, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	templates := make([]*internal.Template, 0, len(s.templates))
+	for _, tmpl := range s.templates {
+		templates = append(templates, tmpl)
+	}
+	return templates, nil
+}
+
+func (s *mockTemplateStore) DeleteTemplate(ctx context.Context, templateID internal.TemplateID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.templates, templateID.String())
+	return nil
+}
+
+// mockOccurrenceStore implements internal.OccurrenceStore in memory
+type mockOccurrenceStore struct {
+	mu     sync.Mutex
+	claims map[string]core.EventID
+}
+
+func newMockOccurrenceStore() *mockOccurrenceStore {
+	return &mockOccurrenceStore{claims: make(map[string]core.EventID)}
+}
+
+func occurrenceKey(templateID internal.TemplateID, original time.Time) string {
+	return templateID.String() + original.UTC().String()
+}
+
+func (s *mockOccurrenceStore) Claim(ctx context.Context, templateID internal.TemplateID, original time.Time, eventID core.EventID) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := occurrenceKey(templateID, original)
+	if _, ok := s.claims[key]; ok {
+		return false, nil
+	}
+	s.claims[key] = eventID
+	return true, nil
+}
+
+func (s *mockOccurrenceStore) Lookup(ctx context.Context, templateID internal.TemplateID, original time.Time) (core.EventID, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	eventID, ok := s.claims[occurrenceKey(templateID, original)]
+	return eventID, ok, nil
+}
+
+func (s *mockOccurrenceStore) Release(ctx context.Context, templateID internal.TemplateID, original time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.claims, occurrenceKey(templateID, original))
+	return nil
+}
+
+// mockEventRepository implements the parts of event.EventRepository used
+// by the scheduler in memory
+type mockEventRepository struct {
+	event.EventRepository
+
+	mu        sync.Mutex
+	events    map[string]*core.Event
+	createErr error
+}
+
+func newMockEventRepository() *mockEventRepository {
+	return &mockEventRepository{events: make(map[string]*core.Event)}
+}
+
+func (r *mockEventRepository) CreateEvent(ctx context.Context, evt *core.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.createErr != nil {
+		return r.createErr
+	}
+	if _, ok := r.events[evt.EventID.String()]; ok {
+		return event.ErrEventExists
+	}
+	r.events[evt.EventID.String()] = evt
+	return nil
+}
+
+func (r *mockEventRepository) GetEvent(ctx context.Context, eventID core.EventID) (*core.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	evt, ok := r.events[eventID.String()]
+	if !ok {
+		return nil, event.ErrEventNotFound
+	}
+	return evt, nil
+}
+
+func (r *mockEventRepository) ListEventsByAttendee(ctx context.Context, userID core.UserID) ([]*core.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := make([]*core.Event, 0)
+	for _, evt := range r.events {
+		if slices.ContainsFunc(evt.Attendees, func(a core.Attendee) bool { return a.User.UserID == userID }) {
+			events = append(events, evt)
+		}
+	}
+	slices.SortFunc(events, func(a, b *core.Event) int { return a.StartsAt.Compare(b.StartsAt) })
+	return events, nil
+}
+
+func (r *mockEventRepository) ListEvents(ctx context.Context, filter event.EventFilter) ([]*core.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := make([]*core.Event, 0)
+	for _, evt := range r.events {
+		if filter.Status != "" && evt.Status != filter.Status {
+			continue
+		}
+		if evt.StartsAt.Before(filter.From) || !evt.StartsAt.Before(filter.To) {
+			continue
+		}
+		events = append(events, evt)
+	}
+	return events, nil
+}
+
+func (r *mockEventRepository) TransitionEvent(ctx context.Context, eventID core.EventID, transition core.EventTransition) (*core.Event, error) {
+	evt, err := r.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return evt, evt.Transition(transition)
+}
+
+func (r *mockEventRepository) RescheduleEvent(ctx context.Context, eventID core.EventID, startsAt, endsAt time.Time) (*core.Event, error) {
+	evt, err := r.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return evt, evt.Reschedule(startsAt, endsAt)
+}
+
+func (r *mockEventRepository) UpdateAttendees(ctx context.Context, eventID core.EventID, fn func(evt *core.Event) error) (*core.Event, error) {
+	stored, err := r.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	// work on a copy, so failing updates leave the stored event untouched
+	evt := *stored
+	evt.Attendees = slices.Clone(stored.Attendees)
+	if err := fn(&evt); err != nil {
+		return nil, err
+	}
+	if err := evt.Validate(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events[eventID.String()] = &evt
+	return &evt, nil
+}
+
+func newTestMaterializer(t *testing.T, tmpl *internal.Template, now time.Time) (*internal.Materializer, *mockEventRepository, *mockOccurrenceStore) {
+	t.Helper()
+	events := newMockEventRepository()
+	occurrences := newMockOccurrenceStore()
+
+	m := internal.NewMaterializer(newMockTemplateStore(tmpl), occurrences, events, 3*7*24*time.Hour)
+	internal.SetMaterializerClock(m, func() time.Time { return now })
+
+	return m, events, occurrences
+}
+
+func TestMaterializer_Materialize(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	tmpl := newWeeklyTemplate(t)
+	now := tmpl.Start.Add(-time.Hour)
+
+	m, events, _ := newTestMaterializer(t, tmpl, now)
+
+	created, err := m.MaterializeAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, created)
+	assert.Len(t, events.events, 3)
+
+	// Occurrences are materialized only once
+	created, err = m.MaterializeAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, created)
+	assert.Len(t, events.events, 3)
+
+	for _, evt := range events.events {
+		assert.Equal(t, core.EventStatusScheduled, evt.Status)
+		assert.Equal(t, tmpl.Title, evt.Title)
+	}
+}
+
+func TestMaterializer_ReleasesClaimOnFailure(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	tmpl := newWeeklyTemplate(t)
+
+	m, events, occurrences := newTestMaterializer(t, tmpl, tmpl.Start.Add(-time.Hour))
+	events.createErr = errors.New("database unavailable")
+
+	_, err := m.Materialize(ctx, tmpl)
+	require.Error(t, err)
+	assert.Empty(t, occurrences.claims)
+
+	events.createErr = nil
+	created, err := m.Materialize(ctx, tmpl)
+	require.NoError(t, err)
+	assert.Len(t, created, 3)
+}
+
+func TestMaterializer_RecoversOrphanedClaim(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	tmpl := newWeeklyTemplate(t)
+
+	m, events, occurrences := newTestMaterializer(t, tmpl, tmpl.Start.Add(-time.Hour))
+
+	// the scheduler stopped after claiming the first occurrence
+	orphaned := core.NewEventID()
+	claimed, err := occurrences.Claim(ctx, tmpl.TemplateID, tmpl.Start, orphaned)
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	created, err := m.Materialize(ctx, tmpl)
+	require.NoError(t, err)
+	require.Len(t, created, 3)
+	assert.Equal(t, orphaned, created[0].EventID)
+	assert.Len(t, events.events, 3)
+
+	again, err := m.Materialize(ctx, tmpl)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+}
+
+func TestMaterializer_ApplyException(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	tmpl := newWeeklyTemplate(t)
+
+	m, _, occurrences := newTestMaterializer(t, tmpl, tmpl.Start.Add(-time.Hour))
+
+	created, err := m.Materialize(ctx, tmpl)
+	require.NoError(t, err)
+	require.Len(t, created, 3)
+
+	// Skipping a materialized occurrence cancels its event
+	err = m.ApplyException(ctx, tmpl, internal.Exception{Occurrence: created[0].StartsAt, Skip: true})
+	require.NoError(t, err)
+	assert.Equal(t, core.EventStatusCancelled, created[0].Status)
+
+	// Moving a materialized occurrence reschedules its event
+	movedTo := created[1].StartsAt.Add(48 * time.Hour)
+	err = m.ApplyException(ctx, tmpl, internal.Exception{Occurrence: created[1].StartsAt, MovedTo: movedTo})
+	require.NoError(t, err)
+	assert.True(t, movedTo.Equal(created[1].StartsAt))
+	assert.True(t, movedTo.Add(4*time.Hour).Equal(created[1].EndsAt))
+
+	// Neither exception produces new events
+	again, err := m.Materialize(ctx, tmpl)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+	assert.Len(t, occurrences.claims, 3)
+
+	// Invalid exceptions are rejected
+	err = m.ApplyException(ctx, tmpl, internal.Exception{Occurrence: created[2].StartsAt})
+	assert.ErrorIs(t, err, internal.ErrTemplateInvalid)
+}
@@ -0,0 +1,167 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/ngoldack/dicetrace/package/core/stream"
+)
+
+const (
+	notifyStream   = "NOTIFY"
+	notifyConsumer = "scheduler-notify"
+
+	// notifyDuplicateWindow must exceed the dispatch interval, so reminders
+	// republished after a restart are dropped as duplicates.
+	notifyDuplicateWindow = 24 * time.Hour
+	notifyMaxAge          = 7 * 24 * time.Hour
+
+	// Failing reminders are redelivered until every sink took them, with
+	// a delay doubling from notifyRetryDelay up to notifyMaxRetryDelay.
+	notifyRetryDelay    = 10 * time.Second
+	notifyMaxRetryDelay = 10 * time.Minute
+)
+
+// NewNotifyStream creates the stream persisting all notify.> messages.
+func NewNotifyStream(ctx context.Context, js jetstream.JetStream) (jetstream.Stream, error) {
+	stream, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:        notifyStream,
+		Description: "Notifications for users",
+		Subjects:    []string{"notify.>"},
+		MaxAge:      notifyMaxAge,
+		Duplicates:  notifyDuplicateWindow,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stream '%s': %w", notifyStream, err)
+	}
+
+	return stream, nil
+}
+
+// JetStreamReminderPublisher publishes reminders to the notify stream. The
+// reminder key is used as message ID, so JetStream drops republished
+// reminders.
+type JetStreamReminderPublisher struct {
+	js jetstream.JetStream
+}
+
+var _ ReminderPublisher = (*JetStreamReminderPublisher)(nil)
+
+func NewJetStreamReminderPublisher(js jetstream.JetStream) *JetStreamReminderPublisher {
+	return &JetStreamReminderPublisher{
+		js: js,
+	}
+}
+
+func (p *JetStreamReminderPublisher) PublishReminder(ctx context.Context, reminder *Reminder) error {
+	data, err := json.Marshal(reminder)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reminder: %w", err)
+	}
+
+	if _, err := p.js.Publish(ctx, SubjectEventReminder, data, jetstream.WithMsgID(reminder.Key())); err != nil {
+		return fmt.Errorf("failed to publish reminder '%s': %w", reminder.Key(), err)
+	}
+
+	return nil
+}
+
+// Notifier delivers published reminders to all sinks. Deliveries are
+// recorded per sink, so redelivered reminders are only sent through the
+// sinks that failed before.
+type Notifier struct {
+	stream jetstream.Stream
+	log    ReminderLog
+	sinks  []Sink
+}
+
+func NewNotifier(stream jetstream.Stream, log ReminderLog, sinks ...Sink) *Notifier {
+	return &Notifier{
+		stream: stream,
+		log:    log,
+		sinks:  sinks,
+	}
+}
+
+// Run consumes reminders with a durable consumer until the context is done.
+// Reminders failing in any sink are redelivered with a backoff until they
+// are delivered.
+func (n *Notifier) Run(ctx context.Context) error {
+	consumer, err := n.stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Durable:       notifyConsumer,
+		Description:   "Delivers event reminders to the configured sinks",
+		FilterSubject: SubjectEventReminder,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		MaxDeliver:    -1,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create consumer '%s': %w", notifyConsumer, err)
+	}
+
+	cc, err := consumer.Consume(func(msg jetstream.Msg) {
+		n.handle(ctx, msg)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to consume reminders: %w", err)
+	}
+	defer cc.Stop()
+
+	<-ctx.Done()
+	return nil
+}
+
+func (n *Notifier) handle(ctx context.Context, msg jetstream.Msg) {
+	var reminder Reminder
+	if err := json.Unmarshal(msg.Data(), &reminder); err != nil {
+		// redelivering cannot fix a malformed message
+		slog.ErrorContext(ctx, "dropping malformed reminder", slog.Any("error", err))
+		_ = msg.Term()
+		return
+	}
+
+	if err := n.Deliver(ctx, &reminder); err != nil {
+		slog.ErrorContext(ctx, "failed to deliver reminder", slog.String("key", reminder.Key()), slog.Any("error", err))
+		var delivered uint64 = 1
+		if meta, err := msg.Metadata(); err == nil {
+			delivered = meta.NumDelivered
+		}
+		_ = msg.NakWithDelay(stream.Backoff(notifyRetryDelay, notifyMaxRetryDelay, delivered))
+		return
+	}
+
+	_ = msg.Ack()
+}
+
+// Deliver sends the reminder to every sink it has not been delivered
+// through yet. A sink is sent the reminder again if recording its delivery
+// fails; the webhook sink passes the reminder key for receivers to
+// deduplicate.
+func (n *Notifier) Deliver(ctx context.Context, reminder *Reminder) error {
+	key := reminder.Key()
+
+	var errs []error
+	for _, sink := range n.sinks {
+		delivered, err := n.log.Delivered(ctx, key, sink.Name())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if delivered {
+			continue
+		}
+
+		if err := sink.Send(ctx, reminder); err != nil {
+			errs = append(errs, fmt.Errorf("sink '%s': %w", sink.Name(), err))
+			continue
+		}
+		if err := n.log.MarkDelivered(ctx, key, sink.Name()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,252 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"go.jetify.com/typeid/v2"
+)
+
+var (
+	ErrPollInvalid = errors.New("invalid poll")
+	ErrPollClosed  = errors.New("poll is closed")
+	ErrNoQuorum    = errors.New("no slot reached the quorum")
+)
+
+type PollID = typeid.TypeID
+
+func NewPollID() PollID {
+	return typeid.MustGenerate("poll")
+}
+
+// Vote is a user's answer for a single candidate slot.
+type Vote string
+
+const (
+	VoteYes   Vote = "yes"
+	VoteMaybe Vote = "maybe"
+	VoteNo    Vote = "no"
+)
+
+// Slot is a candidate date proposed by the organizer.
+type Slot struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at,omitzero"`
+}
+
+// Ballot holds a user's votes, one per slot in the order of Poll.Slots.
+type Ballot struct {
+	User  core.User `json:"user"`
+	Votes []Vote    `json:"votes"`
+}
+
+// Poll asks users which of several candidate slots suits them. Closing the
+// poll creates the event for the best slot.
+type Poll struct {
+	PollID PollID `json:"poll_id"`
+
+	Title    string     `json:"title"`
+	Location string     `json:"location"`
+	Timezone string     `json:"timezone,omitempty"`
+	Host     *core.User `json:"host,omitempty"`
+	Capacity int        `json:"capacity,omitempty"`
+
+	// Quorum is the minimum number of yes votes a slot needs to be picked;
+	// 0 means a single yes vote suffices.
+	Quorum int `json:"quorum,omitempty"`
+
+	Slots   []Slot   `json:"slots"`
+	Ballots []Ballot `json:"ballots"`
+
+	// EventID is set once the poll is closed.
+	EventID core.EventID `json:"event_id,omitzero"`
+}
+
+// SlotTally counts the votes for a single slot.
+type SlotTally struct {
+	Index int  `json:"index"`
+	Slot  Slot `json:"slot"`
+	Yes   int  `json:"yes"`
+	Maybe int  `json:"maybe"`
+	No    int  `json:"no"`
+}
+
+// Closed reports whether an event has been created from the poll.
+func (p *Poll) Closed() bool {
+	return !p.EventID.IsZero()
+}
+
+// Validate checks the poll's settings and slots.
+func (p *Poll) Validate() error {
+	var errs []error
+
+	if strings.TrimSpace(p.Title) == "" {
+		errs = append(errs, errors.New("title is required"))
+	}
+	if p.Timezone != "" {
+		if _, err := time.LoadLocation(p.Timezone); err != nil {
+			errs = append(errs, fmt.Errorf("unknown timezone '%s'", p.Timezone))
+		}
+	}
+	if p.Capacity < 0 {
+		errs = append(errs, errors.New("capacity must not be negative"))
+	}
+	if p.Quorum < 0 {
+		errs = append(errs, errors.New("quorum must not be negative"))
+	}
+	if len(p.Slots) == 0 {
+		errs = append(errs, errors.New("at least one slot is required"))
+	}
+
+	for i, slot := range p.Slots {
+		if slot.StartsAt.IsZero() {
+			errs = append(errs, fmt.Errorf("slots[%d]: starts_at is required", i))
+		} else if !slot.EndsAt.IsZero() && !slot.EndsAt.After(slot.StartsAt) {
+			errs = append(errs, fmt.Errorf("slots[%d]: ends_at must be after starts_at", i))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrPollInvalid, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// Vote records the ballot, replacing an earlier ballot of the same user.
+func (p *Poll) Vote(ballot Ballot) error {
+	if p.Closed() {
+		return ErrPollClosed
+	}
+	if ballot.User.UserID.IsZero() {
+		return fmt.Errorf("%w: user is required", ErrPollInvalid)
+	}
+	if len(ballot.Votes) != len(p.Slots) {
+		return fmt.Errorf("%w: expected %d votes, got %d", ErrPollInvalid, len(p.Slots), len(ballot.Votes))
+	}
+	for i, v := range ballot.Votes {
+		switch v {
+		case VoteYes, VoteMaybe, VoteNo:
+		default:
+			return fmt.Errorf("%w: votes[%d]: unknown vote '%s'", ErrPollInvalid, i, v)
+		}
+	}
+
+	p.Ballots = slices.DeleteFunc(p.Ballots, func(b Ballot) bool {
+		return b.User.UserID == ballot.User.UserID
+	})
+	p.Ballots = append(p.Ballots, ballot)
+
+	return nil
+}
+
+// Tally counts the votes of every slot, in the order of Slots.
+func (p *Poll) Tally() []SlotTally {
+	tally := make([]SlotTally, len(p.Slots))
+	for i, slot := range p.Slots {
+		tally[i] = SlotTally{Index: i, Slot: slot}
+	}
+
+	for _, ballot := range p.Ballots {
+		for i, v := range ballot.Votes {
+			if i >= len(tally) {
+				break
+			}
+			switch v {
+			case VoteYes:
+				tally[i].Yes++
+			case VoteMaybe:
+				tally[i].Maybe++
+			case VoteNo:
+				tally[i].No++
+			}
+		}
+	}
+
+	return tally
+}
+
+// BestSlot picks the slot with the most yes votes that reaches the quorum.
+// Ties are broken by the number of maybe votes, then by the earlier start.
+func (p *Poll) BestSlot() (SlotTally, error) {
+	quorum := max(p.Quorum, 1)
+
+	candidates := slices.DeleteFunc(p.Tally(), func(t SlotTally) bool {
+		return t.Yes < quorum
+	})
+	if len(candidates) == 0 {
+		return SlotTally{}, ErrNoQuorum
+	}
+
+	return slices.MinFunc(candidates, func(a, b SlotTally) int {
+		if a.Yes != b.Yes {
+			return b.Yes - a.Yes
+		}
+		if a.Maybe != b.Maybe {
+			return b.Maybe - a.Maybe
+		}
+		return a.Slot.StartsAt.Compare(b.Slot.StartsAt)
+	}), nil
+}
+
+// Close picks the best slot and builds the scheduled event for it. Voters
+// become attendees: yes votes are confirmed, maybe votes pending and no
+// votes declined. Yes votes exceeding the capacity are kept as pending.
+func (p *Poll) Close() (*core.Event, error) {
+	if p.Closed() {
+		return nil, ErrPollClosed
+	}
+
+	best, err := p.BestSlot()
+	if err != nil {
+		return nil, err
+	}
+
+	evt := &core.Event{
+		EventID:   core.NewEventID(),
+		Title:     p.Title,
+		Location:  p.Location,
+		StartsAt:  best.Slot.StartsAt,
+		EndsAt:    best.Slot.EndsAt,
+		Timezone:  p.Timezone,
+		Host:      p.Host,
+		Capacity:  p.Capacity,
+		Attendees: make([]core.Attendee, 0, len(p.Ballots)),
+		Matches:   []core.Match{},
+	}
+
+	for _, ballot := range p.Ballots {
+		status := core.AttendeeStatusDeclined
+		switch ballot.Votes[best.Index] {
+		case VoteYes:
+			status = core.AttendeeStatusConfirmed
+			if p.Capacity > 0 && evt.CountAttendees(core.AttendeeStatusConfirmed) >= p.Capacity {
+				status = core.AttendeeStatusPending
+			}
+		case VoteMaybe:
+			status = core.AttendeeStatusPending
+		}
+
+		evt.Attendees = append(evt.Attendees, core.Attendee{User: ballot.User, Status: status})
+	}
+
+	if err := evt.Transition(core.EventTransitionSchedule); err != nil {
+		return nil, err
+	}
+	if err := evt.Localize(); err != nil {
+		return nil, err
+	}
+
+	p.EventID = evt.EventID
+
+	return evt, nil
+}
+
+// Reopen undoes Close, e.g. when the event could not be stored.
+func (p *Poll) Reopen() {
+	p.EventID = core.EventID{}
+}
@@ -0,0 +1,270 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/apps/scheduler/internal"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestUser(name string) core.User {
+	return core.User{UserID: core.NewUserID(), Username: name}
+}
+
+func newTestPoll() *internal.Poll {
+	start := time.Date(2025, time.June, 6, 19, 0, 0, 0, time.UTC)
+
+	return &internal.Poll{
+		PollID:   internal.NewPollID(),
+		Title:    "Summer game night",
+		Timezone: "Europe/Berlin",
+		Slots: []internal.Slot{
+			{StartsAt: start, EndsAt: start.Add(4 * time.Hour)},
+			{StartsAt: start.AddDate(0, 0, 1), EndsAt: start.AddDate(0, 0, 1).Add(4 * time.Hour)},
+			{StartsAt: start.AddDate(0, 0, 2)},
+		},
+	}
+}
+
+func vote(t *testing.T, poll *internal.Poll, user core.User, votes ...internal.Vote) {
+	t.Helper()
+	require.NoError(t, poll.Vote(internal.Ballot{User: user, Votes: votes}))
+}
+
+func TestPollValidate(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		modify func(poll *internal.Poll)
+	}{
+		{"missing title", func(poll *internal.Poll) { poll.Title = "" }},
+		{"unknown timezone", func(poll *internal.Poll) { poll.Timezone = "Nowhere/Special" }},
+		{"negative capacity", func(poll *internal.Poll) { poll.Capacity = -1 }},
+		{"negative quorum", func(poll *internal.Poll) { poll.Quorum = -1 }},
+		{"no slots", func(poll *internal.Poll) { poll.Slots = nil }},
+		{"slot without start", func(poll *internal.Poll) { poll.Slots[0].StartsAt = time.Time{} }},
+		{"slot ending before start", func(poll *internal.Poll) { poll.Slots[1].EndsAt = poll.Slots[1].StartsAt }},
+	}
+
+	require.NoError(t, newTestPoll().Validate())
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			poll := newTestPoll()
+			tc.modify(poll)
+			assert.ErrorIs(t, poll.Validate(), internal.ErrPollInvalid)
+		})
+	}
+}
+
+func TestPollVote(t *testing.T) {
+	t.Parallel()
+	poll := newTestPoll()
+	alice := newTestUser("alice")
+
+	vote(t, poll, alice, internal.VoteYes, internal.VoteNo, internal.VoteMaybe)
+	// voting again replaces the earlier ballot
+	vote(t, poll, alice, internal.VoteNo, internal.VoteYes, internal.VoteMaybe)
+	require.Len(t, poll.Ballots, 1)
+
+	tally := poll.Tally()
+	assert.Equal(t, 0, tally[0].Yes)
+	assert.Equal(t, 1, tally[0].No)
+	assert.Equal(t, 1, tally[1].Yes)
+	assert.Equal(t, 1, tally[2].Maybe)
+
+	err := poll.Vote(internal.Ballot{User: alice, Votes: []internal.Vote{internal.VoteYes}})
+	assert.ErrorIs(t, err, internal.ErrPollInvalid, "vote count must match slots")
+
+	err = poll.Vote(internal.Ballot{User: alice, Votes: []internal.Vote{"perhaps", internal.VoteNo, internal.VoteNo}})
+	assert.ErrorIs(t, err, internal.ErrPollInvalid, "unknown votes are rejected")
+
+	err = poll.Vote(internal.Ballot{Votes: []internal.Vote{internal.VoteNo, internal.VoteNo, internal.VoteNo}})
+	assert.ErrorIs(t, err, internal.ErrPollInvalid, "user is required")
+}
+
+func TestPollBestSlot(t *testing.T) {
+	t.Parallel()
+	y, m, n := internal.VoteYes, internal.VoteMaybe, internal.VoteNo
+
+	testCases := []struct {
+		name    string
+		quorum  int
+		ballots [][]internal.Vote
+		want    int
+		wantErr error
+	}{
+		{
+			name:    "most yes votes",
+			ballots: [][]internal.Vote{{y, y, n}, {n, y, n}, {y, y, y}},
+			want:    1,
+		},
+		{
+			name:    "maybe breaks tie",
+			ballots: [][]internal.Vote{{y, y, n}, {n, m, n}},
+			want:    1,
+		},
+		{
+			name:    "earliest breaks remaining tie",
+			ballots: [][]internal.Vote{{n, y, y}, {m, m, m}},
+			want:    1,
+		},
+		{
+			name:    "no yes votes",
+			ballots: [][]internal.Vote{{m, n, n}},
+			wantErr: internal.ErrNoQuorum,
+		},
+		{
+			name:    "quorum not reached",
+			quorum:  3,
+			ballots: [][]internal.Vote{{y, y, n}, {y, n, y}},
+			wantErr: internal.ErrNoQuorum,
+		},
+		{
+			name:    "quorum reached",
+			quorum:  2,
+			ballots: [][]internal.Vote{{y, y, n}, {y, n, y}},
+			want:    0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			poll := newTestPoll()
+			poll.Quorum = tc.quorum
+			for _, votes := range tc.ballots {
+				vote(t, poll, newTestUser("voter"), votes...)
+			}
+
+			best, err := poll.BestSlot()
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, best.Index)
+		})
+	}
+}
+
+func TestPollClose(t *testing.T) {
+	t.Parallel()
+	poll := newTestPoll()
+	poll.Capacity = 2
+
+	alice, bob, carol, dave := newTestUser("alice"), newTestUser("bob"), newTestUser("carol"), newTestUser("dave")
+	vote(t, poll, alice, internal.VoteYes, internal.VoteNo, internal.VoteNo)
+	vote(t, poll, bob, internal.VoteYes, internal.VoteYes, internal.VoteNo)
+	vote(t, poll, carol, internal.VoteYes, internal.VoteMaybe, internal.VoteNo)
+	vote(t, poll, dave, internal.VoteNo, internal.VoteMaybe, internal.VoteYes)
+
+	evt, err := poll.Close()
+	require.NoError(t, err)
+	assert.True(t, poll.Closed())
+	assert.Equal(t, evt.EventID, poll.EventID)
+
+	assert.Equal(t, core.EventStatusScheduled, evt.Status)
+	assert.Equal(t, poll.Title, evt.Title)
+	assert.True(t, evt.StartsAt.Equal(poll.Slots[0].StartsAt))
+	assert.Equal(t, "Europe/Berlin", evt.StartsAt.Location().String())
+	require.NoError(t, evt.Validate())
+
+	statuses := make(map[string]core.AttendeeStatus)
+	for _, a := range evt.Attendees {
+		statuses[a.User.Username] = a.Status
+	}
+	assert.Equal(t, map[string]core.AttendeeStatus{
+		"alice": core.AttendeeStatusConfirmed,
+		"bob":   core.AttendeeStatusConfirmed,
+		"carol": core.AttendeeStatusPending, // over capacity
+		"dave":  core.AttendeeStatusDeclined,
+	}, statuses)
+
+	_, err = poll.Close()
+	assert.ErrorIs(t, err, internal.ErrPollClosed)
+	assert.ErrorIs(t, poll.Vote(internal.Ballot{User: alice, Votes: []internal.Vote{"yes", "yes", "yes"}}), internal.ErrPollClosed)
+}
+
+// mockPollStore implements internal.PollStore in memory
+type mockPollStore struct {
+	mu    sync.Mutex
+	polls map[string]internal.Poll
+}
+
+func newMockPollStore(polls ...*internal.Poll) *mockPollStore {
+	s := &mockPollStore{polls: make(map[string]internal.Poll)}
+	for _, poll := range polls {
+		s.polls[poll.PollID.String()] = *poll
+	}
+	return s
+}
+
+func (s *mockPollStore) CreatePoll(ctx context.Context, poll *internal.Poll) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.polls[poll.PollID.String()] = *poll
+	return nil
+}
+
+func (s *mockPollStore) GetPoll(ctx context.Context, pollID internal.PollID) (*internal.Poll, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	poll, ok := s.polls[pollID.String()]
+	if !ok {
+		return nil, internal.ErrPollNotFound
+	}
+	return &poll, nil
+}
+
+func (s *mockPollStore) UpdatePoll(ctx context.Context, pollID internal.PollID, fn func(poll *internal.Poll) error) (*internal.Poll, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	poll, ok := s.polls[pollID.String()]
+	if !ok {
+		return nil, internal.ErrPollNotFound
+	}
+	if err := fn(&poll); err != nil {
+		return nil, err
+	}
+	s.polls[pollID.String()] = poll
+	return &poll, nil
+}
+
+func TestClosePoll(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	poll := newTestPoll()
+	vote(t, poll, newTestUser("alice"), internal.VoteNo, internal.VoteYes, internal.VoteNo)
+
+	store := newMockPollStore(poll)
+	events := newMockEventRepository()
+
+	events.createErr = errors.New("database unavailable")
+	_, err := internal.ClosePoll(ctx, store, events, poll.PollID)
+	require.Error(t, err)
+
+	stored, err := store.GetPoll(ctx, poll.PollID)
+	require.NoError(t, err)
+	assert.False(t, stored.Closed(), "poll is reopened when the event cannot be created")
+
+	events.createErr = nil
+	evt, err := internal.ClosePoll(ctx, store, events, poll.PollID)
+	require.NoError(t, err)
+	assert.Contains(t, events.events, evt.EventID.String())
+	assert.True(t, evt.StartsAt.Equal(poll.Slots[1].StartsAt))
+
+	stored, err = store.GetPoll(ctx, poll.PollID)
+	require.NoError(t, err)
+	assert.Equal(t, evt.EventID, stored.EventID)
+
+	_, err = internal.ClosePoll(ctx, store, events, poll.PollID)
+	assert.ErrorIs(t, err, internal.ErrPollClosed)
+}
@@ -0,0 +1,252 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/event"
+)
+
+// SubjectEventReminder is the subject reminders are published on.
+const SubjectEventReminder = "notify.event.reminder"
+
+// Reminder notifies the attendees of an upcoming event.
+type Reminder struct {
+	EventID  core.EventID `json:"event_id"`
+	Title    string       `json:"title"`
+	Location string       `json:"location"`
+	StartsAt time.Time    `json:"starts_at"`
+	// Offset is how long before the event start the reminder is due.
+	Offset time.Duration `json:"offset"`
+
+	Host       *core.User  `json:"host,omitempty"`
+	Recipients []core.User `json:"recipients"`
+}
+
+// Key identifies the reminder across restarts and scheduler instances. It
+// includes the start time, so a rescheduled event is reminded again.
+func (r *Reminder) Key() string {
+	return fmt.Sprintf("%s.%d.%d", r.EventID, r.StartsAt.Unix(), int64(r.Offset.Seconds()))
+}
+
+// NewReminder builds the reminder for the event. Confirmed and pending
+// attendees are notified.
+func NewReminder(evt *core.Event, offset time.Duration) *Reminder {
+	recipients := make([]core.User, 0, len(evt.Attendees))
+	for _, a := range evt.Attendees {
+		if a.Status == core.AttendeeStatusConfirmed || a.Status == core.AttendeeStatusPending {
+			recipients = append(recipients, a.User)
+		}
+	}
+
+	return &Reminder{
+		EventID:    evt.EventID,
+		Title:      evt.Title,
+		Location:   evt.Location,
+		StartsAt:   evt.StartsAt,
+		Offset:     offset,
+		Host:       evt.Host,
+		Recipients: recipients,
+	}
+}
+
+// ParseOffsets parses a comma separated list of durations, e.g. "24h,1h".
+func ParseOffsets(value string) ([]time.Duration, error) {
+	offsets := make([]time.Duration, 0)
+	for part := range strings.SplitSeq(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		d, err := time.ParseDuration(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reminder offset '%s': %w", part, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("reminder offset '%s' must be positive", part)
+		}
+
+		offsets = append(offsets, d)
+	}
+
+	slices.Sort(offsets)
+	return slices.Compact(offsets), nil
+}
+
+// ReminderLog records which reminders have been sent.
+type ReminderLog interface {
+	// Pending records the reminder as pending unless it was recorded before.
+	// It reports whether the reminder still has to be published.
+	Pending(ctx context.Context, key string) (bool, error)
+	// MarkSent records the reminder as published.
+	MarkSent(ctx context.Context, key string) error
+
+	// Delivered reports whether the reminder has been delivered through the
+	// sink.
+	Delivered(ctx context.Context, key, sink string) (bool, error)
+	// MarkDelivered records the reminder as delivered through the sink.
+	MarkDelivered(ctx context.Context, key, sink string) error
+}
+
+// ReminderPublisher publishes reminders. Publishing the same reminder twice
+// must not deliver it twice.
+type ReminderPublisher interface {
+	PublishReminder(ctx context.Context, reminder *Reminder) error
+}
+
+// ReminderDispatcher publishes the reminders of upcoming events once they
+// are due.
+//
+// A reminder is marked pending before and sent after publishing. Pending
+// reminders are published again on the next run, which the publisher
+// deduplicates, so every reminder fires exactly once even if the scheduler
+// stops in between.
+type ReminderDispatcher struct {
+	events    event.EventRepository
+	log       ReminderLog
+	publisher ReminderPublisher
+
+	// offsets are sorted ascending.
+	offsets []time.Duration
+	now     func() time.Time
+}
+
+func NewReminderDispatcher(events event.EventRepository, log ReminderLog, publisher ReminderPublisher, offsets []time.Duration) *ReminderDispatcher {
+	offsets = slices.Clone(offsets)
+	slices.Sort(offsets)
+
+	return &ReminderDispatcher{
+		events:    events,
+		log:       log,
+		publisher: publisher,
+		offsets:   offsets,
+		now:       time.Now,
+	}
+}
+
+// Dispatch publishes all due reminders and returns how many were published.
+// Only the nearest due offset of an event is published; earlier offsets
+// missed while the scheduler was down are superseded by it.
+func (d *ReminderDispatcher) Dispatch(ctx context.Context) (int, error) {
+	if len(d.offsets) == 0 {
+		return 0, nil
+	}
+
+	now := d.now()
+	events, err := d.events.ListEvents(ctx, event.EventFilter{
+		Status: core.EventStatusScheduled,
+		From:   now,
+		To:     now.Add(d.offsets[len(d.offsets)-1]),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	var errs []error
+	for _, evt := range events {
+		due := d.dueOffsets(evt, now)
+		if len(due) == 0 {
+			continue
+		}
+
+		sent, err := d.dispatch(ctx, NewReminder(evt, due[0]))
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to dispatch reminder", slog.String("event_id", evt.EventID.String()), slog.Any("error", err))
+			errs = append(errs, err)
+			continue
+		}
+		if sent {
+			published++
+		}
+
+		for _, offset := range due[1:] {
+			if err := d.supersede(ctx, NewReminder(evt, offset)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return published, errors.Join(errs...)
+}
+
+// dueOffsets returns the offsets whose reminder time has passed, nearest to
+// the event start first.
+func (d *ReminderDispatcher) dueOffsets(evt *core.Event, now time.Time) []time.Duration {
+	due := make([]time.Duration, 0)
+	for _, offset := range d.offsets {
+		if !now.Before(evt.StartsAt.Add(-offset)) {
+			due = append(due, offset)
+		}
+	}
+	return due
+}
+
+func (d *ReminderDispatcher) dispatch(ctx context.Context, reminder *Reminder) (bool, error) {
+	pending, err := d.log.Pending(ctx, reminder.Key())
+	if err != nil || !pending {
+		return false, err
+	}
+
+	if err := d.publisher.PublishReminder(ctx, reminder); err != nil {
+		return false, err
+	}
+
+	if err := d.log.MarkSent(ctx, reminder.Key()); err != nil {
+		return false, err
+	}
+
+	slog.InfoContext(ctx, "published reminder",
+		slog.String("event_id", reminder.EventID.String()),
+		slog.Duration("offset", reminder.Offset),
+	)
+
+	return true, nil
+}
+
+// supersede marks the reminder as sent without publishing it.
+func (d *ReminderDispatcher) supersede(ctx context.Context, reminder *Reminder) error {
+	pending, err := d.log.Pending(ctx, reminder.Key())
+	if err != nil || !pending {
+		return err
+	}
+	return d.log.MarkSent(ctx, reminder.Key())
+}
+
+// Run dispatches due reminders every interval until the context is done.
+func (d *ReminderDispatcher) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Dispatch(ctx); err != nil {
+			slog.ErrorContext(ctx, "reminder dispatch failed", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// offsetLabel formats the offset for humans, e.g. "24h" or "30m".
+func offsetLabel(offset time.Duration) string {
+	switch {
+	case offset%time.Hour == 0:
+		return strconv.Itoa(int(offset/time.Hour)) + "h"
+	case offset%time.Minute == 0:
+		return strconv.Itoa(int(offset/time.Minute)) + "m"
+	default:
+		return offset.String()
+	}
+}
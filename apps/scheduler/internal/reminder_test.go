@@ -0,0 +1,229 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/apps/scheduler/internal"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockReminderLog implements internal.ReminderLog in memory
+type mockReminderLog struct {
+	mu    sync.Mutex
+	state map[string]string
+}
+
+func newMockReminderLog() *mockReminderLog {
+	return &mockReminderLog{state: make(map[string]string)}
+}
+
+func (l *mockReminderLog) Pending(ctx context.Context, key string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.state[key]; !ok {
+		l.state[key] = "pending"
+	}
+	return l.state[key] != "sent", nil
+}
+
+func (l *mockReminderLog) MarkSent(ctx context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.state[key] = "sent"
+	return nil
+}
+
+func (l *mockReminderLog) Delivered(ctx context.Context, key, sink string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.state[key+"/"+sink]
+	return ok, nil
+}
+
+func (l *mockReminderLog) MarkDelivered(ctx context.Context, key, sink string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.state[key+"/"+sink] = "delivered"
+	return nil
+}
+
+// mockReminderPublisher records published reminders
+type mockReminderPublisher struct {
+	mu        sync.Mutex
+	published []*internal.Reminder
+	err       error
+}
+
+func (p *mockReminderPublisher) PublishReminder(ctx context.Context, reminder *internal.Reminder) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, reminder)
+	return nil
+}
+
+func TestParseOffsets(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		value   string
+		want    []time.Duration
+		wantErr bool
+	}{
+		{value: "", want: []time.Duration{}},
+		{value: "24h,1h", want: []time.Duration{time.Hour, 24 * time.Hour}},
+		{value: " 30m , 1h, 30m ", want: []time.Duration{30 * time.Minute, time.Hour}},
+		{value: "tomorrow", wantErr: true},
+		{value: "-1h", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			got, err := internal.ParseOffsets(tc.value)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNewReminder(t *testing.T) {
+	t.Parallel()
+	evt := &core.Event{
+		EventID:  core.NewEventID(),
+		Title:    "Game night",
+		StartsAt: time.Now(),
+		Attendees: []core.Attendee{
+			{User: newTestUser("alice"), Status: core.AttendeeStatusConfirmed},
+			{User: newTestUser("bob"), Status: core.AttendeeStatusPending},
+			{User: newTestUser("carol"), Status: core.AttendeeStatusDeclined},
+		},
+	}
+
+	reminder := internal.NewReminder(evt, time.Hour)
+	require.Len(t, reminder.Recipients, 2)
+	assert.Equal(t, "alice", reminder.Recipients[0].Username)
+	assert.Equal(t, "bob", reminder.Recipients[1].Username)
+	assert.Equal(t, fmt.Sprintf("%s.%d.3600", evt.EventID, evt.StartsAt.Unix()), reminder.Key())
+}
+
+func newScheduledEvent(t *testing.T, startsAt time.Time) *core.Event {
+	t.Helper()
+	evt := &core.Event{
+		EventID:   core.NewEventID(),
+		Title:     "Game night",
+		StartsAt:  startsAt,
+		Attendees: []core.Attendee{{User: newTestUser("alice"), Status: core.AttendeeStatusConfirmed}},
+		Matches:   []core.Match{},
+	}
+	require.NoError(t, evt.Transition(core.EventTransitionSchedule))
+	return evt
+}
+
+func TestReminderDispatcher_Dispatch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	now := time.Date(2025, time.June, 5, 12, 0, 0, 0, time.UTC)
+
+	events := newMockEventRepository()
+	tomorrow := newScheduledEvent(t, now.Add(23*time.Hour))
+	soon := newScheduledEvent(t, now.Add(30*time.Minute))
+	later := newScheduledEvent(t, now.Add(72*time.Hour))
+	for _, evt := range []*core.Event{tomorrow, soon, later} {
+		require.NoError(t, events.CreateEvent(ctx, evt))
+	}
+
+	log := newMockReminderLog()
+	publisher := &mockReminderPublisher{}
+	dispatcher := internal.NewReminderDispatcher(events, log, publisher, []time.Duration{24 * time.Hour, time.Hour})
+	internal.SetDispatcherClock(dispatcher, func() time.Time { return now })
+
+	published, err := dispatcher.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+
+	byEvent := make(map[core.EventID]time.Duration)
+	for _, r := range publisher.published {
+		byEvent[r.EventID] = r.Offset
+	}
+	assert.Equal(t, map[core.EventID]time.Duration{
+		tomorrow.EventID: 24 * time.Hour,
+		// the missed 24h reminder is superseded by the 1h reminder
+		soon.EventID: time.Hour,
+	}, byEvent)
+	assert.Equal(t, "sent", log.state[internal.NewReminder(soon, 24*time.Hour).Key()])
+
+	// reminders fire exactly once
+	published, err = dispatcher.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, published)
+	assert.Len(t, publisher.published, 2)
+}
+
+func TestReminderDispatcher_RetriesPending(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	now := time.Date(2025, time.June, 5, 12, 0, 0, 0, time.UTC)
+
+	events := newMockEventRepository()
+	evt := newScheduledEvent(t, now.Add(30*time.Minute))
+	require.NoError(t, events.CreateEvent(ctx, evt))
+
+	log := newMockReminderLog()
+	publisher := &mockReminderPublisher{err: errors.New("nats unavailable")}
+	dispatcher := internal.NewReminderDispatcher(events, log, publisher, []time.Duration{time.Hour})
+	internal.SetDispatcherClock(dispatcher, func() time.Time { return now })
+
+	_, err := dispatcher.Dispatch(ctx)
+	require.Error(t, err)
+	assert.Equal(t, "pending", log.state[internal.NewReminder(evt, time.Hour).Key()])
+
+	publisher.err = nil
+	published, err := dispatcher.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, "sent", log.state[internal.NewReminder(evt, time.Hour).Key()])
+}
+
+func TestReminderDispatcher_RescheduledEvent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	now := time.Date(2025, time.June, 5, 12, 0, 0, 0, time.UTC)
+
+	events := newMockEventRepository()
+	evt := newScheduledEvent(t, now.Add(30*time.Minute))
+	require.NoError(t, events.CreateEvent(ctx, evt))
+
+	log := newMockReminderLog()
+	publisher := &mockReminderPublisher{}
+	dispatcher := internal.NewReminderDispatcher(events, log, publisher, []time.Duration{time.Hour})
+	clock := now
+	internal.SetDispatcherClock(dispatcher, func() time.Time { return clock })
+
+	published, err := dispatcher.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+
+	// the game night is moved to the next day
+	moved := evt.StartsAt.Add(24 * time.Hour)
+	_, err = events.RescheduleEvent(ctx, evt.EventID, moved, time.Time{})
+	require.NoError(t, err)
+
+	clock = moved.Add(-30 * time.Minute)
+	published, err = dispatcher.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, published, "the moved event is reminded again")
+	require.Len(t, publisher.published, 2)
+	assert.Equal(t, moved, publisher.published[1].StartsAt)
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/event"
+)
+
+// SubjectRSVPPrefix prefixes the subjects RSVP changes are published on,
+// followed by the change kind, e.g. "event.rsvp.promoted".
+const SubjectRSVPPrefix = "event.rsvp."
+
+// RSVPPublisher publishes RSVP domain events.
+type RSVPPublisher interface {
+	PublishRSVP(ctx context.Context, change core.RSVPChange) error
+}
+
+type NATSRSVPPublisher struct {
+	nc *nats.Conn
+}
+
+var _ RSVPPublisher = (*NATSRSVPPublisher)(nil)
+
+func NewNATSRSVPPublisher(nc *nats.Conn) *NATSRSVPPublisher {
+	return &NATSRSVPPublisher{
+		nc: nc,
+	}
+}
+
+func (p *NATSRSVPPublisher) PublishRSVP(ctx context.Context, change core.RSVPChange) error {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rsvp change: %w", err)
+	}
+
+	if err := p.nc.Publish(SubjectRSVPPrefix+string(change.Kind), data); err != nil {
+		return fmt.Errorf("failed to publish rsvp change: %w", err)
+	}
+
+	return nil
+}
+
+// RSVP runs the RSVP workflow against the event repository and publishes
+// the resulting changes.
+type RSVP struct {
+	events    event.EventRepository
+	publisher RSVPPublisher
+}
+
+func NewRSVP(events event.EventRepository, publisher RSVPPublisher) *RSVP {
+	return &RSVP{
+		events:    events,
+		publisher: publisher,
+	}
+}
+
+// Invite adds the user as a pending attendee of the event.
+func (s *RSVP) Invite(ctx context.Context, eventID core.EventID, user core.User) (*core.Event, error) {
+	return s.update(ctx, eventID, func(evt *core.Event) ([]core.RSVPChange, error) {
+		return evt.Invite(user)
+	})
+}
+
+// Accept confirms or waitlists the user.
+func (s *RSVP) Accept(ctx context.Context, eventID core.EventID, userID core.UserID) (*core.Event, error) {
+	return s.update(ctx, eventID, func(evt *core.Event) ([]core.RSVPChange, error) {
+		return evt.Accept(userID)
+	})
+}
+
+// Decline declines for the user, promoting from the waitlist if possible.
+func (s *RSVP) Decline(ctx context.Context, eventID core.EventID, userID core.UserID) (*core.Event, error) {
+	return s.update(ctx, eventID, func(evt *core.Event) ([]core.RSVPChange, error) {
+		return evt.Decline(userID)
+	})
+}
+
+// update publishes the changes only after they were stored. Publishing
+// failures are reported, but the stored changes are kept.
+func (s *RSVP) update(ctx context.Context, eventID core.EventID, fn func(evt *core.Event) ([]core.RSVPChange, error)) (*core.Event, error) {
+	var changes []core.RSVPChange
+	evt, err := s.events.UpdateAttendees(ctx, eventID, func(evt *core.Event) error {
+		var err error
+		changes, err = fn(evt)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, change := range changes {
+		if err := s.publisher.PublishRSVP(ctx, change); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return evt, errors.Join(errs...)
+}
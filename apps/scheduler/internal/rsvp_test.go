@@ -0,0 +1,102 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/apps/scheduler/internal"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRSVPPublisher records published changes
+type mockRSVPPublisher struct {
+	mu        sync.Mutex
+	published []core.RSVPChange
+	err       error
+}
+
+func (p *mockRSVPPublisher) PublishRSVP(ctx context.Context, change core.RSVPChange) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, change)
+	return nil
+}
+
+func (p *mockRSVPPublisher) kinds() []core.RSVPKind {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	kinds := make([]core.RSVPKind, 0, len(p.published))
+	for _, c := range p.published {
+		kinds = append(kinds, c.Kind)
+	}
+	return kinds
+}
+
+func TestRSVP(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	events := newMockEventRepository()
+	evt := newScheduledEvent(t, time.Now().Add(24*time.Hour))
+	evt.Capacity = 1
+	require.NoError(t, events.CreateEvent(ctx, evt))
+
+	publisher := &mockRSVPPublisher{}
+	rsvp := internal.NewRSVP(events, publisher)
+
+	host := evt.Attendees[0].User
+	bob := newTestUser("bob")
+
+	_, err := rsvp.Invite(ctx, evt.EventID, bob)
+	require.NoError(t, err)
+
+	updated, err := rsvp.Accept(ctx, evt.EventID, bob.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, core.AttendeeStatusWaitlisted, updated.Attendees[1].Status)
+
+	updated, err = rsvp.Decline(ctx, evt.EventID, host.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, core.AttendeeStatusConfirmed, updated.Attendees[1].Status)
+
+	assert.Equal(t, []core.RSVPKind{
+		core.RSVPInvited,
+		core.RSVPWaitlisted,
+		core.RSVPDeclined,
+		core.RSVPPromoted,
+	}, publisher.kinds())
+
+	// rejected changes are neither stored nor published
+	_, err = rsvp.Invite(ctx, evt.EventID, bob)
+	assert.ErrorIs(t, err, core.ErrAlreadyInvited)
+	_, err = rsvp.Accept(ctx, core.NewEventID(), bob.UserID)
+	assert.ErrorIs(t, err, event.ErrEventNotFound)
+	assert.Len(t, publisher.kinds(), 4)
+}
+
+func TestRSVP_PublishFailureKeepsUpdate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	events := newMockEventRepository()
+	evt := newScheduledEvent(t, time.Now().Add(24*time.Hour))
+	require.NoError(t, events.CreateEvent(ctx, evt))
+
+	rsvp := internal.NewRSVP(events, &mockRSVPPublisher{err: errors.New("nats unavailable")})
+
+	updated, err := rsvp.Invite(ctx, evt.EventID, newTestUser("bob"))
+	require.Error(t, err)
+	require.NotNil(t, updated)
+
+	stored, err := events.GetEvent(ctx, evt.EventID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Attendees, 2)
+}
@@ -0,0 +1,235 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Sink delivers reminders to users.
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, reminder *Reminder) error
+}
+
+// LogSink writes reminders to the structured log.
+type LogSink struct{}
+
+var _ Sink = LogSink{}
+
+func (LogSink) Name() string {
+	return "log"
+}
+
+func (LogSink) Send(ctx context.Context, reminder *Reminder) error {
+	usernames := make([]string, 0, len(reminder.Recipients))
+	for _, u := range reminder.Recipients {
+		usernames = append(usernames, u.Username)
+	}
+
+	slog.InfoContext(ctx, "event reminder",
+		slog.String("event_id", reminder.EventID.String()),
+		slog.String("title", reminder.Title),
+		slog.Time("starts_at", reminder.StartsAt),
+		slog.Duration("offset", reminder.Offset),
+		slog.Any("recipients", usernames),
+	)
+
+	return nil
+}
+
+const webhookTimeout = 10 * time.Second
+
+// WebhookSink posts reminders as JSON to a URL.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+var _ Sink = (*WebhookSink)(nil)
+
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *WebhookSink) Send(ctx context.Context, reminder *Reminder) error {
+	data, err := json.Marshal(reminder)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reminder: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// lets receivers deduplicate redelivered reminders
+	req.Header.Set("Idempotency-Key", reminder.Key())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SMTPConfig configures the SMTPSink.
+type SMTPConfig struct {
+	// Addr is the host:port of the SMTP server.
+	Addr     string
+	Username string
+	Password string
+
+	From string
+	// To lists the receiving addresses, e.g. a group mailbox, as users do
+	// not carry an email address.
+	To []string
+}
+
+const smtpTimeout = 30 * time.Second
+
+// SMTPSink sends reminders as plain text emails.
+type SMTPSink struct {
+	cfg    SMTPConfig
+	dialer net.Dialer
+}
+
+var _ Sink = (*SMTPSink)(nil)
+
+func NewSMTPSink(cfg SMTPConfig) *SMTPSink {
+	return &SMTPSink{
+		cfg: cfg,
+	}
+}
+
+func (s *SMTPSink) Name() string {
+	return "smtp"
+}
+
+// Send delivers the reminder like smtp.SendMail, but gives up once the
+// context is done or smtpTimeout has passed.
+func (s *SMTPSink) Send(ctx context.Context, reminder *Reminder) error {
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	host, _, err := net.SplitHostPort(s.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid smtp address '%s': %w", s.cfg.Addr, err)
+	}
+
+	conn, err := s.dialer.DialContext(ctx, "tcp", s.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to set smtp deadline: %w", err)
+	}
+	// unblocks the client if the context is cancelled before the deadline
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer c.Close()
+
+	if err := s.send(c, host, s.message(reminder)); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SMTPSink) send(c *smtp.Client, host string, msg []byte) error {
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(s.cfg.From); err != nil {
+		return err
+	}
+	for _, to := range s.cfg.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+func (s *SMTPSink) message(reminder *Reminder) []byte {
+	var b strings.Builder
+
+	// titles are user input: line breaks would start new headers
+	subject := fmt.Sprintf("Reminder: %s starts in %s", stripLineBreaks(reminder.Title), offsetLabel(reminder.Offset))
+
+	fmt.Fprintf(&b, "From: %s\r\n", s.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Message-ID: <%s@dicetrace>\r\n", reminder.Key())
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+
+	fmt.Fprintf(&b, "%s starts at %s.\r\n", reminder.Title, reminder.StartsAt.Format("Mon, 02 Jan 2006 15:04 MST"))
+	if reminder.Location != "" {
+		fmt.Fprintf(&b, "Location: %s\r\n", reminder.Location)
+	}
+	if len(reminder.Recipients) > 0 {
+		usernames := make([]string, 0, len(reminder.Recipients))
+		for _, u := range reminder.Recipients {
+			usernames = append(usernames, u.Username)
+		}
+		fmt.Fprintf(&b, "Attending: %s\r\n", strings.Join(usernames, ", "))
+	}
+
+	return []byte(b.String())
+}
+
+// stripLineBreaks replaces CR and LF with spaces.
+func stripLineBreaks(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(s)
+}
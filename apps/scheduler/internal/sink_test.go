@@ -0,0 +1,187 @@
+package internal_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/apps/scheduler/internal"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReminder() *internal.Reminder {
+	return &internal.Reminder{
+		EventID:    core.NewEventID(),
+		Title:      "Game night",
+		StartsAt:   time.Date(2025, time.June, 6, 19, 0, 0, 0, time.UTC),
+		Offset:     time.Hour,
+		Recipients: []core.User{newTestUser("alice")},
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	t.Parallel()
+	reminder := newTestReminder()
+
+	var received internal.Reminder
+	var idempotencyKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKey = r.Header.Get("Idempotency-Key")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	err := internal.NewWebhookSink(srv.URL).Send(context.Background(), reminder)
+	require.NoError(t, err)
+	assert.Equal(t, reminder.Key(), idempotencyKey)
+	assert.Equal(t, reminder.EventID, received.EventID)
+	assert.Equal(t, reminder.Offset, received.Offset)
+}
+
+func TestWebhookSink_ErrorStatus(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	err := internal.NewWebhookSink(srv.URL).Send(context.Background(), newTestReminder())
+	assert.ErrorContains(t, err, "503")
+}
+
+// countingSink counts deliveries and fails while err is set
+type countingSink struct {
+	name string
+	sent int
+	err  error
+}
+
+func (s *countingSink) Name() string { return s.name }
+
+func (s *countingSink) Send(ctx context.Context, reminder *internal.Reminder) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent++
+	return nil
+}
+
+func TestNotifierDeliver(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	reminder := newTestReminder()
+
+	webhook := &countingSink{name: "webhook"}
+	smtp := &countingSink{name: "smtp", err: errors.New("unavailable")}
+	notifier := internal.NewNotifier(nil, newMockReminderLog(), webhook, smtp)
+
+	assert.ErrorContains(t, notifier.Deliver(ctx, reminder), "sink 'smtp'")
+	assert.Equal(t, 1, webhook.sent)
+
+	// redelivery only retries the failed sink
+	smtp.err = nil
+	require.NoError(t, notifier.Deliver(ctx, reminder))
+	assert.Equal(t, 1, webhook.sent)
+	assert.Equal(t, 1, smtp.sent)
+
+	require.NoError(t, notifier.Deliver(ctx, reminder))
+	assert.Equal(t, 1, webhook.sent)
+	assert.Equal(t, 1, smtp.sent)
+}
+
+// serveSMTP accepts a single SMTP session on a local port and sends the
+// received message data to the returned channel. Unless greet is set, the
+// server never answers.
+func serveSMTP(t *testing.T, greet bool) (string, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if !greet {
+			_, _ = io.Copy(io.Discard, conn)
+			return
+		}
+
+		tp := textproto.NewConn(conn)
+		_ = tp.PrintfLine("220 localhost")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			switch cmd, _, _ := strings.Cut(line, " "); strings.ToUpper(cmd) {
+			case "EHLO", "HELO", "MAIL", "RCPT":
+				_ = tp.PrintfLine("250 OK")
+			case "DATA":
+				_ = tp.PrintfLine("354 go ahead")
+				data, err := tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				received <- string(data)
+				_ = tp.PrintfLine("250 OK")
+			case "QUIT":
+				_ = tp.PrintfLine("221 bye")
+				return
+			default:
+				_ = tp.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+
+	return ln.Addr().String(), received
+}
+
+func TestSMTPSink(t *testing.T) {
+	t.Parallel()
+	addr, received := serveSMTP(t, true)
+
+	reminder := newTestReminder()
+	reminder.Title = "Game night\r\nBcc: everyone@example.com"
+
+	sink := internal.NewSMTPSink(internal.SMTPConfig{Addr: addr, From: "dicetrace@example.com", To: []string{"group@example.com"}})
+	require.NoError(t, sink.Send(context.Background(), reminder))
+
+	msg, err := mail.ReadMessage(strings.NewReader(<-received))
+	require.NoError(t, err)
+	assert.Empty(t, msg.Header.Get("Bcc"))
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Reminder: Game night Bcc: everyone@example.com starts in 1h", subject)
+}
+
+func TestSMTPSink_Cancelled(t *testing.T) {
+	t.Parallel()
+	addr, _ := serveSMTP(t, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	sink := internal.NewSMTPSink(internal.SMTPConfig{Addr: addr, From: "dicetrace@example.com", To: []string{"group@example.com"}})
+	assert.Error(t, sink.Send(ctx, newTestReminder()))
+}
@@ -0,0 +1,477 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/ngoldack/dicetrace/package/core"
+	"go.jetify.com/typeid/v2"
+)
+
+const (
+	templateBucket   = "scheduler_templates"
+	occurrenceBucket = "scheduler_occurrences"
+	pollBucket       = "scheduler_polls"
+	reminderBucket   = "scheduler_reminders"
+	feedTokenBucket  = "scheduler_feed_tokens"
+)
+
+var (
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrPollNotFound     = errors.New("poll not found")
+)
+
+// pollUpdateAttempts bounds the retries of UpdatePoll on concurrent writes.
+const pollUpdateAttempts = 5
+
+// TemplateStore persists recurring event templates.
+type TemplateStore interface {
+	SaveTemplate(ctx context.Context, tmpl *Template) error
+	GetTemplate(ctx context.Context, templateID TemplateID) (*Template, error)
+	ListTemplates(ctx context.Context) ([]*Template, error)
+	DeleteTemplate(ctx context.Context, templateID TemplateID) error
+}
+
+// OccurrenceStore tracks which template occurrences have been materialized
+// as events, so every occurrence is materialized exactly once even with
+// several scheduler instances running.
+type OccurrenceStore interface {
+	// Claim records the event for the occurrence. It returns false if the
+	// occurrence has been claimed before.
+	Claim(ctx context.Context, templateID TemplateID, original time.Time, eventID core.EventID) (bool, error)
+	// Lookup returns the event the occurrence was materialized as, if any.
+	Lookup(ctx context.Context, templateID TemplateID, original time.Time) (core.EventID, bool, error)
+	Release(ctx context.Context, templateID TemplateID, original time.Time) error
+}
+
+// FeedTokenStore maps users to the secret tokens identifying their calendar
+// feeds.
+type FeedTokenStore interface {
+	// Token returns the user's feed token, issuing one on first use.
+	Token(ctx context.Context, userID core.UserID) (string, error)
+	// Rotate issues a new feed token for the user, revoking the old one.
+	Rotate(ctx context.Context, userID core.UserID) (string, error)
+	// Resolve returns the user the token was issued to. ok is false for
+	// unknown and revoked tokens.
+	Resolve(ctx context.Context, token string) (userID core.UserID, ok bool, err error)
+}
+
+// PollStore persists availability polls.
+type PollStore interface {
+	CreatePoll(ctx context.Context, poll *Poll) error
+	GetPoll(ctx context.Context, pollID PollID) (*Poll, error)
+	// UpdatePoll applies fn to the current poll and stores the result.
+	// Concurrent updates are serialized, so fn may be called more than once.
+	UpdatePoll(ctx context.Context, pollID PollID, fn func(poll *Poll) error) (*Poll, error)
+}
+
+type KVTemplateStore struct {
+	kv jetstream.KeyValue
+}
+
+var _ TemplateStore = (*KVTemplateStore)(nil)
+
+func NewKVTemplateStore(ctx context.Context, js jetstream.JetStream) (*KVTemplateStore, error) {
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      templateBucket,
+		Description: "Recurring event templates",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key value bucket '%s': %w", templateBucket, err)
+	}
+
+	return &KVTemplateStore{
+		kv: kv,
+	}, nil
+}
+
+func (s *KVTemplateStore) SaveTemplate(ctx context.Context, tmpl *Template) error {
+	data, err := json.Marshal(tmpl)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template: %w", err)
+	}
+
+	if _, err := s.kv.Put(ctx, tmpl.TemplateID.String(), data); err != nil {
+		return fmt.Errorf("failed to store template '%s': %w", tmpl.TemplateID, err)
+	}
+
+	return nil
+}
+
+func (s *KVTemplateStore) GetTemplate(ctx context.Context, templateID TemplateID) (*Template, error) {
+	entry, err := s.kv.Get(ctx, templateID.String())
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return nil, fmt.Errorf("template '%s': %w", templateID, ErrTemplateNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get template '%s': %w", templateID, err)
+	}
+
+	var tmpl Template
+	if err := json.Unmarshal(entry.Value(), &tmpl); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal template '%s': %w", templateID, err)
+	}
+
+	return &tmpl, nil
+}
+
+func (s *KVTemplateStore) ListTemplates(ctx context.Context) ([]*Template, error) {
+	lister, err := s.kv.ListKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	defer lister.Stop()
+
+	templates := make([]*Template, 0)
+	for key := range lister.Keys() {
+		id, err := typeid.Parse(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template key '%s': %w", key, err)
+		}
+
+		tmpl, err := s.GetTemplate(ctx, id)
+		if errors.Is(err, ErrTemplateNotFound) {
+			// deleted while listing
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		templates = append(templates, tmpl)
+	}
+
+	return templates, nil
+}
+
+func (s *KVTemplateStore) DeleteTemplate(ctx context.Context, templateID TemplateID) error {
+	if _, err := s.GetTemplate(ctx, templateID); err != nil {
+		return err
+	}
+
+	if err := s.kv.Delete(ctx, templateID.String()); err != nil {
+		return fmt.Errorf("failed to delete template '%s': %w", templateID, err)
+	}
+
+	return nil
+}
+
+type KVOccurrenceStore struct {
+	kv jetstream.KeyValue
+}
+
+var _ OccurrenceStore = (*KVOccurrenceStore)(nil)
+
+func NewKVOccurrenceStore(ctx context.Context, js jetstream.JetStream) (*KVOccurrenceStore, error) {
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      occurrenceBucket,
+		Description: "Materialized template occurrences",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key value bucket '%s': %w", occurrenceBucket, err)
+	}
+
+	return &KVOccurrenceStore{
+		kv: kv,
+	}, nil
+}
+
+func (s *KVOccurrenceStore) Claim(ctx context.Context, templateID TemplateID, original time.Time, eventID core.EventID) (bool, error) {
+	_, err := s.kv.Create(ctx, occurrenceKey(templateID, original), []byte(eventID.String()))
+	if errors.Is(err, jetstream.ErrKeyExists) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to claim occurrence: %w", err)
+	}
+
+	return true, nil
+}
+
+func (s *KVOccurrenceStore) Lookup(ctx context.Context, templateID TemplateID, original time.Time) (core.EventID, bool, error) {
+	entry, err := s.kv.Get(ctx, occurrenceKey(templateID, original))
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return core.EventID{}, false, nil
+	} else if err != nil {
+		return core.EventID{}, false, fmt.Errorf("failed to look up occurrence: %w", err)
+	}
+
+	eventID, err := typeid.Parse(string(entry.Value()))
+	if err != nil {
+		return core.EventID{}, false, fmt.Errorf("invalid event id '%s': %w", entry.Value(), err)
+	}
+
+	return eventID, true, nil
+}
+
+func (s *KVOccurrenceStore) Release(ctx context.Context, templateID TemplateID, original time.Time) error {
+	if err := s.kv.Delete(ctx, occurrenceKey(templateID, original)); err != nil {
+		return fmt.Errorf("failed to release occurrence: %w", err)
+	}
+	return nil
+}
+
+func occurrenceKey(templateID TemplateID, original time.Time) string {
+	return fmt.Sprintf("%s.%d", templateID, original.Unix())
+}
+
+type KVPollStore struct {
+	kv jetstream.KeyValue
+}
+
+var _ PollStore = (*KVPollStore)(nil)
+
+func NewKVPollStore(ctx context.Context, js jetstream.JetStream) (*KVPollStore, error) {
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      pollBucket,
+		Description: "Availability polls",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key value bucket '%s': %w", pollBucket, err)
+	}
+
+	return &KVPollStore{
+		kv: kv,
+	}, nil
+}
+
+func (s *KVPollStore) CreatePoll(ctx context.Context, poll *Poll) error {
+	data, err := json.Marshal(poll)
+	if err != nil {
+		return fmt.Errorf("failed to marshal poll: %w", err)
+	}
+
+	if _, err := s.kv.Create(ctx, poll.PollID.String(), data); err != nil {
+		return fmt.Errorf("failed to store poll '%s': %w", poll.PollID, err)
+	}
+
+	return nil
+}
+
+func (s *KVPollStore) GetPoll(ctx context.Context, pollID PollID) (*Poll, error) {
+	poll, _, err := s.getPoll(ctx, pollID)
+	return poll, err
+}
+
+func (s *KVPollStore) UpdatePoll(ctx context.Context, pollID PollID, fn func(poll *Poll) error) (*Poll, error) {
+	for range pollUpdateAttempts {
+		poll, revision, err := s.getPoll(ctx, pollID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := fn(poll); err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(poll)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal poll: %w", err)
+		}
+
+		_, err = s.kv.Update(ctx, pollID.String(), data, revision)
+		if errors.Is(err, jetstream.ErrKeyExists) {
+			// modified concurrently, retry on the new revision
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to update poll '%s': %w", pollID, err)
+		}
+
+		return poll, nil
+	}
+
+	return nil, fmt.Errorf("failed to update poll '%s': too many concurrent updates", pollID)
+}
+
+func (s *KVPollStore) getPoll(ctx context.Context, pollID PollID) (*Poll, uint64, error) {
+	entry, err := s.kv.Get(ctx, pollID.String())
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return nil, 0, fmt.Errorf("poll '%s': %w", pollID, ErrPollNotFound)
+	} else if err != nil {
+		return nil, 0, fmt.Errorf("failed to get poll '%s': %w", pollID, err)
+	}
+
+	var poll Poll
+	if err := json.Unmarshal(entry.Value(), &poll); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal poll '%s': %w", pollID, err)
+	}
+
+	return &poll, entry.Revision(), nil
+}
+
+const (
+	reminderPending = "pending"
+	reminderSent    = "sent"
+)
+
+type KVReminderLog struct {
+	kv jetstream.KeyValue
+}
+
+var _ ReminderLog = (*KVReminderLog)(nil)
+
+func NewKVReminderLog(ctx context.Context, js jetstream.JetStream) (*KVReminderLog, error) {
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      reminderBucket,
+		Description: "Sent event reminders",
+		// reminders are only sent for upcoming events
+		TTL: 30 * 24 * time.Hour,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key value bucket '%s': %w", reminderBucket, err)
+	}
+
+	return &KVReminderLog{
+		kv: kv,
+	}, nil
+}
+
+func (l *KVReminderLog) Pending(ctx context.Context, key string) (bool, error) {
+	_, err := l.kv.Create(ctx, key, []byte(reminderPending))
+	if err == nil {
+		return true, nil
+	} else if !errors.Is(err, jetstream.ErrKeyExists) {
+		return false, fmt.Errorf("failed to record reminder '%s': %w", key, err)
+	}
+
+	entry, err := l.kv.Get(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to get reminder '%s': %w", key, err)
+	}
+
+	return string(entry.Value()) != reminderSent, nil
+}
+
+func (l *KVReminderLog) MarkSent(ctx context.Context, key string) error {
+	if _, err := l.kv.Put(ctx, key, []byte(reminderSent)); err != nil {
+		return fmt.Errorf("failed to mark reminder '%s' as sent: %w", key, err)
+	}
+	return nil
+}
+
+func (l *KVReminderLog) Delivered(ctx context.Context, key, sink string) (bool, error) {
+	_, err := l.kv.Get(ctx, deliveryKey(key, sink))
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get delivery of reminder '%s' through '%s': %w", key, sink, err)
+	}
+	return true, nil
+}
+
+func (l *KVReminderLog) MarkDelivered(ctx context.Context, key, sink string) error {
+	if _, err := l.kv.Put(ctx, deliveryKey(key, sink), []byte(reminderSent)); err != nil {
+		return fmt.Errorf("failed to mark reminder '%s' as delivered through '%s': %w", key, sink, err)
+	}
+	return nil
+}
+
+func deliveryKey(key, sink string) string {
+	return fmt.Sprintf("%s.delivered.%s", key, sink)
+}
+
+// KVFeedTokenStore keeps a token key for resolving each token and a user
+// key pointing at the user's current token.
+type KVFeedTokenStore struct {
+	kv jetstream.KeyValue
+}
+
+var _ FeedTokenStore = (*KVFeedTokenStore)(nil)
+
+func NewKVFeedTokenStore(ctx context.Context, js jetstream.JetStream) (*KVFeedTokenStore, error) {
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      feedTokenBucket,
+		Description: "Secret tokens of calendar feeds",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key value bucket '%s': %w", feedTokenBucket, err)
+	}
+
+	return &KVFeedTokenStore{
+		kv: kv,
+	}, nil
+}
+
+func (s *KVFeedTokenStore) Token(ctx context.Context, userID core.UserID) (string, error) {
+	entry, err := s.kv.Get(ctx, feedUserKey(userID))
+	if err == nil {
+		return string(entry.Value()), nil
+	} else if !errors.Is(err, jetstream.ErrKeyNotFound) {
+		return "", fmt.Errorf("failed to get feed token: %w", err)
+	}
+
+	token := NewFeedToken()
+	if _, err := s.kv.Create(ctx, feedTokenKey(token), []byte(userID.String())); err != nil {
+		return "", fmt.Errorf("failed to store feed token: %w", err)
+	}
+
+	_, err = s.kv.Create(ctx, feedUserKey(userID), []byte(token))
+	if errors.Is(err, jetstream.ErrKeyExists) {
+		// issued concurrently
+		_ = s.kv.Delete(ctx, feedTokenKey(token))
+		entry, err := s.kv.Get(ctx, feedUserKey(userID))
+		if err != nil {
+			return "", fmt.Errorf("failed to get feed token: %w", err)
+		}
+		return string(entry.Value()), nil
+	} else if err != nil {
+		return "", fmt.Errorf("failed to store feed token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (s *KVFeedTokenStore) Rotate(ctx context.Context, userID core.UserID) (string, error) {
+	var old string
+	entry, err := s.kv.Get(ctx, feedUserKey(userID))
+	if err == nil {
+		old = string(entry.Value())
+	} else if !errors.Is(err, jetstream.ErrKeyNotFound) {
+		return "", fmt.Errorf("failed to get feed token: %w", err)
+	}
+
+	token := NewFeedToken()
+	if _, err := s.kv.Create(ctx, feedTokenKey(token), []byte(userID.String())); err != nil {
+		return "", fmt.Errorf("failed to store feed token: %w", err)
+	}
+	if _, err := s.kv.Put(ctx, feedUserKey(userID), []byte(token)); err != nil {
+		return "", fmt.Errorf("failed to store feed token: %w", err)
+	}
+
+	if old != "" {
+		if err := s.kv.Delete(ctx, feedTokenKey(old)); err != nil {
+			return "", fmt.Errorf("failed to revoke feed token: %w", err)
+		}
+	}
+
+	return token, nil
+}
+
+func (s *KVFeedTokenStore) Resolve(ctx context.Context, token string) (core.UserID, bool, error) {
+	if !ValidFeedToken(token) {
+		return core.UserID{}, false, nil
+	}
+
+	entry, err := s.kv.Get(ctx, feedTokenKey(token))
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return core.UserID{}, false, nil
+	} else if err != nil {
+		return core.UserID{}, false, fmt.Errorf("failed to resolve feed token: %w", err)
+	}
+
+	userID, err := typeid.Parse(string(entry.Value()))
+	if err != nil {
+		return core.UserID{}, false, fmt.Errorf("invalid user id '%s': %w", entry.Value(), err)
+	}
+
+	return userID, true, nil
+}
+
+func feedUserKey(userID core.UserID) string {
+	return "user." + userID.String()
+}
+
+func feedTokenKey(token string) string {
+	return "token." + token
+}
@@ -0,0 +1,207 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/teambition/rrule-go"
+	"go.jetify.com/typeid/v2"
+)
+
+var ErrTemplateInvalid = errors.New("invalid template")
+
+type TemplateID = typeid.TypeID
+
+func NewTemplateID() TemplateID {
+	return typeid.MustGenerate("template")
+}
+
+// Template describes a recurring game night. Its occurrences are
+// materialized as core.Event instances ahead of time.
+type Template struct {
+	TemplateID TemplateID `json:"template_id"`
+
+	Title    string     `json:"title"`
+	Location string     `json:"location"`
+	Host     *core.User `json:"host,omitempty"`
+	Capacity int        `json:"capacity,omitempty"`
+
+	// Start is the first occurrence. Recurrences keep its wall-clock time in
+	// Timezone, so a 19:00 game night stays at 19:00 across DST changes.
+	Start    time.Time `json:"start"`
+	Timezone string    `json:"timezone"`
+	// DurationMinutes is the length of every occurrence.
+	DurationMinutes int `json:"duration_minutes"`
+
+	// RRule is an iCalendar RRULE value without DTSTART, e.g.
+	// "FREQ=WEEKLY;BYDAY=TH" or "FREQ=MONTHLY;BYDAY=1SA".
+	RRule string `json:"rrule"`
+
+	Exceptions []Exception `json:"exceptions,omitempty"`
+}
+
+// Exception skips or moves a single occurrence of a template.
+type Exception struct {
+	// Occurrence is the start time the recurrence rule produces for the
+	// affected occurrence.
+	Occurrence time.Time `json:"occurrence"`
+
+	Skip bool `json:"skip,omitempty"`
+	// MovedTo is the new start time of the occurrence; ignored when Skip is
+	// set.
+	MovedTo time.Time `json:"moved_to,omitzero"`
+}
+
+// Occurrence is a single instance of a template.
+type Occurrence struct {
+	// Original is the start time produced by the recurrence rule. It
+	// identifies the occurrence even after it was moved.
+	Original time.Time
+	StartsAt time.Time
+	EndsAt   time.Time
+}
+
+// Validate checks that the template can produce occurrences.
+func (t *Template) Validate() error {
+	var errs []error
+
+	if strings.TrimSpace(t.Title) == "" {
+		errs = append(errs, errors.New("title is required"))
+	}
+	if t.Start.IsZero() {
+		errs = append(errs, errors.New("start is required"))
+	}
+	if t.DurationMinutes <= 0 {
+		errs = append(errs, errors.New("duration_minutes must be positive"))
+	}
+	if t.Capacity < 0 {
+		errs = append(errs, errors.New("capacity must not be negative"))
+	}
+	if _, err := t.rule(); err != nil {
+		errs = append(errs, err)
+	}
+
+	for i, ex := range t.Exceptions {
+		if ex.Occurrence.IsZero() {
+			errs = append(errs, fmt.Errorf("exceptions[%d]: occurrence is required", i))
+		}
+		if !ex.Skip && ex.MovedTo.IsZero() {
+			errs = append(errs, fmt.Errorf("exceptions[%d]: either skip or moved_to is required", i))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrTemplateInvalid, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// AddException records the exception, replacing an earlier exception for the
+// same occurrence.
+func (t *Template) AddException(ex Exception) {
+	t.Exceptions = slices.DeleteFunc(t.Exceptions, func(e Exception) bool {
+		return e.Occurrence.Equal(ex.Occurrence)
+	})
+	t.Exceptions = append(t.Exceptions, ex)
+}
+
+// Occurrences returns all occurrences starting in [from, to), with
+// exceptions applied, ordered by start time.
+func (t *Template) Occurrences(from, to time.Time) ([]Occurrence, error) {
+	rule, err := t.rule()
+	if err != nil {
+		return nil, err
+	}
+
+	duration := time.Duration(t.DurationMinutes) * time.Minute
+	occurrences := make([]Occurrence, 0)
+
+	for _, start := range rule.Between(from, to, true) {
+		if start.Equal(to) || t.exception(start) != nil {
+			continue
+		}
+
+		occurrences = append(occurrences, Occurrence{
+			Original: start,
+			StartsAt: start,
+			EndsAt:   start.Add(duration),
+		})
+	}
+
+	// Moved occurrences may land in the window even though their original
+	// start lies outside of it.
+	loc := rule.GetDTStart().Location()
+	for _, ex := range t.Exceptions {
+		if ex.Skip || ex.MovedTo.Before(from) || !ex.MovedTo.Before(to) {
+			continue
+		}
+
+		occurrences = append(occurrences, Occurrence{
+			Original: ex.Occurrence.In(loc),
+			StartsAt: ex.MovedTo.In(loc),
+			EndsAt:   ex.MovedTo.In(loc).Add(duration),
+		})
+	}
+
+	slices.SortFunc(occurrences, func(a, b Occurrence) int {
+		return a.StartsAt.Compare(b.StartsAt)
+	})
+
+	return occurrences, nil
+}
+
+// NewEvent builds the scheduled event for an occurrence of the template.
+func (t *Template) NewEvent(occ Occurrence) (*core.Event, error) {
+	evt := &core.Event{
+		EventID:   core.NewEventID(),
+		Title:     t.Title,
+		Location:  t.Location,
+		StartsAt:  occ.StartsAt,
+		EndsAt:    occ.EndsAt,
+		Timezone:  t.Timezone,
+		Host:      t.Host,
+		Capacity:  t.Capacity,
+		Attendees: []core.Attendee{},
+		Matches:   []core.Match{},
+	}
+
+	if err := evt.Transition(core.EventTransitionSchedule); err != nil {
+		return nil, err
+	}
+
+	return evt, nil
+}
+
+func (t *Template) exception(original time.Time) *Exception {
+	for i := range t.Exceptions {
+		if t.Exceptions[i].Occurrence.Equal(original) {
+			return &t.Exceptions[i]
+		}
+	}
+	return nil
+}
+
+func (t *Template) rule() (*rrule.RRule, error) {
+	loc, err := time.LoadLocation(t.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone '%s': %w", t.Timezone, err)
+	}
+
+	opt, err := rrule.StrToROptionInLocation(t.RRule, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rrule '%s': %w", t.RRule, err)
+	}
+	opt.Dtstart = t.Start.In(loc)
+
+	rule, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rrule '%s': %w", t.RRule, err)
+	}
+
+	return rule, nil
+}
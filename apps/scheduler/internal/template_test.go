@@ -0,0 +1,171 @@
+package internal_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/apps/scheduler/internal"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	require.NoError(t, err)
+	return loc
+}
+
+func newWeeklyTemplate(t *testing.T) *internal.Template {
+	t.Helper()
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+
+	return &internal.Template{
+		TemplateID:      internal.NewTemplateID(),
+		Title:           "Thursday game night",
+		Location:        "Kitchen table",
+		Start:           time.Date(2025, time.March, 6, 19, 0, 0, 0, berlin),
+		Timezone:        "Europe/Berlin",
+		DurationMinutes: 240,
+		RRule:           "FREQ=WEEKLY;BYDAY=TH",
+	}
+}
+
+func TestTemplateOccurrences_WeeklyAcrossDST(t *testing.T) {
+	t.Parallel()
+	tmpl := newWeeklyTemplate(t)
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+
+	// Germany switches to summer time on March 30th, 2025
+	occurrences, err := tmpl.Occurrences(
+		time.Date(2025, time.March, 20, 0, 0, 0, 0, berlin),
+		time.Date(2025, time.April, 10, 0, 0, 0, 0, berlin),
+	)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 3)
+
+	for i, day := range []int{20, 27, 3} {
+		occ := occurrences[i]
+		assert.Equal(t, day, occ.StartsAt.Day())
+		assert.Equal(t, 19, occ.StartsAt.In(berlin).Hour(), "wall-clock time must survive DST")
+		assert.Equal(t, 4*time.Hour, occ.EndsAt.Sub(occ.StartsAt))
+		assert.True(t, occ.Original.Equal(occ.StartsAt))
+	}
+}
+
+func TestTemplateOccurrences_FirstSaturdayOfMonth(t *testing.T) {
+	t.Parallel()
+	tmpl := newWeeklyTemplate(t)
+	tmpl.RRule = "FREQ=MONTHLY;BYDAY=1SA"
+	tmpl.Start = time.Date(2025, time.January, 4, 14, 0, 0, 0, mustLoadLocation(t, "Europe/Berlin"))
+
+	occurrences, err := tmpl.Occurrences(
+		time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC),
+	)
+	require.NoError(t, err)
+
+	days := make([]string, 0, len(occurrences))
+	for _, occ := range occurrences {
+		days = append(days, occ.StartsAt.Format(time.DateOnly))
+	}
+	assert.Equal(t, []string{"2025-01-04", "2025-02-01", "2025-03-01", "2025-04-05"}, days)
+}
+
+func TestTemplateOccurrences_Exceptions(t *testing.T) {
+	t.Parallel()
+	tmpl := newWeeklyTemplate(t)
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+
+	skipped := time.Date(2025, time.March, 13, 19, 0, 0, 0, berlin)
+	moved := time.Date(2025, time.March, 20, 19, 0, 0, 0, berlin)
+	movedTo := time.Date(2025, time.March, 22, 16, 0, 0, 0, berlin)
+
+	tmpl.AddException(internal.Exception{Occurrence: skipped, Skip: true})
+	tmpl.AddException(internal.Exception{Occurrence: moved, MovedTo: movedTo})
+	require.NoError(t, tmpl.Validate())
+
+	occurrences, err := tmpl.Occurrences(
+		time.Date(2025, time.March, 10, 0, 0, 0, 0, berlin),
+		time.Date(2025, time.March, 28, 0, 0, 0, 0, berlin),
+	)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 2)
+
+	assert.True(t, occurrences[0].Original.Equal(moved))
+	assert.True(t, occurrences[0].StartsAt.Equal(movedTo))
+	assert.True(t, occurrences[0].EndsAt.Equal(movedTo.Add(4*time.Hour)))
+
+	assert.Equal(t, 27, occurrences[1].StartsAt.Day())
+
+	// A moved occurrence is found through its new date even when the
+	// original date lies outside of the window
+	occurrences, err = tmpl.Occurrences(
+		time.Date(2025, time.March, 21, 0, 0, 0, 0, berlin),
+		time.Date(2025, time.March, 23, 0, 0, 0, 0, berlin),
+	)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 1)
+	assert.True(t, occurrences[0].StartsAt.Equal(movedTo))
+}
+
+func TestTemplateAddException_ReplacesExisting(t *testing.T) {
+	t.Parallel()
+	tmpl := newWeeklyTemplate(t)
+	occurrence := tmpl.Start.AddDate(0, 0, 7)
+
+	tmpl.AddException(internal.Exception{Occurrence: occurrence, Skip: true})
+	tmpl.AddException(internal.Exception{Occurrence: occurrence, MovedTo: occurrence.Add(time.Hour)})
+
+	require.Len(t, tmpl.Exceptions, 1)
+	assert.False(t, tmpl.Exceptions[0].Skip)
+}
+
+func TestTemplateValidate(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		modify func(tmpl *internal.Template)
+	}{
+		{"missing title", func(tmpl *internal.Template) { tmpl.Title = " " }},
+		{"missing start", func(tmpl *internal.Template) { tmpl.Start = time.Time{} }},
+		{"zero duration", func(tmpl *internal.Template) { tmpl.DurationMinutes = 0 }},
+		{"negative capacity", func(tmpl *internal.Template) { tmpl.Capacity = -1 }},
+		{"unknown timezone", func(tmpl *internal.Template) { tmpl.Timezone = "Nowhere/Special" }},
+		{"invalid rrule", func(tmpl *internal.Template) { tmpl.RRule = "FREQ=SOMETIMES" }},
+		{"empty exception", func(tmpl *internal.Template) {
+			tmpl.Exceptions = []internal.Exception{{Occurrence: tmpl.Start}}
+		}},
+	}
+
+	require.NoError(t, newWeeklyTemplate(t).Validate())
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl := newWeeklyTemplate(t)
+			tc.modify(tmpl)
+			assert.ErrorIs(t, tmpl.Validate(), internal.ErrTemplateInvalid)
+		})
+	}
+}
+
+func TestTemplateNewEvent(t *testing.T) {
+	t.Parallel()
+	tmpl := newWeeklyTemplate(t)
+	tmpl.Capacity = 6
+
+	occurrences, err := tmpl.Occurrences(tmpl.Start, tmpl.Start.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, occurrences, 1)
+
+	evt, err := tmpl.NewEvent(occurrences[0])
+	require.NoError(t, err)
+	assert.Equal(t, core.EventStatusScheduled, evt.Status)
+	assert.Equal(t, tmpl.Title, evt.Title)
+	assert.Equal(t, tmpl.Location, evt.Location)
+	assert.Equal(t, tmpl.Timezone, evt.Timezone)
+	assert.Equal(t, tmpl.Capacity, evt.Capacity)
+	assert.True(t, tmpl.Start.Equal(evt.StartsAt))
+	assert.NoError(t, evt.Validate())
+}
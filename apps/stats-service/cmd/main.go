@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/apps/stats-service/internal"
+	"github.com/ngoldack/dicetrace/package/core/logger"
+	"github.com/ngoldack/dicetrace/package/core/service"
+	"github.com/ngoldack/dicetrace/package/core/stream"
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/ngoldack/dicetrace/package/rating"
+)
+
+func main() {
+	if err := Run(context.Background()); err != nil {
+		panic(err)
+	}
+}
+
+func Run(ctx context.Context) error {
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	logger.SetupLogger()
+
+	slog.Info("starting stats-service...")
+
+	nc, err := nats.Connect(os.Getenv("NATS_URL"))
+	if err != nil {
+		return fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	defer nc.Close()
+
+	pool, err := pgxpool.New(ctx, os.Getenv("DATABASE_URL"))
+	if err != nil {
+		return fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+	defer pool.Close()
+
+	if err := event.Migrate(ctx, pool); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	repo := event.NewPostgreSQLEventRepository(pool)
+	ratings := internal.NewRatings(repo, rating.Config{})
+	stats := internal.NewStats(repo)
+	seasons := internal.NewSeasons(repo)
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	if err := stream.CreateStreams(ctx, js); err != nil {
+		return err
+	}
+
+	consumer, err := internal.NewMatchConsumer(ctx, js, ratings, stats)
+	if err != nil {
+		return err
+	}
+
+	if err := ratings.Load(ctx); err != nil {
+		return err
+	}
+	if err := stats.Load(ctx); err != nil {
+		return err
+	}
+
+	// The durable consumer resumes after the last match handled, so no
+	// match finalized while the service was down is missed.
+	go func() {
+		if err := consumer.Run(ctx); err != nil {
+			slog.Error("failed to consume matches", slog.Any("error", err))
+			cancel()
+		}
+	}()
+
+	srv, err := service.NewService(ctx, nc, service.Config{
+		Name:    "stats-service",
+		Version: "1.0.0",
+		Endpoints: map[string]func() micro.Handler{
+			"rating-leaderboard": func() micro.Handler { return internal.HandlerRatingLeaderboard(ratings) },
+			"rating-players":     func() micro.Handler { return internal.HandlerPlayerRatings(ratings) },
+			"stats-player":       func() micro.Handler { return internal.HandlerPlayerStats(stats) },
+			"season-standings":   func() micro.Handler { return internal.HandlerSeasonStandings(seasons) },
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+	slog.Info("context cancelled, shutting down stats-service...")
+
+	if err := srv.Stop(); err != nil {
+		slog.Error("failed to stop micro service", "error", err)
+	}
+
+	slog.Info("stats-service exited gracefully")
+
+	return nil
+}
@@ -0,0 +1,10 @@
+module github.com/ngoldack/dicetrace/apps/stats-service
+
+go 1.25.3
+
+require (
+	github.com/jackc/pgx/v5 v5.7.4
+	github.com/nats-io/nats.go v1.47.0
+	github.com/stretchr/testify v1.11.1
+	go.jetify.com/typeid/v2 v2.0.0-alpha.3
+)
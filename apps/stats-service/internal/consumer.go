@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/core/stream"
+)
+
+// matchesDurable names the durable consumer of match results.
+const matchesDurable = "stats-service-matches"
+
+// MatchFinalizedConsumer keeps derived state up to date with finalized
+// matches.
+type MatchFinalizedConsumer interface {
+	ApplyFinalized(ctx context.Context, finalized core.MatchFinalized) error
+	// ApplyCorrected updates the state derived from a finalized match whose
+	// result was corrected.
+	ApplyCorrected(ctx context.Context, corrected core.MatchFinalized) error
+}
+
+// NewMatchConsumer returns the durable consumer feeding every finalized and
+// corrected match to the consumers. Matches are delivered at least once, so
+// consumers ignore matches they already applied.
+func NewMatchConsumer(ctx context.Context, js jetstream.JetStream, consumers ...MatchFinalizedConsumer) (*stream.Consumer, error) {
+	processed, err := stream.NewKVProcessedLog(ctx, js, matchesDurable)
+	if err != nil {
+		return nil, err
+	}
+
+	return stream.NewConsumer(js, stream.ConsumerConfig{
+		Durable:     matchesDurable,
+		Description: "Finalized and corrected matches for ratings and statistics",
+		Types:       []core.DomainEventType{core.DomainEventMatchFinalized, core.DomainEventMatchCorrected},
+	}, processed, HandleMatchResult(consumers...)), nil
+}
+
+// HandleMatchResult returns the handler applying match.finalized and
+// match.corrected events to the consumers. Every consumer sees the match
+// even if another one fails; the event is redelivered if any failed.
+func HandleMatchResult(consumers ...MatchFinalizedConsumer) stream.Handler {
+	return func(ctx context.Context, evt *core.DomainEvent) error {
+		var result core.MatchFinalized
+		if err := evt.DecodePayload(&result); err != nil {
+			return fmt.Errorf("%w: %w", stream.ErrMalformedEvent, err)
+		}
+
+		errs := make([]error, 0, len(consumers))
+		for _, c := range consumers {
+			switch evt.Type {
+			case core.DomainEventMatchFinalized:
+				errs = append(errs, c.ApplyFinalized(ctx, result))
+			case core.DomainEventMatchCorrected:
+				errs = append(errs, c.ApplyCorrected(ctx, result))
+			default:
+				return fmt.Errorf("%w: unexpected type %s", stream.ErrMalformedEvent, evt.Type)
+			}
+		}
+
+		return errors.Join(errs...)
+	}
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/rating"
+)
+
+const (
+	ErrorBGGIDInvalid   = "bgg_id_invalid"
+	ErrorLimitInvalid   = "limit_invalid"
+	ErrorRequestInvalid = "request_invalid"
+)
+
+// PlayerRatingsRequest lists the users to look up the ratings of.
+type PlayerRatingsRequest struct {
+	UserIDs []core.UserID `json:"user_ids"`
+}
+
+// HandlerRatingLeaderboard returns the rating leaderboard of the game given
+// by the optional bgg_id header, or the overall leaderboard without it. The
+// optional limit header caps the number of entries.
+func HandlerRatingLeaderboard(ratings *Ratings) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		scope, ok := scopeFromHeader(r)
+		if !ok {
+			r.Error(ErrorBGGIDInvalid, "BGG ID is invalid", nil)
+			return
+		}
+
+		limit, ok := limitFromHeader(r)
+		if !ok {
+			r.Error(ErrorLimitInvalid, "limit is invalid", nil)
+			return
+		}
+
+		_ = r.RespondJSON(ratings.Leaderboard(scope, limit))
+	})
+}
+
+// HandlerPlayerRatings returns the ratings of the users listed by the
+// request body, a PlayerRatingsRequest, in the game given by the optional
+// bgg_id header, or overall without it. Users without a rating are left out.
+func HandlerPlayerRatings(ratings *Ratings) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		scope, ok := scopeFromHeader(r)
+		if !ok {
+			r.Error(ErrorBGGIDInvalid, "BGG ID is invalid", nil)
+			return
+		}
+
+		var req PlayerRatingsRequest
+		if err := json.Unmarshal(r.Data(), &req); err != nil {
+			r.Error(ErrorRequestInvalid, "failed to decode player ratings request", nil)
+			return
+		}
+
+		_ = r.RespondJSON(ratings.Players(scope, req.UserIDs))
+	})
+}
+
+// scopeFromHeader parses the optional bgg_id header. Without it, ratings are
+// overall.
+func scopeFromHeader(r micro.Request) (rating.Scope, bool) {
+	value := r.Headers().Get("bgg_id")
+	if value == "" {
+		return rating.Overall, true
+	}
+
+	bggID, err := strconv.Atoi(value)
+	if err != nil || bggID <= 0 {
+		return rating.Overall, false
+	}
+
+	return rating.BGGScope(bggID), true
+}
+
+// limitFromHeader parses the optional limit header. Zero means no limit.
+func limitFromHeader(r micro.Request) (int, bool) {
+	value := r.Headers().Get("limit")
+	if value == "" {
+		return 0, true
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return 0, false
+	}
+
+	return limit, true
+}
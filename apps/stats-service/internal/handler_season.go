@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"encoding/json"
+
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/package/core/service"
+	"github.com/ngoldack/dicetrace/package/season"
+)
+
+const ErrorSeasonInvalid = "season_invalid"
+
+// HandlerSeasonStandings returns the standings of the season given in the
+// request body, best first.
+func HandlerSeasonStandings(seasons *Seasons) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		ctx, cancel := service.RequestContext()
+		defer cancel()
+
+		var sn season.Season
+		if err := json.Unmarshal(r.Data(), &sn); err != nil {
+			r.Error(ErrorSeasonInvalid, "failed to decode season", nil)
+			return
+		}
+
+		standings, err := seasons.Standings(ctx, sn)
+		if err != nil {
+			service.RespondError(r, err, nil)
+			return
+		}
+
+		_ = r.RespondJSON(standings)
+	})
+}
@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/package/core/service"
+)
+
+const (
+	ErrorUserIDMissing = "user_id_missing"
+	ErrorNoStats       = "no_stats"
+)
+
+var statsErrorCodes = []service.ErrorCode{
+	{Err: ErrNoStats, Code: ErrorNoStats},
+}
+
+// HandlerPlayerStats returns the statistics of the player given by the
+// user_id header.
+func HandlerPlayerStats(s *Stats) micro.Handler {
+	return micro.HandlerFunc(func(r micro.Request) {
+		userID, ok := service.IDFromHeader(r, "user_id", "user")
+		if !ok {
+			r.Error(ErrorUserIDMissing, "user ID is missing or invalid", nil)
+			return
+		}
+
+		ps, err := s.Player(userID)
+		if err != nil {
+			service.RespondError(r, err, statsErrorCodes)
+			return
+		}
+
+		_ = r.RespondJSON(ps)
+	})
+}
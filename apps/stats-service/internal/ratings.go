@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/ngoldack/dicetrace/package/rating"
+)
+
+// Ratings serves the player ratings of all finalized matches. Ratings are
+// recomputed from the stored matches on start and when a match arrives out
+// of chronological order, and updated incrementally otherwise.
+type Ratings struct {
+	matches event.MatchRepository
+	cfg     rating.Config
+
+	mu     sync.RWMutex
+	engine *rating.Engine
+}
+
+var _ MatchFinalizedConsumer = (*Ratings)(nil)
+
+func NewRatings(matches event.MatchRepository, cfg rating.Config) *Ratings {
+	return &Ratings{
+		matches: matches,
+		cfg:     cfg,
+		engine:  rating.NewEngine(cfg),
+	}
+}
+
+// Load recomputes the ratings from all stored matches.
+func (r *Ratings) Load(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.recompute(ctx)
+}
+
+func (r *Ratings) recompute(ctx context.Context) error {
+	matches, err := r.matches.ListFinalizedMatches(ctx, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	engine, err := rating.Recompute(r.cfg, matches)
+	if err != nil {
+		return fmt.Errorf("failed to recompute ratings: %w", err)
+	}
+	r.engine = engine
+
+	return nil
+}
+
+// ApplyFinalized rates the finalized match. Matches that were already
+// rated are ignored.
+func (r *Ratings) ApplyFinalized(ctx context.Context, finalized core.MatchFinalized) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.engine.Apply(finalized.Match)
+	switch {
+	case errors.Is(err, core.ErrMatchApplied):
+		return nil
+	case errors.Is(err, core.ErrMatchOutOfOrder):
+		// The match-service stores matches before publishing them, so the
+		// full history includes this match.
+		slog.Info("recomputing ratings for out of order match", slog.String("match_id", finalized.Match.MatchID.String()))
+		return r.recompute(ctx)
+	default:
+		return err
+	}
+}
+
+// ApplyCorrected recomputes the ratings, as the corrected match may have been
+// followed by others it changes the outcome of.
+func (r *Ratings) ApplyCorrected(ctx context.Context, corrected core.MatchFinalized) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	slog.Info("recomputing ratings for corrected match", slog.String("match_id", corrected.Match.MatchID.String()))
+	return r.recompute(ctx)
+}
+
+// Players returns the ratings of the users in the scope. Users without a
+// rating are left out.
+func (r *Ratings) Players(scope rating.Scope, userIDs []core.UserID) []rating.PlayerRating {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ratings := make([]rating.PlayerRating, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if pr, ok := r.engine.Player(scope, userID); ok {
+			ratings = append(ratings, pr)
+		}
+	}
+	return ratings
+}
+
+// Leaderboard returns the best rated players of the scope. A positive
+// limit caps the number of entries.
+func (r *Ratings) Leaderboard(scope rating.Scope, limit int) []rating.PlayerRating {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.engine.Leaderboard(scope, limit)
+}
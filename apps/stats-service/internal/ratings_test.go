@@ -0,0 +1,179 @@
+package internal_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/apps/stats-service/internal"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/core/stream"
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/ngoldack/dicetrace/package/rating"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	azul  = core.Game{GameID: core.NewGameID(), BGGID: 230802, Name: "Azul"}
+	start = time.Date(2025, time.March, 6, 19, 0, 0, 0, time.UTC)
+)
+
+// mockRepository implements the parts of event.MatchRepository used by the
+// stats-service in memory
+type mockRepository struct {
+	event.MatchRepository
+
+	mu    sync.Mutex
+	event *core.Event
+	lists int
+}
+
+func newMockRepository() *mockRepository {
+	return &mockRepository{event: &core.Event{EventID: core.NewEventID(), Title: "Game Night"}}
+}
+
+func (r *mockRepository) ListFinalizedMatches(ctx context.Context, since time.Time) ([]core.Match, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lists++
+	matches := make([]core.Match, 0, len(r.event.Matches))
+	for _, m := range r.event.Matches {
+		if since.IsZero() || !m.PlayedAt().Before(since) {
+			matches = append(matches, m)
+		}
+	}
+	return core.Chronological(matches), nil
+}
+
+// store adds the match to the event and returns its finalized event.
+func (r *mockRepository) store(match core.Match) core.MatchFinalized {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.event.Matches = append(r.event.Matches, match)
+	return core.MatchFinalized{EventID: r.event.EventID, Match: match, Placements: match.Placements()}
+}
+
+func newPlayers(names ...string) []core.User {
+	players := make([]core.User, 0, len(names))
+	for _, name := range names {
+		players = append(players, core.User{UserID: core.NewUserID(), Username: name})
+	}
+	return players
+}
+
+func newFinalizedMatch(endedAt time.Time, players []core.User, values ...float64) core.Match {
+	scores := make([]core.Score, 0, len(players))
+	for i, p := range players {
+		scores = append(scores, core.Score{UserID: p.UserID, Value: values[i]})
+	}
+	return core.Match{
+		MatchID:    core.NewMatchID(),
+		Status:     core.MatchStatusFinalized,
+		Game:       azul,
+		Players:    players,
+		StartedAt:  endedAt.Add(-time.Hour),
+		EndedAt:    endedAt,
+		Scoreboard: core.Scoreboard{Scores: scores, ScoreUnit: core.ScoreUnitPoints},
+	}
+}
+
+func TestRatingsLoad(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob")
+	repo := newMockRepository()
+	repo.store(newFinalizedMatch(start, players, 61, 58))
+
+	ratings := internal.NewRatings(repo, rating.Config{})
+	require.NoError(t, ratings.Load(t.Context()))
+
+	board := ratings.Leaderboard(rating.BGGScope(azul.BGGID), 0)
+	require.Len(t, board, 2)
+	assert.Equal(t, players[0], board[0].User)
+}
+
+func TestRatingsPlayers(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob", "carol")
+	repo := newMockRepository()
+	repo.store(newFinalizedMatch(start, players[:2], 61, 58))
+
+	ratings := internal.NewRatings(repo, rating.Config{})
+	require.NoError(t, ratings.Load(t.Context()))
+
+	got := ratings.Players(rating.Overall, []core.UserID{players[1].UserID, players[2].UserID})
+	require.Len(t, got, 1, "unrated players are left out")
+	assert.Equal(t, players[1], got[0].User)
+	assert.Empty(t, ratings.Players(rating.BGGScope(1), []core.UserID{players[0].UserID}))
+}
+
+func TestRatingsApplyFinalized(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob")
+	repo := newMockRepository()
+	ratings := internal.NewRatings(repo, rating.Config{})
+	require.NoError(t, ratings.Load(t.Context()))
+
+	later := repo.store(newFinalizedMatch(start.Add(time.Hour), players, 3, 2))
+	require.NoError(t, ratings.ApplyFinalized(t.Context(), later))
+	require.NoError(t, ratings.ApplyFinalized(t.Context(), later), "redelivered matches are ignored")
+	assert.Equal(t, 1, repo.lists)
+
+	earlier := repo.store(newFinalizedMatch(start, players, 2, 3))
+	require.NoError(t, ratings.ApplyFinalized(t.Context(), earlier))
+	assert.Equal(t, 2, repo.lists, "out of order matches are recomputed")
+
+	board := ratings.Leaderboard(rating.Overall, 0)
+	require.Len(t, board, 2)
+	for _, pr := range board {
+		assert.Equal(t, 2, pr.Matches)
+		assert.Equal(t, 1, pr.Wins)
+	}
+}
+
+func TestRatingsApplyCorrected(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob")
+	repo := newMockRepository()
+	finalized := repo.store(newFinalizedMatch(start, players, 61, 58))
+
+	ratings := internal.NewRatings(repo, rating.Config{})
+	require.NoError(t, ratings.Load(t.Context()))
+
+	// bob's bonus was miscounted
+	corrected := finalized.Match
+	require.NoError(t, corrected.CorrectScore(core.Score{UserID: players[1].UserID, Value: 64}))
+	repo.event.Matches[0] = corrected
+	require.NoError(t, ratings.ApplyCorrected(t.Context(), core.MatchFinalized{EventID: finalized.EventID, Match: corrected, Placements: corrected.Placements()}))
+	assert.Equal(t, 2, repo.lists, "corrected matches are recomputed")
+
+	top := ratings.Leaderboard(rating.Overall, 1)
+	require.Len(t, top, 1)
+	assert.Equal(t, players[1], top[0].User)
+	assert.Equal(t, 1, top[0].Matches)
+}
+
+func TestHandleMatchResult(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob")
+	repo := newMockRepository()
+	ratings := internal.NewRatings(repo, rating.Config{})
+	handle := internal.HandleMatchResult(ratings)
+
+	finalized := repo.store(newFinalizedMatch(start, players, 10, 20))
+	evt, err := core.NewDomainEvent(core.DomainEventMatchFinalized, finalized.Match.MatchID, start, finalized)
+	require.NoError(t, err)
+	require.NoError(t, handle(t.Context(), evt))
+
+	top := ratings.Leaderboard(rating.Overall, 1)
+	require.Len(t, top, 1)
+	assert.Equal(t, players[1], top[0].User)
+
+	evt.Type = core.DomainEventMatchCorrected
+	require.NoError(t, handle(t.Context(), evt))
+	assert.Equal(t, 1, repo.lists, "corrected matches are recomputed")
+
+	evt.Payload = []byte("not json")
+	assert.ErrorIs(t, handle(t.Context(), evt), stream.ErrMalformedEvent)
+}
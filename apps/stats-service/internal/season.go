@@ -0,0 +1,33 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/ngoldack/dicetrace/package/season"
+)
+
+// Seasons computes season standings from the stored matches.
+type Seasons struct {
+	matches event.MatchRepository
+}
+
+func NewSeasons(matches event.MatchRepository) *Seasons {
+	return &Seasons{
+		matches: matches,
+	}
+}
+
+// Standings validates the season and ranks its players.
+func (s *Seasons) Standings(ctx context.Context, sn season.Season) ([]season.Standing, error) {
+	if err := sn.Validate(); err != nil {
+		return nil, err
+	}
+
+	matches, err := s.matches.ListFinalizedMatches(ctx, sn.StartsAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return sn.Standings(matches), nil
+}
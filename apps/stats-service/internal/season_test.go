@@ -0,0 +1,35 @@
+package internal_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/apps/stats-service/internal"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/season"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeasonsStandings(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob")
+	repo := newMockRepository()
+	repo.store(newFinalizedMatch(start, players, 10, 20))
+	repo.store(newFinalizedMatch(start.AddDate(1, 0, 0), players, 20, 10))
+
+	seasons := internal.NewSeasons(repo)
+	sn := season.Season{Name: "2025", StartsAt: start.AddDate(0, -1, 0), EndsAt: start.AddDate(0, 6, 0)}
+
+	standings, err := seasons.Standings(t.Context(), sn)
+	require.NoError(t, err)
+	require.Len(t, standings, 2)
+	assert.Equal(t, players[1], standings[0].User)
+	assert.InDelta(t, 3.0, standings[0].Points, 1e-9)
+
+	sn.EndsAt = time.Time{}
+	_, err = seasons.Standings(t.Context(), sn)
+	var verr *core.ValidationError
+	assert.True(t, errors.As(err, &verr))
+}
@@ -0,0 +1,100 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/ngoldack/dicetrace/package/stats"
+)
+
+// ErrNoStats is returned for players who did not finish any match.
+var ErrNoStats = errors.New("player has no statistics")
+
+// Stats serves the player statistics of all finalized matches. Like
+// Ratings, statistics are recomputed from the stored matches on start and
+// when a match arrives out of chronological order.
+type Stats struct {
+	matches event.MatchRepository
+
+	mu         sync.RWMutex
+	aggregator *stats.Aggregator
+}
+
+var _ MatchFinalizedConsumer = (*Stats)(nil)
+
+func NewStats(matches event.MatchRepository) *Stats {
+	return &Stats{
+		matches:    matches,
+		aggregator: stats.NewAggregator(),
+	}
+}
+
+// Load recomputes the statistics from all stored matches.
+func (s *Stats) Load(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.recompute(ctx)
+}
+
+func (s *Stats) recompute(ctx context.Context) error {
+	matches, err := s.matches.ListFinalizedMatches(ctx, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	aggregator, err := stats.Recompute(matches)
+	if err != nil {
+		return fmt.Errorf("failed to recompute statistics: %w", err)
+	}
+	s.aggregator = aggregator
+
+	return nil
+}
+
+// ApplyFinalized adds the finalized match to the statistics. Matches that
+// were already added are ignored.
+func (s *Stats) ApplyFinalized(ctx context.Context, finalized core.MatchFinalized) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.aggregator.Apply(finalized.Match)
+	switch {
+	case errors.Is(err, core.ErrMatchApplied):
+		return nil
+	case errors.Is(err, core.ErrMatchOutOfOrder):
+		slog.Info("recomputing statistics for out of order match", slog.String("match_id", finalized.Match.MatchID.String()))
+		return s.recompute(ctx)
+	default:
+		return err
+	}
+}
+
+// ApplyCorrected recomputes the statistics, as the corrected match may have been
+// followed by others it changes the outcome of.
+func (s *Stats) ApplyCorrected(ctx context.Context, corrected core.MatchFinalized) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slog.Info("recomputing statistics for corrected match", slog.String("match_id", corrected.Match.MatchID.String()))
+	return s.recompute(ctx)
+}
+
+// Player returns the player's statistics.
+func (s *Stats) Player(userID core.UserID) (stats.PlayerStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ps, ok := s.aggregator.Player(userID)
+	if !ok {
+		return stats.PlayerStats{}, fmt.Errorf("user '%s': %w", userID, ErrNoStats)
+	}
+
+	return ps, nil
+}
@@ -0,0 +1,34 @@
+package internal_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/apps/stats-service/internal"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob")
+	repo := newMockRepository()
+	repo.store(newFinalizedMatch(start.Add(time.Hour), players, 3, 2))
+
+	s := internal.NewStats(repo)
+	require.NoError(t, s.Load(t.Context()))
+
+	earlier := repo.store(newFinalizedMatch(start, players, 3, 2))
+	require.NoError(t, s.ApplyFinalized(t.Context(), earlier))
+	require.NoError(t, s.ApplyFinalized(t.Context(), earlier))
+	assert.Equal(t, 2, repo.lists, "out of order matches are recomputed")
+
+	alice, err := s.Player(players[0].UserID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, alice.Plays)
+	assert.Equal(t, 2, alice.CurrentStreak)
+
+	_, err = s.Player(core.NewUserID())
+	assert.ErrorIs(t, err, internal.ErrNoStats)
+}
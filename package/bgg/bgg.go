@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/kkjdaniel/gogeek/collection"
 	"github.com/kkjdaniel/gogeek/thing"
 	"github.com/kkjdaniel/gogeek/user"
 	"tailscale.com/util/singleflight"
@@ -14,19 +15,22 @@ import (
 type BGGService interface {
 	FetchThing(ctx context.Context, id int) (*thing.Item, error)
 	FetchUser(ctx context.Context, username string) (*user.User, error)
+	FetchCollection(ctx context.Context, username string) (*collection.Collection, error)
 }
 
 type bggServiceImpl struct {
-	cache   BGGCache
-	sfUser  singleflight.Group[string, *user.User]
-	sfThing singleflight.Group[int, *thing.Item]
+	cache        BGGCache
+	sfUser       singleflight.Group[string, *user.User]
+	sfThing      singleflight.Group[int, *thing.Item]
+	sfCollection singleflight.Group[string, *collection.Collection]
 }
 
 func NewBGGService(cache BGGCache) BGGService {
 	return &bggServiceImpl{
-		cache:   cache,
-		sfUser:  singleflight.Group[string, *user.User]{},
-		sfThing: singleflight.Group[int, *thing.Item]{},
+		cache:        cache,
+		sfUser:       singleflight.Group[string, *user.User]{},
+		sfThing:      singleflight.Group[int, *thing.Item]{},
+		sfCollection: singleflight.Group[string, *collection.Collection]{},
 	}
 }
 
@@ -105,3 +109,36 @@ func (s *bggServiceImpl) FetchUser(ctx context.Context, username string) (*user.
 
 	return u, nil
 }
+
+func (s *bggServiceImpl) FetchCollection(ctx context.Context, username string) (*collection.Collection, error) {
+	c, err := s.cache.GetCollection(ctx, username)
+	if err != nil && !errors.Is(err, ErrCacheMiss) {
+		return nil, fmt.Errorf("failed to get collection from cache: %w", err)
+	}
+
+	if c != nil {
+		// found in cache
+		slog.DebugContext(ctx, "collection found in cache; returning", slog.String("username", username))
+		return c, nil
+	}
+
+	res := <-s.sfCollection.DoChanContext(ctx, username, func(ctx context.Context) (*collection.Collection, error) {
+		col, err := collection.Query(username)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query collection from BGG API: %w", err)
+		}
+
+		slog.DebugContext(ctx, "collection fetched from BGG API", slog.String("username", username), slog.Int("items", len(col.Items)))
+		err = s.cache.SetCollection(ctx, username, col)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set collection in cache: %w", err)
+		}
+
+		return col, nil
+	})
+	if res.Err != nil {
+		return nil, fmt.Errorf("failed to fetch collection of '%s': %w", username, res.Err)
+	}
+
+	return res.Val, nil
+}
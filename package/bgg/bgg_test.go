@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/kkjdaniel/gogeek/collection"
 	"github.com/kkjdaniel/gogeek/thing"
 	"github.com/kkjdaniel/gogeek/user"
 	"github.com/ngoldack/dicetrace/package/bgg"
@@ -14,18 +15,22 @@ import (
 
 // mockCache implements bgg.BGGCache for unit testing
 type mockCache struct {
-	things      map[int]*thing.Item
-	users       map[string]*user.User
-	getThingErr error
-	setThingErr error
-	getUserErr  error
-	setUserErr  error
+	things           map[int]*thing.Item
+	users            map[string]*user.User
+	collections      map[string]*collection.Collection
+	getThingErr      error
+	setThingErr      error
+	getUserErr       error
+	setUserErr       error
+	getCollectionErr error
+	setCollectionErr error
 }
 
 func newMockCache() *mockCache {
 	return &mockCache{
-		things: make(map[int]*thing.Item),
-		users:  make(map[string]*user.User),
+		things:      make(map[int]*thing.Item),
+		users:       make(map[string]*user.User),
+		collections: make(map[string]*collection.Collection),
 	}
 }
 
@@ -65,6 +70,24 @@ func (m *mockCache) SetUser(ctx context.Context, usr *user.User) error {
 	return nil
 }
 
+func (m *mockCache) GetCollection(ctx context.Context, username string) (*collection.Collection, error) {
+	if m.getCollectionErr != nil {
+		return nil, m.getCollectionErr
+	}
+	if c, ok := m.collections[username]; ok {
+		return c, nil
+	}
+	return nil, nil
+}
+
+func (m *mockCache) SetCollection(ctx context.Context, username string, c *collection.Collection) error {
+	if m.setCollectionErr != nil {
+		return m.setCollectionErr
+	}
+	m.collections[username] = c
+	return nil
+}
+
 func TestBGGService_FetchThing_CacheHit(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -133,6 +156,38 @@ func TestBGGService_FetchUser_CacheError(t *testing.T) {
 	assert.ErrorContains(t, err, "failed to get user from cache")
 }
 
+func TestBGGService_FetchCollection_CacheHit(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	cache := newMockCache()
+	service := bgg.NewBGGService(cache)
+
+	// Pre-populate cache with a collection
+	expectedCollection := &collection.Collection{TotalItems: 1, Items: []collection.CollectionItem{{ObjectID: 42}}}
+	cache.collections["testuser"] = expectedCollection
+
+	// Fetch should return cached collection
+	result, err := service.FetchCollection(ctx, "testuser")
+	require.NoError(t, err)
+	assert.Equal(t, expectedCollection, result)
+}
+
+func TestBGGService_FetchCollection_CacheError(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	cache := newMockCache()
+	service := bgg.NewBGGService(cache)
+
+	// Simulate cache error (but not a cache miss)
+	cache.getCollectionErr = errors.New("redis connection failed")
+
+	// Should return the cache error
+	result, err := service.FetchCollection(ctx, "testuser")
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.ErrorContains(t, err, "failed to get collection from cache")
+}
+
 func TestBGGService_NewBGGService(t *testing.T) {
 	t.Parallel()
 	cache := newMockCache()
@@ -141,5 +196,5 @@ func TestBGGService_NewBGGService(t *testing.T) {
 }
 
 // Note: Testing cache miss scenarios with the real BGG API requires integration tests
-// The current implementation calls thing.Query() and user.Query() which are external API calls
+// The current implementation calls thing.Query(), user.Query() and collection.Query() which are external API calls
 // For those scenarios, see cache_integration_test.go
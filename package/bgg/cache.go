@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/kkjdaniel/gogeek/collection"
 	"github.com/kkjdaniel/gogeek/thing"
 	"github.com/kkjdaniel/gogeek/user"
 	"github.com/redis/go-redis/v9"
@@ -18,6 +19,10 @@ const (
 
 	userCacheTTL    = 24 * time.Hour
 	userCachePrefix = "bgg:user:"
+
+	// collections change more often than games and users
+	collectionCacheTTL    = 6 * time.Hour
+	collectionCachePrefix = "bgg:collection:"
 )
 
 var ErrCacheMiss = fmt.Errorf("cache miss")
@@ -29,6 +34,9 @@ type BGGCache interface {
 
 	GetUser(ctx context.Context, username string) (*user.User, error)
 	SetUser(ctx context.Context, user *user.User) error
+
+	GetCollection(ctx context.Context, username string) (*collection.Collection, error)
+	SetCollection(ctx context.Context, username string, c *collection.Collection) error
 }
 
 type RedisBGGCache struct {
@@ -114,3 +122,40 @@ func (c *RedisBGGCache) SetUser(ctx context.Context, usr *user.User) error {
 func generateUserCacheKey(username string) string {
 	return userCachePrefix + username
 }
+
+func (c *RedisBGGCache) GetCollection(ctx context.Context, username string) (*collection.Collection, error) {
+	key := generateCollectionCacheKey(username)
+	data, err := c.rc.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, fmt.Errorf("collection '%s' cache miss: %w", username, errors.Join(ErrCacheMiss, err))
+	} else if err != nil {
+		return nil, err
+	}
+
+	var col collection.Collection
+	err = json.Unmarshal(data, &col)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal collection cache data: %w", err)
+	}
+
+	return &col, nil
+}
+
+func (c *RedisBGGCache) SetCollection(ctx context.Context, username string, col *collection.Collection) error {
+	key := generateCollectionCacheKey(username)
+	data, err := json.Marshal(col)
+	if err != nil {
+		return fmt.Errorf("failed to marshal collection cache data: %w", err)
+	}
+
+	err = c.rc.Set(ctx, key, data, collectionCacheTTL).Err()
+	if err != nil {
+		return fmt.Errorf("failed to set collection cache: %w", err)
+	}
+
+	return nil
+}
+
+func generateCollectionCacheKey(username string) string {
+	return collectionCachePrefix + username
+}
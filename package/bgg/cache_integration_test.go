@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kkjdaniel/gogeek/collection"
 	"github.com/kkjdaniel/gogeek/thing"
 	"github.com/kkjdaniel/gogeek/user"
 	"github.com/ngoldack/dicetrace/package/bgg"
@@ -228,6 +229,45 @@ func TestRedisBGGCache_User_GetMiss(t *testing.T) {
 	assert.True(t, errors.Is(err, bgg.ErrCacheMiss), "error should be ErrCacheMiss")
 }
 
+func TestRedisBGGCache_Collection_SetAndGet(t *testing.T) {
+	t.Parallel()
+	client := setupTestRedisClient(t)
+
+	cache := bgg.NewRedisBGGCache(client)
+	ctx := context.Background()
+
+	// Create test data
+	testCollection := &collection.Collection{
+		TotalItems: 2,
+		Items:      []collection.CollectionItem{{ObjectID: 822}, {ObjectID: 230802}},
+	}
+
+	// Test SetCollection
+	err := cache.SetCollection(ctx, "testuser", testCollection)
+	require.NoError(t, err, "SetCollection should not return an error")
+
+	// Test GetCollection
+	retrieved, err := cache.GetCollection(ctx, "testuser")
+	require.NoError(t, err, "GetCollection should not return an error")
+	assert.NotNil(t, retrieved, "retrieved collection should not be nil")
+	assert.Equal(t, testCollection.TotalItems, retrieved.TotalItems, "total items should match")
+	assert.Len(t, retrieved.Items, 2, "items should match")
+}
+
+func TestRedisBGGCache_Collection_GetMiss(t *testing.T) {
+	t.Parallel()
+	client := setupTestRedisClient(t)
+
+	cache := bgg.NewRedisBGGCache(client)
+	ctx := context.Background()
+
+	// Test GetCollection with non-existent username
+	retrieved, err := cache.GetCollection(ctx, "nonexistentuser")
+	assert.Error(t, err, "GetCollection should return an error for cache miss")
+	assert.Nil(t, retrieved, "retrieved collection should be nil on cache miss")
+	assert.True(t, errors.Is(err, bgg.ErrCacheMiss), "error should be ErrCacheMiss")
+}
+
 func TestRedisBGGCache_Thing_Overwrite(t *testing.T) {
 	t.Parallel()
 	client := setupTestRedisClient(t)
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// breakdownTolerance absorbs floating point error when summing score lines.
+const breakdownTolerance = 1e-9
+
+// Duration is how long the match took, or 0 if its start or end is unknown.
+func (m *Match) Duration() time.Duration {
+	if m.StartedAt.IsZero() || m.EndedAt.IsZero() {
+		return 0
+	}
+	return m.EndedAt.Sub(m.StartedAt)
+}
+
+// Line returns the value the score sheet records for the label.
+func (s Score) Line(label string) (float64, bool) {
+	return lineValue(s.Breakdown, label)
+}
+
+// Line returns the value the team's score sheet records for the label.
+func (s TeamScore) Line(label string) (float64, bool) {
+	return lineValue(s.Breakdown, label)
+}
+
+// Labels returns the rounds or categories used by any score sheet of the
+// scoreboard, in order of first appearance.
+func (s *Scoreboard) Labels() []string {
+	labels := make([]string, 0)
+	seen := make(map[string]bool)
+
+	add := func(breakdown []ScoreLine) {
+		for _, line := range breakdown {
+			if !seen[line.Label] {
+				seen[line.Label] = true
+				labels = append(labels, line.Label)
+			}
+		}
+	}
+
+	for _, score := range s.Scores {
+		add(score.Breakdown)
+	}
+	for _, score := range s.TeamScores {
+		add(score.Breakdown)
+	}
+
+	return labels
+}
+
+func lineValue(breakdown []ScoreLine, label string) (float64, bool) {
+	for _, line := range breakdown {
+		if line.Label == label {
+			return line.Value, true
+		}
+	}
+	return 0, false
+}
+
+// validateBreakdown checks that the score lines are labelled uniquely and
+// sum to the score's value. Outcomes and ranks cannot be broken down.
+func (s *Scoreboard) validateBreakdown(verr *ValidationError, field string, value float64, breakdown []ScoreLine) {
+	if s.ScoreUnit.Outcome() || s.ScoreUnit == ScoreUnitRank {
+		verr.Add(field+".breakdown", "score unit '%s' cannot be broken down", s.ScoreUnit)
+		return
+	}
+
+	labels := make(map[string]int, len(breakdown))
+	sum := 0.0
+	valid := true
+
+	for i, line := range breakdown {
+		lineField := fmt.Sprintf("%s.breakdown[%d]", field, i)
+
+		if strings.TrimSpace(line.Label) == "" {
+			verr.Add(lineField+".label", "is required")
+		} else if first, ok := labels[line.Label]; ok {
+			verr.Add(lineField+".label", "'%s' is already listed at breakdown[%d]", line.Label, first)
+		} else {
+			labels[line.Label] = i
+		}
+
+		if !finite(line.Value) {
+			verr.Add(lineField+".value", "must be a finite number")
+			valid = false
+			continue
+		}
+		sum += line.Value
+	}
+
+	if valid && finite(value) && math.Abs(sum-value) > breakdownTolerance*max(1, math.Abs(value)) {
+		verr.Add(field+".breakdown", "lines sum to %g, but the score is %g", sum, value)
+	}
+}
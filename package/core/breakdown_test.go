@@ -0,0 +1,228 @@
+package core_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sevenWondersSheet splits 46 points into the 7 Wonders scoring categories.
+func sevenWondersSheet() []core.ScoreLine {
+	return []core.ScoreLine{
+		{Label: "Military", Value: 9},
+		{Label: "Treasury", Value: 4},
+		{Label: "Wonder", Value: 10},
+		{Label: "Civilian", Value: 12},
+		{Label: "Science", Value: 11},
+	}
+}
+
+func TestMatchTimingValidation(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, time.March, 6, 19, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		startedAt time.Time
+		endedAt   time.Time
+		fields    []string
+	}{
+		{name: "untimed"},
+		{name: "started only", startedAt: start},
+		{name: "started and ended", startedAt: start, endedAt: start.Add(45 * time.Minute)},
+		{name: "ended without start", endedAt: start, fields: []string{"ended_at"}},
+		{name: "ended before start", startedAt: start, endedAt: start.Add(-time.Minute), fields: []string{"ended_at"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			m := newValidMatch()
+			m.StartedAt, m.EndedAt = tc.startedAt, tc.endedAt
+
+			err := m.Validate()
+			if len(tc.fields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var verr *core.ValidationError
+			require.ErrorAs(t, err, &verr)
+
+			fields := make([]string, 0, len(verr.Errors))
+			for _, fe := range verr.Errors {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, tc.fields, fields)
+		})
+	}
+}
+
+func TestMatchDuration(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, time.March, 6, 19, 30, 0, 0, time.UTC)
+
+	m := newValidMatch()
+	assert.Zero(t, m.Duration())
+
+	m.StartedAt = start
+	assert.Zero(t, m.Duration())
+
+	m.EndedAt = start.Add(95 * time.Minute)
+	assert.Equal(t, 95*time.Minute, m.Duration())
+}
+
+func TestScoreBreakdownValidation(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		unit   core.ScoreUnit
+		value  float64
+		lines  []core.ScoreLine
+		fields []string
+	}{
+		{name: "categories", unit: core.ScoreUnitPoints, value: 46, lines: sevenWondersSheet()},
+		{
+			name:  "rounds with fractions",
+			unit:  core.ScoreUnitPoints,
+			value: 0.3,
+			lines: []core.ScoreLine{{Label: "Round 1", Value: 0.1}, {Label: "Round 2", Value: 0.2}},
+		},
+		{
+			name:  "negative round",
+			unit:  core.ScoreUnitPoints,
+			value: 5,
+			lines: []core.ScoreLine{{Label: "Round 1", Value: 8}, {Label: "Round 2", Value: -3}},
+		},
+		{
+			name:  "timed rounds",
+			unit:  core.ScoreUnitTime,
+			value: 300,
+			lines: []core.ScoreLine{{Label: "Lap 1", Value: 140}, {Label: "Lap 2", Value: 160}},
+		},
+		{
+			name:   "sum mismatch",
+			unit:   core.ScoreUnitPoints,
+			value:  50,
+			lines:  sevenWondersSheet(),
+			fields: []string{"scoreboard.scores[0].breakdown"},
+		},
+		{
+			name:   "missing label",
+			unit:   core.ScoreUnitPoints,
+			value:  3,
+			lines:  []core.ScoreLine{{Label: "Round 1", Value: 1}, {Value: 2}},
+			fields: []string{"scoreboard.scores[0].breakdown[1].label"},
+		},
+		{
+			name:   "duplicate label",
+			unit:   core.ScoreUnitPoints,
+			value:  3,
+			lines:  []core.ScoreLine{{Label: "Science", Value: 1}, {Label: "Science", Value: 2}},
+			fields: []string{"scoreboard.scores[0].breakdown[1].label"},
+		},
+		{
+			name:   "non-finite line",
+			unit:   core.ScoreUnitPoints,
+			value:  3,
+			lines:  []core.ScoreLine{{Label: "Round 1", Value: math.Inf(1)}},
+			fields: []string{"scoreboard.scores[0].breakdown[0].value"},
+		},
+		{
+			name:   "outcome unit",
+			unit:   core.ScoreUnitWinLoss,
+			value:  core.ScoreWin,
+			lines:  []core.ScoreLine{{Label: "Round 1", Value: 1}},
+			fields: []string{"scoreboard.scores[0].breakdown"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			m := newMatchWithScores(tc.unit, "", tc.value)
+			m.Scoreboard.Scores[0].Breakdown = tc.lines
+
+			err := m.Validate()
+			if len(tc.fields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var verr *core.ValidationError
+			require.ErrorAs(t, err, &verr)
+
+			fields := make([]string, 0, len(verr.Errors))
+			for _, fe := range verr.Errors {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, tc.fields, fields)
+		})
+	}
+}
+
+func TestTeamScoreBreakdownValidation(t *testing.T) {
+	t.Parallel()
+	m := newTeamMatch()
+	m.Scoreboard.TeamScores[1].Breakdown = []core.ScoreLine{{Label: "Round 1", Value: 2}, {Label: "Round 2", Value: 4}}
+
+	var verr *core.ValidationError
+	require.ErrorAs(t, m.Validate(), &verr)
+	require.Len(t, verr.Errors, 1)
+	assert.Equal(t, "scoreboard.team_scores[1].breakdown", verr.Errors[0].Field)
+
+	m.Scoreboard.TeamScores[1].Breakdown[1].Value = 5
+	assert.NoError(t, m.Validate())
+}
+
+func TestScoreboardLabels(t *testing.T) {
+	t.Parallel()
+	m := newMatchWithScores(core.ScoreUnitPoints, "", 46, 7)
+	m.Scoreboard.Scores[0].Breakdown = sevenWondersSheet()
+	m.Scoreboard.Scores[1].Breakdown = []core.ScoreLine{{Label: "Science", Value: 4}, {Label: "Guilds", Value: 3}}
+	require.NoError(t, m.Validate())
+
+	assert.Equal(t, []string{"Military", "Treasury", "Wonder", "Civilian", "Science", "Guilds"}, m.Scoreboard.Labels())
+
+	science, ok := m.Scoreboard.Scores[1].Line("Science")
+	require.True(t, ok)
+	assert.InDelta(t, 4.0, science, 0)
+
+	_, ok = m.Scoreboard.Scores[0].Line("Guilds")
+	assert.False(t, ok)
+}
+
+func TestEncodeMatchRejectsBreakdownMismatch(t *testing.T) {
+	t.Parallel()
+	m := newMatchWithScores(core.ScoreUnitPoints, "", 40)
+	m.Scoreboard.Scores[0].Breakdown = sevenWondersSheet()
+
+	var buf bytes.Buffer
+	var verr *core.ValidationError
+	require.ErrorAs(t, core.EncodeMatch(&buf, m), &verr)
+	assert.Zero(t, buf.Len())
+}
+
+func TestEncodeDecodeTimedMatch(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, time.March, 6, 19, 30, 0, 0, time.UTC)
+
+	original := newMatchWithScores(core.ScoreUnitPoints, "", 46)
+	original.StartedAt = start
+	original.EndedAt = start.Add(40 * time.Minute)
+	original.Scoreboard.Scores[0].Breakdown = sevenWondersSheet()
+
+	var buf bytes.Buffer
+	require.NoError(t, core.EncodeMatch(&buf, original))
+	assert.Contains(t, buf.String(), `"started_at":"2025-03-06T19:30:00Z"`)
+
+	decoded, err := core.DecodeMatch(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, original, decoded)
+	assert.Equal(t, 40*time.Minute, decoded.Duration())
+}
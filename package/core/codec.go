@@ -0,0 +1,484 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"mime"
+	"sync"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// Content types of the built-in codecs.
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeMsgpack  = "application/msgpack"
+)
+
+var (
+	// ErrUnsupportedContentType is returned for content types without a
+	// registered codec.
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+	// ErrUnsupportedValue is returned when a codec cannot represent a value,
+	// e.g. protobuf for values without a protobuf message.
+	ErrUnsupportedValue = errors.New("value not supported by codec")
+	// ErrMessageTooLarge is returned for protobuf array elements exceeding
+	// MaxProtobufMessageSize.
+	ErrMessageTooLarge = errors.New("message too large")
+)
+
+// Codec encodes and decodes values in a single content type. Arrays are
+// encoded element by element, so bulk payloads can be decoded while they
+// are read.
+type Codec interface {
+	ContentType() string
+	Encode(w io.Writer, v any) error
+	Decode(r io.Reader, v any) error
+	NewArrayEncoder(w io.Writer) ArrayEncoder
+	NewArrayDecoder(r io.Reader) ArrayDecoder
+}
+
+// ArrayEncoder writes the elements of an array. Close completes the array,
+// which is empty if no element was encoded.
+type ArrayEncoder interface {
+	Encode(v any) error
+	Close() error
+}
+
+// ArrayDecoder reads the elements of an array. Next returns io.EOF after the
+// last element.
+type ArrayDecoder interface {
+	Next(v any) error
+}
+
+// ProtoMarshaler is implemented by values encoded as a protobuf message.
+type ProtoMarshaler interface {
+	MarshalProto() ([]byte, error)
+}
+
+// ProtoUnmarshaler is implemented by values decoded from a protobuf message.
+type ProtoUnmarshaler interface {
+	UnmarshalProto(data []byte) error
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{
+		ContentTypeJSON:     JSONCodec{},
+		ContentTypeProtobuf: ProtobufCodec{},
+		ContentTypeMsgpack:  MsgpackCodec{},
+	}
+)
+
+// RegisterCodec makes the codec available for its content type, replacing
+// any codec registered before.
+func RegisterCodec(codec Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[codec.ContentType()] = codec
+}
+
+// CodecFor returns the codec of the content type. Media type parameters,
+// like a charset, are ignored and an empty content type selects JSON.
+func CodecFor(contentType string) (Codec, error) {
+	mediaType := ContentTypeJSON
+	if contentType != "" {
+		var err error
+		mediaType, _, err = mime.ParseMediaType(contentType)
+		if err != nil {
+			return nil, fmt.Errorf("content type '%s': %w", contentType, ErrUnsupportedContentType)
+		}
+	}
+
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	codec, ok := codecs[mediaType]
+	if !ok {
+		return nil, fmt.Errorf("content type '%s': %w", contentType, ErrUnsupportedContentType)
+	}
+	return codec, nil
+}
+
+// validator and localizer are implemented by values checked when they are
+// encoded or decoded, like Event and Match.
+type validator interface {
+	Validate() error
+}
+
+type localizer interface {
+	Localize() error
+}
+
+// Encode writes the value in the content type. Values with a Validate
+// method are validated first.
+func Encode[T any](w io.Writer, contentType string, v *T) error {
+	codec, err := CodecFor(contentType)
+	if err != nil {
+		return err
+	}
+	if err := validate(v); err != nil {
+		return err
+	}
+	return codec.Encode(w, v)
+}
+
+// Decode reads a value in the content type. Values with a Validate method
+// are validated and values with a Localize method are localized.
+func Decode[T any](r io.Reader, contentType string) (*T, error) {
+	codec, err := CodecFor(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	var v T
+	if err := codec.Decode(r, &v); err != nil {
+		return nil, err
+	}
+	if err := prepare(&v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// EncodeArray writes the values as an array in the content type, checking
+// every value like Encode.
+func EncodeArray[T any](w io.Writer, contentType string, values []T) error {
+	codec, err := CodecFor(contentType)
+	if err != nil {
+		return err
+	}
+
+	enc := codec.NewArrayEncoder(w)
+	for i := range values {
+		if err := validate(&values[i]); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+		if err := enc.Encode(&values[i]); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	return enc.Close()
+}
+
+// DecodeArray reads an array in the content type one element at a time,
+// checking every element like Decode. Iteration stops after the first
+// error.
+func DecodeArray[T any](r io.Reader, contentType string) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		codec, err := CodecFor(contentType)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		dec := codec.NewArrayDecoder(r)
+		for i := 0; ; i++ {
+			var v T
+			err := dec.Next(&v)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err == nil {
+				err = prepare(&v)
+			}
+			if err != nil {
+				yield(nil, fmt.Errorf("element %d: %w", i, err))
+				return
+			}
+			if !yield(&v, nil) {
+				return
+			}
+		}
+	}
+}
+
+func validate(v any) error {
+	if v, ok := v.(validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// prepare validates and localizes a decoded value.
+func prepare(v any) error {
+	if err := validate(v); err != nil {
+		return err
+	}
+	if v, ok := v.(localizer); ok {
+		return v.Localize()
+	}
+	return nil
+}
+
+// JSONCodec encodes values as JSON, one value per line. Arrays are JSON
+// arrays.
+type JSONCodec struct{}
+
+func (JSONCodec) ContentType() string { return ContentTypeJSON }
+
+func (JSONCodec) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+func (JSONCodec) Decode(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+func (JSONCodec) NewArrayEncoder(w io.Writer) ArrayEncoder {
+	return &jsonArrayEncoder{w: w}
+}
+
+func (JSONCodec) NewArrayDecoder(r io.Reader) ArrayDecoder {
+	return &jsonArrayDecoder{dec: json.NewDecoder(r)}
+}
+
+type jsonArrayEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonArrayEncoder) Encode(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	sep := []byte{','}
+	if e.count == 0 {
+		sep = []byte{'['}
+	}
+	if _, err := e.w.Write(append(sep, data...)); err != nil {
+		return err
+	}
+	e.count++
+	return nil
+}
+
+func (e *jsonArrayEncoder) Close() error {
+	end := "]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+type jsonArrayDecoder struct {
+	dec     *json.Decoder
+	started bool
+}
+
+func (d *jsonArrayDecoder) Next(v any) error {
+	if !d.started {
+		tok, err := d.dec.Token()
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		} else if err != nil {
+			return err
+		}
+		if tok != json.Delim('[') {
+			return fmt.Errorf("expected array, got %v", tok)
+		}
+		d.started = true
+	}
+
+	if !d.dec.More() {
+		if _, err := d.dec.Token(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	return d.dec.Decode(v)
+}
+
+// MsgpackCodec encodes values as MessagePack. Structs are encoded as maps
+// keyed by their JSON field names.
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) ContentType() string { return ContentTypeMsgpack }
+
+func (MsgpackCodec) Encode(w io.Writer, v any) error {
+	return newMsgpackEncoder(w).Encode(v)
+}
+
+func (MsgpackCodec) Decode(r io.Reader, v any) error {
+	return newMsgpackDecoder(r).Decode(v)
+}
+
+func (MsgpackCodec) NewArrayEncoder(w io.Writer) ArrayEncoder {
+	return &msgpackArrayEncoder{w: w}
+}
+
+func (MsgpackCodec) NewArrayDecoder(r io.Reader) ArrayDecoder {
+	return &msgpackArrayDecoder{dec: newMsgpackDecoder(r), remaining: -1}
+}
+
+func newMsgpackEncoder(w io.Writer) *msgpack.Encoder {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	return enc
+}
+
+func newMsgpackDecoder(r io.Reader) *msgpack.Decoder {
+	dec := msgpack.NewDecoder(r)
+	dec.SetCustomStructTag("json")
+	return dec
+}
+
+// msgpackArrayEncoder buffers the encoded elements, as MessagePack arrays
+// are prefixed with their length.
+type msgpackArrayEncoder struct {
+	w        io.Writer
+	elements [][]byte
+}
+
+func (e *msgpackArrayEncoder) Encode(v any) error {
+	var buf bytes.Buffer
+	if err := newMsgpackEncoder(&buf).Encode(v); err != nil {
+		return err
+	}
+	e.elements = append(e.elements, buf.Bytes())
+	return nil
+}
+
+func (e *msgpackArrayEncoder) Close() error {
+	if err := msgpack.NewEncoder(e.w).EncodeArrayLen(len(e.elements)); err != nil {
+		return err
+	}
+	for _, data := range e.elements {
+		if _, err := e.w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type msgpackArrayDecoder struct {
+	dec       *msgpack.Decoder
+	remaining int
+}
+
+func (d *msgpackArrayDecoder) Next(v any) error {
+	if d.remaining == -1 {
+		n, err := d.dec.DecodeArrayLen()
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		} else if err != nil {
+			return err
+		}
+		d.remaining = max(n, 0)
+	}
+
+	if d.remaining == 0 {
+		return io.EOF
+	}
+	d.remaining--
+	return d.dec.Decode(v)
+}
+
+// ProtobufCodec encodes values as protobuf messages. Values are either
+// messages themselves or implement ProtoMarshaler and ProtoUnmarshaler.
+// Arrays are sequences of messages, each prefixed with its varint encoded
+// length.
+type ProtobufCodec struct{}
+
+func (ProtobufCodec) ContentType() string { return ContentTypeProtobuf }
+
+func (ProtobufCodec) Encode(w io.Writer, v any) error {
+	data, err := marshalProto(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (ProtobufCodec) Decode(r io.Reader, v any) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return unmarshalProto(data, v)
+}
+
+func (ProtobufCodec) NewArrayEncoder(w io.Writer) ArrayEncoder {
+	return &protobufArrayEncoder{w: w}
+}
+
+func (ProtobufCodec) NewArrayDecoder(r io.Reader) ArrayDecoder {
+	return &protobufArrayDecoder{r: bufio.NewReader(r)}
+}
+
+func marshalProto(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case proto.Message:
+		return proto.Marshal(v)
+	case ProtoMarshaler:
+		return v.MarshalProto()
+	default:
+		return nil, fmt.Errorf("%T: %w", v, ErrUnsupportedValue)
+	}
+}
+
+func unmarshalProto(data []byte, v any) error {
+	switch v := v.(type) {
+	case proto.Message:
+		return proto.Unmarshal(data, v)
+	case ProtoUnmarshaler:
+		return v.UnmarshalProto(data)
+	default:
+		return fmt.Errorf("%T: %w", v, ErrUnsupportedValue)
+	}
+}
+
+type protobufArrayEncoder struct {
+	w io.Writer
+}
+
+func (e *protobufArrayEncoder) Encode(v any) error {
+	data, err := marshalProto(v)
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxProtobufMessageSize {
+		return fmt.Errorf("element of %d bytes: %w", len(data), ErrMessageTooLarge)
+	}
+	_, err = e.w.Write(append(binary.AppendUvarint(nil, uint64(len(data))), data...))
+	return err
+}
+
+func (e *protobufArrayEncoder) Close() error { return nil }
+
+// MaxProtobufMessageSize bounds the length of a single element in a protobuf
+// array, so a corrupt or hostile length prefix cannot allocate unbounded
+// memory.
+const MaxProtobufMessageSize = 4 << 20
+
+type protobufArrayDecoder struct {
+	r *bufio.Reader
+}
+
+func (d *protobufArrayDecoder) Next(v any) error {
+	// io.EOF is returned only if no byte of the length was read
+	size, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return err
+	}
+	if size > MaxProtobufMessageSize {
+		return fmt.Errorf("element of %d bytes: %w", size, ErrMessageTooLarge)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(d.r, data); err != nil {
+		return err
+	}
+	return unmarshalProto(data, v)
+}
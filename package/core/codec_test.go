@@ -0,0 +1,236 @@
+package core_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func newTestMatch() core.Match {
+	alice, bob := newPlayer("alice"), newPlayer("bob")
+	return core.Match{
+		MatchID: core.NewMatchID(),
+		Game:    core.Game{GameID: core.NewGameID(), BGGID: 230802, Name: "Azul", Categories: []string{"Abstract"}},
+		Players: []core.User{alice, bob},
+		Scoreboard: core.Scoreboard{
+			Scores:    []core.Score{{UserID: alice.UserID, Value: 61}, {UserID: bob.UserID, Value: 58}},
+			ScoreUnit: core.ScoreUnitPoints,
+		},
+		Status:    core.MatchStatusFinalized,
+		StartedAt: matchStart,
+		EndedAt:   matchStart.Add(time.Hour),
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	t.Parallel()
+	for _, contentType := range []string{core.ContentTypeJSON, core.ContentTypeMsgpack, "application/json; charset=utf-8", ""} {
+		t.Run(contentType, func(t *testing.T) {
+			t.Parallel()
+			original := newTestMatch()
+
+			var buf bytes.Buffer
+			require.NoError(t, core.Encode(&buf, contentType, &original))
+
+			decoded, err := core.Decode[core.Match](&buf, contentType)
+			require.NoError(t, err)
+			assert.Equal(t, original.MatchID, decoded.MatchID)
+			assert.Equal(t, original.Players, decoded.Players)
+			assert.Equal(t, original.Scoreboard, decoded.Scoreboard)
+			assert.True(t, original.EndedAt.Equal(decoded.EndedAt))
+		})
+	}
+}
+
+func TestEncodeDecodeEventMsgpack(t *testing.T) {
+	t.Parallel()
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	original := &core.Event{
+		EventID:   core.NewEventID(),
+		Status:    core.EventStatusScheduled,
+		StartsAt:  time.Date(2025, time.March, 6, 19, 0, 0, 0, berlin),
+		Timezone:  "Europe/Berlin",
+		Attendees: []core.Attendee{{User: newPlayer("host"), Status: core.AttendeeStatusConfirmed}},
+		Matches:   []core.Match{},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, core.Encode(&buf, core.ContentTypeMsgpack, original))
+
+	decoded, err := core.Decode[core.Event](&buf, core.ContentTypeMsgpack)
+	require.NoError(t, err)
+	assert.Equal(t, original.Attendees, decoded.Attendees)
+	assert.True(t, original.StartsAt.Equal(decoded.StartsAt))
+	assert.Equal(t, "Europe/Berlin", decoded.StartsAt.Location().String())
+}
+
+func TestEncodeDecodeValidates(t *testing.T) {
+	t.Parallel()
+	invalid := newTestMatch()
+	invalid.Scoreboard.Scores = invalid.Scoreboard.Scores[:1]
+
+	var buf bytes.Buffer
+	var verr *core.ValidationError
+	require.ErrorAs(t, core.Encode(&buf, core.ContentTypeMsgpack, &invalid), &verr)
+	assert.Zero(t, buf.Len())
+
+	// encoded without validation, e.g. by another service
+	require.NoError(t, core.MsgpackCodec{}.Encode(&buf, &invalid))
+	decoded, err := core.Decode[core.Match](&buf, core.ContentTypeMsgpack)
+	assert.Nil(t, decoded)
+	require.ErrorAs(t, err, &verr)
+}
+
+func TestCodecForUnsupported(t *testing.T) {
+	t.Parallel()
+	for _, contentType := range []string{"text/csv", "not a media type;"} {
+		_, err := core.CodecFor(contentType)
+		assert.ErrorIs(t, err, core.ErrUnsupportedContentType, contentType)
+	}
+
+	var buf bytes.Buffer
+	game := core.Game{Name: "Azul"}
+	assert.ErrorIs(t, core.Encode(&buf, "text/csv", &game), core.ErrUnsupportedContentType)
+}
+
+func TestProtobufCodec(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, core.Encode(&buf, core.ContentTypeProtobuf, wrapperspb.String("Azul")))
+	decoded, err := core.Decode[wrapperspb.StringValue](&buf, core.ContentTypeProtobuf)
+	require.NoError(t, err)
+	assert.Equal(t, "Azul", decoded.GetValue())
+
+	team := core.Team{TeamID: core.NewTeamID(), Name: "Red"}
+	assert.ErrorIs(t, core.Encode(&buf, core.ContentTypeProtobuf, &team), core.ErrUnsupportedValue)
+}
+
+func TestEncodeDecodeArray(t *testing.T) {
+	t.Parallel()
+	for _, contentType := range []string{core.ContentTypeJSON, core.ContentTypeMsgpack} {
+		t.Run(contentType, func(t *testing.T) {
+			t.Parallel()
+			matches := []core.Match{newTestMatch(), newTestMatch(), newTestMatch()}
+
+			var buf bytes.Buffer
+			require.NoError(t, core.EncodeArray(&buf, contentType, matches))
+
+			var decoded []core.MatchID
+			for match, err := range core.DecodeArray[core.Match](&buf, contentType) {
+				require.NoError(t, err)
+				decoded = append(decoded, match.MatchID)
+			}
+			assert.Equal(t, []core.MatchID{matches[0].MatchID, matches[1].MatchID, matches[2].MatchID}, decoded)
+
+			buf.Reset()
+			require.NoError(t, core.EncodeArray[core.Match](&buf, contentType, nil))
+			for _, err := range core.DecodeArray[core.Match](&buf, contentType) {
+				t.Fatalf("unexpected element, error %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeArrayProtobuf(t *testing.T) {
+	t.Parallel()
+	names := []wrapperspb.StringValue{{Value: "Azul"}, {Value: "Cascadia"}}
+
+	var buf bytes.Buffer
+	require.NoError(t, core.EncodeArray(&buf, core.ContentTypeProtobuf, names))
+
+	var decoded []string
+	for name, err := range core.DecodeArray[wrapperspb.StringValue](&buf, core.ContentTypeProtobuf) {
+		require.NoError(t, err)
+		decoded = append(decoded, name.GetValue())
+	}
+	assert.Equal(t, []string{"Azul", "Cascadia"}, decoded)
+}
+
+func TestDecodeArrayProtobufTooLarge(t *testing.T) {
+	t.Parallel()
+	// a length prefix of 1 TiB followed by no data
+	payload := binary.AppendUvarint(nil, 1<<40)
+
+	var err error
+	for _, err = range core.DecodeArray[wrapperspb.StringValue](bytes.NewReader(payload), core.ContentTypeProtobuf) {
+		break
+	}
+	assert.ErrorIs(t, err, core.ErrMessageTooLarge)
+
+	large := []wrapperspb.StringValue{{Value: strings.Repeat("x", core.MaxProtobufMessageSize)}}
+	assert.ErrorIs(t, core.EncodeArray(io.Discard, core.ContentTypeProtobuf, large), core.ErrMessageTooLarge)
+}
+
+func TestDecodeArrayStreams(t *testing.T) {
+	t.Parallel()
+	first := `{"user_id":"` + core.NewUserID().String() + `","value":61}`
+
+	// the second element is never read, so the payload may stay incomplete
+	var values []float64
+	for score, err := range core.DecodeArray[core.Score](strings.NewReader("["+first+",{"), core.ContentTypeJSON) {
+		require.NoError(t, err)
+		values = append(values, score.Value)
+		break
+	}
+	assert.Equal(t, []float64{61}, values)
+}
+
+func TestDecodeArrayErrors(t *testing.T) {
+	t.Parallel()
+	invalid := newTestMatch()
+	invalid.Scoreboard.Scores = invalid.Scoreboard.Scores[:1]
+
+	var buf bytes.Buffer
+	enc := core.JSONCodec{}.NewArrayEncoder(&buf)
+	valid := newTestMatch()
+	require.NoError(t, enc.Encode(&valid))
+	require.NoError(t, enc.Encode(&invalid))
+	require.NoError(t, enc.Close())
+
+	testCases := []struct {
+		name    string
+		payload string
+		decoded int
+		invalid bool
+		err     error
+	}{
+		{name: "invalid element", payload: buf.String(), decoded: 1, invalid: true},
+		{name: "no array", payload: `{"match_id":""}`},
+		{name: "empty payload", payload: "", err: io.ErrUnexpectedEOF},
+		{name: "truncated", payload: "[" + buf.String()[1:30]},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			decoded := 0
+			var err error
+			for _, err = range core.DecodeArray[core.Match](strings.NewReader(tc.payload), core.ContentTypeJSON) {
+				if err != nil {
+					break
+				}
+				decoded++
+			}
+			require.Error(t, err)
+			assert.Equal(t, tc.decoded, decoded)
+
+			if tc.invalid {
+				var verr *core.ValidationError
+				assert.ErrorAs(t, err, &verr)
+			}
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			}
+		})
+	}
+}
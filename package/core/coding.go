@@ -1,111 +1,77 @@
 package core
 
-import (
-	"encoding/json"
-	"io"
-)
+import "io"
+
+// The functions below encode and decode single values as JSON. They predate
+// Encode and Decode, which support every registered content type. Like
+// encoding/json, the decoders ignore unknown fields; DecodeStrict rejects
+// payloads not conforming to the type's JSON Schema.
 
 // User encoding/decoding
 func EncodeUser(w io.Writer, user *User) error {
-	encoder := json.NewEncoder(w)
-	return encoder.Encode(user)
+	return Encode(w, ContentTypeJSON, user)
 }
 
 func DecodeUser(r io.Reader) (*User, error) {
-	decoder := json.NewDecoder(r)
-	var user User
-	if err := decoder.Decode(&user); err != nil {
-		return nil, err
-	}
-	return &user, nil
+	return Decode[User](r, ContentTypeJSON)
 }
 
 // Attendee encoding/decoding
 func EncodeAttendee(w io.Writer, attendee *Attendee) error {
-	encoder := json.NewEncoder(w)
-	return encoder.Encode(attendee)
+	return Encode(w, ContentTypeJSON, attendee)
 }
 
 func DecodeAttendee(r io.Reader) (*Attendee, error) {
-	decoder := json.NewDecoder(r)
-	var attendee Attendee
-	if err := decoder.Decode(&attendee); err != nil {
-		return nil, err
-	}
-	return &attendee, nil
+	return Decode[Attendee](r, ContentTypeJSON)
 }
 
 // Event encoding/decoding
+//
+// Start and end times are encoded as RFC 3339 timestamps carrying the offset
+// of the event's timezone; decoded events are localized and validated.
 func EncodeEvent(w io.Writer, event *Event) error {
-	encoder := json.NewEncoder(w)
-	return encoder.Encode(event)
+	return Encode(w, ContentTypeJSON, event)
 }
 
 func DecodeEvent(r io.Reader) (*Event, error) {
-	decoder := json.NewDecoder(r)
-	var event Event
-	if err := decoder.Decode(&event); err != nil {
-		return nil, err
-	}
-	return &event, nil
+	return Decode[Event](r, ContentTypeJSON)
 }
 
 // Match encoding/decoding
+//
+// Matches are validated on both encoding and decoding, so scoreboards always
+// agree with the match's players.
 func EncodeMatch(w io.Writer, match *Match) error {
-	encoder := json.NewEncoder(w)
-	return encoder.Encode(match)
+	return Encode(w, ContentTypeJSON, match)
 }
 
 func DecodeMatch(r io.Reader) (*Match, error) {
-	decoder := json.NewDecoder(r)
-	var match Match
-	if err := decoder.Decode(&match); err != nil {
-		return nil, err
-	}
-	return &match, nil
+	return Decode[Match](r, ContentTypeJSON)
 }
 
 // Score encoding/decoding
 func EncodeScore(w io.Writer, score *Score) error {
-	encoder := json.NewEncoder(w)
-	return encoder.Encode(score)
+	return Encode(w, ContentTypeJSON, score)
 }
 
 func DecodeScore(r io.Reader) (*Score, error) {
-	decoder := json.NewDecoder(r)
-	var score Score
-	if err := decoder.Decode(&score); err != nil {
-		return nil, err
-	}
-	return &score, nil
+	return Decode[Score](r, ContentTypeJSON)
 }
 
 // Scoreboard encoding/decoding
 func EncodeScoreboard(w io.Writer, scoreboard *Scoreboard) error {
-	encoder := json.NewEncoder(w)
-	return encoder.Encode(scoreboard)
+	return Encode(w, ContentTypeJSON, scoreboard)
 }
 
 func DecodeScoreboard(r io.Reader) (*Scoreboard, error) {
-	decoder := json.NewDecoder(r)
-	var scoreboard Scoreboard
-	if err := decoder.Decode(&scoreboard); err != nil {
-		return nil, err
-	}
-	return &scoreboard, nil
+	return Decode[Scoreboard](r, ContentTypeJSON)
 }
 
 // Game encoding/decoding
 func EncodeGame(w io.Writer, game *Game) error {
-	encoder := json.NewEncoder(w)
-	return encoder.Encode(game)
+	return Encode(w, ContentTypeJSON, game)
 }
 
 func DecodeGame(r io.Reader) (*Game, error) {
-	decoder := json.NewDecoder(r)
-	var game Game
-	if err := decoder.Decode(&game); err != nil {
-		return nil, err
-	}
-	return &game, nil
+	return Decode[Game](r, ContentTypeJSON)
 }
@@ -2,7 +2,9 @@ package core_test
 
 import (
 	"bytes"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ngoldack/dicetrace/package/core"
 	"github.com/stretchr/testify/assert"
@@ -83,6 +85,16 @@ func TestEncodeDecodeEvent(t *testing.T) {
 
 func TestEncodeDecodeMatch(t *testing.T) {
 	t.Parallel()
+	player1 := core.User{
+		UserID:      core.NewUserID(),
+		Username:    "player1",
+		BGGUsername: "bggplayer1",
+	}
+	player2 := core.User{
+		UserID:      core.NewUserID(),
+		Username:    "player2",
+		BGGUsername: "bggplayer2",
+	}
 	original := &core.Match{
 		MatchID: core.NewMatchID(),
 		Game: core.Game{
@@ -92,22 +104,11 @@ func TestEncodeDecodeMatch(t *testing.T) {
 			Name:       "Gloomhaven",
 			Categories: []string{"Adventure", "Fantasy"},
 		},
-		Players: []core.User{
-			{
-				UserID:      core.NewUserID(),
-				Username:    "player1",
-				BGGUsername: "bggplayer1",
-			},
-			{
-				UserID:      core.NewUserID(),
-				Username:    "player2",
-				BGGUsername: "bggplayer2",
-			},
-		},
+		Players: []core.User{player1, player2},
 		Scoreboard: core.Scoreboard{
 			Scores: []core.Score{
-				{UserID: core.NewUserID(), Value: 100},
-				{UserID: core.NewUserID(), Value: 95},
+				{UserID: player1.UserID, Value: 100},
+				{UserID: player2.UserID, Value: 95},
 			},
 			ScoreUnit: core.ScoreUnitPoints,
 		},
@@ -186,6 +187,7 @@ func TestEncodeDecodeAttendeeStatus(t *testing.T) {
 		{"confirmed", core.AttendeeStatusConfirmed},
 		{"pending", core.AttendeeStatusPending},
 		{"declined", core.AttendeeStatusDeclined},
+		{"waitlisted", core.AttendeeStatusWaitlisted},
 	}
 
 	for _, tc := range testCases {
@@ -218,6 +220,7 @@ func TestEncodeDecodeEventStatus(t *testing.T) {
 		{"scheduled", core.EventStatusScheduled},
 		{"ongoing", core.EventStatusOngoing},
 		{"completed", core.EventStatusCompleted},
+		{"cancelled", core.EventStatusCancelled},
 	}
 
 	for _, tc := range testCases {
@@ -269,3 +272,69 @@ func TestEncodeDecodeScoreUnit(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeDecodeEventSchedule(t *testing.T) {
+	t.Parallel()
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	host := core.User{
+		UserID:   core.NewUserID(),
+		Username: "host",
+	}
+	original := &core.Event{
+		EventID:  core.NewEventID(),
+		Status:   core.EventStatusScheduled,
+		Title:    "Thursday game night",
+		Location: "Kitchen table",
+		StartsAt: time.Date(2025, time.March, 6, 19, 0, 0, 0, berlin),
+		EndsAt:   time.Date(2025, time.March, 6, 23, 30, 0, 0, berlin),
+		Timezone: "Europe/Berlin",
+		Host:     &host,
+		Capacity: 5,
+		Attendees: []core.Attendee{
+			{User: host, Status: core.AttendeeStatusConfirmed},
+		},
+		Matches: []core.Match{},
+	}
+
+	var buf bytes.Buffer
+	err = core.EncodeEvent(&buf, original)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `"starts_at":"2025-03-06T19:00:00+01:00"`)
+
+	decoded, err := core.DecodeEvent(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, original.Title, decoded.Title)
+	assert.Equal(t, original.Location, decoded.Location)
+	assert.Equal(t, original.Host, decoded.Host)
+	assert.Equal(t, original.Capacity, decoded.Capacity)
+	assert.True(t, original.StartsAt.Equal(decoded.StartsAt))
+	assert.True(t, original.EndsAt.Equal(decoded.EndsAt))
+	assert.Equal(t, "Europe/Berlin", decoded.StartsAt.Location().String())
+	assert.Equal(t, "Europe/Berlin", decoded.EndsAt.Location().String())
+}
+
+func TestDecodeEventLocalizesTimes(t *testing.T) {
+	t.Parallel()
+	payload := `{"event_id":"` + core.NewEventID().String() + `","status":"scheduled",` +
+		`"starts_at":"2025-07-03T17:00:00Z","timezone":"Europe/Berlin","attendees":[],"matches":[]}`
+
+	decoded, err := core.DecodeEvent(strings.NewReader(payload))
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", decoded.StartsAt.Location().String())
+	assert.Equal(t, 19, decoded.StartsAt.Hour())
+}
+
+func TestDecodeEventInvalid(t *testing.T) {
+	t.Parallel()
+	payload := `{"event_id":"` + core.NewEventID().String() + `","status":"scheduled",` +
+		`"starts_at":"2025-07-03T19:00:00+02:00","ends_at":"2025-07-03T18:00:00+02:00","attendees":[],"matches":[]}`
+
+	decoded, err := core.DecodeEvent(strings.NewReader(payload))
+	assert.Nil(t, decoded)
+
+	var verr *core.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "ends_at", verr.Errors[0].Field)
+}
@@ -1,15 +1,21 @@
 package core
 
+import "time"
+
+// EventStatus is the lifecycle state of an Event. The zero value denotes an
+// event that has not been scheduled yet. Statuses change only through the
+// transitions defined in event_state.go.
 type EventStatus string
 
 const (
 	EventStatusScheduled EventStatus = "scheduled"
 	EventStatusOngoing   EventStatus = "ongoing"
 	EventStatusCompleted EventStatus = "completed"
+	EventStatusCancelled EventStatus = "cancelled"
 )
 
 type User struct {
-	UserID      UserID `json:"user_id"`
+	UserID      UserID `json:"user_id" typeid:"user"`
 	Username    string `json:"username"`
 	BGGUsername string `json:"bgg_username"`
 }
@@ -20,6 +26,9 @@ const (
 	AttendeeStatusConfirmed AttendeeStatus = "confirmed"
 	AttendeeStatusPending   AttendeeStatus = "pending"
 	AttendeeStatusDeclined  AttendeeStatus = "declined"
+	// AttendeeStatusWaitlisted marks attendees who accepted while the event
+	// was at capacity. They are promoted in the order they were waitlisted.
+	AttendeeStatusWaitlisted AttendeeStatus = "waitlisted"
 )
 
 type Attendee struct {
@@ -28,47 +37,150 @@ type Attendee struct {
 }
 
 type Event struct {
-	EventID EventID `json:"event_id"`
+	EventID EventID `json:"event_id" typeid:"event"`
 
 	Status EventStatus `json:"status"`
 
+	Title    string `json:"title"`
+	Location string `json:"location"`
+
+	// StartsAt and EndsAt are expressed in the event's Timezone once the
+	// event has been localized.
+	StartsAt time.Time `json:"starts_at,omitzero"`
+	EndsAt   time.Time `json:"ends_at,omitzero"`
+	// Timezone is the IANA time zone the event takes place in, e.g.
+	// "Europe/Berlin". Empty means the times are kept as given.
+	Timezone string `json:"timezone,omitempty"`
+
+	Host *User `json:"host,omitempty"`
+	// Capacity limits the number of confirmed attendees; 0 means unlimited.
+	Capacity int `json:"capacity,omitempty"`
+
 	Attendees []Attendee `json:"attendees"`
 	Matches   []Match    `json:"matches"`
 }
 
+// MatchStatus is the recording state of a Match. The zero value denotes a
+// match recorded in one go, which is complete like a finalized one.
+type MatchStatus string
+
+const (
+	MatchStatusInProgress MatchStatus = "in_progress"
+	MatchStatusFinalized  MatchStatus = "finalized"
+)
+
 type Match struct {
-	MatchID MatchID `json:"match_id"`
+	MatchID MatchID     `json:"match_id" typeid:"match"`
+	Status  MatchStatus `json:"status,omitempty"`
 
 	Game    Game   `json:"game"`
 	Players []User `json:"players"`
+	// Teams groups the players for team games; empty for free-for-all
+	// matches. Every player belongs to exactly one team.
+	Teams []Team `json:"teams,omitempty"`
+
+	// StartedAt and EndedAt record when the match was played; both are
+	// optional.
+	StartedAt time.Time `json:"started_at,omitzero"`
+	EndedAt   time.Time `json:"ended_at,omitzero"`
 
 	Scoreboard Scoreboard `json:"scoreboard"`
 }
 
+// Team is a group of players competing together in a match.
+type Team struct {
+	TeamID  TeamID   `json:"team_id" typeid:"team"`
+	Name    string   `json:"name"`
+	Members []UserID `json:"members" typeid:"user"`
+}
+
 type ScoreUnit string
 
 const (
 	ScoreUnitPoints ScoreUnit = "points"
 	ScoreUnitCustom ScoreUnit = "custom"
+	// ScoreUnitCooperative is used for games the players win or lose
+	// together. Every player scores ScoreWin or ScoreLoss, all alike.
+	ScoreUnitCooperative ScoreUnit = "cooperative"
+	// ScoreUnitWinLoss records only whether each player won (ScoreWin) or
+	// lost (ScoreLoss).
+	ScoreUnitWinLoss ScoreUnit = "win_loss"
+	// ScoreUnitRank records finishing positions without a numeric score,
+	// starting at 1 for the winner.
+	ScoreUnitRank ScoreUnit = "rank"
+	// ScoreUnitTime records durations in seconds. Faster times win unless
+	// the scoreboard's direction says otherwise.
+	ScoreUnitTime ScoreUnit = "time"
+)
+
+// Values of outcome-only score units.
+const (
+	ScoreLoss float64 = 0
+	ScoreWin  float64 = 1
+)
+
+// ScoreDirection tells which end of the scoreboard wins. The zero value
+// means higher scores win.
+type ScoreDirection string
+
+const (
+	ScoreDirectionHigherWins ScoreDirection = "higher_wins"
+	ScoreDirectionLowerWins  ScoreDirection = "lower_wins"
 )
 
 type Score struct {
-	UserID UserID  `json:"user_id"`
+	UserID UserID  `json:"user_id" typeid:"user"`
 	Value  float64 `json:"value"`
+	// Tiebreakers decide between equal values. They are compared in order,
+	// in the scoreboard's direction.
+	Tiebreakers []float64 `json:"tiebreakers,omitempty"`
+	// Breakdown optionally splits Value into rounds or scoring categories,
+	// which must sum to Value.
+	Breakdown []ScoreLine `json:"breakdown,omitempty"`
+}
+
+// TeamScore is the score of a whole team.
+type TeamScore struct {
+	TeamID      TeamID      `json:"team_id" typeid:"team"`
+	Value       float64     `json:"value"`
+	Tiebreakers []float64   `json:"tiebreakers,omitempty"`
+	Breakdown   []ScoreLine `json:"breakdown,omitempty"`
+}
+
+// ScoreLine is one round or scoring category of a score sheet, e.g.
+// "Round 3" or "Military".
+type ScoreLine struct {
+	Label string  `json:"label"`
+	Value float64 `json:"value"`
 }
 
 type Scoreboard struct {
-	// Scores listed in the order of players in the Match.Players slice
-	Scores    []Score   `json:"scores"`
-	ScoreUnit ScoreUnit `json:"score_unit"`
+	// Scores listed in the order of players in the Match.Players slice.
+	// Optional in team matches, where it holds individual contributions.
+	Scores []Score `json:"scores"`
+	// TeamScores listed in the order of teams in the Match.Teams slice. Team
+	// matches are ranked by team scores.
+	TeamScores []TeamScore    `json:"team_scores,omitempty"`
+	ScoreUnit  ScoreUnit      `json:"score_unit"`
+	Direction  ScoreDirection `json:"direction,omitempty"`
 }
 
 type Game struct {
-	GameID GameID `json:"game_id"`
+	GameID GameID `json:"game_id" typeid:"game"`
 	BGGID  int    `json:"bgg_id"`
 
 	Rating float64 `json:"rating"`
 
 	Name       string   `json:"name"`
 	Categories []string `json:"categories"`
+
+	// Scoring is how matches of the game are scored by default.
+	Scoring Scoring `json:"scoring,omitzero"`
+}
+
+// Scoring configures a game's scoreboards. The zero value scores points,
+// higher wins.
+type Scoring struct {
+	Unit      ScoreUnit      `json:"unit,omitempty"`
+	Direction ScoreDirection `json:"direction,omitempty"`
 }
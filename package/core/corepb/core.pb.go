@@ -0,0 +1,1283 @@
+// Binary wire format of the core types, see core.ContentTypeProtobuf. The
+// messages mirror the core structs and their JSON field names; JSON stays
+// the default encoding.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v5.29.3
+// source: core.proto
+
+package corepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AttendeeStatus int32
+
+const (
+	AttendeeStatus_ATTENDEE_STATUS_UNSPECIFIED AttendeeStatus = 0
+	AttendeeStatus_ATTENDEE_STATUS_CONFIRMED   AttendeeStatus = 1
+	AttendeeStatus_ATTENDEE_STATUS_PENDING     AttendeeStatus = 2
+	AttendeeStatus_ATTENDEE_STATUS_DECLINED    AttendeeStatus = 3
+	AttendeeStatus_ATTENDEE_STATUS_WAITLISTED  AttendeeStatus = 4
+)
+
+// Enum value maps for AttendeeStatus.
+var (
+	AttendeeStatus_name = map[int32]string{
+		0: "ATTENDEE_STATUS_UNSPECIFIED",
+		1: "ATTENDEE_STATUS_CONFIRMED",
+		2: "ATTENDEE_STATUS_PENDING",
+		3: "ATTENDEE_STATUS_DECLINED",
+		4: "ATTENDEE_STATUS_WAITLISTED",
+	}
+	AttendeeStatus_value = map[string]int32{
+		"ATTENDEE_STATUS_UNSPECIFIED": 0,
+		"ATTENDEE_STATUS_CONFIRMED":   1,
+		"ATTENDEE_STATUS_PENDING":     2,
+		"ATTENDEE_STATUS_DECLINED":    3,
+		"ATTENDEE_STATUS_WAITLISTED":  4,
+	}
+)
+
+func (x AttendeeStatus) Enum() *AttendeeStatus {
+	p := new(AttendeeStatus)
+	*p = x
+	return p
+}
+
+func (x AttendeeStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AttendeeStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_core_proto_enumTypes[0].Descriptor()
+}
+
+func (AttendeeStatus) Type() protoreflect.EnumType {
+	return &file_core_proto_enumTypes[0]
+}
+
+func (x AttendeeStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AttendeeStatus.Descriptor instead.
+func (AttendeeStatus) EnumDescriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{0}
+}
+
+type EventStatus int32
+
+const (
+	// Events that have not been scheduled yet.
+	EventStatus_EVENT_STATUS_UNSPECIFIED EventStatus = 0
+	EventStatus_EVENT_STATUS_SCHEDULED   EventStatus = 1
+	EventStatus_EVENT_STATUS_ONGOING     EventStatus = 2
+	EventStatus_EVENT_STATUS_COMPLETED   EventStatus = 3
+	EventStatus_EVENT_STATUS_CANCELLED   EventStatus = 4
+)
+
+// Enum value maps for EventStatus.
+var (
+	EventStatus_name = map[int32]string{
+		0: "EVENT_STATUS_UNSPECIFIED",
+		1: "EVENT_STATUS_SCHEDULED",
+		2: "EVENT_STATUS_ONGOING",
+		3: "EVENT_STATUS_COMPLETED",
+		4: "EVENT_STATUS_CANCELLED",
+	}
+	EventStatus_value = map[string]int32{
+		"EVENT_STATUS_UNSPECIFIED": 0,
+		"EVENT_STATUS_SCHEDULED":   1,
+		"EVENT_STATUS_ONGOING":     2,
+		"EVENT_STATUS_COMPLETED":   3,
+		"EVENT_STATUS_CANCELLED":   4,
+	}
+)
+
+func (x EventStatus) Enum() *EventStatus {
+	p := new(EventStatus)
+	*p = x
+	return p
+}
+
+func (x EventStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_core_proto_enumTypes[1].Descriptor()
+}
+
+func (EventStatus) Type() protoreflect.EnumType {
+	return &file_core_proto_enumTypes[1]
+}
+
+func (x EventStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventStatus.Descriptor instead.
+func (EventStatus) EnumDescriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{1}
+}
+
+type MatchStatus int32
+
+const (
+	// Matches recorded in one go.
+	MatchStatus_MATCH_STATUS_UNSPECIFIED MatchStatus = 0
+	MatchStatus_MATCH_STATUS_IN_PROGRESS MatchStatus = 1
+	MatchStatus_MATCH_STATUS_FINALIZED   MatchStatus = 2
+)
+
+// Enum value maps for MatchStatus.
+var (
+	MatchStatus_name = map[int32]string{
+		0: "MATCH_STATUS_UNSPECIFIED",
+		1: "MATCH_STATUS_IN_PROGRESS",
+		2: "MATCH_STATUS_FINALIZED",
+	}
+	MatchStatus_value = map[string]int32{
+		"MATCH_STATUS_UNSPECIFIED": 0,
+		"MATCH_STATUS_IN_PROGRESS": 1,
+		"MATCH_STATUS_FINALIZED":   2,
+	}
+)
+
+func (x MatchStatus) Enum() *MatchStatus {
+	p := new(MatchStatus)
+	*p = x
+	return p
+}
+
+func (x MatchStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MatchStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_core_proto_enumTypes[2].Descriptor()
+}
+
+func (MatchStatus) Type() protoreflect.EnumType {
+	return &file_core_proto_enumTypes[2]
+}
+
+func (x MatchStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MatchStatus.Descriptor instead.
+func (MatchStatus) EnumDescriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{2}
+}
+
+type ScoreUnit int32
+
+const (
+	// Points, the default of games without a scoring.
+	ScoreUnit_SCORE_UNIT_UNSPECIFIED ScoreUnit = 0
+	ScoreUnit_SCORE_UNIT_POINTS      ScoreUnit = 1
+	ScoreUnit_SCORE_UNIT_CUSTOM      ScoreUnit = 2
+	ScoreUnit_SCORE_UNIT_COOPERATIVE ScoreUnit = 3
+	ScoreUnit_SCORE_UNIT_WIN_LOSS    ScoreUnit = 4
+	ScoreUnit_SCORE_UNIT_RANK        ScoreUnit = 5
+	ScoreUnit_SCORE_UNIT_TIME        ScoreUnit = 6
+)
+
+// Enum value maps for ScoreUnit.
+var (
+	ScoreUnit_name = map[int32]string{
+		0: "SCORE_UNIT_UNSPECIFIED",
+		1: "SCORE_UNIT_POINTS",
+		2: "SCORE_UNIT_CUSTOM",
+		3: "SCORE_UNIT_COOPERATIVE",
+		4: "SCORE_UNIT_WIN_LOSS",
+		5: "SCORE_UNIT_RANK",
+		6: "SCORE_UNIT_TIME",
+	}
+	ScoreUnit_value = map[string]int32{
+		"SCORE_UNIT_UNSPECIFIED": 0,
+		"SCORE_UNIT_POINTS":      1,
+		"SCORE_UNIT_CUSTOM":      2,
+		"SCORE_UNIT_COOPERATIVE": 3,
+		"SCORE_UNIT_WIN_LOSS":    4,
+		"SCORE_UNIT_RANK":        5,
+		"SCORE_UNIT_TIME":        6,
+	}
+)
+
+func (x ScoreUnit) Enum() *ScoreUnit {
+	p := new(ScoreUnit)
+	*p = x
+	return p
+}
+
+func (x ScoreUnit) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ScoreUnit) Descriptor() protoreflect.EnumDescriptor {
+	return file_core_proto_enumTypes[3].Descriptor()
+}
+
+func (ScoreUnit) Type() protoreflect.EnumType {
+	return &file_core_proto_enumTypes[3]
+}
+
+func (x ScoreUnit) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ScoreUnit.Descriptor instead.
+func (ScoreUnit) EnumDescriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{3}
+}
+
+type ScoreDirection int32
+
+const (
+	// Higher scores win.
+	ScoreDirection_SCORE_DIRECTION_UNSPECIFIED ScoreDirection = 0
+	ScoreDirection_SCORE_DIRECTION_HIGHER_WINS ScoreDirection = 1
+	ScoreDirection_SCORE_DIRECTION_LOWER_WINS  ScoreDirection = 2
+)
+
+// Enum value maps for ScoreDirection.
+var (
+	ScoreDirection_name = map[int32]string{
+		0: "SCORE_DIRECTION_UNSPECIFIED",
+		1: "SCORE_DIRECTION_HIGHER_WINS",
+		2: "SCORE_DIRECTION_LOWER_WINS",
+	}
+	ScoreDirection_value = map[string]int32{
+		"SCORE_DIRECTION_UNSPECIFIED": 0,
+		"SCORE_DIRECTION_HIGHER_WINS": 1,
+		"SCORE_DIRECTION_LOWER_WINS":  2,
+	}
+)
+
+func (x ScoreDirection) Enum() *ScoreDirection {
+	p := new(ScoreDirection)
+	*p = x
+	return p
+}
+
+func (x ScoreDirection) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ScoreDirection) Descriptor() protoreflect.EnumDescriptor {
+	return file_core_proto_enumTypes[4].Descriptor()
+}
+
+func (ScoreDirection) Type() protoreflect.EnumType {
+	return &file_core_proto_enumTypes[4]
+}
+
+func (x ScoreDirection) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ScoreDirection.Descriptor instead.
+func (ScoreDirection) EnumDescriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{4}
+}
+
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	BggUsername   string                 `protobuf:"bytes,3,opt,name=bgg_username,json=bggUsername,proto3" json:"bgg_username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_core_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *User) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *User) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *User) GetBggUsername() string {
+	if x != nil {
+		return x.BggUsername
+	}
+	return ""
+}
+
+type Attendee struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Status        AttendeeStatus         `protobuf:"varint,2,opt,name=status,proto3,enum=dicetrace.core.v1.AttendeeStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attendee) Reset() {
+	*x = Attendee{}
+	mi := &file_core_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attendee) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attendee) ProtoMessage() {}
+
+func (x *Attendee) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attendee.ProtoReflect.Descriptor instead.
+func (*Attendee) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Attendee) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *Attendee) GetStatus() AttendeeStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AttendeeStatus_ATTENDEE_STATUS_UNSPECIFIED
+}
+
+type Event struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	EventId  string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Status   EventStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=dicetrace.core.v1.EventStatus" json:"status,omitempty"`
+	Title    string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Location string                 `protobuf:"bytes,4,opt,name=location,proto3" json:"location,omitempty"`
+	// Times are sent in UTC and localized to the timezone when decoded.
+	StartsAt      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt        *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	Timezone      string                 `protobuf:"bytes,7,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Host          *User                  `protobuf:"bytes,8,opt,name=host,proto3" json:"host,omitempty"`
+	Capacity      int32                  `protobuf:"varint,9,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Attendees     []*Attendee            `protobuf:"bytes,10,rep,name=attendees,proto3" json:"attendees,omitempty"`
+	Matches       []*Match               `protobuf:"bytes,11,rep,name=matches,proto3" json:"matches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_core_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Event) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *Event) GetStatus() EventStatus {
+	if x != nil {
+		return x.Status
+	}
+	return EventStatus_EVENT_STATUS_UNSPECIFIED
+}
+
+func (x *Event) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Event) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Event) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *Event) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *Event) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *Event) GetHost() *User {
+	if x != nil {
+		return x.Host
+	}
+	return nil
+}
+
+func (x *Event) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *Event) GetAttendees() []*Attendee {
+	if x != nil {
+		return x.Attendees
+	}
+	return nil
+}
+
+func (x *Event) GetMatches() []*Match {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+type Match struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	Status        MatchStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=dicetrace.core.v1.MatchStatus" json:"status,omitempty"`
+	Game          *Game                  `protobuf:"bytes,3,opt,name=game,proto3" json:"game,omitempty"`
+	Players       []*User                `protobuf:"bytes,4,rep,name=players,proto3" json:"players,omitempty"`
+	Teams         []*Team                `protobuf:"bytes,5,rep,name=teams,proto3" json:"teams,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	EndedAt       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=ended_at,json=endedAt,proto3" json:"ended_at,omitempty"`
+	Scoreboard    *Scoreboard            `protobuf:"bytes,8,opt,name=scoreboard,proto3" json:"scoreboard,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Match) Reset() {
+	*x = Match{}
+	mi := &file_core_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Match) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Match) ProtoMessage() {}
+
+func (x *Match) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Match.ProtoReflect.Descriptor instead.
+func (*Match) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Match) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *Match) GetStatus() MatchStatus {
+	if x != nil {
+		return x.Status
+	}
+	return MatchStatus_MATCH_STATUS_UNSPECIFIED
+}
+
+func (x *Match) GetGame() *Game {
+	if x != nil {
+		return x.Game
+	}
+	return nil
+}
+
+func (x *Match) GetPlayers() []*User {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *Match) GetTeams() []*Team {
+	if x != nil {
+		return x.Teams
+	}
+	return nil
+}
+
+func (x *Match) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Match) GetEndedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndedAt
+	}
+	return nil
+}
+
+func (x *Match) GetScoreboard() *Scoreboard {
+	if x != nil {
+		return x.Scoreboard
+	}
+	return nil
+}
+
+type Team struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeamId        string                 `protobuf:"bytes,1,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Members       []string               `protobuf:"bytes,3,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Team) Reset() {
+	*x = Team{}
+	mi := &file_core_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Team) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Team) ProtoMessage() {}
+
+func (x *Team) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Team.ProtoReflect.Descriptor instead.
+func (*Team) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Team) GetTeamId() string {
+	if x != nil {
+		return x.TeamId
+	}
+	return ""
+}
+
+func (x *Team) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Team) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type ScoreLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Label         string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScoreLine) Reset() {
+	*x = ScoreLine{}
+	mi := &file_core_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScoreLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoreLine) ProtoMessage() {}
+
+func (x *ScoreLine) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoreLine.ProtoReflect.Descriptor instead.
+func (*ScoreLine) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ScoreLine) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *ScoreLine) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type Score struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	Tiebreakers   []float64              `protobuf:"fixed64,3,rep,packed,name=tiebreakers,proto3" json:"tiebreakers,omitempty"`
+	Breakdown     []*ScoreLine           `protobuf:"bytes,4,rep,name=breakdown,proto3" json:"breakdown,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Score) Reset() {
+	*x = Score{}
+	mi := &file_core_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Score) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Score) ProtoMessage() {}
+
+func (x *Score) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Score.ProtoReflect.Descriptor instead.
+func (*Score) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Score) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Score) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Score) GetTiebreakers() []float64 {
+	if x != nil {
+		return x.Tiebreakers
+	}
+	return nil
+}
+
+func (x *Score) GetBreakdown() []*ScoreLine {
+	if x != nil {
+		return x.Breakdown
+	}
+	return nil
+}
+
+type TeamScore struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeamId        string                 `protobuf:"bytes,1,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	Tiebreakers   []float64              `protobuf:"fixed64,3,rep,packed,name=tiebreakers,proto3" json:"tiebreakers,omitempty"`
+	Breakdown     []*ScoreLine           `protobuf:"bytes,4,rep,name=breakdown,proto3" json:"breakdown,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TeamScore) Reset() {
+	*x = TeamScore{}
+	mi := &file_core_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TeamScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TeamScore) ProtoMessage() {}
+
+func (x *TeamScore) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TeamScore.ProtoReflect.Descriptor instead.
+func (*TeamScore) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TeamScore) GetTeamId() string {
+	if x != nil {
+		return x.TeamId
+	}
+	return ""
+}
+
+func (x *TeamScore) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *TeamScore) GetTiebreakers() []float64 {
+	if x != nil {
+		return x.Tiebreakers
+	}
+	return nil
+}
+
+func (x *TeamScore) GetBreakdown() []*ScoreLine {
+	if x != nil {
+		return x.Breakdown
+	}
+	return nil
+}
+
+type Scoreboard struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scores        []*Score               `protobuf:"bytes,1,rep,name=scores,proto3" json:"scores,omitempty"`
+	TeamScores    []*TeamScore           `protobuf:"bytes,2,rep,name=team_scores,json=teamScores,proto3" json:"team_scores,omitempty"`
+	ScoreUnit     ScoreUnit              `protobuf:"varint,3,opt,name=score_unit,json=scoreUnit,proto3,enum=dicetrace.core.v1.ScoreUnit" json:"score_unit,omitempty"`
+	Direction     ScoreDirection         `protobuf:"varint,4,opt,name=direction,proto3,enum=dicetrace.core.v1.ScoreDirection" json:"direction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Scoreboard) Reset() {
+	*x = Scoreboard{}
+	mi := &file_core_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Scoreboard) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Scoreboard) ProtoMessage() {}
+
+func (x *Scoreboard) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Scoreboard.ProtoReflect.Descriptor instead.
+func (*Scoreboard) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Scoreboard) GetScores() []*Score {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
+func (x *Scoreboard) GetTeamScores() []*TeamScore {
+	if x != nil {
+		return x.TeamScores
+	}
+	return nil
+}
+
+func (x *Scoreboard) GetScoreUnit() ScoreUnit {
+	if x != nil {
+		return x.ScoreUnit
+	}
+	return ScoreUnit_SCORE_UNIT_UNSPECIFIED
+}
+
+func (x *Scoreboard) GetDirection() ScoreDirection {
+	if x != nil {
+		return x.Direction
+	}
+	return ScoreDirection_SCORE_DIRECTION_UNSPECIFIED
+}
+
+type Scoring struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Unit          ScoreUnit              `protobuf:"varint,1,opt,name=unit,proto3,enum=dicetrace.core.v1.ScoreUnit" json:"unit,omitempty"`
+	Direction     ScoreDirection         `protobuf:"varint,2,opt,name=direction,proto3,enum=dicetrace.core.v1.ScoreDirection" json:"direction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Scoring) Reset() {
+	*x = Scoring{}
+	mi := &file_core_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Scoring) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Scoring) ProtoMessage() {}
+
+func (x *Scoring) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Scoring.ProtoReflect.Descriptor instead.
+func (*Scoring) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Scoring) GetUnit() ScoreUnit {
+	if x != nil {
+		return x.Unit
+	}
+	return ScoreUnit_SCORE_UNIT_UNSPECIFIED
+}
+
+func (x *Scoring) GetDirection() ScoreDirection {
+	if x != nil {
+		return x.Direction
+	}
+	return ScoreDirection_SCORE_DIRECTION_UNSPECIFIED
+}
+
+type Game struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameId        string                 `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	BggId         int64                  `protobuf:"varint,2,opt,name=bgg_id,json=bggId,proto3" json:"bgg_id,omitempty"`
+	Rating        float64                `protobuf:"fixed64,3,opt,name=rating,proto3" json:"rating,omitempty"`
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Categories    []string               `protobuf:"bytes,5,rep,name=categories,proto3" json:"categories,omitempty"`
+	Scoring       *Scoring               `protobuf:"bytes,6,opt,name=scoring,proto3" json:"scoring,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Game) Reset() {
+	*x = Game{}
+	mi := &file_core_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Game) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Game) ProtoMessage() {}
+
+func (x *Game) ProtoReflect() protoreflect.Message {
+	mi := &file_core_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Game.ProtoReflect.Descriptor instead.
+func (*Game) Descriptor() ([]byte, []int) {
+	return file_core_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Game) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *Game) GetBggId() int64 {
+	if x != nil {
+		return x.BggId
+	}
+	return 0
+}
+
+func (x *Game) GetRating() float64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Game) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Game) GetCategories() []string {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *Game) GetScoring() *Scoring {
+	if x != nil {
+		return x.Scoring
+	}
+	return nil
+}
+
+var File_core_proto protoreflect.FileDescriptor
+
+const file_core_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"core.proto\x12\x11dicetrace.core.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"^\n" +
+	"\x04User\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12!\n" +
+	"\fbgg_username\x18\x03 \x01(\tR\vbggUsername\"r\n" +
+	"\bAttendee\x12+\n" +
+	"\x04user\x18\x01 \x01(\v2\x17.dicetrace.core.v1.UserR\x04user\x129\n" +
+	"\x06status\x18\x02 \x01(\x0e2!.dicetrace.core.v1.AttendeeStatusR\x06status\"\xce\x03\n" +
+	"\x05Event\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x126\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1e.dicetrace.core.v1.EventStatusR\x06status\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x1a\n" +
+	"\blocation\x18\x04 \x01(\tR\blocation\x127\n" +
+	"\tstarts_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\bstartsAt\x123\n" +
+	"\aends_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x06endsAt\x12\x1a\n" +
+	"\btimezone\x18\a \x01(\tR\btimezone\x12+\n" +
+	"\x04host\x18\b \x01(\v2\x17.dicetrace.core.v1.UserR\x04host\x12\x1a\n" +
+	"\bcapacity\x18\t \x01(\x05R\bcapacity\x129\n" +
+	"\tattendees\x18\n" +
+	" \x03(\v2\x1b.dicetrace.core.v1.AttendeeR\tattendees\x122\n" +
+	"\amatches\x18\v \x03(\v2\x18.dicetrace.core.v1.MatchR\amatches\"\x9a\x03\n" +
+	"\x05Match\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x126\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1e.dicetrace.core.v1.MatchStatusR\x06status\x12+\n" +
+	"\x04game\x18\x03 \x01(\v2\x17.dicetrace.core.v1.GameR\x04game\x121\n" +
+	"\aplayers\x18\x04 \x03(\v2\x17.dicetrace.core.v1.UserR\aplayers\x12-\n" +
+	"\x05teams\x18\x05 \x03(\v2\x17.dicetrace.core.v1.TeamR\x05teams\x129\n" +
+	"\n" +
+	"started_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x125\n" +
+	"\bended_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\aendedAt\x12=\n" +
+	"\n" +
+	"scoreboard\x18\b \x01(\v2\x1d.dicetrace.core.v1.ScoreboardR\n" +
+	"scoreboard\"M\n" +
+	"\x04Team\x12\x17\n" +
+	"\ateam_id\x18\x01 \x01(\tR\x06teamId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\amembers\x18\x03 \x03(\tR\amembers\"7\n" +
+	"\tScoreLine\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\"\x94\x01\n" +
+	"\x05Score\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\x12 \n" +
+	"\vtiebreakers\x18\x03 \x03(\x01R\vtiebreakers\x12:\n" +
+	"\tbreakdown\x18\x04 \x03(\v2\x1c.dicetrace.core.v1.ScoreLineR\tbreakdown\"\x98\x01\n" +
+	"\tTeamScore\x12\x17\n" +
+	"\ateam_id\x18\x01 \x01(\tR\x06teamId\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\x12 \n" +
+	"\vtiebreakers\x18\x03 \x03(\x01R\vtiebreakers\x12:\n" +
+	"\tbreakdown\x18\x04 \x03(\v2\x1c.dicetrace.core.v1.ScoreLineR\tbreakdown\"\xfb\x01\n" +
+	"\n" +
+	"Scoreboard\x120\n" +
+	"\x06scores\x18\x01 \x03(\v2\x18.dicetrace.core.v1.ScoreR\x06scores\x12=\n" +
+	"\vteam_scores\x18\x02 \x03(\v2\x1c.dicetrace.core.v1.TeamScoreR\n" +
+	"teamScores\x12;\n" +
+	"\n" +
+	"score_unit\x18\x03 \x01(\x0e2\x1c.dicetrace.core.v1.ScoreUnitR\tscoreUnit\x12?\n" +
+	"\tdirection\x18\x04 \x01(\x0e2!.dicetrace.core.v1.ScoreDirectionR\tdirection\"|\n" +
+	"\aScoring\x120\n" +
+	"\x04unit\x18\x01 \x01(\x0e2\x1c.dicetrace.core.v1.ScoreUnitR\x04unit\x12?\n" +
+	"\tdirection\x18\x02 \x01(\x0e2!.dicetrace.core.v1.ScoreDirectionR\tdirection\"\xb8\x01\n" +
+	"\x04Game\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\tR\x06gameId\x12\x15\n" +
+	"\x06bgg_id\x18\x02 \x01(\x03R\x05bggId\x12\x16\n" +
+	"\x06rating\x18\x03 \x01(\x01R\x06rating\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"categories\x18\x05 \x03(\tR\n" +
+	"categories\x124\n" +
+	"\ascoring\x18\x06 \x01(\v2\x1a.dicetrace.core.v1.ScoringR\ascoring*\xab\x01\n" +
+	"\x0eAttendeeStatus\x12\x1f\n" +
+	"\x1bATTENDEE_STATUS_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19ATTENDEE_STATUS_CONFIRMED\x10\x01\x12\x1b\n" +
+	"\x17ATTENDEE_STATUS_PENDING\x10\x02\x12\x1c\n" +
+	"\x18ATTENDEE_STATUS_DECLINED\x10\x03\x12\x1e\n" +
+	"\x1aATTENDEE_STATUS_WAITLISTED\x10\x04*\x99\x01\n" +
+	"\vEventStatus\x12\x1c\n" +
+	"\x18EVENT_STATUS_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16EVENT_STATUS_SCHEDULED\x10\x01\x12\x18\n" +
+	"\x14EVENT_STATUS_ONGOING\x10\x02\x12\x1a\n" +
+	"\x16EVENT_STATUS_COMPLETED\x10\x03\x12\x1a\n" +
+	"\x16EVENT_STATUS_CANCELLED\x10\x04*e\n" +
+	"\vMatchStatus\x12\x1c\n" +
+	"\x18MATCH_STATUS_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18MATCH_STATUS_IN_PROGRESS\x10\x01\x12\x1a\n" +
+	"\x16MATCH_STATUS_FINALIZED\x10\x02*\xb4\x01\n" +
+	"\tScoreUnit\x12\x1a\n" +
+	"\x16SCORE_UNIT_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11SCORE_UNIT_POINTS\x10\x01\x12\x15\n" +
+	"\x11SCORE_UNIT_CUSTOM\x10\x02\x12\x1a\n" +
+	"\x16SCORE_UNIT_COOPERATIVE\x10\x03\x12\x17\n" +
+	"\x13SCORE_UNIT_WIN_LOSS\x10\x04\x12\x13\n" +
+	"\x0fSCORE_UNIT_RANK\x10\x05\x12\x13\n" +
+	"\x0fSCORE_UNIT_TIME\x10\x06*r\n" +
+	"\x0eScoreDirection\x12\x1f\n" +
+	"\x1bSCORE_DIRECTION_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bSCORE_DIRECTION_HIGHER_WINS\x10\x01\x12\x1e\n" +
+	"\x1aSCORE_DIRECTION_LOWER_WINS\x10\x02B3Z1github.com/ngoldack/dicetrace/package/core/corepbb\x06proto3"
+
+var (
+	file_core_proto_rawDescOnce sync.Once
+	file_core_proto_rawDescData []byte
+)
+
+func file_core_proto_rawDescGZIP() []byte {
+	file_core_proto_rawDescOnce.Do(func() {
+		file_core_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_core_proto_rawDesc), len(file_core_proto_rawDesc)))
+	})
+	return file_core_proto_rawDescData
+}
+
+var file_core_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_core_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_core_proto_goTypes = []any{
+	(AttendeeStatus)(0),           // 0: dicetrace.core.v1.AttendeeStatus
+	(EventStatus)(0),              // 1: dicetrace.core.v1.EventStatus
+	(MatchStatus)(0),              // 2: dicetrace.core.v1.MatchStatus
+	(ScoreUnit)(0),                // 3: dicetrace.core.v1.ScoreUnit
+	(ScoreDirection)(0),           // 4: dicetrace.core.v1.ScoreDirection
+	(*User)(nil),                  // 5: dicetrace.core.v1.User
+	(*Attendee)(nil),              // 6: dicetrace.core.v1.Attendee
+	(*Event)(nil),                 // 7: dicetrace.core.v1.Event
+	(*Match)(nil),                 // 8: dicetrace.core.v1.Match
+	(*Team)(nil),                  // 9: dicetrace.core.v1.Team
+	(*ScoreLine)(nil),             // 10: dicetrace.core.v1.ScoreLine
+	(*Score)(nil),                 // 11: dicetrace.core.v1.Score
+	(*TeamScore)(nil),             // 12: dicetrace.core.v1.TeamScore
+	(*Scoreboard)(nil),            // 13: dicetrace.core.v1.Scoreboard
+	(*Scoring)(nil),               // 14: dicetrace.core.v1.Scoring
+	(*Game)(nil),                  // 15: dicetrace.core.v1.Game
+	(*timestamppb.Timestamp)(nil), // 16: google.protobuf.Timestamp
+}
+var file_core_proto_depIdxs = []int32{
+	5,  // 0: dicetrace.core.v1.Attendee.user:type_name -> dicetrace.core.v1.User
+	0,  // 1: dicetrace.core.v1.Attendee.status:type_name -> dicetrace.core.v1.AttendeeStatus
+	1,  // 2: dicetrace.core.v1.Event.status:type_name -> dicetrace.core.v1.EventStatus
+	16, // 3: dicetrace.core.v1.Event.starts_at:type_name -> google.protobuf.Timestamp
+	16, // 4: dicetrace.core.v1.Event.ends_at:type_name -> google.protobuf.Timestamp
+	5,  // 5: dicetrace.core.v1.Event.host:type_name -> dicetrace.core.v1.User
+	6,  // 6: dicetrace.core.v1.Event.attendees:type_name -> dicetrace.core.v1.Attendee
+	8,  // 7: dicetrace.core.v1.Event.matches:type_name -> dicetrace.core.v1.Match
+	2,  // 8: dicetrace.core.v1.Match.status:type_name -> dicetrace.core.v1.MatchStatus
+	15, // 9: dicetrace.core.v1.Match.game:type_name -> dicetrace.core.v1.Game
+	5,  // 10: dicetrace.core.v1.Match.players:type_name -> dicetrace.core.v1.User
+	9,  // 11: dicetrace.core.v1.Match.teams:type_name -> dicetrace.core.v1.Team
+	16, // 12: dicetrace.core.v1.Match.started_at:type_name -> google.protobuf.Timestamp
+	16, // 13: dicetrace.core.v1.Match.ended_at:type_name -> google.protobuf.Timestamp
+	13, // 14: dicetrace.core.v1.Match.scoreboard:type_name -> dicetrace.core.v1.Scoreboard
+	10, // 15: dicetrace.core.v1.Score.breakdown:type_name -> dicetrace.core.v1.ScoreLine
+	10, // 16: dicetrace.core.v1.TeamScore.breakdown:type_name -> dicetrace.core.v1.ScoreLine
+	11, // 17: dicetrace.core.v1.Scoreboard.scores:type_name -> dicetrace.core.v1.Score
+	12, // 18: dicetrace.core.v1.Scoreboard.team_scores:type_name -> dicetrace.core.v1.TeamScore
+	3,  // 19: dicetrace.core.v1.Scoreboard.score_unit:type_name -> dicetrace.core.v1.ScoreUnit
+	4,  // 20: dicetrace.core.v1.Scoreboard.direction:type_name -> dicetrace.core.v1.ScoreDirection
+	3,  // 21: dicetrace.core.v1.Scoring.unit:type_name -> dicetrace.core.v1.ScoreUnit
+	4,  // 22: dicetrace.core.v1.Scoring.direction:type_name -> dicetrace.core.v1.ScoreDirection
+	14, // 23: dicetrace.core.v1.Game.scoring:type_name -> dicetrace.core.v1.Scoring
+	24, // [24:24] is the sub-list for method output_type
+	24, // [24:24] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
+}
+
+func init() { file_core_proto_init() }
+func file_core_proto_init() {
+	if File_core_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_core_proto_rawDesc), len(file_core_proto_rawDesc)),
+			NumEnums:      5,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_core_proto_goTypes,
+		DependencyIndexes: file_core_proto_depIdxs,
+		EnumInfos:         file_core_proto_enumTypes,
+		MessageInfos:      file_core_proto_msgTypes,
+	}.Build()
+	File_core_proto = out.File
+	file_core_proto_goTypes = nil
+	file_core_proto_depIdxs = nil
+}
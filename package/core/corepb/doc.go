@@ -0,0 +1,6 @@
+// Package corepb holds the protobuf messages of the core types, generated
+// from core.proto. Use the converters of package core, like
+// core.MatchFromProto, to work with them.
+package corepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative core.proto
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.jetify.com/typeid/v2"
+)
+
+// DomainEventType names a state change as "<aggregate>.<change>", e.g.
+// "match.finalized". The aggregate is the kind of entity that changed.
+type DomainEventType string
+
+// Domain event types and their payloads.
+const (
+	// DomainEventUserSaved carries the saved User.
+	DomainEventUserSaved DomainEventType = "user.saved"
+
+	// DomainEventEventCreated carries the created Event.
+	DomainEventEventCreated DomainEventType = "event.created"
+	// DomainEventEventTransitioned carries the Event in its new status.
+	DomainEventEventTransitioned DomainEventType = "event.transitioned"
+	// DomainEventEventRescheduled carries the Event at its new times.
+	DomainEventEventRescheduled DomainEventType = "event.rescheduled"
+	// DomainEventAttendeeAdded carries the added Attendee.
+	DomainEventAttendeeAdded DomainEventType = "event.attendee_added"
+	// DomainEventAttendeeRemoved carries the removed attendee's User; only
+	// the user ID is set.
+	DomainEventAttendeeRemoved DomainEventType = "event.attendee_removed"
+	// DomainEventAttendeesUpdated carries the Event with its new attendees.
+	DomainEventAttendeesUpdated DomainEventType = "event.attendees_updated"
+	// DomainEventTournamentCreated carries the tournament.Tournament the
+	// event runs as.
+	DomainEventTournamentCreated DomainEventType = "event.tournament_created"
+	// DomainEventTournamentRoundStarted carries the tournament.Tournament
+	// with its new round. The round's matches are announced as started.
+	DomainEventTournamentRoundStarted DomainEventType = "event.tournament_round_started"
+
+	// DomainEventMatchRecorded carries a Match recorded in one go.
+	DomainEventMatchRecorded DomainEventType = "match.recorded"
+	// DomainEventMatchStarted carries the Match started in progress.
+	DomainEventMatchStarted DomainEventType = "match.started"
+	// DomainEventMatchUpdated carries the Match after a change in progress.
+	DomainEventMatchUpdated DomainEventType = "match.updated"
+	// DomainEventMatchFinalized carries the MatchFinalized event.
+	DomainEventMatchFinalized DomainEventType = "match.finalized"
+	// DomainEventMatchCorrected carries the MatchFinalized event of a
+	// finalized match whose result was corrected. Results derived from the
+	// match must be recomputed.
+	DomainEventMatchCorrected DomainEventType = "match.corrected"
+)
+
+// Aggregate returns the kind of entity the event type changes, e.g.
+// "match".
+func (t DomainEventType) Aggregate() string {
+	aggregate, _, _ := strings.Cut(string(t), ".")
+	return aggregate
+}
+
+// DomainEventVersion is the version of the payloads of new domain events.
+// It is increased when a payload changes incompatibly.
+const DomainEventVersion = 1
+
+// DomainEvent is the envelope every state change is announced in.
+type DomainEvent struct {
+	// ID identifies the event, so consumers can drop redeliveries.
+	ID   DomainEventID   `json:"id" typeid:"domainevent"`
+	Type DomainEventType `json:"type"`
+	// AggregateID identifies the changed entity, e.g. the MatchID.
+	AggregateID typeid.TypeID `json:"aggregate_id"`
+	// Version is the payload version, see DomainEventVersion.
+	Version    int             `json:"version"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// NewDomainEvent returns an event of the change with the payload encoded as
+// JSON.
+func NewDomainEvent(eventType DomainEventType, aggregateID typeid.TypeID, occurredAt time.Time, payload any) (*DomainEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s payload: %w", eventType, err)
+	}
+
+	return &DomainEvent{
+		ID:          NewDomainEventID(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Version:     DomainEventVersion,
+		OccurredAt:  occurredAt,
+		Payload:     data,
+	}, nil
+}
+
+// DecodePayload unmarshals the payload into v, which should match the
+// payload documented for the event's type.
+func (e *DomainEvent) DecodePayload(v any) error {
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return fmt.Errorf("failed to unmarshal %s payload of domain event '%s': %w", e.Type, e.ID, err)
+	}
+	return nil
+}
+
+// DomainEventPublisher announces state changes once they are stored.
+type DomainEventPublisher interface {
+	PublishDomainEvent(ctx context.Context, evt *DomainEvent) error
+}
@@ -0,0 +1,41 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainEventTypeAggregate(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "user", core.DomainEventUserSaved.Aggregate())
+	assert.Equal(t, "event", core.DomainEventAttendeesUpdated.Aggregate())
+	assert.Equal(t, "match", core.DomainEventMatchFinalized.Aggregate())
+}
+
+func TestNewDomainEvent(t *testing.T) {
+	t.Parallel()
+	alice := newPlayer("alice")
+	occurredAt := time.Date(2025, time.March, 6, 21, 0, 0, 0, time.UTC)
+
+	evt, err := core.NewDomainEvent(core.DomainEventUserSaved, alice.UserID, occurredAt, alice)
+	require.NoError(t, err)
+	assert.Equal(t, "domainevent", evt.ID.Prefix())
+	assert.Equal(t, core.DomainEventUserSaved, evt.Type)
+	assert.Equal(t, alice.UserID, evt.AggregateID)
+	assert.Equal(t, core.DomainEventVersion, evt.Version)
+	assert.Equal(t, occurredAt, evt.OccurredAt)
+
+	var payload core.User
+	require.NoError(t, evt.DecodePayload(&payload))
+	assert.Equal(t, alice, payload)
+
+	var wrong []core.User
+	assert.Error(t, evt.DecodePayload(&wrong))
+
+	_, err = core.NewDomainEvent(core.DomainEventUserSaved, alice.UserID, occurredAt, func() {})
+	assert.Error(t, err)
+}
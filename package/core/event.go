@@ -0,0 +1,81 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// CountAttendees returns the number of attendees with the given status.
+func (e *Event) CountAttendees(status AttendeeStatus) int {
+	n := 0
+	for _, a := range e.Attendees {
+		if a.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Localize converts StartsAt, EndsAt and the match times into the event's
+// Timezone. Events without a timezone are left untouched.
+func (e *Event) Localize() error {
+	if e.Timezone == "" {
+		return nil
+	}
+
+	loc, err := time.LoadLocation(e.Timezone)
+	if err != nil {
+		return fmt.Errorf("failed to load timezone '%s': %w", e.Timezone, err)
+	}
+
+	if !e.StartsAt.IsZero() {
+		e.StartsAt = e.StartsAt.In(loc)
+	}
+	if !e.EndsAt.IsZero() {
+		e.EndsAt = e.EndsAt.In(loc)
+	}
+
+	for i := range e.Matches {
+		m := &e.Matches[i]
+		if !m.StartedAt.IsZero() {
+			m.StartedAt = m.StartedAt.In(loc)
+		}
+		if !m.EndedAt.IsZero() {
+			m.EndedAt = m.EndedAt.In(loc)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the event's scheduling fields and matches. It returns a
+// *ValidationError listing every offending field.
+func (e *Event) Validate() error {
+	verr := &ValidationError{}
+
+	if e.Timezone != "" {
+		if _, err := time.LoadLocation(e.Timezone); err != nil {
+			verr.Add("timezone", "unknown time zone '%s'", e.Timezone)
+		}
+	}
+
+	if !e.EndsAt.IsZero() {
+		if e.StartsAt.IsZero() {
+			verr.Add("ends_at", "must not be set without starts_at")
+		} else if !e.EndsAt.After(e.StartsAt) {
+			verr.Add("ends_at", "must be after starts_at")
+		}
+	}
+
+	if e.Capacity < 0 {
+		verr.Add("capacity", "must not be negative")
+	} else if confirmed := e.CountAttendees(AttendeeStatusConfirmed); e.Capacity > 0 && confirmed > e.Capacity {
+		verr.Add("capacity", "%d confirmed attendees exceed the capacity of %d", confirmed, e.Capacity)
+	}
+
+	for i := range e.Matches {
+		e.Matches[i].validate(verr, fmt.Sprintf("matches[%d].", i))
+	}
+
+	return verr.Err()
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// EventTransition names a change of an event's status.
+type EventTransition string
+
+const (
+	EventTransitionSchedule EventTransition = "schedule"
+	EventTransitionStart    EventTransition = "start"
+	EventTransitionComplete EventTransition = "complete"
+	EventTransitionCancel   EventTransition = "cancel"
+)
+
+var (
+	// ErrInvalidTransition is returned when a transition is unknown or not
+	// allowed from the event's current status.
+	ErrInvalidTransition = errors.New("invalid event transition")
+	// ErrTransitionGuard is returned when a transition is allowed from the
+	// current status but the event does not satisfy its preconditions.
+	ErrTransitionGuard = errors.New("event transition guard failed")
+	// ErrEventClosed is returned when mutating a completed or cancelled event.
+	ErrEventClosed = errors.New("event is closed")
+)
+
+// TransitionError describes a rejected event transition. It wraps either
+// ErrInvalidTransition or ErrTransitionGuard.
+type TransitionError struct {
+	Transition EventTransition
+	From       EventStatus
+	Reason     string
+	Err        error
+}
+
+func (e *TransitionError) Error() string {
+	from := string(e.From)
+	if from == "" {
+		from = "unscheduled"
+	}
+	return fmt.Sprintf("cannot %s event in status '%s': %s", e.Transition, from, e.Reason)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return e.Err
+}
+
+type eventTransition struct {
+	from []EventStatus
+	to   EventStatus
+	// guard returns a reason why the event may not transition, or "" if it may.
+	guard func(e *Event) string
+}
+
+var eventTransitions = map[EventTransition]eventTransition{
+	EventTransitionSchedule: {
+		// Cancelled events may be rescheduled.
+		from: []EventStatus{"", EventStatusCancelled},
+		to:   EventStatusScheduled,
+		guard: func(e *Event) string {
+			if e.StartsAt.IsZero() {
+				return "start time is not set"
+			}
+			return ""
+		},
+	},
+	EventTransitionStart: {
+		from: []EventStatus{EventStatusScheduled},
+		to:   EventStatusOngoing,
+		guard: func(e *Event) string {
+			if e.CountAttendees(AttendeeStatusConfirmed) == 0 {
+				return "no confirmed attendees"
+			}
+			return ""
+		},
+	},
+	EventTransitionComplete: {
+		from: []EventStatus{EventStatusOngoing},
+		to:   EventStatusCompleted,
+	},
+	EventTransitionCancel: {
+		from: []EventStatus{EventStatusScheduled, EventStatusOngoing},
+		to:   EventStatusCancelled,
+	},
+}
+
+// CanTransition reports whether the transition may be applied to the event
+// without changing it. The returned error is a *TransitionError.
+func (e *Event) CanTransition(t EventTransition) error {
+	_, err := e.nextStatus(t)
+	return err
+}
+
+// Transition applies the transition to the event's status. The returned
+// error is a *TransitionError.
+func (e *Event) Transition(t EventTransition) error {
+	next, err := e.nextStatus(t)
+	if err != nil {
+		return err
+	}
+
+	e.Status = next
+
+	return nil
+}
+
+func (e *Event) nextStatus(t EventTransition) (EventStatus, error) {
+	rule, ok := eventTransitions[t]
+	if !ok {
+		return "", &TransitionError{Transition: t, From: e.Status, Reason: "unknown transition", Err: ErrInvalidTransition}
+	}
+
+	if !slices.Contains(rule.from, e.Status) {
+		return "", &TransitionError{Transition: t, From: e.Status, Reason: "not allowed from this status", Err: ErrInvalidTransition}
+	}
+
+	if rule.guard != nil {
+		if reason := rule.guard(e); reason != "" {
+			return "", &TransitionError{Transition: t, From: e.Status, Reason: reason, Err: ErrTransitionGuard}
+		}
+	}
+
+	return rule.to, nil
+}
+
+// CheckMutable returns ErrEventClosed if the event's attendees and matches
+// may no longer change.
+func (e *Event) CheckMutable() error {
+	if e.Status == EventStatusCompleted || e.Status == EventStatusCancelled {
+		return fmt.Errorf("event '%s' is %s: %w", e.EventID, e.Status, ErrEventClosed)
+	}
+	return nil
+}
+
+// CheckNew verifies that a newly created event starts in an initial status:
+// either unscheduled, or scheduled with the schedule guards satisfied.
+func (e *Event) CheckNew() error {
+	switch e.Status {
+	case "":
+		return nil
+	case EventStatusScheduled:
+		draft := *e
+		draft.Status = ""
+		return draft.CanTransition(EventTransitionSchedule)
+	default:
+		return &TransitionError{
+			Transition: EventTransitionSchedule,
+			From:       e.Status,
+			Reason:     "new events must be unscheduled or scheduled",
+			Err:        ErrInvalidTransition,
+		}
+	}
+}
+
+// Reschedule moves an unscheduled or scheduled event to new start and end
+// times. Events that already started or were closed can't be rescheduled.
+func (e *Event) Reschedule(startsAt, endsAt time.Time) error {
+	if e.Status != "" && e.Status != EventStatusScheduled {
+		return &TransitionError{
+			Transition: EventTransitionSchedule,
+			From:       e.Status,
+			Reason:     "only unscheduled or scheduled events can be rescheduled",
+			Err:        ErrInvalidTransition,
+		}
+	}
+
+	e.StartsAt = startsAt
+	e.EndsAt = endsAt
+
+	return e.Localize()
+}
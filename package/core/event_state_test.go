@@ -0,0 +1,186 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventTransition(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, time.March, 6, 19, 0, 0, 0, time.UTC)
+	confirmed := []core.Attendee{newAttendee(core.AttendeeStatusConfirmed)}
+
+	testCases := []struct {
+		name       string
+		event      core.Event
+		transition core.EventTransition
+		want       core.EventStatus
+		wantErr    error
+	}{
+		{
+			name:       "schedule new event",
+			event:      core.Event{StartsAt: start},
+			transition: core.EventTransitionSchedule,
+			want:       core.EventStatusScheduled,
+		},
+		{
+			name:       "schedule without start time",
+			event:      core.Event{},
+			transition: core.EventTransitionSchedule,
+			wantErr:    core.ErrTransitionGuard,
+		},
+		{
+			name:       "reschedule cancelled event",
+			event:      core.Event{Status: core.EventStatusCancelled, StartsAt: start},
+			transition: core.EventTransitionSchedule,
+			want:       core.EventStatusScheduled,
+		},
+		{
+			name:       "schedule completed event",
+			event:      core.Event{Status: core.EventStatusCompleted, StartsAt: start},
+			transition: core.EventTransitionSchedule,
+			wantErr:    core.ErrInvalidTransition,
+		},
+		{
+			name:       "start scheduled event",
+			event:      core.Event{Status: core.EventStatusScheduled, Attendees: confirmed},
+			transition: core.EventTransitionStart,
+			want:       core.EventStatusOngoing,
+		},
+		{
+			name: "start without confirmed attendees",
+			event: core.Event{
+				Status:    core.EventStatusScheduled,
+				Attendees: []core.Attendee{newAttendee(core.AttendeeStatusPending)},
+			},
+			transition: core.EventTransitionStart,
+			wantErr:    core.ErrTransitionGuard,
+		},
+		{
+			name:       "start unscheduled event",
+			event:      core.Event{Attendees: confirmed},
+			transition: core.EventTransitionStart,
+			wantErr:    core.ErrInvalidTransition,
+		},
+		{
+			name:       "complete ongoing event",
+			event:      core.Event{Status: core.EventStatusOngoing},
+			transition: core.EventTransitionComplete,
+			want:       core.EventStatusCompleted,
+		},
+		{
+			name:       "complete scheduled event",
+			event:      core.Event{Status: core.EventStatusScheduled},
+			transition: core.EventTransitionComplete,
+			wantErr:    core.ErrInvalidTransition,
+		},
+		{
+			name:       "cancel scheduled event",
+			event:      core.Event{Status: core.EventStatusScheduled},
+			transition: core.EventTransitionCancel,
+			want:       core.EventStatusCancelled,
+		},
+		{
+			name:       "cancel ongoing event",
+			event:      core.Event{Status: core.EventStatusOngoing},
+			transition: core.EventTransitionCancel,
+			want:       core.EventStatusCancelled,
+		},
+		{
+			name:       "cancel completed event",
+			event:      core.Event{Status: core.EventStatusCompleted},
+			transition: core.EventTransitionCancel,
+			wantErr:    core.ErrInvalidTransition,
+		},
+		{
+			name:       "unknown transition",
+			event:      core.Event{Status: core.EventStatusScheduled},
+			transition: "postpone",
+			wantErr:    core.ErrInvalidTransition,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			evt := tc.event
+			from := evt.Status
+
+			err := evt.Transition(tc.transition)
+			if tc.wantErr == nil {
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, evt.Status)
+				return
+			}
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, from, evt.Status, "status must not change on error")
+
+			var terr *core.TransitionError
+			require.ErrorAs(t, err, &terr)
+			assert.Equal(t, tc.transition, terr.Transition)
+			assert.Equal(t, from, terr.From)
+		})
+	}
+}
+
+func TestEventCanTransition(t *testing.T) {
+	t.Parallel()
+	evt := core.Event{Status: core.EventStatusOngoing}
+
+	require.NoError(t, evt.CanTransition(core.EventTransitionComplete))
+	assert.Equal(t, core.EventStatusOngoing, evt.Status)
+}
+
+func TestEventCheckMutable(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		status  core.EventStatus
+		mutable bool
+	}{
+		{"", true},
+		{core.EventStatusScheduled, true},
+		{core.EventStatusOngoing, true},
+		{core.EventStatusCompleted, false},
+		{core.EventStatusCancelled, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.status), func(t *testing.T) {
+			evt := core.Event{EventID: core.NewEventID(), Status: tc.status}
+			err := evt.CheckMutable()
+			if tc.mutable {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, core.ErrEventClosed)
+			}
+		})
+	}
+}
+
+func TestEventCheckNew(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, time.March, 6, 19, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, (&core.Event{}).CheckNew())
+	assert.NoError(t, (&core.Event{Status: core.EventStatusScheduled, StartsAt: start}).CheckNew())
+	assert.ErrorIs(t, (&core.Event{Status: core.EventStatusScheduled}).CheckNew(), core.ErrTransitionGuard)
+	assert.ErrorIs(t, (&core.Event{Status: core.EventStatusCompleted, StartsAt: start}).CheckNew(), core.ErrInvalidTransition)
+}
+
+func TestEventReschedule(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, time.March, 6, 17, 0, 0, 0, time.UTC)
+
+	evt := core.Event{Status: core.EventStatusScheduled, Timezone: "Europe/Berlin"}
+	require.NoError(t, evt.Reschedule(start, start.Add(3*time.Hour)))
+	assert.True(t, start.Equal(evt.StartsAt))
+	assert.Equal(t, "Europe/Berlin", evt.StartsAt.Location().String())
+
+	ongoing := core.Event{Status: core.EventStatusOngoing}
+	assert.ErrorIs(t, ongoing.Reschedule(start, time.Time{}), core.ErrInvalidTransition)
+	assert.True(t, ongoing.StartsAt.IsZero())
+}
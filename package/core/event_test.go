@@ -0,0 +1,133 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAttendee(status core.AttendeeStatus) core.Attendee {
+	return core.Attendee{
+		User: core.User{
+			UserID:   core.NewUserID(),
+			Username: "testuser",
+		},
+		Status: status,
+	}
+}
+
+func TestEventValidate(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, time.March, 6, 19, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name   string
+		event  core.Event
+		fields []string
+	}{
+		{
+			name:  "unscheduled",
+			event: core.Event{},
+		},
+		{
+			name: "valid schedule",
+			event: core.Event{
+				StartsAt: start,
+				EndsAt:   start.Add(4 * time.Hour),
+				Timezone: "Europe/Berlin",
+				Capacity: 2,
+				Attendees: []core.Attendee{
+					newAttendee(core.AttendeeStatusConfirmed),
+					newAttendee(core.AttendeeStatusConfirmed),
+					newAttendee(core.AttendeeStatusPending),
+				},
+			},
+		},
+		{
+			name:   "end before start",
+			event:  core.Event{StartsAt: start, EndsAt: start.Add(-time.Hour)},
+			fields: []string{"ends_at"},
+		},
+		{
+			name:   "end equals start",
+			event:  core.Event{StartsAt: start, EndsAt: start},
+			fields: []string{"ends_at"},
+		},
+		{
+			name:   "end without start",
+			event:  core.Event{EndsAt: start},
+			fields: []string{"ends_at"},
+		},
+		{
+			name:   "unknown timezone",
+			event:  core.Event{Timezone: "Mars/Olympus_Mons"},
+			fields: []string{"timezone"},
+		},
+		{
+			name:   "negative capacity",
+			event:  core.Event{Capacity: -1},
+			fields: []string{"capacity"},
+		},
+		{
+			name: "over capacity",
+			event: core.Event{
+				Capacity: 1,
+				Attendees: []core.Attendee{
+					newAttendee(core.AttendeeStatusConfirmed),
+					newAttendee(core.AttendeeStatusConfirmed),
+				},
+			},
+			fields: []string{"capacity"},
+		},
+		{
+			name: "multiple errors",
+			event: core.Event{
+				StartsAt: start,
+				EndsAt:   start,
+				Timezone: "Nowhere",
+				Capacity: -3,
+			},
+			fields: []string{"timezone", "ends_at", "capacity"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.event.Validate()
+			if len(tc.fields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var verr *core.ValidationError
+			require.ErrorAs(t, err, &verr)
+
+			fields := make([]string, 0, len(verr.Errors))
+			for _, fe := range verr.Errors {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, tc.fields, fields)
+		})
+	}
+}
+
+func TestEventLocalize(t *testing.T) {
+	t.Parallel()
+	evt := core.Event{
+		StartsAt: time.Date(2025, time.January, 9, 18, 0, 0, 0, time.UTC),
+		Timezone: "America/New_York",
+		Matches: []core.Match{
+			{StartedAt: time.Date(2025, time.January, 9, 19, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	require.NoError(t, evt.Localize())
+	assert.Equal(t, "America/New_York", evt.StartsAt.Location().String())
+	assert.Equal(t, 13, evt.StartsAt.Hour())
+	assert.True(t, evt.EndsAt.IsZero())
+	assert.Equal(t, 14, evt.Matches[0].StartedAt.Hour())
+	assert.True(t, evt.Matches[0].EndedAt.IsZero())
+}
@@ -4,12 +4,20 @@ go 1.25.3
 
 require (
 	github.com/golang-cz/devslog v0.0.15
+	github.com/invopop/jsonschema v0.14.0
+	github.com/vmihailenco/msgpack/v5 v5.4.1
 	go.jetify.com/typeid/v2 v2.0.0-alpha.3
+	google.golang.org/protobuf v1.36.10
 )
 
 require (
+	github.com/bahlo/generic-list-go v0.2.0 // indirect
+	github.com/buger/jsonparser v1.1.2 // indirect
 	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
 	github.com/gofrs/uuid/v5 v5.3.2 // indirect
+	github.com/pb33f/ordered-map/v2 v2.3.1 // indirect
 	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
 	github.com/stretchr/testify v1.11.1 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	go.yaml.in/yaml/v4 v4.0.0-rc.2 // indirect
 )
@@ -0,0 +1,312 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"go.jetify.com/typeid/v2"
+)
+
+// Decode reads an iCalendar stream. Every VEVENT becomes an event; other
+// components are ignored. Events created by other applications get a new
+// event ID, attendees a new user ID.
+func Decode(r io.Reader) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &Calendar{Events: []*core.Event{}}
+	var stack []string
+	var props []property
+
+	for i, raw := range lines {
+		p, err := parseLine(raw)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		component := strings.ToUpper(p.value)
+		switch p.name {
+		case "BEGIN":
+			stack = append(stack, component)
+			if component == "VEVENT" {
+				props = nil
+			}
+			continue
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1] != component {
+				return nil, fmt.Errorf("line %d: unexpected END:%s: %w", i+1, p.value, ErrInvalid)
+			}
+			stack = stack[:len(stack)-1]
+
+			if component == "VEVENT" {
+				evt, err := decodeEvent(props)
+				if err != nil {
+					return nil, err
+				}
+				cal.Events = append(cal.Events, evt)
+			}
+			continue
+		}
+
+		switch {
+		case len(stack) == 1 && stack[0] == "VCALENDAR" && p.name == "X-WR-CALNAME":
+			cal.Name = unescapeText(p.value)
+		case len(stack) == 2 && stack[1] == "VEVENT":
+			props = append(props, p)
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("unterminated %s: %w", stack[len(stack)-1], ErrInvalid)
+	}
+
+	return cal, nil
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// unfold joins folded content lines.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+
+	return lines, nil
+}
+
+// parseLine splits a content line into name, parameters and value.
+// Parameter values may be quoted and contain ';', ':' and ','.
+func parseLine(line string) (property, error) {
+	p := property{params: make(map[string]string)}
+
+	end := strings.IndexAny(line, ";:")
+	if end <= 0 {
+		return p, fmt.Errorf("malformed content line: %w", ErrInvalid)
+	}
+	p.name = strings.ToUpper(line[:end])
+	rest := line[end:]
+
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return p, fmt.Errorf("malformed parameter in %s: %w", p.name, ErrInvalid)
+		}
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			closing := strings.IndexByte(rest[1:], '"')
+			if closing < 0 {
+				return p, fmt.Errorf("unterminated quote in %s: %w", p.name, ErrInvalid)
+			}
+			value = rest[1 : closing+1]
+			rest = rest[closing+2:]
+		} else {
+			stop := strings.IndexAny(rest, ";:")
+			if stop < 0 {
+				return p, fmt.Errorf("missing value in %s: %w", p.name, ErrInvalid)
+			}
+			value = rest[:stop]
+			rest = rest[stop:]
+		}
+
+		p.params[name] = value
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return p, fmt.Errorf("missing value in %s: %w", p.name, ErrInvalid)
+	}
+	p.value = rest[1:]
+
+	return p, nil
+}
+
+func decodeEvent(props []property) (*core.Event, error) {
+	evt := &core.Event{
+		Attendees: []core.Attendee{},
+		Matches:   []core.Match{},
+	}
+
+	get := func(name string) (property, bool) {
+		for _, p := range props {
+			if p.name == name {
+				return p, true
+			}
+		}
+		return property{}, false
+	}
+
+	uid, _ := get("UID")
+	evt.EventID = eventIDFromUID(uid.value)
+
+	if tz, ok := get(propTimezone); ok {
+		evt.Timezone = tz.value
+	}
+
+	start, ok := get("DTSTART")
+	if !ok {
+		return nil, fmt.Errorf("event '%s': missing DTSTART: %w", uid.value, ErrInvalid)
+	}
+	if evt.Timezone == "" {
+		evt.Timezone = start.params["TZID"]
+	}
+
+	var err error
+	if evt.StartsAt, err = parseTime(start, evt.Timezone); err != nil {
+		return nil, fmt.Errorf("event '%s': DTSTART: %w", uid.value, err)
+	}
+	if end, ok := get("DTEND"); ok {
+		if evt.EndsAt, err = parseTime(end, evt.Timezone); err != nil {
+			return nil, fmt.Errorf("event '%s': DTEND: %w", uid.value, err)
+		}
+	}
+
+	if p, ok := get("SUMMARY"); ok {
+		evt.Title = unescapeText(p.value)
+	}
+	if p, ok := get("LOCATION"); ok {
+		evt.Location = unescapeText(p.value)
+	}
+
+	evt.Status = core.EventStatusScheduled
+	if p, ok := get("STATUS"); ok && strings.EqualFold(p.value, "CANCELLED") {
+		evt.Status = core.EventStatusCancelled
+	}
+	if p, ok := get(propStatus); ok {
+		switch status := core.EventStatus(p.value); status {
+		case core.EventStatusScheduled, core.EventStatusOngoing, core.EventStatusCompleted, core.EventStatusCancelled:
+			evt.Status = status
+		}
+	}
+
+	if p, ok := get(propCapacity); ok {
+		if evt.Capacity, err = strconv.Atoi(p.value); err != nil {
+			return nil, fmt.Errorf("event '%s': invalid capacity '%s': %w", uid.value, p.value, ErrInvalid)
+		}
+	}
+
+	for _, p := range props {
+		switch p.name {
+		case "ORGANIZER":
+			host := userFromProperty(p)
+			evt.Host = &host
+		case "ATTENDEE":
+			evt.Attendees = append(evt.Attendees, core.Attendee{
+				User:   userFromProperty(p),
+				Status: statusFromProperty(p),
+			})
+		}
+	}
+
+	if err := evt.Validate(); err != nil {
+		return nil, fmt.Errorf("event '%s': %w", uid.value, err)
+	}
+	if err := evt.Localize(); err != nil {
+		return nil, fmt.Errorf("event '%s': %w", uid.value, err)
+	}
+
+	return evt, nil
+}
+
+func eventIDFromUID(uid string) core.EventID {
+	if id, err := typeid.Parse(strings.TrimSuffix(uid, uidDomain)); err == nil && id.Prefix() == "event" {
+		return id
+	}
+	return core.NewEventID()
+}
+
+// parseTime parses DATE-TIME values in UTC, with a TZID or floating, and
+// DATE values. Floating times and dates are interpreted in the event's
+// timezone, falling back to UTC.
+func parseTime(p property, timezone string) (time.Time, error) {
+	loc := time.UTC
+	if tzid := p.params["TZID"]; tzid != "" {
+		timezone = tzid
+	}
+	if timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return time.Time{}, fmt.Errorf("unknown timezone '%s': %w", timezone, ErrInvalid)
+		}
+	}
+
+	value := p.value
+	layout := dateTimeLayout
+	switch {
+	case p.params["VALUE"] == "DATE" || len(value) == len(dateLayout):
+		layout = dateLayout
+	case strings.HasSuffix(value, "Z"):
+		value = strings.TrimSuffix(value, "Z")
+		loc = time.UTC
+	}
+
+	t, err := time.ParseInLocation(layout, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time '%s': %w", p.value, ErrInvalid)
+	}
+
+	return t, nil
+}
+
+func userFromProperty(p property) core.User {
+	user := core.User{
+		Username:    p.params["CN"],
+		BGGUsername: p.params[paramBGGName],
+	}
+
+	if id, err := typeid.Parse(strings.TrimPrefix(p.value, userURNPrefix)); err == nil && id.Prefix() == "user" {
+		user.UserID = id
+	} else {
+		user.UserID = core.NewUserID()
+	}
+
+	if user.Username == "" {
+		user.Username = strings.TrimPrefix(strings.ToLower(p.value), "mailto:")
+	}
+
+	return user
+}
+
+func statusFromProperty(p property) core.AttendeeStatus {
+	switch status := core.AttendeeStatus(p.params[paramStatus]); status {
+	case core.AttendeeStatusConfirmed, core.AttendeeStatusPending, core.AttendeeStatusDeclined, core.AttendeeStatusWaitlisted:
+		return status
+	}
+
+	if status, ok := attendeeStatus[strings.ToUpper(p.params["PARTSTAT"])]; ok {
+		return status
+	}
+	return core.AttendeeStatusPending
+}
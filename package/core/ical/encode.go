@@ -0,0 +1,135 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ngoldack/dicetrace/package/core"
+)
+
+// maxLineOctets is the maximum length of a content line before folding.
+const maxLineOctets = 75
+
+// Encode writes the calendar as an iCalendar stream. Every event must have
+// a start time.
+func Encode(w io.Writer, cal *Calendar) error {
+	return encode(w, cal, time.Now())
+}
+
+func encode(w io.Writer, cal *Calendar, now time.Time) error {
+	e := &encoder{w: bufio.NewWriter(w)}
+
+	e.line("BEGIN", nil, "VCALENDAR")
+	e.line("VERSION", nil, "2.0")
+	e.line("PRODID", nil, prodID)
+	e.line("CALSCALE", nil, "GREGORIAN")
+	e.line("METHOD", nil, "PUBLISH")
+	if cal.Name != "" {
+		e.line("X-WR-CALNAME", nil, escapeText(cal.Name))
+	}
+
+	for _, evt := range cal.Events {
+		if evt.StartsAt.IsZero() {
+			return fmt.Errorf("event '%s': %w", evt.EventID, ErrNotScheduled)
+		}
+		e.event(evt, now)
+	}
+
+	e.line("END", nil, "VCALENDAR")
+
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+type param struct {
+	name, value string
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) event(evt *core.Event, now time.Time) {
+	e.line("BEGIN", nil, "VEVENT")
+	e.line("UID", nil, evt.EventID.String()+uidDomain)
+	e.line("DTSTAMP", nil, formatUTC(now))
+	e.line("DTSTART", nil, formatUTC(evt.StartsAt))
+	if !evt.EndsAt.IsZero() {
+		e.line("DTEND", nil, formatUTC(evt.EndsAt))
+	}
+	e.line("SUMMARY", nil, escapeText(evt.Title))
+	if evt.Location != "" {
+		e.line("LOCATION", nil, escapeText(evt.Location))
+	}
+	e.line("STATUS", nil, eventStatus(evt.Status))
+
+	if evt.Host != nil {
+		e.line("ORGANIZER", userParams(*evt.Host), userURNPrefix+evt.Host.UserID.String())
+	}
+	for _, a := range evt.Attendees {
+		params := append(userParams(a.User),
+			param{"PARTSTAT", partStat[a.Status]},
+			param{paramStatus, string(a.Status)},
+		)
+		e.line("ATTENDEE", params, userURNPrefix+a.User.UserID.String())
+	}
+
+	if evt.Timezone != "" {
+		e.line(propTimezone, nil, evt.Timezone)
+	}
+	if evt.Status != "" {
+		e.line(propStatus, nil, string(evt.Status))
+	}
+	if evt.Capacity > 0 {
+		e.line(propCapacity, nil, strconv.Itoa(evt.Capacity))
+	}
+
+	e.line("END", nil, "VEVENT")
+}
+
+func userParams(u core.User) []param {
+	params := []param{{"CN", u.Username}}
+	if u.BGGUsername != "" {
+		params = append(params, param{paramBGGName, u.BGGUsername})
+	}
+	return params
+}
+
+// line writes a content line, folded after maxLineOctets octets without
+// splitting UTF-8 sequences.
+func (e *encoder) line(name string, params []param, value string) {
+	if e.err != nil {
+		return
+	}
+
+	s := name
+	for _, p := range params {
+		s += ";" + p.name + "=" + quoteParam(p.value)
+	}
+	// TEXT values are escaped; other values must not end the line either
+	s += ":" + stripControls(value)
+
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+
+		if _, e.err = e.w.WriteString(s[:cut] + "\r\n "); e.err != nil {
+			return
+		}
+		s = s[cut:]
+		// continuation lines start with a space
+		limit = maxLineOctets - 1
+	}
+
+	_, e.err = e.w.WriteString(s + "\r\n")
+}
@@ -0,0 +1,124 @@
+// Package ical converts events to and from iCalendar (RFC 5545).
+//
+// Times are written in UTC. Fields without an iCalendar equivalent, like the
+// event's timezone, status and capacity, are kept in X-DICETRACE-*
+// properties so events round-trip without loss. Calendars from other
+// applications decode with the standard properties only.
+package ical
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ngoldack/dicetrace/package/core"
+)
+
+const (
+	prodID = "-//dicetrace//scheduler//EN"
+
+	// uidDomain is appended to event IDs to form globally unique UIDs.
+	uidDomain = "@dicetrace"
+	// userURNPrefix prefixes user IDs to form calendar user addresses, as
+	// users do not carry an email address.
+	userURNPrefix = "urn:dicetrace:"
+
+	propTimezone = "X-DICETRACE-TIMEZONE"
+	propStatus   = "X-DICETRACE-STATUS"
+	propCapacity = "X-DICETRACE-CAPACITY"
+	paramStatus  = "X-DICETRACE-STATUS"
+	paramBGGName = "X-DICETRACE-BGG-USERNAME"
+
+	dateTimeLayout = "20060102T150405"
+	dateLayout     = "20060102"
+)
+
+var (
+	ErrNotScheduled = errors.New("event has no start time")
+	ErrInvalid      = errors.New("invalid iCalendar data")
+)
+
+// Calendar is a named collection of events.
+type Calendar struct {
+	Name   string
+	Events []*core.Event
+}
+
+// partStat maps attendee statuses to the iCalendar participation status.
+var partStat = map[core.AttendeeStatus]string{
+	core.AttendeeStatusConfirmed:  "ACCEPTED",
+	core.AttendeeStatusPending:    "NEEDS-ACTION",
+	core.AttendeeStatusDeclined:   "DECLINED",
+	core.AttendeeStatusWaitlisted: "TENTATIVE",
+}
+
+// attendeeStatus maps participation statuses of calendars from other
+// applications back to attendee statuses.
+var attendeeStatus = map[string]core.AttendeeStatus{
+	"ACCEPTED":     core.AttendeeStatusConfirmed,
+	"NEEDS-ACTION": core.AttendeeStatusPending,
+	"TENTATIVE":    core.AttendeeStatusPending,
+	"DECLINED":     core.AttendeeStatusDeclined,
+}
+
+func eventStatus(status core.EventStatus) string {
+	switch status {
+	case core.EventStatusCancelled:
+		return "CANCELLED"
+	case "":
+		return "TENTATIVE"
+	default:
+		return "CONFIRMED"
+	}
+}
+
+func formatUTC(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout) + "Z"
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\r", `\n`, "\n", `\n`)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func unescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// quoteParam quotes parameter values containing characters that would end
+// the parameter. Control characters, which parameter values cannot contain
+// and which would end the content line, are removed.
+func quoteParam(s string) string {
+	s = stripControls(strings.ReplaceAll(s, `"`, "'"))
+	if strings.ContainsAny(s, ";:,") {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+// stripControls removes control characters except horizontal tabs.
+func stripControls(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,207 @@
+package ical_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/core/ical"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEvent(t *testing.T) *core.Event {
+	t.Helper()
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	host := core.User{UserID: core.NewUserID(), Username: "host", BGGUsername: "bgghost"}
+	start := time.Date(2025, time.June, 6, 19, 0, 0, 0, berlin)
+
+	return &core.Event{
+		EventID:  core.NewEventID(),
+		Status:   core.EventStatusScheduled,
+		Title:    "Game night; bring snacks, please",
+		Location: "Main St. 1\nBackyard",
+		StartsAt: start,
+		EndsAt:   start.Add(4 * time.Hour),
+		Timezone: "Europe/Berlin",
+		Host:     &host,
+		Capacity: 4,
+		Attendees: []core.Attendee{
+			{User: host, Status: core.AttendeeStatusConfirmed},
+			{User: core.User{UserID: core.NewUserID(), Username: "alice"}, Status: core.AttendeeStatusPending},
+			{User: core.User{UserID: core.NewUserID(), Username: "bob"}, Status: core.AttendeeStatusDeclined},
+			{User: core.User{UserID: core.NewUserID(), Username: "carol"}, Status: core.AttendeeStatusWaitlisted},
+		},
+		Matches: []core.Match{},
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	t.Parallel()
+	original := newTestEvent(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, ical.Encode(&buf, &ical.Calendar{Name: "Game nights", Events: []*core.Event{original}}))
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n"))
+	assert.Contains(t, out, "DTSTART:20250606T170000Z\r\n")
+	assert.Contains(t, out, `SUMMARY:Game night\; bring snacks\, please`)
+	assert.Contains(t, out, "PARTSTAT=ACCEPTED")
+	assert.Contains(t, out, "PARTSTAT=NEEDS-ACTION")
+	assert.Contains(t, out, "PARTSTAT=DECLINED")
+
+	cal, err := ical.Decode(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, "Game nights", cal.Name)
+	require.Len(t, cal.Events, 1)
+
+	decoded := cal.Events[0]
+	assert.Equal(t, original.EventID, decoded.EventID)
+	assert.Equal(t, original.Status, decoded.Status)
+	assert.Equal(t, original.Title, decoded.Title)
+	assert.Equal(t, original.Location, decoded.Location)
+	assert.Equal(t, original.Timezone, decoded.Timezone)
+	assert.Equal(t, original.Capacity, decoded.Capacity)
+	assert.Equal(t, original.Host, decoded.Host)
+	assert.Equal(t, original.Attendees, decoded.Attendees)
+	assert.True(t, original.StartsAt.Equal(decoded.StartsAt))
+	assert.True(t, original.EndsAt.Equal(decoded.EndsAt))
+	assert.Equal(t, "Europe/Berlin", decoded.StartsAt.Location().String())
+}
+
+func TestEncode_FoldsLongLines(t *testing.T) {
+	t.Parallel()
+	evt := newTestEvent(t)
+	evt.Title = strings.Repeat("Würfelspiel ", 20)
+
+	var buf bytes.Buffer
+	require.NoError(t, ical.Encode(&buf, &ical.Calendar{Events: []*core.Event{evt}}))
+
+	for line := range strings.SplitSeq(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+	}
+
+	cal, err := ical.Decode(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, evt.Title, cal.Events[0].Title)
+}
+
+func TestEncode_LineBreaksCannotInjectProperties(t *testing.T) {
+	t.Parallel()
+	evt := newTestEvent(t)
+	evt.Title = "Game night\rEND:VEVENT"
+	evt.Host.Username = "host\r\nEND:VEVENT\r\nBEGIN:VEVENT"
+	evt.Attendees[1].User.BGGUsername = "alice\nX-INJECTED:1"
+
+	var buf bytes.Buffer
+	require.NoError(t, ical.Encode(&buf, &ical.Calendar{Events: []*core.Event{evt}}))
+
+	lines := strings.Split(buf.String(), "\r\n")
+	for _, line := range lines {
+		assert.NotContains(t, line, "\r")
+		assert.NotContains(t, line, "\n")
+		assert.False(t, strings.HasPrefix(line, "X-INJECTED"))
+	}
+	assert.Equal(t, 1, countLines(lines, "BEGIN:VEVENT"))
+	assert.Equal(t, 1, countLines(lines, "END:VEVENT"))
+
+	cal, err := ical.Decode(&buf)
+	require.NoError(t, err)
+	require.Len(t, cal.Events, 1)
+	assert.Equal(t, "Game night\nEND:VEVENT", cal.Events[0].Title)
+	assert.Equal(t, "hostEND:VEVENTBEGIN:VEVENT", cal.Events[0].Host.Username)
+}
+
+func countLines(lines []string, want string) int {
+	n := 0
+	for _, line := range lines {
+		if line == want {
+			n++
+		}
+	}
+	return n
+}
+
+func TestEncode_RequiresStart(t *testing.T) {
+	t.Parallel()
+	evt := newTestEvent(t)
+	evt.StartsAt = time.Time{}
+	evt.EndsAt = time.Time{}
+
+	err := ical.Encode(&bytes.Buffer{}, &ical.Calendar{Events: []*core.Event{evt}})
+	assert.ErrorIs(t, err, ical.ErrNotScheduled)
+}
+
+func TestDecode_ForeignCalendar(t *testing.T) {
+	t.Parallel()
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example//Calendar//EN",
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Berlin",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:1234567890@example.com",
+		"DTSTAMP:20250601T120000Z",
+		"DTSTART;TZID=Europe/Berlin:20250606T190000",
+		"DTEND;TZID=Europe/Berlin:20250606T230000",
+		"SUMMARY:Catan eve",
+		" ning",
+		"STATUS:CANCELLED",
+		`ATTENDEE;CN="Doe, Jane";PARTSTAT=TENTATIVE:mailto:jane@example.com`,
+		"ATTENDEE;PARTSTAT=ACCEPTED:mailto:JOHN@example.com",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"SUMMARY:Reminder",
+		"END:VALARM",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	cal, err := ical.Decode(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, cal.Events, 1)
+
+	evt := cal.Events[0]
+	assert.Equal(t, "event", evt.EventID.Prefix())
+	assert.Equal(t, "Catan evening", evt.Title)
+	assert.Equal(t, core.EventStatusCancelled, evt.Status)
+	assert.Equal(t, "Europe/Berlin", evt.Timezone)
+	assert.Equal(t, 19, evt.StartsAt.Hour())
+	assert.Equal(t, 4*time.Hour, evt.EndsAt.Sub(evt.StartsAt))
+
+	require.Len(t, evt.Attendees, 2)
+	assert.Equal(t, "Doe, Jane", evt.Attendees[0].User.Username)
+	assert.Equal(t, core.AttendeeStatusPending, evt.Attendees[0].Status)
+	assert.Equal(t, "john@example.com", evt.Attendees[1].User.Username)
+	assert.Equal(t, core.AttendeeStatusConfirmed, evt.Attendees[1].Status)
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{"unterminated", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n"},
+		{"mismatched end", "BEGIN:VCALENDAR\r\nEND:VEVENT\r\n"},
+		{"malformed line", "BEGIN:VCALENDAR\r\nnonsense\r\nEND:VCALENDAR\r\n"},
+		{"missing start", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:1\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"},
+		{"invalid start", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART:tomorrow\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"},
+		{"unknown timezone", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;TZID=Nowhere/Special:20250606T190000\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ical.Decode(strings.NewReader(tc.data))
+			assert.ErrorIs(t, err, ical.ErrInvalid)
+		})
+	}
+}
@@ -25,3 +25,15 @@ type GameID = typeid.TypeID
 func NewGameID() GameID {
 	return typeid.MustGenerate("game")
 }
+
+type TeamID = typeid.TypeID
+
+func NewTeamID() TeamID {
+	return typeid.MustGenerate("team")
+}
+
+type DomainEventID = typeid.TypeID
+
+func NewDomainEventID() DomainEventID {
+	return typeid.MustGenerate("domainevent")
+}
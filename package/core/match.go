@@ -0,0 +1,167 @@
+package core
+
+import (
+	"fmt"
+	"math"
+)
+
+// Validate checks the match's players and scoreboard. It returns a
+// *ValidationError listing every offending field.
+func (m *Match) Validate() error {
+	verr := &ValidationError{}
+	m.validate(verr, "")
+	return verr.Err()
+}
+
+func (m *Match) validate(verr *ValidationError, prefix string) {
+	if m.MatchID.IsZero() {
+		verr.Add(prefix+"match_id", "is required")
+	}
+	if m.Game.GameID.IsZero() {
+		verr.Add(prefix+"game.game_id", "is required")
+	}
+	m.Game.Scoring.validate(verr, prefix+"game.scoring.")
+
+	if !m.EndedAt.IsZero() {
+		if m.StartedAt.IsZero() {
+			verr.Add(prefix+"ended_at", "must not be set without started_at")
+		} else if m.EndedAt.Before(m.StartedAt) {
+			verr.Add(prefix+"ended_at", "must not be before started_at")
+		}
+	}
+
+	switch m.Status {
+	case "", MatchStatusInProgress, MatchStatusFinalized:
+	default:
+		verr.Add(prefix+"status", "unknown match status '%s'", m.Status)
+	}
+
+	// Players may join a match while it is in progress.
+	if len(m.Players) == 0 && m.Status != MatchStatusInProgress {
+		verr.Add(prefix+"players", "at least one player is required")
+	}
+
+	seen := make(map[UserID]int, len(m.Players))
+	for i, p := range m.Players {
+		field := fmt.Sprintf("%splayers[%d]", prefix, i)
+		if p.UserID.IsZero() {
+			verr.Add(field+".user_id", "is required")
+			continue
+		}
+		if first, ok := seen[p.UserID]; ok {
+			verr.Add(field+".user_id", "player %s is already listed at players[%d]", describeUser(p), first)
+			continue
+		}
+		seen[p.UserID] = i
+	}
+
+	if len(m.Teams) > 0 {
+		m.validateTeams(verr, prefix)
+	}
+
+	m.Scoreboard.validate(verr, prefix+"scoreboard.", m.Players, m.Teams)
+}
+
+// Validate checks that the scoreboard scores exactly the given players: every
+// player has exactly one score, no one else is scored, and scores are listed
+// in the order of players. It returns a *ValidationError listing every
+// offending field.
+func (s *Scoreboard) Validate(players []User) error {
+	verr := &ValidationError{}
+	s.validate(verr, "", players, nil)
+	return verr.Err()
+}
+
+// validate checks the scoreboard of a match with the given players and teams.
+// In team matches, team scores are required and player scores optional.
+func (s *Scoreboard) validate(verr *ValidationError, prefix string, players []User, teams []Team) {
+	if !s.ScoreUnit.Valid() {
+		verr.Add(prefix+"score_unit", "unknown score unit '%s'", s.ScoreUnit)
+	}
+	if !s.Direction.Valid() {
+		verr.Add(prefix+"direction", "unknown score direction '%s'", s.Direction)
+	} else if s.ScoreUnit.Valid() && !s.ScoreUnit.allowsDirection(s.Direction) {
+		verr.Add(prefix+"direction", "score unit '%s' does not support direction '%s'", s.ScoreUnit, s.Direction)
+	}
+
+	if len(teams) > 0 {
+		s.validateTeamScores(verr, prefix, teams)
+		if len(s.Scores) == 0 {
+			return
+		}
+	} else if len(s.TeamScores) > 0 {
+		verr.Add(prefix+"team_scores", "only team matches have team scores")
+	}
+
+	byID := make(map[UserID]User, len(players))
+	for _, p := range players {
+		byID[p.UserID] = p
+	}
+
+	scored := make(map[UserID]int, len(s.Scores))
+	for i, score := range s.Scores {
+		field := fmt.Sprintf("%sscores[%d]", prefix, i)
+
+		s.validateValue(verr, field, score.Value, score.Tiebreakers, score.Breakdown, len(s.Scores))
+
+		player, ok := byID[score.UserID]
+		if !ok {
+			verr.Add(field+".user_id", "user '%s' is not a player of the match", score.UserID)
+			continue
+		}
+
+		if first, ok := scored[score.UserID]; ok {
+			verr.Add(field+".user_id", "player %s is already scored at scores[%d]", describeUser(player), first)
+			continue
+		}
+		scored[score.UserID] = i
+
+		if i < len(players) && players[i].UserID != score.UserID {
+			verr.Add(field+".user_id", "expected the score of player %s, got %s; scores must follow the order of players",
+				describeUser(players[i]), describeUser(player))
+		}
+	}
+
+	for _, p := range players {
+		if _, ok := scored[p.UserID]; !ok && !p.UserID.IsZero() {
+			verr.Add(prefix+"scores", "player %s has no score", describeUser(p))
+		}
+	}
+
+	if s.ScoreUnit == ScoreUnitCooperative {
+		for i, score := range s.Scores {
+			if score.Value != s.Scores[0].Value {
+				verr.Add(fmt.Sprintf("%sscores[%d].value", prefix, i), "cooperative games are won or lost by all players alike")
+			}
+		}
+	}
+}
+
+// validateValue checks a player or team score value, its tiebreakers and
+// breakdown. entries is the number of scored players or teams.
+func (s *Scoreboard) validateValue(verr *ValidationError, field string, value float64, tiebreakers []float64, breakdown []ScoreLine, entries int) {
+	if !finite(value) {
+		verr.Add(field+".value", "must be a finite number")
+	} else if msg := s.ScoreUnit.checkValue(value, entries); msg != "" {
+		verr.Add(field+".value", "%s", msg)
+	}
+	for j, tb := range tiebreakers {
+		if !finite(tb) {
+			verr.Add(fmt.Sprintf("%s.tiebreakers[%d]", field, j), "must be a finite number")
+		}
+	}
+	if len(breakdown) > 0 {
+		s.validateBreakdown(verr, field, value, breakdown)
+	}
+}
+
+func finite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+func describeUser(u User) string {
+	if u.Username == "" {
+		return fmt.Sprintf("'%s'", u.UserID)
+	}
+	return fmt.Sprintf("'%s' (%s)", u.Username, u.UserID)
+}
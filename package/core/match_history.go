@@ -0,0 +1,149 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	// ErrVersionConflict is returned when a command does not directly
+	// follow the last command of the match's history.
+	ErrVersionConflict = errors.New("match version conflict")
+	// ErrInvalidCommand is returned for malformed commands: of an unknown
+	// kind, lacking their payload or starting a match out of place.
+	ErrInvalidCommand = errors.New("invalid match command")
+)
+
+// MatchCommandKind is the kind of change a MatchCommand makes.
+type MatchCommandKind string
+
+const (
+	// MatchCommandStarted records the match as started, see
+	// MatchCommand.Match. It is the first command of every history.
+	MatchCommandStarted MatchCommandKind = "started"
+	// MatchCommandPlayerAdded adds MatchCommand.Player.
+	MatchCommandPlayerAdded MatchCommandKind = "player_added"
+	// MatchCommandScoreSet replaces the scoreboard of the match in progress
+	// with MatchCommand.Scoreboard.
+	MatchCommandScoreSet MatchCommandKind = "score_set"
+	// MatchCommandScoreCorrected replaces a player's score of the
+	// finalized match with MatchCommand.Score.
+	MatchCommandScoreCorrected MatchCommandKind = "score_corrected"
+	// MatchCommandFinalized makes the result final at MatchCommand.At.
+	MatchCommandFinalized MatchCommandKind = "finalized"
+)
+
+// MatchCommand is a single change to a match. The state of a match is
+// derived by applying its commands in order, see MatchAggregate.
+type MatchCommand struct {
+	Kind MatchCommandKind `json:"kind"`
+	// Version is the 1-based position of the command in the match's
+	// history.
+	Version int `json:"version"`
+	// By is the user who made the change, if known.
+	By UserID    `json:"by,omitzero" typeid:"user"`
+	At time.Time `json:"at"`
+
+	// The payload; only the field of the command's kind is set.
+	Match      *Match      `json:"match,omitempty"`
+	Player     *User       `json:"player,omitempty"`
+	Scoreboard *Scoreboard `json:"scoreboard,omitempty"`
+	Score      *Score      `json:"score,omitempty"`
+	// Reason optionally explains a correction.
+	Reason string `json:"reason,omitempty"`
+}
+
+// MatchAggregate is the state of a match after the first Version commands
+// of its history. Stored aggregates serve as snapshots, so a match can be
+// rebuilt without replaying its full history.
+type MatchAggregate struct {
+	Match   Match `json:"match"`
+	Version int   `json:"version"`
+}
+
+// ReplayMatch rebuilds a match by applying the commands to the snapshot, or
+// to no state if the snapshot is nil. Commands the snapshot already
+// contains are skipped.
+func ReplayMatch(snapshot *MatchAggregate, history []MatchCommand) (*MatchAggregate, error) {
+	agg := &MatchAggregate{}
+	if snapshot != nil {
+		agg.Match, agg.Version = cloneMatch(snapshot.Match), snapshot.Version
+	}
+
+	for _, cmd := range history {
+		if cmd.Version <= agg.Version {
+			continue
+		}
+		if err := agg.Apply(cmd); err != nil {
+			return nil, err
+		}
+	}
+
+	return agg, nil
+}
+
+// Apply applies the next command of the match's history. Commands leaving
+// the match invalid are rejected and the aggregate is left unchanged.
+func (a *MatchAggregate) Apply(cmd MatchCommand) error {
+	if cmd.Version != a.Version+1 {
+		return fmt.Errorf("command %d of match '%s' at version %d: %w", cmd.Version, a.Match.MatchID, a.Version, ErrVersionConflict)
+	}
+
+	if (a.Version == 0) != (cmd.Kind == MatchCommandStarted) {
+		return fmt.Errorf("%s command %d: %w: histories start with the started command only", cmd.Kind, cmd.Version, ErrInvalidCommand)
+	}
+
+	var err error
+	switch cmd.Kind {
+	case MatchCommandStarted:
+		if cmd.Match == nil {
+			return missingPayload(cmd, "match")
+		}
+		if err := cmd.Match.Validate(); err != nil {
+			return err
+		}
+		a.Match = cloneMatch(*cmd.Match)
+	case MatchCommandPlayerAdded:
+		if cmd.Player == nil {
+			return missingPayload(cmd, "player")
+		}
+		err = a.Match.AddPlayer(*cmd.Player)
+	case MatchCommandScoreSet:
+		if cmd.Scoreboard == nil {
+			return missingPayload(cmd, "scoreboard")
+		}
+		scoreboard := *cmd.Scoreboard
+		scoreboard.Scores = slices.Clone(scoreboard.Scores)
+		scoreboard.TeamScores = slices.Clone(scoreboard.TeamScores)
+		err = a.Match.SubmitScores(scoreboard)
+	case MatchCommandScoreCorrected:
+		if cmd.Score == nil {
+			return missingPayload(cmd, "score")
+		}
+		err = a.Match.CorrectScore(*cmd.Score)
+	case MatchCommandFinalized:
+		err = a.Match.Finalize(cmd.At)
+	default:
+		return fmt.Errorf("command %d: %w: unknown kind '%s'", cmd.Version, ErrInvalidCommand, cmd.Kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	a.Version = cmd.Version
+	return nil
+}
+
+func missingPayload(cmd MatchCommand, field string) error {
+	return fmt.Errorf("%s command %d: %w: %s is missing", cmd.Kind, cmd.Version, ErrInvalidCommand, field)
+}
+
+// cloneMatch copies the slices of the match that commands change.
+func cloneMatch(m Match) Match {
+	m.Players = slices.Clone(m.Players)
+	m.Scoreboard.Scores = slices.Clone(m.Scoreboard.Scores)
+	m.Scoreboard.TeamScores = slices.Clone(m.Scoreboard.TeamScores)
+	return m
+}
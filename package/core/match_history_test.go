@@ -0,0 +1,145 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var matchEnd = matchStart.Add(40 * time.Minute)
+
+// matchHistory returns the commands of a two player match played to the end
+// and corrected afterwards.
+func matchHistory(alice, bob core.User) []core.MatchCommand {
+	started := core.StartMatch(core.Game{GameID: core.NewGameID(), Name: "Azul"}, []core.User{alice}, matchStart)
+	return []core.MatchCommand{
+		{Kind: core.MatchCommandStarted, Version: 1, At: matchStart, Match: started},
+		{Kind: core.MatchCommandPlayerAdded, Version: 2, At: matchStart, Player: &bob},
+		{Kind: core.MatchCommandScoreSet, Version: 3, At: matchStart, Scoreboard: &core.Scoreboard{
+			Scores:    []core.Score{{UserID: alice.UserID, Value: 61}, {UserID: bob.UserID, Value: 58}},
+			ScoreUnit: core.ScoreUnitPoints,
+		}},
+		{Kind: core.MatchCommandFinalized, Version: 4, At: matchEnd},
+		{Kind: core.MatchCommandScoreCorrected, Version: 5, At: matchEnd, Score: &core.Score{UserID: bob.UserID, Value: 64}, Reason: "miscounted bonus"},
+	}
+}
+
+func TestReplayMatch(t *testing.T) {
+	t.Parallel()
+	alice, bob := newPlayer("alice"), newPlayer("bob")
+	history := matchHistory(alice, bob)
+
+	agg, err := core.ReplayMatch(nil, history)
+	require.NoError(t, err)
+	assert.Equal(t, 5, agg.Version)
+	assert.Equal(t, []core.User{alice, bob}, agg.Match.Players)
+	assert.True(t, agg.Match.Finalized())
+	assert.Equal(t, matchEnd, agg.Match.EndedAt)
+	assert.Equal(t, []core.Score{{UserID: alice.UserID, Value: 61}, {UserID: bob.UserID, Value: 64}}, agg.Match.Scoreboard.Scores)
+
+	// the history is left untouched
+	assert.Equal(t, []core.User{alice}, history[0].Match.Players)
+	assert.Equal(t, 58.0, history[2].Scoreboard.Scores[1].Value)
+
+	snapshot, err := core.ReplayMatch(nil, history[:3])
+	require.NoError(t, err)
+	fromSnapshot, err := core.ReplayMatch(snapshot, history)
+	require.NoError(t, err)
+	assert.Equal(t, agg, fromSnapshot)
+	assert.False(t, snapshot.Match.Finalized())
+}
+
+func TestMatchAggregateApplyErrors(t *testing.T) {
+	t.Parallel()
+	alice, bob := newPlayer("alice"), newPlayer("bob")
+	history := matchHistory(alice, bob)
+
+	testCases := []struct {
+		name    string
+		applied int
+		cmd     core.MatchCommand
+		err     error
+	}{
+		{
+			name: "first command not started",
+			cmd:  history[1],
+			err:  core.ErrVersionConflict,
+		},
+		{
+			name: "history not started",
+			cmd:  core.MatchCommand{Kind: core.MatchCommandFinalized, Version: 1, At: matchEnd},
+			err:  core.ErrInvalidCommand,
+		},
+		{
+			name:    "started twice",
+			applied: 1,
+			cmd:     core.MatchCommand{Kind: core.MatchCommandStarted, Version: 2, Match: history[0].Match},
+			err:     core.ErrInvalidCommand,
+		},
+		{
+			name:    "version skipped",
+			applied: 1,
+			cmd:     history[2],
+			err:     core.ErrVersionConflict,
+		},
+		{
+			name:    "version repeated",
+			applied: 2,
+			cmd:     history[1],
+			err:     core.ErrVersionConflict,
+		},
+		{
+			name:    "missing payload",
+			applied: 1,
+			cmd:     core.MatchCommand{Kind: core.MatchCommandPlayerAdded, Version: 2},
+			err:     core.ErrInvalidCommand,
+		},
+		{
+			name:    "unknown kind",
+			applied: 1,
+			cmd:     core.MatchCommand{Kind: "abandoned", Version: 2},
+			err:     core.ErrInvalidCommand,
+		},
+		{
+			name:    "correction in progress",
+			applied: 3,
+			cmd:     core.MatchCommand{Kind: core.MatchCommandScoreCorrected, Version: 4, Score: &core.Score{UserID: bob.UserID, Value: 64}},
+			err:     core.ErrMatchNotFinalized,
+		},
+		{
+			name:    "correction of stranger",
+			applied: 4,
+			cmd:     core.MatchCommand{Kind: core.MatchCommandScoreCorrected, Version: 5, Score: &core.Score{UserID: core.NewUserID(), Value: 1}},
+			err:     core.ErrNotPlaying,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			agg, err := core.ReplayMatch(nil, history[:tc.applied])
+			require.NoError(t, err)
+			before, err := core.ReplayMatch(nil, history[:tc.applied])
+			require.NoError(t, err)
+
+			err = agg.Apply(tc.cmd)
+			require.ErrorIs(t, err, tc.err)
+			assert.Equal(t, before, agg)
+		})
+	}
+}
+
+func TestMatchCorrectScoreInvalid(t *testing.T) {
+	t.Parallel()
+	alice := newPlayer("alice")
+	m := core.StartMatch(core.Game{GameID: core.NewGameID(), Name: "Azul", Scoring: core.Scoring{Unit: core.ScoreUnitRank}}, []core.User{alice}, matchStart)
+	require.NoError(t, m.Finalize(matchEnd))
+
+	err := m.CorrectScore(core.Score{UserID: alice.UserID, Value: 0})
+	var verr *core.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, []core.Score{{UserID: alice.UserID, Value: 1}}, m.Scoreboard.Scores)
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrMatchOutOfOrder is returned when applying a match that was played
+	// before the last applied one.
+	ErrMatchOutOfOrder = errors.New("match is out of chronological order")
+	// ErrMatchApplied is returned when applying a match twice.
+	ErrMatchApplied = errors.New("match is already applied")
+)
+
+// Key identifies the game by its BoardGameGeek ID, so games not stored yet,
+// such as collection candidates, match their stored counterparts, or by its
+// game ID for games not on BoardGameGeek.
+func (g *Game) Key() string {
+	if g.BGGID != 0 {
+		return "bgg:" + strconv.Itoa(g.BGGID)
+	}
+	return "game:" + g.GameID.String()
+}
+
+// PlayedAt is when the match ended, or started if the end is unknown.
+func (m *Match) PlayedAt() time.Time {
+	if !m.EndedAt.IsZero() {
+		return m.EndedAt
+	}
+	return m.StartedAt
+}
+
+// CompareChronologically orders matches by when they were played, ties
+// broken by match ID.
+func CompareChronologically(a, b *Match) int {
+	return compareChronologically(a.PlayedAt(), a.MatchID.String(), b.PlayedAt(), b.MatchID.String())
+}
+
+func compareChronologically(a time.Time, aID string, b time.Time, bID string) int {
+	if c := a.Compare(b); c != 0 {
+		return c
+	}
+	return cmp.Compare(aID, bID)
+}
+
+// Chronological returns the finalized matches in chronological order, see
+// CompareChronologically. Matches in progress are skipped.
+func Chronological(matches []Match) []Match {
+	finalized := make([]Match, 0, len(matches))
+	for _, m := range matches {
+		if m.Finalized() {
+			finalized = append(finalized, m)
+		}
+	}
+
+	slices.SortStableFunc(finalized, func(a, b Match) int {
+		return CompareChronologically(&a, &b)
+	})
+	return finalized
+}
+
+// MatchSequence guards state derived from finalized matches, such as
+// ratings or statistics, that must apply every match once and in
+// chronological order. The zero value is ready to use.
+type MatchSequence struct {
+	applied map[MatchID]bool
+
+	last   time.Time
+	lastID string
+}
+
+// Next records the match as applied, or returns ErrMatchNotFinalized,
+// ErrMatchApplied or ErrMatchOutOfOrder if it must not be applied next.
+func (s *MatchSequence) Next(match *Match) error {
+	if !match.Finalized() {
+		return fmt.Errorf("match '%s': %w", match.MatchID, ErrMatchNotFinalized)
+	}
+	if s.applied[match.MatchID] {
+		return fmt.Errorf("match '%s': %w", match.MatchID, ErrMatchApplied)
+	}
+
+	at := match.PlayedAt()
+	if len(s.applied) > 0 && compareChronologically(at, match.MatchID.String(), s.last, s.lastID) < 0 {
+		return fmt.Errorf("match '%s' played at %s: %w", match.MatchID, at.Format(time.RFC3339), ErrMatchOutOfOrder)
+	}
+
+	if s.applied == nil {
+		s.applied = make(map[MatchID]bool)
+	}
+	s.applied[match.MatchID] = true
+	s.last, s.lastID = at, match.MatchID.String()
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchSequence(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, 3, 1, 19, 0, 0, 0, time.UTC)
+
+	first, second := newTestMatch(), newTestMatch()
+	first.EndedAt, second.EndedAt = start, start.Add(time.Hour)
+	inProgress := newTestMatch()
+	inProgress.Status = core.MatchStatusInProgress
+
+	var seq core.MatchSequence
+	assert.ErrorIs(t, seq.Next(&inProgress), core.ErrMatchNotFinalized)
+	assert.NoError(t, seq.Next(&second))
+	assert.ErrorIs(t, seq.Next(&second), core.ErrMatchApplied)
+	assert.ErrorIs(t, seq.Next(&first), core.ErrMatchOutOfOrder)
+
+	assert.Equal(t, []core.Match{first, second}, core.Chronological([]core.Match{second, inProgress, first}))
+}
+
+func TestGameKey(t *testing.T) {
+	t.Parallel()
+	azul := core.Game{GameID: core.NewGameID(), BGGID: 230802}
+	again := core.Game{GameID: core.NewGameID(), BGGID: 230802}
+	homemade := core.Game{GameID: core.NewGameID()}
+
+	assert.Equal(t, azul.Key(), again.Key())
+	assert.NotEqual(t, azul.Key(), homemade.Key())
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	// ErrMatchFinalized is returned when changing a match that is no longer
+	// in progress.
+	ErrMatchFinalized = errors.New("match is finalized")
+	// ErrAlreadyPlaying is returned when adding a player twice.
+	ErrAlreadyPlaying = errors.New("user already plays in the match")
+	// ErrMatchNotFinalized is returned when correcting or applying a match
+	// that is still in progress.
+	ErrMatchNotFinalized = errors.New("match is not finalized")
+	// ErrNotPlaying is returned for a user who does not play in the match.
+	ErrNotPlaying = errors.New("user does not play in the match")
+)
+
+// MatchFinalized is the domain event emitted once a match's result is final.
+type MatchFinalized struct {
+	EventID    EventID     `json:"event_id" typeid:"event"`
+	Match      Match       `json:"match"`
+	Placements []Placement `json:"placements"`
+}
+
+// StartMatch returns a new match in progress. Every player starts with the
+// default score of the game's scoring, see Game.NewScoreboard.
+func StartMatch(game Game, players []User, startedAt time.Time) *Match {
+	if players == nil {
+		players = []User{}
+	}
+
+	return &Match{
+		MatchID:    NewMatchID(),
+		Status:     MatchStatusInProgress,
+		Game:       game,
+		Players:    players,
+		StartedAt:  startedAt,
+		Scoreboard: game.NewScoreboard(players),
+	}
+}
+
+// Finalized reports whether the match's result is final. Matches recorded
+// without a status are complete.
+func (m *Match) Finalized() bool {
+	return m.Status != MatchStatusInProgress
+}
+
+// CheckInProgress returns ErrMatchFinalized if the match may no longer
+// change.
+func (m *Match) CheckInProgress() error {
+	if m.Finalized() {
+		return fmt.Errorf("match '%s': %w", m.MatchID, ErrMatchFinalized)
+	}
+	return nil
+}
+
+// AddPlayer adds the user to the match in progress with the default score
+// of the scoreboard's unit.
+func (m *Match) AddPlayer(user User) error {
+	if err := m.CheckInProgress(); err != nil {
+		return err
+	}
+
+	for _, p := range m.Players {
+		if p.UserID == user.UserID {
+			return fmt.Errorf("player %s: %w", describeUser(user), ErrAlreadyPlaying)
+		}
+	}
+
+	m.Players = append(m.Players, user)
+	m.Scoreboard.Scores = append(m.Scoreboard.Scores, Score{UserID: user.UserID, Value: m.Scoreboard.ScoreUnit.initialValue()})
+
+	return nil
+}
+
+// SubmitScores replaces the scoreboard of the match in progress, unless the
+// match would become invalid. The match stays in progress, so scores may be
+// corrected until it is finalized.
+func (m *Match) SubmitScores(scoreboard Scoreboard) error {
+	if err := m.CheckInProgress(); err != nil {
+		return err
+	}
+
+	previous := m.Scoreboard
+	m.Scoreboard = scoreboard
+
+	if err := m.Validate(); err != nil {
+		m.Scoreboard = previous
+		return err
+	}
+
+	return nil
+}
+
+// Finalize makes the match's result final, unless the match is invalid. The
+// end time is set to at unless it was recorded already.
+func (m *Match) Finalize(at time.Time) error {
+	if err := m.CheckInProgress(); err != nil {
+		return err
+	}
+
+	endedAt := m.EndedAt
+	m.Status = MatchStatusFinalized
+	if m.EndedAt.IsZero() {
+		m.EndedAt = at
+	}
+
+	if err := m.Validate(); err != nil {
+		m.Status, m.EndedAt = MatchStatusInProgress, endedAt
+		return err
+	}
+
+	return nil
+}
+
+// CorrectScore replaces the score of a player of the finalized match,
+// unless the match would become invalid. Scores of matches in progress are
+// submitted instead, see SubmitScores.
+func (m *Match) CorrectScore(score Score) error {
+	if !m.Finalized() {
+		return fmt.Errorf("match '%s': %w", m.MatchID, ErrMatchNotFinalized)
+	}
+
+	i := slices.IndexFunc(m.Scoreboard.Scores, func(s Score) bool { return s.UserID == score.UserID })
+	if i == -1 {
+		return fmt.Errorf("user '%s' in match '%s': %w", score.UserID, m.MatchID, ErrNotPlaying)
+	}
+
+	// the scores may be shared with a copy of the match
+	previous := m.Scoreboard.Scores
+	m.Scoreboard.Scores = slices.Clone(previous)
+	m.Scoreboard.Scores[i] = score
+
+	if err := m.Validate(); err != nil {
+		m.Scoreboard.Scores = previous
+		return err
+	}
+
+	return nil
+}
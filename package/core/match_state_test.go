@@ -0,0 +1,114 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var matchStart = time.Date(2025, time.March, 6, 19, 30, 0, 0, time.UTC)
+
+func TestStartMatch(t *testing.T) {
+	t.Parallel()
+	game := core.Game{GameID: core.NewGameID(), Name: "Azul", Scoring: core.Scoring{Unit: core.ScoreUnitRank}}
+	alice := newPlayer("alice")
+
+	m := core.StartMatch(game, []core.User{alice}, matchStart)
+	require.NoError(t, m.Validate())
+	assert.Equal(t, core.MatchStatusInProgress, m.Status)
+	assert.False(t, m.Finalized())
+	assert.Equal(t, matchStart, m.StartedAt)
+	assert.Equal(t, []core.Score{{UserID: alice.UserID, Value: 1}}, m.Scoreboard.Scores)
+
+	empty := core.StartMatch(game, nil, matchStart)
+	assert.NoError(t, empty.Validate())
+	assert.Equal(t, []core.User{}, empty.Players)
+}
+
+func TestMatchAddPlayer(t *testing.T) {
+	t.Parallel()
+	game := core.Game{GameID: core.NewGameID(), Name: "Azul"}
+	alice, bob := newPlayer("alice"), newPlayer("bob")
+
+	m := core.StartMatch(game, nil, matchStart)
+	require.NoError(t, m.AddPlayer(alice))
+	require.NoError(t, m.AddPlayer(bob))
+	assert.Equal(t, []core.User{alice, bob}, m.Players)
+	assert.NoError(t, m.Validate())
+
+	err := m.AddPlayer(alice)
+	require.ErrorIs(t, err, core.ErrAlreadyPlaying)
+	assert.Len(t, m.Players, 2)
+}
+
+func TestMatchSubmitScores(t *testing.T) {
+	t.Parallel()
+	game := core.Game{GameID: core.NewGameID(), Name: "Azul"}
+	alice, bob := newPlayer("alice"), newPlayer("bob")
+	m := core.StartMatch(game, []core.User{alice, bob}, matchStart)
+
+	valid := core.Scoreboard{
+		Scores:    []core.Score{{UserID: alice.UserID, Value: 61}, {UserID: bob.UserID, Value: 58}},
+		ScoreUnit: core.ScoreUnitPoints,
+	}
+	require.NoError(t, m.SubmitScores(valid))
+	assert.Equal(t, valid, m.Scoreboard)
+
+	invalid := core.Scoreboard{
+		Scores:    []core.Score{{UserID: alice.UserID, Value: 70}},
+		ScoreUnit: core.ScoreUnitPoints,
+	}
+	var verr *core.ValidationError
+	require.ErrorAs(t, m.SubmitScores(invalid), &verr)
+	assert.Equal(t, valid, m.Scoreboard)
+}
+
+func TestMatchFinalize(t *testing.T) {
+	t.Parallel()
+	game := core.Game{GameID: core.NewGameID(), Name: "Azul"}
+	end := matchStart.Add(40 * time.Minute)
+
+	t.Run("sets end time", func(t *testing.T) {
+		t.Parallel()
+		m := core.StartMatch(game, []core.User{newPlayer("alice")}, matchStart)
+
+		require.NoError(t, m.Finalize(end))
+		assert.True(t, m.Finalized())
+		assert.Equal(t, core.MatchStatusFinalized, m.Status)
+		assert.Equal(t, end, m.EndedAt)
+
+		assert.ErrorIs(t, m.Finalize(end), core.ErrMatchFinalized)
+		assert.ErrorIs(t, m.AddPlayer(newPlayer("bob")), core.ErrMatchFinalized)
+		assert.ErrorIs(t, m.SubmitScores(m.Scoreboard), core.ErrMatchFinalized)
+	})
+
+	t.Run("keeps recorded end time", func(t *testing.T) {
+		t.Parallel()
+		m := core.StartMatch(game, []core.User{newPlayer("alice")}, matchStart)
+		m.EndedAt = matchStart.Add(time.Hour)
+
+		require.NoError(t, m.Finalize(end))
+		assert.Equal(t, matchStart.Add(time.Hour), m.EndedAt)
+	})
+
+	t.Run("requires players", func(t *testing.T) {
+		t.Parallel()
+		m := core.StartMatch(game, nil, matchStart)
+
+		var verr *core.ValidationError
+		require.ErrorAs(t, m.Finalize(end), &verr)
+		assert.Equal(t, "players", verr.Errors[0].Field)
+		assert.False(t, m.Finalized())
+		assert.True(t, m.EndedAt.IsZero())
+	})
+
+	t.Run("matches without status are complete", func(t *testing.T) {
+		t.Parallel()
+		m := newValidMatch()
+		assert.True(t, m.Finalized())
+		assert.ErrorIs(t, m.Finalize(end), core.ErrMatchFinalized)
+	})
+}
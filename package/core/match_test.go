@@ -0,0 +1,174 @@
+package core_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPlayer(username string) core.User {
+	return core.User{UserID: core.NewUserID(), Username: username}
+}
+
+func newValidMatch() *core.Match {
+	alice, bob := newPlayer("alice"), newPlayer("bob")
+	return &core.Match{
+		MatchID: core.NewMatchID(),
+		Game:    core.Game{GameID: core.NewGameID(), Name: "Azul"},
+		Players: []core.User{alice, bob},
+		Scoreboard: core.Scoreboard{
+			Scores: []core.Score{
+				{UserID: alice.UserID, Value: 72},
+				{UserID: bob.UserID, Value: 64},
+			},
+			ScoreUnit: core.ScoreUnitPoints,
+		},
+	}
+}
+
+func fields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+
+	var verr *core.ValidationError
+	require.ErrorAs(t, err, &verr)
+
+	out := make([]string, 0, len(verr.Errors))
+	for _, fe := range verr.Errors {
+		out = append(out, fe.Field)
+	}
+	return out
+}
+
+func TestMatchValidate(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		modify func(m *core.Match)
+		fields []string
+	}{
+		{
+			name:   "valid",
+			modify: func(m *core.Match) {},
+		},
+		{
+			name:   "missing ids",
+			modify: func(m *core.Match) { m.MatchID = core.MatchID{}; m.Game.GameID = core.GameID{} },
+			fields: []string{"match_id", "game.game_id"},
+		},
+		{
+			name: "no players",
+			modify: func(m *core.Match) {
+				m.Players = nil
+				m.Scoreboard.Scores = nil
+			},
+			fields: []string{"players"},
+		},
+		{
+			name: "duplicate player",
+			modify: func(m *core.Match) {
+				m.Players = append(m.Players, m.Players[0])
+			},
+			fields: []string{"players[2].user_id"},
+		},
+		{
+			name:   "unknown score unit",
+			modify: func(m *core.Match) { m.Scoreboard.ScoreUnit = "goals" },
+			fields: []string{"scoreboard.score_unit"},
+		},
+		{
+			name:   "missing score",
+			modify: func(m *core.Match) { m.Scoreboard.Scores = m.Scoreboard.Scores[:1] },
+			fields: []string{"scoreboard.scores"},
+		},
+		{
+			name: "stranger scored",
+			modify: func(m *core.Match) {
+				m.Scoreboard.Scores = append(m.Scoreboard.Scores, core.Score{UserID: core.NewUserID(), Value: 1})
+			},
+			fields: []string{"scoreboard.scores[2].user_id"},
+		},
+		{
+			name: "player scored twice",
+			modify: func(m *core.Match) {
+				m.Scoreboard.Scores[1].UserID = m.Players[0].UserID
+			},
+			fields: []string{"scoreboard.scores[1].user_id", "scoreboard.scores"},
+		},
+		{
+			name: "scores out of order",
+			modify: func(m *core.Match) {
+				s := m.Scoreboard.Scores
+				s[0], s[1] = s[1], s[0]
+			},
+			fields: []string{"scoreboard.scores[0].user_id", "scoreboard.scores[1].user_id"},
+		},
+		{
+			name:   "unknown direction",
+			modify: func(m *core.Match) { m.Scoreboard.Direction = "sideways" },
+			fields: []string{"scoreboard.direction"},
+		},
+		{
+			name:   "infinite tiebreaker",
+			modify: func(m *core.Match) { m.Scoreboard.Scores[1].Tiebreakers = []float64{1, math.Inf(1)} },
+			fields: []string{"scoreboard.scores[1].tiebreakers[1]"},
+		},
+		{
+			name:   "not a number",
+			modify: func(m *core.Match) { m.Scoreboard.Scores[0].Value = math.NaN() },
+			fields: []string{"scoreboard.scores[0].value"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			m := newValidMatch()
+			tc.modify(m)
+			assert.Equal(t, tc.fields, fields(t, m.Validate()))
+		})
+	}
+}
+
+func TestScoreboardValidate_Messages(t *testing.T) {
+	t.Parallel()
+	m := newValidMatch()
+	stranger := core.NewUserID()
+	m.Scoreboard.Scores[1].UserID = stranger
+
+	err := m.Scoreboard.Validate(m.Players)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "scores[1].user_id: user '"+stranger.String()+"' is not a player of the match")
+	assert.ErrorContains(t, err, "scores: player 'bob' ("+m.Players[1].UserID.String()+") has no score")
+}
+
+func TestEventValidate_Matches(t *testing.T) {
+	t.Parallel()
+	m := newValidMatch()
+	m.Scoreboard.Scores = m.Scoreboard.Scores[:1]
+
+	evt := core.Event{Matches: []core.Match{*newValidMatch(), *m}}
+	assert.Equal(t, []string{"matches[1].scoreboard.scores"}, fields(t, evt.Validate()))
+}
+
+func TestMatchCodingValidates(t *testing.T) {
+	t.Parallel()
+	m := newValidMatch()
+	m.Scoreboard.Scores = nil
+
+	var buf bytes.Buffer
+	var verr *core.ValidationError
+	assert.ErrorAs(t, core.EncodeMatch(&buf, m), &verr)
+
+	payload := `{"match_id":"` + core.NewMatchID().String() + `","game":{"game_id":"` + core.NewGameID().String() + `"},` +
+		`"players":[{"user_id":"` + core.NewUserID().String() + `"}],"scoreboard":{"scores":[],"score_unit":"points"}}`
+	decoded, err := core.DecodeMatch(bytes.NewBufferString(payload))
+	assert.Nil(t, decoded)
+	assert.ErrorAs(t, err, &verr)
+}
@@ -0,0 +1,567 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core/corepb"
+	"go.jetify.com/typeid/v2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The converters below map the core types to their protobuf messages in
+// corepb and back. Both directions return a *ValidationError listing every
+// field that has no representation on the other side, addressed by its JSON
+// path. Empty lists are decoded like their JSON counterparts: required lists
+// become empty slices, optional ones stay nil.
+
+var attendeeStatuses = map[AttendeeStatus]corepb.AttendeeStatus{
+	"":                       corepb.AttendeeStatus_ATTENDEE_STATUS_UNSPECIFIED,
+	AttendeeStatusConfirmed:  corepb.AttendeeStatus_ATTENDEE_STATUS_CONFIRMED,
+	AttendeeStatusPending:    corepb.AttendeeStatus_ATTENDEE_STATUS_PENDING,
+	AttendeeStatusDeclined:   corepb.AttendeeStatus_ATTENDEE_STATUS_DECLINED,
+	AttendeeStatusWaitlisted: corepb.AttendeeStatus_ATTENDEE_STATUS_WAITLISTED,
+}
+
+var eventStatuses = map[EventStatus]corepb.EventStatus{
+	"":                   corepb.EventStatus_EVENT_STATUS_UNSPECIFIED,
+	EventStatusScheduled: corepb.EventStatus_EVENT_STATUS_SCHEDULED,
+	EventStatusOngoing:   corepb.EventStatus_EVENT_STATUS_ONGOING,
+	EventStatusCompleted: corepb.EventStatus_EVENT_STATUS_COMPLETED,
+	EventStatusCancelled: corepb.EventStatus_EVENT_STATUS_CANCELLED,
+}
+
+var matchStatuses = map[MatchStatus]corepb.MatchStatus{
+	"":                    corepb.MatchStatus_MATCH_STATUS_UNSPECIFIED,
+	MatchStatusInProgress: corepb.MatchStatus_MATCH_STATUS_IN_PROGRESS,
+	MatchStatusFinalized:  corepb.MatchStatus_MATCH_STATUS_FINALIZED,
+}
+
+var scoreUnits = map[ScoreUnit]corepb.ScoreUnit{
+	"":                   corepb.ScoreUnit_SCORE_UNIT_UNSPECIFIED,
+	ScoreUnitPoints:      corepb.ScoreUnit_SCORE_UNIT_POINTS,
+	ScoreUnitCustom:      corepb.ScoreUnit_SCORE_UNIT_CUSTOM,
+	ScoreUnitCooperative: corepb.ScoreUnit_SCORE_UNIT_COOPERATIVE,
+	ScoreUnitWinLoss:     corepb.ScoreUnit_SCORE_UNIT_WIN_LOSS,
+	ScoreUnitRank:        corepb.ScoreUnit_SCORE_UNIT_RANK,
+	ScoreUnitTime:        corepb.ScoreUnit_SCORE_UNIT_TIME,
+}
+
+var scoreDirections = map[ScoreDirection]corepb.ScoreDirection{
+	"":                       corepb.ScoreDirection_SCORE_DIRECTION_UNSPECIFIED,
+	ScoreDirectionHigherWins: corepb.ScoreDirection_SCORE_DIRECTION_HIGHER_WINS,
+	ScoreDirectionLowerWins:  corepb.ScoreDirection_SCORE_DIRECTION_LOWER_WINS,
+}
+
+// User
+
+func (u *User) ToProto() (*corepb.User, error) {
+	return u.toProto(), nil
+}
+
+func UserFromProto(pb *corepb.User) (*User, error) {
+	verr := &ValidationError{}
+	u := userFromProto(verr, "", pb)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (u *User) MarshalProto() ([]byte, error) {
+	return marshalMessage(u.ToProto())
+}
+
+func (u *User) UnmarshalProto(data []byte) error {
+	return unmarshalMessage(data, u, UserFromProto)
+}
+
+func (u *User) toProto() *corepb.User {
+	return &corepb.User{
+		UserId:      idToProto(u.UserID),
+		Username:    u.Username,
+		BggUsername: u.BGGUsername,
+	}
+}
+
+func userFromProto(verr *ValidationError, prefix string, pb *corepb.User) User {
+	return User{
+		UserID:      idFromProto(verr, prefix+"user_id", pb.GetUserId()),
+		Username:    pb.GetUsername(),
+		BGGUsername: pb.GetBggUsername(),
+	}
+}
+
+// Attendee
+
+func (a *Attendee) ToProto() (*corepb.Attendee, error) {
+	verr := &ValidationError{}
+	pb := a.toProto(verr, "")
+	return pb, verr.Err()
+}
+
+func AttendeeFromProto(pb *corepb.Attendee) (*Attendee, error) {
+	verr := &ValidationError{}
+	a := attendeeFromProto(verr, "", pb)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (a *Attendee) MarshalProto() ([]byte, error) {
+	return marshalMessage(a.ToProto())
+}
+
+func (a *Attendee) UnmarshalProto(data []byte) error {
+	return unmarshalMessage(data, a, AttendeeFromProto)
+}
+
+func (a *Attendee) toProto(verr *ValidationError, prefix string) *corepb.Attendee {
+	return &corepb.Attendee{
+		User:   a.User.toProto(),
+		Status: enumToProto(verr, prefix+"status", attendeeStatuses, a.Status),
+	}
+}
+
+func attendeeFromProto(verr *ValidationError, prefix string, pb *corepb.Attendee) Attendee {
+	return Attendee{
+		User:   userFromProto(verr, prefix+"user.", pb.GetUser()),
+		Status: enumFromProto(verr, prefix+"status", attendeeStatuses, pb.GetStatus()),
+	}
+}
+
+// Event
+
+func (e *Event) ToProto() (*corepb.Event, error) {
+	verr := &ValidationError{}
+	pb := e.toProto(verr)
+	return pb, verr.Err()
+}
+
+// EventFromProto converts the message to an event. Its times are in UTC
+// until the event is localized.
+func EventFromProto(pb *corepb.Event) (*Event, error) {
+	verr := &ValidationError{}
+	e := eventFromProto(verr, pb)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (e *Event) MarshalProto() ([]byte, error) {
+	return marshalMessage(e.ToProto())
+}
+
+func (e *Event) UnmarshalProto(data []byte) error {
+	return unmarshalMessage(data, e, EventFromProto)
+}
+
+func (e *Event) toProto(verr *ValidationError) *corepb.Event {
+	pb := &corepb.Event{
+		EventId:   idToProto(e.EventID),
+		Status:    enumToProto(verr, "status", eventStatuses, e.Status),
+		Title:     e.Title,
+		Location:  e.Location,
+		StartsAt:  timeToProto(e.StartsAt),
+		EndsAt:    timeToProto(e.EndsAt),
+		Timezone:  e.Timezone,
+		Attendees: make([]*corepb.Attendee, 0, len(e.Attendees)),
+		Matches:   make([]*corepb.Match, 0, len(e.Matches)),
+	}
+	if e.Host != nil {
+		pb.Host = e.Host.toProto()
+	}
+	if e.Capacity > math.MaxInt32 {
+		verr.Add("capacity", "must not exceed %d", math.MaxInt32)
+	} else {
+		pb.Capacity = int32(e.Capacity)
+	}
+	for i := range e.Attendees {
+		pb.Attendees = append(pb.Attendees, e.Attendees[i].toProto(verr, fmt.Sprintf("attendees[%d].", i)))
+	}
+	for i := range e.Matches {
+		pb.Matches = append(pb.Matches, e.Matches[i].toProto(verr, fmt.Sprintf("matches[%d].", i)))
+	}
+	return pb
+}
+
+func eventFromProto(verr *ValidationError, pb *corepb.Event) Event {
+	e := Event{
+		EventID:   idFromProto(verr, "event_id", pb.GetEventId()),
+		Status:    enumFromProto(verr, "status", eventStatuses, pb.GetStatus()),
+		Title:     pb.GetTitle(),
+		Location:  pb.GetLocation(),
+		StartsAt:  timeFromProto(verr, "starts_at", pb.GetStartsAt()),
+		EndsAt:    timeFromProto(verr, "ends_at", pb.GetEndsAt()),
+		Timezone:  pb.GetTimezone(),
+		Capacity:  int(pb.GetCapacity()),
+		Attendees: make([]Attendee, 0, len(pb.GetAttendees())),
+		Matches:   make([]Match, 0, len(pb.GetMatches())),
+	}
+	if pb.GetHost() != nil {
+		host := userFromProto(verr, "host.", pb.GetHost())
+		e.Host = &host
+	}
+	for i, a := range pb.GetAttendees() {
+		e.Attendees = append(e.Attendees, attendeeFromProto(verr, fmt.Sprintf("attendees[%d].", i), a))
+	}
+	for i, m := range pb.GetMatches() {
+		e.Matches = append(e.Matches, matchFromProto(verr, fmt.Sprintf("matches[%d].", i), m))
+	}
+	return e
+}
+
+// Match
+
+func (m *Match) ToProto() (*corepb.Match, error) {
+	verr := &ValidationError{}
+	pb := m.toProto(verr, "")
+	return pb, verr.Err()
+}
+
+func MatchFromProto(pb *corepb.Match) (*Match, error) {
+	verr := &ValidationError{}
+	m := matchFromProto(verr, "", pb)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (m *Match) MarshalProto() ([]byte, error) {
+	return marshalMessage(m.ToProto())
+}
+
+func (m *Match) UnmarshalProto(data []byte) error {
+	return unmarshalMessage(data, m, MatchFromProto)
+}
+
+func (m *Match) toProto(verr *ValidationError, prefix string) *corepb.Match {
+	pb := &corepb.Match{
+		MatchId:    idToProto(m.MatchID),
+		Status:     enumToProto(verr, prefix+"status", matchStatuses, m.Status),
+		Game:       m.Game.toProto(verr, prefix+"game."),
+		Players:    make([]*corepb.User, 0, len(m.Players)),
+		StartedAt:  timeToProto(m.StartedAt),
+		EndedAt:    timeToProto(m.EndedAt),
+		Scoreboard: m.Scoreboard.toProto(verr, prefix+"scoreboard."),
+	}
+	for i := range m.Players {
+		pb.Players = append(pb.Players, m.Players[i].toProto())
+	}
+	for _, t := range m.Teams {
+		pb.Teams = append(pb.Teams, &corepb.Team{
+			TeamId:  idToProto(t.TeamID),
+			Name:    t.Name,
+			Members: idsToProto(t.Members),
+		})
+	}
+	return pb
+}
+
+func matchFromProto(verr *ValidationError, prefix string, pb *corepb.Match) Match {
+	m := Match{
+		MatchID:    idFromProto(verr, prefix+"match_id", pb.GetMatchId()),
+		Status:     enumFromProto(verr, prefix+"status", matchStatuses, pb.GetStatus()),
+		Game:       gameFromProto(verr, prefix+"game.", pb.GetGame()),
+		Players:    make([]User, 0, len(pb.GetPlayers())),
+		StartedAt:  timeFromProto(verr, prefix+"started_at", pb.GetStartedAt()),
+		EndedAt:    timeFromProto(verr, prefix+"ended_at", pb.GetEndedAt()),
+		Scoreboard: scoreboardFromProto(verr, prefix+"scoreboard.", pb.GetScoreboard()),
+	}
+	for i, p := range pb.GetPlayers() {
+		m.Players = append(m.Players, userFromProto(verr, fmt.Sprintf("%splayers[%d].", prefix, i), p))
+	}
+	for i, t := range pb.GetTeams() {
+		field := fmt.Sprintf("%steams[%d].", prefix, i)
+		m.Teams = append(m.Teams, Team{
+			TeamID:  idFromProto(verr, field+"team_id", t.GetTeamId()),
+			Name:    t.GetName(),
+			Members: idsFromProto(verr, field+"members", t.GetMembers()),
+		})
+	}
+	return m
+}
+
+// Score
+
+func (s *Score) ToProto() (*corepb.Score, error) {
+	return s.toProto(), nil
+}
+
+func ScoreFromProto(pb *corepb.Score) (*Score, error) {
+	verr := &ValidationError{}
+	s := scoreFromProto(verr, "", pb)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *Score) MarshalProto() ([]byte, error) {
+	return marshalMessage(s.ToProto())
+}
+
+func (s *Score) UnmarshalProto(data []byte) error {
+	return unmarshalMessage(data, s, ScoreFromProto)
+}
+
+func (s *Score) toProto() *corepb.Score {
+	return &corepb.Score{
+		UserId:      idToProto(s.UserID),
+		Value:       s.Value,
+		Tiebreakers: s.Tiebreakers,
+		Breakdown:   scoreLinesToProto(s.Breakdown),
+	}
+}
+
+func scoreFromProto(verr *ValidationError, prefix string, pb *corepb.Score) Score {
+	return Score{
+		UserID:      idFromProto(verr, prefix+"user_id", pb.GetUserId()),
+		Value:       pb.GetValue(),
+		Tiebreakers: pb.GetTiebreakers(),
+		Breakdown:   scoreLinesFromProto(pb.GetBreakdown()),
+	}
+}
+
+// Scoreboard
+
+func (s *Scoreboard) ToProto() (*corepb.Scoreboard, error) {
+	verr := &ValidationError{}
+	pb := s.toProto(verr, "")
+	return pb, verr.Err()
+}
+
+func ScoreboardFromProto(pb *corepb.Scoreboard) (*Scoreboard, error) {
+	verr := &ValidationError{}
+	s := scoreboardFromProto(verr, "", pb)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *Scoreboard) MarshalProto() ([]byte, error) {
+	return marshalMessage(s.ToProto())
+}
+
+func (s *Scoreboard) UnmarshalProto(data []byte) error {
+	return unmarshalMessage(data, s, ScoreboardFromProto)
+}
+
+func (s *Scoreboard) toProto(verr *ValidationError, prefix string) *corepb.Scoreboard {
+	pb := &corepb.Scoreboard{
+		Scores:    make([]*corepb.Score, 0, len(s.Scores)),
+		ScoreUnit: enumToProto(verr, prefix+"score_unit", scoreUnits, s.ScoreUnit),
+		Direction: enumToProto(verr, prefix+"direction", scoreDirections, s.Direction),
+	}
+	for i := range s.Scores {
+		pb.Scores = append(pb.Scores, s.Scores[i].toProto())
+	}
+	for _, ts := range s.TeamScores {
+		pb.TeamScores = append(pb.TeamScores, &corepb.TeamScore{
+			TeamId:      idToProto(ts.TeamID),
+			Value:       ts.Value,
+			Tiebreakers: ts.Tiebreakers,
+			Breakdown:   scoreLinesToProto(ts.Breakdown),
+		})
+	}
+	return pb
+}
+
+func scoreboardFromProto(verr *ValidationError, prefix string, pb *corepb.Scoreboard) Scoreboard {
+	s := Scoreboard{
+		Scores:    make([]Score, 0, len(pb.GetScores())),
+		ScoreUnit: enumFromProto(verr, prefix+"score_unit", scoreUnits, pb.GetScoreUnit()),
+		Direction: enumFromProto(verr, prefix+"direction", scoreDirections, pb.GetDirection()),
+	}
+	for i, score := range pb.GetScores() {
+		s.Scores = append(s.Scores, scoreFromProto(verr, fmt.Sprintf("%sscores[%d].", prefix, i), score))
+	}
+	for i, ts := range pb.GetTeamScores() {
+		s.TeamScores = append(s.TeamScores, TeamScore{
+			TeamID:      idFromProto(verr, fmt.Sprintf("%steam_scores[%d].team_id", prefix, i), ts.GetTeamId()),
+			Value:       ts.GetValue(),
+			Tiebreakers: ts.GetTiebreakers(),
+			Breakdown:   scoreLinesFromProto(ts.GetBreakdown()),
+		})
+	}
+	return s
+}
+
+func scoreLinesToProto(lines []ScoreLine) []*corepb.ScoreLine {
+	var pb []*corepb.ScoreLine
+	for _, l := range lines {
+		pb = append(pb, &corepb.ScoreLine{Label: l.Label, Value: l.Value})
+	}
+	return pb
+}
+
+func scoreLinesFromProto(pb []*corepb.ScoreLine) []ScoreLine {
+	var lines []ScoreLine
+	for _, l := range pb {
+		lines = append(lines, ScoreLine{Label: l.GetLabel(), Value: l.GetValue()})
+	}
+	return lines
+}
+
+// Game
+
+func (g *Game) ToProto() (*corepb.Game, error) {
+	verr := &ValidationError{}
+	pb := g.toProto(verr, "")
+	return pb, verr.Err()
+}
+
+func GameFromProto(pb *corepb.Game) (*Game, error) {
+	verr := &ValidationError{}
+	g := gameFromProto(verr, "", pb)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (g *Game) MarshalProto() ([]byte, error) {
+	return marshalMessage(g.ToProto())
+}
+
+func (g *Game) UnmarshalProto(data []byte) error {
+	return unmarshalMessage(data, g, GameFromProto)
+}
+
+func (g *Game) toProto(verr *ValidationError, prefix string) *corepb.Game {
+	pb := &corepb.Game{
+		GameId:     idToProto(g.GameID),
+		BggId:      int64(g.BGGID),
+		Rating:     g.Rating,
+		Name:       g.Name,
+		Categories: g.Categories,
+	}
+	if g.Scoring != (Scoring{}) {
+		pb.Scoring = &corepb.Scoring{
+			Unit:      enumToProto(verr, prefix+"scoring.unit", scoreUnits, g.Scoring.Unit),
+			Direction: enumToProto(verr, prefix+"scoring.direction", scoreDirections, g.Scoring.Direction),
+		}
+	}
+	return pb
+}
+
+func gameFromProto(verr *ValidationError, prefix string, pb *corepb.Game) Game {
+	return Game{
+		GameID:     idFromProto(verr, prefix+"game_id", pb.GetGameId()),
+		BGGID:      int(pb.GetBggId()),
+		Rating:     pb.GetRating(),
+		Name:       pb.GetName(),
+		Categories: append([]string{}, pb.GetCategories()...),
+		Scoring: Scoring{
+			Unit:      enumFromProto(verr, prefix+"scoring.unit", scoreUnits, pb.GetScoring().GetUnit()),
+			Direction: enumFromProto(verr, prefix+"scoring.direction", scoreDirections, pb.GetScoring().GetDirection()),
+		},
+	}
+}
+
+// Helpers
+
+// marshalMessage marshals the converted message unless the conversion
+// failed.
+func marshalMessage[M proto.Message](pb M, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(pb)
+}
+
+// unmarshalMessage unmarshals data into a message of type M and converts it
+// into v.
+func unmarshalMessage[T any, M any, PM interface {
+	*M
+	proto.Message
+}](data []byte, v *T, fromProto func(PM) (*T, error)) error {
+	pb := PM(new(M))
+	if err := proto.Unmarshal(data, pb); err != nil {
+		return err
+	}
+
+	converted, err := fromProto(pb)
+	if err != nil {
+		return err
+	}
+	*v = *converted
+	return nil
+}
+
+func idToProto(id typeid.TypeID) string {
+	if id.IsZero() {
+		return ""
+	}
+	return id.String()
+}
+
+func idFromProto(verr *ValidationError, field, s string) typeid.TypeID {
+	if s == "" {
+		return typeid.TypeID{}
+	}
+	id, err := typeid.Parse(s)
+	if err != nil {
+		verr.Add(field, "invalid id '%s'", s)
+	}
+	return id
+}
+
+func idsToProto(ids []typeid.TypeID) []string {
+	s := make([]string, 0, len(ids))
+	for _, id := range ids {
+		s = append(s, idToProto(id))
+	}
+	return s
+}
+
+func idsFromProto(verr *ValidationError, field string, s []string) []typeid.TypeID {
+	ids := make([]typeid.TypeID, 0, len(s))
+	for i, id := range s {
+		ids = append(ids, idFromProto(verr, fmt.Sprintf("%s[%d]", field, i), id))
+	}
+	return ids
+}
+
+// timeToProto encodes zero times as absent timestamps.
+func timeToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func timeFromProto(verr *ValidationError, field string, ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	if err := ts.CheckValid(); err != nil {
+		verr.Add(field, "invalid timestamp: %v", err)
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func enumToProto[S ~string, P ~int32](verr *ValidationError, field string, values map[S]P, v S) P {
+	pb, ok := values[v]
+	if !ok {
+		verr.Add(field, "unknown value '%s'", v)
+	}
+	return pb
+}
+
+func enumFromProto[S ~string, P ~int32](verr *ValidationError, field string, values map[S]P, pb P) S {
+	for v, p := range values {
+		if p == pb {
+			return v
+		}
+	}
+	verr.Add(field, "unknown value %d", pb)
+	return ""
+}
@@ -0,0 +1,141 @@
+package core_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/core/corepb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestProtoRoundTrip(t *testing.T) {
+	t.Parallel()
+	alice, bob := newPlayer("alice"), newPlayer("bob")
+	red, blue := core.NewTeamID(), core.NewTeamID()
+
+	match := newTestMatch()
+	teamMatch := core.Match{
+		MatchID: core.NewMatchID(),
+		Status:  core.MatchStatusInProgress,
+		Game: core.Game{
+			GameID:     core.NewGameID(),
+			Name:       "Codenames",
+			Categories: []string{},
+			Scoring:    core.Scoring{Unit: core.ScoreUnitPoints, Direction: core.ScoreDirectionLowerWins},
+		},
+		Players: []core.User{alice, bob},
+		Teams: []core.Team{
+			{TeamID: red, Name: "Red", Members: []core.UserID{alice.UserID}},
+			{TeamID: blue, Name: "Blue", Members: []core.UserID{bob.UserID}},
+		},
+		StartedAt: matchStart,
+		Scoreboard: core.Scoreboard{
+			Scores: []core.Score{
+				{UserID: alice.UserID, Value: 3, Breakdown: []core.ScoreLine{{Label: "Round 1", Value: 1}, {Label: "Round 2", Value: 2}}},
+				{UserID: bob.UserID, Value: 3, Tiebreakers: []float64{2}},
+			},
+			TeamScores: []core.TeamScore{
+				{TeamID: red, Value: 3},
+				{TeamID: blue, Value: 3, Tiebreakers: []float64{1}},
+			},
+			ScoreUnit: core.ScoreUnitPoints,
+			Direction: core.ScoreDirectionLowerWins,
+		},
+	}
+
+	testCases := []struct {
+		name  string
+		check func(t *testing.T)
+	}{
+		{"user", func(t *testing.T) { roundTrip(t, &alice) }},
+		{"attendee", func(t *testing.T) {
+			roundTrip(t, &core.Attendee{User: bob, Status: core.AttendeeStatusWaitlisted})
+		}},
+		{"score", func(t *testing.T) { roundTrip(t, &teamMatch.Scoreboard.Scores[0]) }},
+		{"scoreboard", func(t *testing.T) { roundTrip(t, &teamMatch.Scoreboard) }},
+		{"game", func(t *testing.T) { roundTrip(t, &teamMatch.Game) }},
+		{"match", func(t *testing.T) { roundTrip(t, &match) }},
+		{"team match", func(t *testing.T) { roundTrip(t, &teamMatch) }},
+		{"event", func(t *testing.T) {
+			roundTrip(t, &core.Event{
+				EventID:   core.NewEventID(),
+				Status:    core.EventStatusCompleted,
+				Title:     "Thursday game night",
+				StartsAt:  matchStart.Add(-time.Hour),
+				EndsAt:    matchStart.Add(4 * time.Hour),
+				Host:      &alice,
+				Capacity:  4,
+				Attendees: []core.Attendee{{User: alice, Status: core.AttendeeStatusConfirmed}},
+				Matches:   []core.Match{match},
+			})
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.check(t)
+		})
+	}
+}
+
+func roundTrip[T any](t *testing.T, original *T) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, core.Encode(&buf, core.ContentTypeProtobuf, original))
+
+	decoded, err := core.Decode[T](&buf, core.ContentTypeProtobuf)
+	require.NoError(t, err)
+	assert.Equal(t, original, decoded)
+}
+
+func TestProtoEventLocalized(t *testing.T) {
+	t.Parallel()
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	original := &core.Event{
+		EventID:   core.NewEventID(),
+		Status:    core.EventStatusScheduled,
+		StartsAt:  time.Date(2025, time.March, 6, 19, 0, 0, 0, berlin),
+		Timezone:  "Europe/Berlin",
+		Attendees: []core.Attendee{},
+		Matches:   []core.Match{},
+	}
+
+	pb, err := original.ToProto()
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-06T18:00:00Z", pb.GetStartsAt().AsTime().Format(time.RFC3339))
+
+	data, err := proto.Marshal(pb)
+	require.NoError(t, err)
+	decoded, err := core.Decode[core.Event](bytes.NewReader(data), core.ContentTypeProtobuf)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", decoded.StartsAt.Location().String())
+	assert.Equal(t, 19, decoded.StartsAt.Hour())
+	assert.True(t, decoded.EndsAt.IsZero())
+	assert.Nil(t, decoded.Host)
+}
+
+func TestProtoConversionErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := (&core.Attendee{User: newPlayer("alice"), Status: "maybe"}).ToProto()
+	assert.Equal(t, []string{"status"}, fields(t, err))
+
+	match := newTestMatch()
+	match.Scoreboard.ScoreUnit = "stars"
+	_, err = match.ToProto()
+	assert.Equal(t, []string{"scoreboard.score_unit"}, fields(t, err))
+
+	_, err = core.MatchFromProto(&corepb.Match{
+		MatchId: "not an id",
+		Status:  corepb.MatchStatus(42),
+		Players: []*corepb.User{{UserId: core.NewUserID().String()}, {UserId: "nobody"}},
+	})
+	assert.Equal(t, []string{"match_id", "status", "players[1].user_id"}, fields(t, err))
+}
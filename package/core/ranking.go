@@ -0,0 +1,126 @@
+package core
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Valid reports whether the direction is known. The zero value is valid.
+func (d ScoreDirection) Valid() bool {
+	switch d {
+	case "", ScoreDirectionHigherWins, ScoreDirectionLowerWins:
+		return true
+	default:
+		return false
+	}
+}
+
+// Placement is a player's result in a match.
+type Placement struct {
+	UserID UserID `json:"user_id,omitzero" typeid:"user"`
+	// TeamID is set in team matches to the team the placement was earned by.
+	TeamID TeamID  `json:"team_id,omitzero" typeid:"team"`
+	Score  float64 `json:"score"`
+	// Place is the 1-based standing. Tied players share a place and the
+	// following places are skipped, e.g. 1, 2, 2, 4.
+	Place int `json:"place"`
+	// Tied is set if another player shares the place.
+	Tied bool `json:"tied,omitempty"`
+	// Won is set for players in first place; for outcome units for players
+	// who scored ScoreWin.
+	Won bool `json:"won"`
+}
+
+// Rank computes the placements using standard competition ranking in the
+// scoreboard's effective direction. Scores with equal value and tiebreakers
+// tie. Placements are ordered by place, tied players in the order of the
+// scoreboard.
+func (s *Scoreboard) Rank() []Placement {
+	return s.rank(s.Scores)
+}
+
+func (s *Scoreboard) rank(scores []Score) []Placement {
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+
+	slices.SortStableFunc(order, func(a, b int) int {
+		return s.compare(scores[a], scores[b])
+	})
+
+	placements := make([]Placement, len(order))
+	for i, idx := range order {
+		score := scores[idx]
+		placements[i] = Placement{UserID: score.UserID, Score: score.Value, Place: i + 1}
+
+		if i > 0 && s.compare(scores[order[i-1]], score) == 0 {
+			placements[i].Place = placements[i-1].Place
+			placements[i].Tied = true
+			placements[i-1].Tied = true
+		}
+	}
+
+	for i := range placements {
+		if s.ScoreUnit.Outcome() {
+			placements[i].Won = placements[i].Score == ScoreWin
+		} else {
+			placements[i].Won = placements[i].Place == 1
+		}
+	}
+
+	return placements
+}
+
+// Winners returns the players who won: those in first place, several if
+// they tied, or for outcome units everyone who scored ScoreWin, possibly
+// no one.
+func (s *Scoreboard) Winners() []UserID {
+	winners := make([]UserID, 0, 1)
+	for _, p := range s.Rank() {
+		if p.Won {
+			winners = append(winners, p.UserID)
+		}
+	}
+	return winners
+}
+
+// Placement returns the placement of the player, if scored.
+func (s *Scoreboard) Placement(userID UserID) (Placement, bool) {
+	for _, p := range s.Rank() {
+		if p.UserID == userID {
+			return p, true
+		}
+	}
+	return Placement{}, false
+}
+
+// compare orders a before b if a ranks better. Missing tiebreakers rank
+// worse than present ones.
+func (s *Scoreboard) compare(a, b Score) int {
+	if c := s.compareValues(a.Value, b.Value); c != 0 {
+		return c
+	}
+
+	for i := range max(len(a.Tiebreakers), len(b.Tiebreakers)) {
+		switch {
+		case i >= len(a.Tiebreakers):
+			return 1
+		case i >= len(b.Tiebreakers):
+			return -1
+		}
+
+		if c := s.compareValues(a.Tiebreakers[i], b.Tiebreakers[i]); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+func (s *Scoreboard) compareValues(a, b float64) int {
+	if s.EffectiveDirection() == ScoreDirectionLowerWins {
+		return cmp.Compare(a, b)
+	}
+	return cmp.Compare(b, a)
+}
@@ -0,0 +1,125 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func newScoreboard(direction core.ScoreDirection, scores ...core.Score) (*core.Scoreboard, []core.UserID) {
+	ids := make([]core.UserID, len(scores))
+	for i := range scores {
+		ids[i] = core.NewUserID()
+		scores[i].UserID = ids[i]
+	}
+	return &core.Scoreboard{Scores: scores, ScoreUnit: core.ScoreUnitPoints, Direction: direction}, ids
+}
+
+func places(placements []core.Placement, ids []core.UserID) []int {
+	byID := make(map[core.UserID]int, len(placements))
+	for _, p := range placements {
+		byID[p.UserID] = p.Place
+	}
+
+	out := make([]int, len(ids))
+	for i, id := range ids {
+		out[i] = byID[id]
+	}
+	return out
+}
+
+func TestScoreboardRank(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name      string
+		direction core.ScoreDirection
+		scores    []core.Score
+		want      []int
+	}{
+		{
+			name:   "higher wins by default",
+			scores: []core.Score{{Value: 10}, {Value: 30}, {Value: 20}},
+			want:   []int{3, 1, 2},
+		},
+		{
+			name:      "lower wins",
+			direction: core.ScoreDirectionLowerWins,
+			scores:    []core.Score{{Value: 72}, {Value: 68}, {Value: 75}},
+			want:      []int{2, 1, 3},
+		},
+		{
+			name:   "standard competition ranking",
+			scores: []core.Score{{Value: 50}, {Value: 40}, {Value: 40}, {Value: 30}},
+			want:   []int{1, 2, 2, 4},
+		},
+		{
+			name:   "shared first place",
+			scores: []core.Score{{Value: 50}, {Value: 50}, {Value: 10}},
+			want:   []int{1, 1, 3},
+		},
+		{
+			name: "tiebreakers decide equal values",
+			scores: []core.Score{
+				{Value: 40, Tiebreakers: []float64{3}},
+				{Value: 40, Tiebreakers: []float64{5}},
+				{Value: 40, Tiebreakers: []float64{5, 1}},
+			},
+			want: []int{3, 2, 1},
+		},
+		{
+			name:      "tiebreakers follow the direction",
+			direction: core.ScoreDirectionLowerWins,
+			scores: []core.Score{
+				{Value: 12, Tiebreakers: []float64{3}},
+				{Value: 12, Tiebreakers: []float64{2}},
+			},
+			want: []int{2, 1},
+		},
+		{
+			name: "equal tiebreakers tie",
+			scores: []core.Score{
+				{Value: 40, Tiebreakers: []float64{2}},
+				{Value: 40, Tiebreakers: []float64{2}},
+			},
+			want: []int{1, 1},
+		},
+		{
+			name:   "no scores",
+			scores: []core.Score{},
+			want:   []int{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sb, ids := newScoreboard(tc.direction, tc.scores...)
+			assert.Equal(t, tc.want, places(sb.Rank(), ids))
+		})
+	}
+}
+
+func TestScoreboardRank_Order(t *testing.T) {
+	t.Parallel()
+	sb, ids := newScoreboard("", core.Score{Value: 40}, core.Score{Value: 50}, core.Score{Value: 40})
+
+	assert.Equal(t, []core.Placement{
+		{UserID: ids[1], Score: 50, Place: 1, Won: true},
+		{UserID: ids[0], Score: 40, Place: 2, Tied: true},
+		{UserID: ids[2], Score: 40, Place: 2, Tied: true},
+	}, sb.Rank())
+}
+
+func TestScoreboardWinners(t *testing.T) {
+	t.Parallel()
+	sb, ids := newScoreboard("", core.Score{Value: 50}, core.Score{Value: 50}, core.Score{Value: 10})
+	assert.Equal(t, []core.UserID{ids[0], ids[1]}, sb.Winners())
+
+	p, ok := sb.Placement(ids[2])
+	assert.True(t, ok)
+	assert.Equal(t, 3, p.Place)
+
+	_, ok = sb.Placement(core.NewUserID())
+	assert.False(t, ok)
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	// ErrAlreadyInvited is returned when inviting a user who already attends
+	// the event.
+	ErrAlreadyInvited = errors.New("user is already invited")
+	// ErrNotInvited is returned when responding for a user who was not
+	// invited to the event.
+	ErrNotInvited = errors.New("user is not invited")
+)
+
+// RSVPKind names a change of an attendee's status.
+type RSVPKind string
+
+const (
+	RSVPInvited    RSVPKind = "invited"
+	RSVPAccepted   RSVPKind = "accepted"
+	RSVPDeclined   RSVPKind = "declined"
+	RSVPWaitlisted RSVPKind = "waitlisted"
+	RSVPPromoted   RSVPKind = "promoted"
+)
+
+// RSVPChange is the domain event emitted for every attendee status change.
+type RSVPChange struct {
+	Kind    RSVPKind       `json:"kind"`
+	EventID EventID        `json:"event_id" typeid:"event"`
+	User    User           `json:"user"`
+	From    AttendeeStatus `json:"from,omitempty"`
+	To      AttendeeStatus `json:"to"`
+}
+
+// Invite adds the user as a pending attendee.
+func (e *Event) Invite(user User) ([]RSVPChange, error) {
+	if err := e.CheckMutable(); err != nil {
+		return nil, err
+	}
+
+	if e.attendeeIndex(user.UserID) >= 0 {
+		return nil, fmt.Errorf("user '%s' in event '%s': %w", user.UserID, e.EventID, ErrAlreadyInvited)
+	}
+
+	e.Attendees = append(e.Attendees, Attendee{User: user, Status: AttendeeStatusPending})
+
+	return []RSVPChange{e.change(RSVPInvited, user, "", AttendeeStatusPending)}, nil
+}
+
+// Accept confirms the invited user. If the event is at capacity the user is
+// waitlisted instead and moved behind everyone waitlisted before.
+// Accepting again does not change anything.
+func (e *Event) Accept(userID UserID) ([]RSVPChange, error) {
+	idx, err := e.invitedIndex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	a := e.Attendees[idx]
+	if a.Status == AttendeeStatusConfirmed {
+		return nil, nil
+	}
+
+	if !e.hasCapacity() {
+		if a.Status == AttendeeStatusWaitlisted {
+			return nil, nil
+		}
+
+		e.Attendees = slices.Delete(e.Attendees, idx, idx+1)
+		e.Attendees = append(e.Attendees, Attendee{User: a.User, Status: AttendeeStatusWaitlisted})
+
+		return []RSVPChange{e.change(RSVPWaitlisted, a.User, a.Status, AttendeeStatusWaitlisted)}, nil
+	}
+
+	e.Attendees[idx].Status = AttendeeStatusConfirmed
+
+	return []RSVPChange{e.change(RSVPAccepted, a.User, a.Status, AttendeeStatusConfirmed)}, nil
+}
+
+// Decline marks the invited user as declined. If a confirmed attendee
+// declines, the first waitlisted attendee is promoted to confirmed.
+func (e *Event) Decline(userID UserID) ([]RSVPChange, error) {
+	idx, err := e.invitedIndex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	a := e.Attendees[idx]
+	if a.Status == AttendeeStatusDeclined {
+		return nil, nil
+	}
+
+	e.Attendees[idx].Status = AttendeeStatusDeclined
+	changes := []RSVPChange{e.change(RSVPDeclined, a.User, a.Status, AttendeeStatusDeclined)}
+
+	if a.Status == AttendeeStatusConfirmed {
+		changes = append(changes, e.PromoteWaitlisted()...)
+	}
+
+	return changes, nil
+}
+
+// PromoteWaitlisted confirms waitlisted attendees in order while the event
+// has capacity left.
+func (e *Event) PromoteWaitlisted() []RSVPChange {
+	var changes []RSVPChange
+	for i := range e.Attendees {
+		if !e.hasCapacity() {
+			break
+		}
+
+		a := &e.Attendees[i]
+		if a.Status != AttendeeStatusWaitlisted {
+			continue
+		}
+
+		a.Status = AttendeeStatusConfirmed
+		changes = append(changes, e.change(RSVPPromoted, a.User, AttendeeStatusWaitlisted, AttendeeStatusConfirmed))
+	}
+	return changes
+}
+
+func (e *Event) hasCapacity() bool {
+	return e.Capacity == 0 || e.CountAttendees(AttendeeStatusConfirmed) < e.Capacity
+}
+
+func (e *Event) attendeeIndex(userID UserID) int {
+	return slices.IndexFunc(e.Attendees, func(a Attendee) bool {
+		return a.User.UserID == userID
+	})
+}
+
+// invitedIndex returns the index of the user's attendee entry on a mutable
+// event.
+func (e *Event) invitedIndex(userID UserID) (int, error) {
+	if err := e.CheckMutable(); err != nil {
+		return -1, err
+	}
+
+	idx := e.attendeeIndex(userID)
+	if idx < 0 {
+		return -1, fmt.Errorf("user '%s' in event '%s': %w", userID, e.EventID, ErrNotInvited)
+	}
+
+	return idx, nil
+}
+
+func (e *Event) change(kind RSVPKind, user User, from, to AttendeeStatus) RSVPChange {
+	return RSVPChange{
+		Kind:    kind,
+		EventID: e.EventID,
+		User:    user,
+		From:    from,
+		To:      to,
+	}
+}
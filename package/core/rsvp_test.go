@@ -0,0 +1,146 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRSVPEvent(capacity int) *core.Event {
+	return &core.Event{
+		EventID:   core.NewEventID(),
+		Status:    core.EventStatusScheduled,
+		Capacity:  capacity,
+		Attendees: []core.Attendee{},
+		Matches:   []core.Match{},
+	}
+}
+
+func invite(t *testing.T, evt *core.Event, username string) core.User {
+	t.Helper()
+	user := core.User{UserID: core.NewUserID(), Username: username}
+	_, err := evt.Invite(user)
+	require.NoError(t, err)
+	return user
+}
+
+func statuses(evt *core.Event) []string {
+	out := make([]string, 0, len(evt.Attendees))
+	for _, a := range evt.Attendees {
+		out = append(out, a.User.Username+":"+string(a.Status))
+	}
+	return out
+}
+
+func TestEventInvite(t *testing.T) {
+	t.Parallel()
+	evt := newRSVPEvent(0)
+	alice := core.User{UserID: core.NewUserID(), Username: "alice"}
+
+	changes, err := evt.Invite(alice)
+	require.NoError(t, err)
+	assert.Equal(t, []core.RSVPChange{{
+		Kind:    core.RSVPInvited,
+		EventID: evt.EventID,
+		User:    alice,
+		To:      core.AttendeeStatusPending,
+	}}, changes)
+
+	_, err = evt.Invite(alice)
+	assert.ErrorIs(t, err, core.ErrAlreadyInvited)
+
+	require.NoError(t, evt.Transition(core.EventTransitionCancel))
+	_, err = evt.Invite(core.User{UserID: core.NewUserID()})
+	assert.ErrorIs(t, err, core.ErrEventClosed)
+}
+
+func TestEventAccept(t *testing.T) {
+	t.Parallel()
+	evt := newRSVPEvent(1)
+	alice := invite(t, evt, "alice")
+	bob := invite(t, evt, "bob")
+	carol := invite(t, evt, "carol")
+
+	changes, err := evt.Accept(alice.UserID)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, core.RSVPAccepted, changes[0].Kind)
+	assert.Equal(t, core.AttendeeStatusPending, changes[0].From)
+
+	// accepting again is a no-op
+	changes, err = evt.Accept(alice.UserID)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// the event is at capacity, so later acceptances are waitlisted in order
+	changes, err = evt.Accept(carol.UserID)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, core.RSVPWaitlisted, changes[0].Kind)
+
+	_, err = evt.Accept(bob.UserID)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"alice:confirmed", "carol:waitlisted", "bob:waitlisted"}, statuses(evt))
+	require.NoError(t, evt.Validate())
+
+	_, err = evt.Accept(core.NewUserID())
+	assert.ErrorIs(t, err, core.ErrNotInvited)
+}
+
+func TestEventDecline(t *testing.T) {
+	t.Parallel()
+	evt := newRSVPEvent(1)
+	alice := invite(t, evt, "alice")
+	bob := invite(t, evt, "bob")
+	carol := invite(t, evt, "carol")
+
+	for _, u := range []core.User{alice, bob, carol} {
+		_, err := evt.Accept(u.UserID)
+		require.NoError(t, err)
+	}
+
+	// a waitlisted attendee declining does not promote anyone
+	changes, err := evt.Decline(carol.UserID)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, core.RSVPDeclined, changes[0].Kind)
+	assert.Equal(t, core.AttendeeStatusWaitlisted, changes[0].From)
+
+	// a confirmed attendee declining promotes the first waitlisted
+	changes, err = evt.Decline(alice.UserID)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, core.RSVPDeclined, changes[0].Kind)
+	assert.Equal(t, alice, changes[0].User)
+	assert.Equal(t, core.RSVPPromoted, changes[1].Kind)
+	assert.Equal(t, bob, changes[1].User)
+	assert.Equal(t, core.AttendeeStatusConfirmed, changes[1].To)
+
+	assert.Equal(t, []string{"alice:declined", "bob:confirmed", "carol:declined"}, statuses(evt))
+
+	changes, err = evt.Decline(alice.UserID)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestEventPromoteWaitlisted(t *testing.T) {
+	t.Parallel()
+	evt := newRSVPEvent(1)
+	alice := invite(t, evt, "alice")
+	bob := invite(t, evt, "bob")
+	carol := invite(t, evt, "carol")
+	for _, u := range []core.User{alice, bob, carol} {
+		_, err := evt.Accept(u.UserID)
+		require.NoError(t, err)
+	}
+
+	assert.Empty(t, evt.PromoteWaitlisted())
+
+	evt.Capacity = 0
+	changes := evt.PromoteWaitlisted()
+	assert.Len(t, changes, 2)
+	assert.Equal(t, 3, evt.CountAttendees(core.AttendeeStatusConfirmed))
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"reflect"
+
+	"github.com/invopop/jsonschema"
+)
+
+//go:generate go run schema_gen.go
+
+// typeIDSuffixPattern matches the base32 suffix of type ids.
+const typeIDSuffixPattern = `[0-7][0-9a-hjkmnp-tv-z]{25}`
+
+// typeIDPattern matches type ids as encoded in JSON: a lowercase prefix and
+// a base32 suffix.
+const typeIDPattern = `^([a-z]([a-z_]{0,61}[a-z])?_)?` + typeIDSuffixPattern + `$`
+
+// typeIDPatternFor matches type ids with the prefix.
+func typeIDPatternFor(prefix string) string {
+	return `^` + prefix + `_` + typeIDSuffixPattern + `$`
+}
+
+var schemaReflector = &jsonschema.Reflector{
+	Mapper: schemaType,
+}
+
+// schemaType maps ids and string enums, which the reflector would describe
+// as plain objects and strings.
+func schemaType(t reflect.Type) *jsonschema.Schema {
+	if t == typeIDType {
+		return &jsonschema.Schema{Type: "string", Pattern: typeIDPattern}
+	}
+	values, ok := enumValues[t]
+	if !ok {
+		return nil
+	}
+	enum := make([]any, 0, len(values))
+	for _, v := range values {
+		enum = append(enum, v)
+	}
+	return &jsonschema.Schema{Type: "string", Enum: enum}
+}
+
+// Schema returns the JSON Schema of the type's JSON encoding. Values
+// conforming to it are accepted by DecodeStrict. Fields tagged omitempty or
+// omitzero are optional, all others required; unknown fields are rejected.
+func Schema[T any]() *jsonschema.Schema {
+	t := reflect.TypeFor[T]()
+	schema := schemaReflector.ReflectFromType(t)
+	restrictIDPrefixes(schema, t, map[reflect.Type]bool{})
+	return schema
+}
+
+// restrictIDPrefixes narrows the patterns of id properties to the prefix in
+// the typeid tag of their field, as the mapper only sees the id type.
+func restrictIDPrefixes(schema *jsonschema.Schema, t reflect.Type, seen map[reflect.Type]bool) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == typeIDType || t == timeType || seen[t] {
+		return
+	}
+	seen[t] = true
+
+	def := schema.Definitions[t.Name()]
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		name, _, ok := jsonField(sf)
+		if !ok {
+			continue
+		}
+		restrictIDPrefixes(schema, sf.Type, seen)
+
+		idPrefix := sf.Tag.Get("typeid")
+		if def == nil || idPrefix == "" {
+			continue
+		}
+		prop, ok := def.Properties.Get(name)
+		if !ok {
+			continue
+		}
+		if prop.Items != nil {
+			prop = prop.Items
+		}
+		prop.Pattern = typeIDPatternFor(idPrefix)
+	}
+}
+
+// Schemas returns the JSON Schemas of the core types with JSON encoders, by
+// the name of the type in snake case. They are generated into the schema
+// directory by go generate.
+func Schemas() map[string]*jsonschema.Schema {
+	return map[string]*jsonschema.Schema{
+		"user":       Schema[User](),
+		"attendee":   Schema[Attendee](),
+		"event":      Schema[Event](),
+		"match":      Schema[Match](),
+		"score":      Schema[Score](),
+		"scoreboard": Schema[Scoreboard](),
+		"game":       Schema[Game](),
+	}
+}
@@ -0,0 +1,32 @@
+//go:build ignore
+
+// schema_gen writes the JSON Schemas of the core types to the schema
+// directory.
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ngoldack/dicetrace/package/core"
+)
+
+func main() {
+	const dir = "schema"
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Fatal(err)
+	}
+
+	for name, schema := range core.Schemas() {
+		data, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			log.Fatalf("failed to encode schema %s: %v", name, err)
+		}
+		path := filepath.Join(dir, name+".schema.json")
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
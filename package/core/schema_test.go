@@ -0,0 +1,66 @@
+package core_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemasGenerated(t *testing.T) {
+	t.Parallel()
+	for name, schema := range core.Schemas() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			want, err := json.MarshalIndent(schema, "", "  ")
+			require.NoError(t, err)
+
+			got, err := os.ReadFile(filepath.Join("schema", name+".schema.json"))
+			require.NoError(t, err, "run go generate")
+			assert.JSONEq(t, string(want), string(got), "run go generate")
+		})
+	}
+}
+
+func TestSchemaAttendee(t *testing.T) {
+	t.Parallel()
+	schema := core.Schema[core.Attendee]()
+
+	attendee := schema.Definitions["Attendee"]
+	require.NotNil(t, attendee)
+	assert.Equal(t, []string{"user", "status"}, attendee.Required)
+	status, ok := attendee.Properties.Get("status")
+	require.True(t, ok)
+	assert.Equal(t, []any{"confirmed", "pending", "declined", "waitlisted"}, status.Enum)
+
+	user := schema.Definitions["User"]
+	require.NotNil(t, user)
+	userID, ok := user.Properties.Get("user_id")
+	require.True(t, ok)
+	assert.NotEmpty(t, userID.Pattern)
+	assert.Regexp(t, userID.Pattern, core.NewUserID().String())
+	assert.NotRegexp(t, userID.Pattern, core.NewEventID().String())
+}
+
+func TestSchemaIDPrefixes(t *testing.T) {
+	t.Parallel()
+	schema := core.Schema[core.Team]()
+
+	members, ok := schema.Definitions["Team"].Properties.Get("members")
+	require.True(t, ok)
+	require.NotNil(t, members.Items)
+	assert.Regexp(t, members.Items.Pattern, core.NewUserID().String())
+	assert.NotRegexp(t, members.Items.Pattern, core.NewTeamID().String())
+}
+
+func TestSchemaOptionalFields(t *testing.T) {
+	t.Parallel()
+	schema := core.Schema[core.Match]()
+	assert.Equal(t, []string{"match_id", "game", "players", "scoreboard"}, schema.Definitions["Match"].Required)
+	assert.Equal(t, []string{"scores", "score_unit"}, schema.Definitions["Scoreboard"].Required)
+	assert.Empty(t, schema.Definitions["Scoring"].Required)
+}
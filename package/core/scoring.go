@@ -0,0 +1,122 @@
+package core
+
+import (
+	"fmt"
+	"math"
+)
+
+// Valid reports whether the unit is a known score unit.
+func (u ScoreUnit) Valid() bool {
+	switch u {
+	case ScoreUnitPoints, ScoreUnitCustom, ScoreUnitCooperative, ScoreUnitWinLoss, ScoreUnitRank, ScoreUnitTime:
+		return true
+	default:
+		return false
+	}
+}
+
+// Outcome reports whether the unit records only wins and losses.
+func (u ScoreUnit) Outcome() bool {
+	return u == ScoreUnitCooperative || u == ScoreUnitWinLoss
+}
+
+// DefaultDirection is the direction used when a scoreboard does not set one.
+func (u ScoreUnit) DefaultDirection() ScoreDirection {
+	switch u {
+	case ScoreUnitRank, ScoreUnitTime:
+		return ScoreDirectionLowerWins
+	default:
+		return ScoreDirectionHigherWins
+	}
+}
+
+// allowsDirection reports whether a scoreboard of the unit may set the
+// direction. Ranks and outcomes have a fixed meaning.
+func (u ScoreUnit) allowsDirection(d ScoreDirection) bool {
+	if d == "" {
+		return true
+	}
+	switch u {
+	case ScoreUnitRank, ScoreUnitCooperative, ScoreUnitWinLoss:
+		return d == u.DefaultDirection()
+	default:
+		return true
+	}
+}
+
+// checkValue returns why the value is invalid for the unit, or an empty
+// string. players is the number of scored players.
+func (u ScoreUnit) checkValue(value float64, players int) string {
+	switch u {
+	case ScoreUnitCooperative, ScoreUnitWinLoss:
+		if value != ScoreWin && value != ScoreLoss {
+			return fmt.Sprintf("must be %g (win) or %g (loss)", ScoreWin, ScoreLoss)
+		}
+	case ScoreUnitRank:
+		if value != math.Trunc(value) || value < 1 || value > float64(players) {
+			return fmt.Sprintf("must be a whole rank between 1 and %d", players)
+		}
+	case ScoreUnitTime:
+		if value < 0 {
+			return "must not be negative"
+		}
+	}
+	return ""
+}
+
+// initialValue is the score a player starts with: a loss for outcome units,
+// a shared first place for ranks and zero otherwise.
+func (u ScoreUnit) initialValue() float64 {
+	if u == ScoreUnitRank {
+		return 1
+	}
+	return 0
+}
+
+// EffectiveDirection is the scoreboard's direction, falling back to the
+// default of its unit.
+func (s *Scoreboard) EffectiveDirection() ScoreDirection {
+	if s.Direction != "" {
+		return s.Direction
+	}
+	return s.ScoreUnit.DefaultDirection()
+}
+
+// NewScoreboard returns an empty scoreboard for the players, configured by
+// the game's scoring. Every player starts with a zero score; outcome units
+// start as a loss and ranks as a shared first place.
+func (g *Game) NewScoreboard(players []User) Scoreboard {
+	unit := g.Scoring.Unit
+	if unit == "" {
+		unit = ScoreUnitPoints
+	}
+
+	scores := make([]Score, 0, len(players))
+	for _, p := range players {
+		scores = append(scores, Score{UserID: p.UserID, Value: unit.initialValue()})
+	}
+
+	return Scoreboard{
+		Scores:    scores,
+		ScoreUnit: unit,
+		Direction: g.Scoring.Direction,
+	}
+}
+
+// Validate checks the game's scoring configuration.
+func (s Scoring) Validate() error {
+	verr := &ValidationError{}
+	s.validate(verr, "")
+	return verr.Err()
+}
+
+func (s Scoring) validate(verr *ValidationError, prefix string) {
+	if s.Unit != "" && !s.Unit.Valid() {
+		verr.Add(prefix+"unit", "unknown score unit '%s'", s.Unit)
+	}
+	if !s.Direction.Valid() {
+		verr.Add(prefix+"direction", "unknown score direction '%s'", s.Direction)
+	} else if s.Unit.Valid() && !s.Unit.allowsDirection(s.Direction) {
+		verr.Add(prefix+"direction", "score unit '%s' does not support direction '%s'", s.Unit, s.Direction)
+	}
+}
@@ -0,0 +1,216 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMatchWithScores(unit core.ScoreUnit, direction core.ScoreDirection, values ...float64) *core.Match {
+	m := &core.Match{
+		MatchID: core.NewMatchID(),
+		Game:    core.Game{GameID: core.NewGameID(), Name: "Pandemic"},
+		Scoreboard: core.Scoreboard{
+			Scores:    []core.Score{},
+			ScoreUnit: unit,
+			Direction: direction,
+		},
+	}
+	for _, v := range values {
+		p := newPlayer("player")
+		m.Players = append(m.Players, p)
+		m.Scoreboard.Scores = append(m.Scoreboard.Scores, core.Score{UserID: p.UserID, Value: v})
+	}
+	return m
+}
+
+func TestScoreUnitValidation(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name      string
+		unit      core.ScoreUnit
+		direction core.ScoreDirection
+		values    []float64
+		fields    []string
+	}{
+		{name: "cooperative win", unit: core.ScoreUnitCooperative, values: []float64{1, 1, 1}},
+		{name: "cooperative loss", unit: core.ScoreUnitCooperative, values: []float64{0, 0}},
+		{
+			name:   "cooperative split outcome",
+			unit:   core.ScoreUnitCooperative,
+			values: []float64{1, 0},
+			fields: []string{"scoreboard.scores[1].value"},
+		},
+		{
+			name:   "cooperative non-outcome value",
+			unit:   core.ScoreUnitCooperative,
+			values: []float64{2, 2},
+			fields: []string{"scoreboard.scores[0].value", "scoreboard.scores[1].value"},
+		},
+		{name: "win/loss", unit: core.ScoreUnitWinLoss, values: []float64{1, 0, 0}},
+		{
+			name:      "win/loss with lower wins",
+			unit:      core.ScoreUnitWinLoss,
+			direction: core.ScoreDirectionLowerWins,
+			values:    []float64{1, 0},
+			fields:    []string{"scoreboard.direction"},
+		},
+		{name: "rank", unit: core.ScoreUnitRank, values: []float64{2, 1, 2}},
+		{
+			name:   "rank out of range",
+			unit:   core.ScoreUnitRank,
+			values: []float64{1, 3},
+			fields: []string{"scoreboard.scores[1].value"},
+		},
+		{
+			name:   "fractional rank",
+			unit:   core.ScoreUnitRank,
+			values: []float64{1.5, 1},
+			fields: []string{"scoreboard.scores[0].value"},
+		},
+		{
+			name:      "rank with higher wins",
+			unit:      core.ScoreUnitRank,
+			direction: core.ScoreDirectionHigherWins,
+			values:    []float64{1, 2},
+			fields:    []string{"scoreboard.direction"},
+		},
+		{name: "time", unit: core.ScoreUnitTime, values: []float64{312.5, 290}},
+		{name: "survival time", unit: core.ScoreUnitTime, direction: core.ScoreDirectionHigherWins, values: []float64{60, 90}},
+		{
+			name:   "negative time",
+			unit:   core.ScoreUnitTime,
+			values: []float64{-1, 10},
+			fields: []string{"scoreboard.scores[0].value"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			m := newMatchWithScores(tc.unit, tc.direction, tc.values...)
+			assert.Equal(t, tc.fields, fields(t, m.Validate()))
+		})
+	}
+}
+
+func TestScoreUnitRanking(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name    string
+		unit    core.ScoreUnit
+		values  []float64
+		places  []int
+		winners []int
+	}{
+		{
+			name:    "cooperative win",
+			unit:    core.ScoreUnitCooperative,
+			values:  []float64{1, 1},
+			places:  []int{1, 1},
+			winners: []int{0, 1},
+		},
+		{
+			name:    "cooperative loss",
+			unit:    core.ScoreUnitCooperative,
+			values:  []float64{0, 0},
+			places:  []int{1, 1},
+			winners: []int{},
+		},
+		{
+			name:    "win/loss",
+			unit:    core.ScoreUnitWinLoss,
+			values:  []float64{0, 1, 0},
+			places:  []int{2, 1, 2},
+			winners: []int{1},
+		},
+		{
+			name:    "rank",
+			unit:    core.ScoreUnitRank,
+			values:  []float64{3, 1, 2},
+			places:  []int{3, 1, 2},
+			winners: []int{1},
+		},
+		{
+			name:    "fastest time wins",
+			unit:    core.ScoreUnitTime,
+			values:  []float64{95, 80, 120},
+			places:  []int{2, 1, 3},
+			winners: []int{1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			m := newMatchWithScores(tc.unit, "", tc.values...)
+			require.NoError(t, m.Validate())
+
+			ids := make([]core.UserID, len(m.Players))
+			for i, p := range m.Players {
+				ids[i] = p.UserID
+			}
+			assert.Equal(t, tc.places, places(m.Scoreboard.Rank(), ids))
+
+			winners := make([]core.UserID, 0, len(tc.winners))
+			for _, i := range tc.winners {
+				winners = append(winners, ids[i])
+			}
+			assert.Equal(t, winners, m.Scoreboard.Winners())
+		})
+	}
+}
+
+func TestGameNewScoreboard(t *testing.T) {
+	t.Parallel()
+	players := []core.User{newPlayer("alice"), newPlayer("bob")}
+
+	testCases := []struct {
+		name    string
+		scoring core.Scoring
+		unit    core.ScoreUnit
+		initial float64
+	}{
+		{name: "default", unit: core.ScoreUnitPoints, initial: 0},
+		{name: "cooperative", scoring: core.Scoring{Unit: core.ScoreUnitCooperative}, unit: core.ScoreUnitCooperative, initial: core.ScoreLoss},
+		{name: "rank", scoring: core.Scoring{Unit: core.ScoreUnitRank}, unit: core.ScoreUnitRank, initial: 1},
+		{
+			name:    "golf",
+			scoring: core.Scoring{Unit: core.ScoreUnitPoints, Direction: core.ScoreDirectionLowerWins},
+			unit:    core.ScoreUnitPoints,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			game := core.Game{GameID: core.NewGameID(), Scoring: tc.scoring}
+
+			sb := game.NewScoreboard(players)
+			assert.Equal(t, tc.unit, sb.ScoreUnit)
+			assert.Equal(t, tc.scoring.Direction, sb.Direction)
+			require.Len(t, sb.Scores, 2)
+			assert.Equal(t, players[1].UserID, sb.Scores[1].UserID)
+			assert.Equal(t, tc.initial, sb.Scores[0].Value)
+			assert.NoError(t, sb.Validate(players))
+		})
+	}
+}
+
+func TestScoringValidate(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, core.Scoring{}.Validate())
+	assert.NoError(t, core.Scoring{Unit: core.ScoreUnitTime, Direction: core.ScoreDirectionHigherWins}.Validate())
+
+	err := core.Scoring{Unit: "goals"}.Validate()
+	assert.Equal(t, []string{"unit"}, fields(t, err))
+
+	err = core.Scoring{Unit: core.ScoreUnitRank, Direction: core.ScoreDirectionHigherWins}.Validate()
+	assert.Equal(t, []string{"direction"}, fields(t, err))
+
+	m := newValidMatch()
+	m.Game.Scoring.Direction = "sideways"
+	assert.Equal(t, []string{"game.scoring.direction"}, fields(t, m.Validate()))
+}
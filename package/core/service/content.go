@@ -0,0 +1,69 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/package/core"
+)
+
+// Headers negotiating the encoding of payloads. Payloads without a
+// Content-Type header are JSON, and so are responses to requests without an
+// Accept header.
+const (
+	HeaderContentType = "Content-Type"
+	HeaderAccept      = "Accept"
+)
+
+// ResponseContentType returns the content type to respond to the request
+// in: the first type listed in its Accept header that has a codec, or JSON.
+func ResponseContentType(r micro.Request) string {
+	return negotiate(r.Headers().Get(HeaderAccept))
+}
+
+func negotiate(accept string) string {
+	for _, option := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(option))
+		if err != nil {
+			continue
+		}
+		if _, err := core.CodecFor(mediaType); err == nil {
+			return mediaType
+		}
+	}
+	return core.ContentTypeJSON
+}
+
+// DecodeRequest decodes the request's payload in its content type.
+func DecodeRequest[T any](r micro.Request) (*T, error) {
+	return core.Decode[T](bytes.NewReader(r.Data()), r.Headers().Get(HeaderContentType))
+}
+
+// Respond responds with the value encoded in the content type the request
+// accepts.
+func Respond[T any](r micro.Request, v *T) error {
+	contentType := ResponseContentType(r)
+
+	var buf bytes.Buffer
+	if err := core.Encode(&buf, contentType, v); err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+
+	return r.Respond(buf.Bytes(), micro.WithHeaders(micro.Headers{HeaderContentType: {contentType}}))
+}
+
+// Accept asks the service receiving the message to respond in the content
+// type.
+func Accept(msg *nats.Msg, contentType string) {
+	msg.Header.Set(HeaderAccept, contentType)
+}
+
+// DecodeResponse decodes a response payload in its content type, so JSON
+// responses of services unaware of the Accept header are still decoded.
+func DecodeResponse[T any](msg *nats.Msg) (*T, error) {
+	return core.Decode[T](bytes.NewReader(msg.Data), msg.Header.Get(HeaderContentType))
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go/micro"
+	"github.com/ngoldack/dicetrace/package/core"
+	"go.jetify.com/typeid/v2"
+)
+
+// Service error codes shared by all services.
+const (
+	ErrorInternal   = "internal_error"
+	ErrorValidation = "validation_failed"
+	ErrorTransition = "invalid_transition"
+)
+
+const requestTimeout = 10 * time.Second
+
+// RequestContext returns the context a single request is handled in.
+func RequestContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), requestTimeout)
+}
+
+// IDFromHeader parses the typeid in the given request header.
+func IDFromHeader(r micro.Request, header, prefix string) (typeid.TypeID, bool) {
+	value := r.Headers().Get(header)
+	if value == "" {
+		return typeid.TypeID{}, false
+	}
+
+	id, err := typeid.Parse(value)
+	if err != nil || id.Prefix() != prefix {
+		return typeid.TypeID{}, false
+	}
+
+	return id, true
+}
+
+// ErrorCode maps a domain error to the service error code it is reported as.
+type ErrorCode struct {
+	Err  error
+	Code string
+}
+
+// RespondError maps domain errors to service errors. The codes are checked
+// in order, so the first matching entry wins for errors wrapping several
+// targets. Errors not matching any known kind are logged and reported as
+// internal errors.
+func RespondError(r micro.Request, err error, codes []ErrorCode) {
+	for _, c := range codes {
+		if errors.Is(err, c.Err) {
+			r.Error(c.Code, ErrorDescription(err), nil)
+			return
+		}
+	}
+
+	var verr *core.ValidationError
+	if errors.As(err, &verr) {
+		r.Error(ErrorValidation, ErrorDescription(err), nil)
+		return
+	}
+
+	var terr *core.TransitionError
+	if errors.As(err, &terr) {
+		r.Error(ErrorTransition, ErrorDescription(err), nil)
+		return
+	}
+
+	slog.Error("request failed", slog.String("subject", r.Subject()), slog.Any("error", err))
+	r.Error(ErrorInternal, "internal error", nil)
+}
+
+// ErrorDescription flattens err into a single line, as service error
+// descriptions are transported in a message header.
+func ErrorDescription(err error) string {
+	return strings.ReplaceAll(err.Error(), "\n", "; ")
+}
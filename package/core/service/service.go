@@ -43,8 +43,10 @@ func (s *Service) Stop() error {
 	return s.srv.Stop()
 }
 
+// Call sends a request to the endpoint of the service. Endpoints are served
+// on the subject of their name, so serviceName only labels errors.
 func Call(ctx context.Context, nc *nats.Conn, serviceName, endpoint string, req []byte) ([]byte, error) {
-	msg := nats.NewMsg(fmt.Sprintf("%s.%s", serviceName, endpoint))
+	msg := nats.NewMsg(endpoint)
 	msg.Data = req
 
 	resp, err := nc.RequestMsgWithContext(ctx, msg)
@@ -0,0 +1,240 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/ngoldack/dicetrace/package/core"
+)
+
+// ErrMalformedEvent is returned for messages that are no domain event.
+// They are dropped, as redelivering cannot fix them.
+var ErrMalformedEvent = errors.New("malformed domain event")
+
+const (
+	defaultRetryDelay    = 10 * time.Second
+	defaultMaxRetryDelay = 10 * time.Minute
+
+	// processedTTL keeps handled event IDs beyond the duplicate window of
+	// the streams.
+	processedTTL = 7 * 24 * time.Hour
+)
+
+// Handler handles a domain event. Failing events are redelivered, so
+// handlers see every event at least once.
+type Handler func(ctx context.Context, evt *core.DomainEvent) error
+
+// ProcessedLog remembers the domain events a consumer has handled, so
+// redelivered events are handled only once.
+type ProcessedLog interface {
+	Processed(ctx context.Context, id core.DomainEventID) (bool, error)
+	MarkProcessed(ctx context.Context, id core.DomainEventID) error
+}
+
+type KVProcessedLog struct {
+	kv jetstream.KeyValue
+}
+
+var _ ProcessedLog = (*KVProcessedLog)(nil)
+
+// NewKVProcessedLog returns the log of the durable consumer.
+func NewKVProcessedLog(ctx context.Context, js jetstream.JetStream, durable string) (*KVProcessedLog, error) {
+	bucket := "processed_" + durable
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      bucket,
+		Description: fmt.Sprintf("Domain events handled by %s", durable),
+		TTL:         processedTTL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key value bucket '%s': %w", bucket, err)
+	}
+
+	return &KVProcessedLog{
+		kv: kv,
+	}, nil
+}
+
+func (l *KVProcessedLog) Processed(ctx context.Context, id core.DomainEventID) (bool, error) {
+	_, err := l.kv.Get(ctx, id.String())
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get domain event '%s': %w", id, err)
+	}
+	return true, nil
+}
+
+func (l *KVProcessedLog) MarkProcessed(ctx context.Context, id core.DomainEventID) error {
+	if _, err := l.kv.Put(ctx, id.String(), nil); err != nil {
+		return fmt.Errorf("failed to mark domain event '%s' as processed: %w", id, err)
+	}
+	return nil
+}
+
+// ConsumerConfig configures a durable consumer.
+type ConsumerConfig struct {
+	// Durable names the consumer. It resumes after the last acknowledged
+	// event when restarted.
+	Durable     string
+	Description string
+	// Types are the consumed events, all of the same aggregate.
+	Types []core.DomainEventType
+
+	// MaxDeliver optionally bounds the deliveries of a failing event, which
+	// are unlimited by default. An event failing its last delivery is
+	// published to the consumer's dead-letter subject, see DeadLetterSubject.
+	MaxDeliver int
+	// RetryDelay delays the first redelivery of a failing event, 10 seconds
+	// by default. The delay doubles with every further redelivery up to
+	// MaxRetryDelay, 10 minutes by default.
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+}
+
+// Backoff returns the delay before redelivering a message that failed its
+// delivered-th delivery: the delay doubles per delivery up to maxDelay.
+func Backoff(delay, maxDelay time.Duration, delivered uint64) time.Duration {
+	for ; delivered > 1 && delay < maxDelay; delivered-- {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// Consumer feeds the domain events of a stream to a handler.
+type Consumer struct {
+	js        jetstream.JetStream
+	cfg       ConsumerConfig
+	processed ProcessedLog
+	handler   Handler
+}
+
+func NewConsumer(js jetstream.JetStream, cfg ConsumerConfig, processed ProcessedLog, handler Handler) *Consumer {
+	if cfg.MaxDeliver <= 0 {
+		cfg.MaxDeliver = -1
+	}
+	if cfg.RetryDelay == 0 {
+		cfg.RetryDelay = defaultRetryDelay
+	}
+	if cfg.MaxRetryDelay == 0 {
+		cfg.MaxRetryDelay = defaultMaxRetryDelay
+	}
+
+	return &Consumer{
+		js:        js,
+		cfg:       cfg,
+		processed: processed,
+		handler:   handler,
+	}
+}
+
+// Run consumes events with a durable consumer until the context is done.
+func (c *Consumer) Run(ctx context.Context) error {
+	if len(c.cfg.Types) == 0 {
+		return fmt.Errorf("consumer '%s' has no event types", c.cfg.Durable)
+	}
+
+	name, ok := StreamOf(c.cfg.Types[0])
+	if !ok {
+		return fmt.Errorf("consumer '%s': no stream for %s", c.cfg.Durable, c.cfg.Types[0])
+	}
+	subjects := make([]string, 0, len(c.cfg.Types))
+	for _, t := range c.cfg.Types {
+		if other, _ := StreamOf(t); other != name {
+			return fmt.Errorf("consumer '%s': %s is not in stream '%s'", c.cfg.Durable, t, name)
+		}
+		subjects = append(subjects, Subject(t))
+	}
+
+	consumer, err := c.js.CreateOrUpdateConsumer(ctx, name, jetstream.ConsumerConfig{
+		Durable:        c.cfg.Durable,
+		Description:    c.cfg.Description,
+		FilterSubjects: subjects,
+		AckPolicy:      jetstream.AckExplicitPolicy,
+		MaxDeliver:     c.cfg.MaxDeliver,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create consumer '%s': %w", c.cfg.Durable, err)
+	}
+
+	cc, err := consumer.Consume(func(msg jetstream.Msg) {
+		c.handle(ctx, msg)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to consume domain events: %w", err)
+	}
+	defer cc.Stop()
+
+	<-ctx.Done()
+	return nil
+}
+
+func (c *Consumer) handle(ctx context.Context, msg jetstream.Msg) {
+	err := c.Process(ctx, msg.Data())
+	switch {
+	case errors.Is(err, ErrMalformedEvent):
+		slog.ErrorContext(ctx, "dropping malformed domain event", slog.String("consumer", c.cfg.Durable), slog.Any("error", err))
+		_ = msg.Term()
+	case err != nil:
+		slog.ErrorContext(ctx, "failed to handle domain event", slog.String("consumer", c.cfg.Durable), slog.Any("error", err))
+		c.retry(ctx, msg)
+	default:
+		_ = msg.Ack()
+	}
+}
+
+// retry redelivers the failed message with a backoff. On its last delivery
+// the message is published to the dead-letter subject instead, as JetStream
+// would drop it silently.
+func (c *Consumer) retry(ctx context.Context, msg jetstream.Msg) {
+	var delivered uint64 = 1
+	if meta, err := msg.Metadata(); err == nil {
+		delivered = meta.NumDelivered
+	}
+
+	if c.cfg.MaxDeliver < 0 || delivered < uint64(c.cfg.MaxDeliver) {
+		_ = msg.NakWithDelay(Backoff(c.cfg.RetryDelay, c.cfg.MaxRetryDelay, delivered))
+		return
+	}
+
+	subject := DeadLetterSubject(c.cfg.Durable)
+	if _, err := c.js.Publish(ctx, subject, msg.Data()); err != nil {
+		slog.ErrorContext(ctx, "failed to dead-letter domain event", slog.String("consumer", c.cfg.Durable),
+			slog.String("data", string(msg.Data())), slog.Any("error", err))
+		_ = msg.NakWithDelay(c.cfg.MaxRetryDelay)
+		return
+	}
+
+	slog.ErrorContext(ctx, "dead-lettered domain event", slog.String("consumer", c.cfg.Durable), slog.String("subject", subject))
+	_ = msg.Term()
+}
+
+// Process decodes a delivered domain event and hands it to the handler,
+// unless it was handled before.
+func (c *Consumer) Process(ctx context.Context, data []byte) error {
+	var evt core.DomainEvent
+	if err := json.Unmarshal(data, &evt); err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedEvent, err)
+	}
+	if evt.ID.IsZero() {
+		return fmt.Errorf("%w: missing id", ErrMalformedEvent)
+	}
+
+	processed, err := c.processed.Processed(ctx, evt.ID)
+	if err != nil {
+		return err
+	}
+	if processed {
+		return nil
+	}
+
+	if err := c.handler(ctx, &evt); err != nil {
+		return fmt.Errorf("domain event '%s': %w", evt.ID, err)
+	}
+
+	return c.processed.MarkProcessed(ctx, evt.ID)
+}
@@ -0,0 +1,123 @@
+package stream_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/core/stream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockProcessedLog implements stream.ProcessedLog in memory
+type mockProcessedLog struct {
+	mu        sync.Mutex
+	processed map[string]bool
+}
+
+func (l *mockProcessedLog) Processed(ctx context.Context, id core.DomainEventID) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.processed[id.String()], nil
+}
+
+func (l *mockProcessedLog) MarkProcessed(ctx context.Context, id core.DomainEventID) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.processed == nil {
+		l.processed = make(map[string]bool)
+	}
+	l.processed[id.String()] = true
+	return nil
+}
+
+func newEventData(t *testing.T) (*core.DomainEvent, []byte) {
+	t.Helper()
+	user := core.User{UserID: core.NewUserID(), Username: "alice"}
+	evt, err := core.NewDomainEvent(core.DomainEventUserSaved, user.UserID, time.Now(), user)
+	require.NoError(t, err)
+	data, err := json.Marshal(evt)
+	require.NoError(t, err)
+	return evt, data
+}
+
+func TestSubject(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "domain.match.finalized", stream.Subject(core.DomainEventMatchFinalized))
+
+	name, ok := stream.StreamOf(core.DomainEventAttendeeAdded)
+	assert.True(t, ok)
+	assert.Equal(t, stream.StreamEvents, name)
+
+	_, ok = stream.StreamOf("poll.closed")
+	assert.False(t, ok)
+}
+
+func TestConsumerProcess(t *testing.T) {
+	t.Parallel()
+	evt, data := newEventData(t)
+
+	var handled []core.DomainEventID
+	consumer := stream.NewConsumer(nil, stream.ConsumerConfig{Durable: "test"}, &mockProcessedLog{}, func(ctx context.Context, got *core.DomainEvent) error {
+		handled = append(handled, got.ID)
+		return nil
+	})
+
+	require.NoError(t, consumer.Process(t.Context(), data))
+	require.NoError(t, consumer.Process(t.Context(), data), "redelivery")
+	assert.Equal(t, []core.DomainEventID{evt.ID}, handled, "handled once")
+}
+
+func TestConsumerProcessFailure(t *testing.T) {
+	t.Parallel()
+	_, data := newEventData(t)
+	testErr := errors.New("database unavailable")
+
+	attempts := 0
+	consumer := stream.NewConsumer(nil, stream.ConsumerConfig{Durable: "test"}, &mockProcessedLog{}, func(ctx context.Context, got *core.DomainEvent) error {
+		attempts++
+		if attempts == 1 {
+			return testErr
+		}
+		return nil
+	})
+
+	require.ErrorIs(t, consumer.Process(t.Context(), data), testErr)
+	require.NoError(t, consumer.Process(t.Context(), data), "failed events are handled again")
+	assert.Equal(t, 2, attempts)
+}
+
+func TestConsumerProcessMalformed(t *testing.T) {
+	t.Parallel()
+	consumer := stream.NewConsumer(nil, stream.ConsumerConfig{Durable: "test"}, &mockProcessedLog{}, func(ctx context.Context, got *core.DomainEvent) error {
+		t.Error("malformed events must not be handled")
+		return nil
+	})
+
+	for _, data := range []string{"not json", `{"type":"user.saved"}`} {
+		assert.ErrorIs(t, consumer.Process(t.Context(), []byte(data)), stream.ErrMalformedEvent, data)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		delivered uint64
+		want      time.Duration
+	}{
+		{delivered: 0, want: 10 * time.Second},
+		{delivered: 1, want: 10 * time.Second},
+		{delivered: 2, want: 20 * time.Second},
+		{delivered: 4, want: 80 * time.Second},
+		{delivered: 7, want: 10 * time.Minute},
+		{delivered: 1000, want: 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, stream.Backoff(10*time.Second, 10*time.Minute, tt.delivered), tt.delivered)
+	}
+}
@@ -0,0 +1,118 @@
+// Package stream persists domain events in JetStream and feeds them to
+// durable consumers. Every aggregate has its own stream: changes of users,
+// events and matches are published on "domain.<type>", e.g.
+// "domain.match.finalized", to the stream of the type's aggregate.
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/ngoldack/dicetrace/package/core"
+)
+
+// SubjectPrefix prefixes the subjects domain events are published on.
+const SubjectPrefix = "domain."
+
+// DeadLetterPrefix prefixes the dead-letter subjects of consumers, see
+// DeadLetterSubject.
+const DeadLetterPrefix = "deadletter."
+
+// Streams of the aggregates.
+const (
+	StreamUsers   = "DOMAIN_USERS"
+	StreamEvents  = "DOMAIN_EVENTS"
+	StreamMatches = "DOMAIN_MATCHES"
+
+	// StreamDeadLetters keeps the domain events consumers gave up on.
+	StreamDeadLetters = "DEAD_LETTERS"
+)
+
+const (
+	// duplicateWindow bounds how long JetStream drops republished events.
+	duplicateWindow = 24 * time.Hour
+	maxAge          = 90 * 24 * time.Hour
+)
+
+// aggregates maps the aggregates to their streams.
+var aggregates = map[string]string{
+	"user":  StreamUsers,
+	"event": StreamEvents,
+	"match": StreamMatches,
+}
+
+// Subject returns the subject events of the type are published on.
+func Subject(eventType core.DomainEventType) string {
+	return SubjectPrefix + string(eventType)
+}
+
+// StreamOf returns the stream events of the type are persisted in.
+func StreamOf(eventType core.DomainEventType) (string, bool) {
+	name, ok := aggregates[eventType.Aggregate()]
+	return name, ok
+}
+
+// DeadLetterSubject returns the subject the durable consumer publishes the
+// events it gave up on to.
+func DeadLetterSubject(durable string) string {
+	return DeadLetterPrefix + durable
+}
+
+// CreateStreams creates or updates the streams of all aggregates and the
+// dead-letter stream.
+func CreateStreams(ctx context.Context, js jetstream.JetStream) error {
+	for aggregate, name := range aggregates {
+		_, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+			Name:        name,
+			Description: fmt.Sprintf("Domain events of %ss", aggregate),
+			Subjects:    []string{SubjectPrefix + aggregate + ".>"},
+			MaxAge:      maxAge,
+			Duplicates:  duplicateWindow,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create stream '%s': %w", name, err)
+		}
+	}
+
+	_, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:        StreamDeadLetters,
+		Description: "Domain events consumers gave up on",
+		Subjects:    []string{DeadLetterPrefix + ">"},
+		MaxAge:      maxAge,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create stream '%s': %w", StreamDeadLetters, err)
+	}
+
+	return nil
+}
+
+// JetStreamPublisher publishes domain events to their streams. The event ID
+// is used as message ID, so JetStream drops republished events.
+type JetStreamPublisher struct {
+	js jetstream.JetStream
+}
+
+var _ core.DomainEventPublisher = (*JetStreamPublisher)(nil)
+
+func NewJetStreamPublisher(js jetstream.JetStream) *JetStreamPublisher {
+	return &JetStreamPublisher{
+		js: js,
+	}
+}
+
+func (p *JetStreamPublisher) PublishDomainEvent(ctx context.Context, evt *core.DomainEvent) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal domain event: %w", err)
+	}
+
+	if _, err := p.js.Publish(ctx, Subject(evt.Type), data, jetstream.WithMsgID(evt.ID.String())); err != nil {
+		return fmt.Errorf("failed to publish domain event '%s': %w", evt.ID, err)
+	}
+
+	return nil
+}
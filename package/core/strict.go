@@ -0,0 +1,238 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"go.jetify.com/typeid/v2"
+)
+
+// Errors classifying the field errors of strict decoding, see DecodeStrict.
+var (
+	ErrUnknownField = errors.New("unknown field")
+	ErrRequired     = errors.New("required field missing")
+	ErrInvalidValue = errors.New("invalid value")
+)
+
+var (
+	typeIDType = reflect.TypeFor[typeid.TypeID]()
+	timeType   = reflect.TypeFor[time.Time]()
+)
+
+// enumValues lists the values of the string enums. The zero value is listed
+// for enums where it has a meaning of its own.
+var enumValues = map[reflect.Type][]string{
+	reflect.TypeFor[EventStatus](): {
+		"", string(EventStatusScheduled), string(EventStatusOngoing), string(EventStatusCompleted), string(EventStatusCancelled),
+	},
+	reflect.TypeFor[AttendeeStatus](): {
+		string(AttendeeStatusConfirmed), string(AttendeeStatusPending), string(AttendeeStatusDeclined), string(AttendeeStatusWaitlisted),
+	},
+	reflect.TypeFor[MatchStatus](): {
+		"", string(MatchStatusInProgress), string(MatchStatusFinalized),
+	},
+	reflect.TypeFor[ScoreUnit](): {
+		string(ScoreUnitPoints), string(ScoreUnitCustom), string(ScoreUnitCooperative),
+		string(ScoreUnitWinLoss), string(ScoreUnitRank), string(ScoreUnitTime),
+	},
+	reflect.TypeFor[ScoreDirection](): {
+		"", string(ScoreDirectionHigherWins), string(ScoreDirectionLowerWins),
+	},
+}
+
+// DecodeStrict reads a JSON value that must conform to the type's JSON
+// Schema: unknown fields, missing fields not tagged omitempty or omitzero,
+// empty or malformed IDs, IDs without the prefix in the typeid tag of their
+// field and unknown enum values are rejected. It returns a
+// *ValidationError listing every offending field, classified as
+// ErrUnknownField, ErrRequired or ErrInvalidValue. Conforming values are
+// validated and localized like in Decode.
+//
+// Lists may be null, as encoding/json encodes nil slices.
+func DecodeStrict[T any](r io.Reader) (*T, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var raw any
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	verr := &ValidationError{}
+	checkStrict(verr, "", raw, reflect.TypeFor[T](), "")
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	v := new(T)
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	if err := prepare(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// checkStrict checks the decoded JSON value at the path against the Go
+// type it is decoded into. IDs must have the idPrefix unless it is empty.
+func checkStrict(verr *ValidationError, path string, raw any, t reflect.Type, idPrefix string) {
+	if t.Kind() == reflect.Pointer {
+		if raw == nil {
+			return
+		}
+		t = t.Elem()
+	}
+	field := path
+	if field == "" {
+		field = "$"
+	}
+
+	if values, ok := enumValues[t]; ok {
+		s, ok := raw.(string)
+		if !ok {
+			verr.addErr(field, ErrInvalidValue, "must be a string")
+		} else if !slices.Contains(values, s) {
+			verr.addErr(field, ErrInvalidValue, "unknown value '%s'", s)
+		}
+		return
+	}
+
+	switch t {
+	case typeIDType:
+		s, ok := raw.(string)
+		switch {
+		case !ok:
+			verr.addErr(field, ErrInvalidValue, "must be a string")
+		case s == "":
+			verr.addErr(field, ErrRequired, "is required")
+		default:
+			id, err := typeid.Parse(s)
+			if err != nil {
+				verr.addErr(field, ErrInvalidValue, "invalid id '%s'", s)
+			} else if idPrefix != "" && id.Prefix() != idPrefix {
+				verr.addErr(field, ErrInvalidValue, "id '%s' is not a %s id", s, idPrefix)
+			}
+		}
+		return
+	case timeType:
+		s, ok := raw.(string)
+		if !ok {
+			verr.addErr(field, ErrInvalidValue, "must be a string")
+		} else if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+			verr.addErr(field, ErrInvalidValue, "invalid RFC 3339 timestamp '%s'", s)
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		if _, ok := raw.(string); !ok {
+			verr.addErr(field, ErrInvalidValue, "must be a string")
+		}
+	case reflect.Bool:
+		if _, ok := raw.(bool); !ok {
+			verr.addErr(field, ErrInvalidValue, "must be a boolean")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := raw.(json.Number); !ok {
+			verr.addErr(field, ErrInvalidValue, "must be an integer")
+		} else if _, err := n.Int64(); err != nil {
+			verr.addErr(field, ErrInvalidValue, "must be an integer")
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := raw.(json.Number); !ok {
+			verr.addErr(field, ErrInvalidValue, "must be a number")
+		}
+	case reflect.Slice:
+		if raw == nil {
+			return
+		}
+		elems, ok := raw.([]any)
+		if !ok {
+			verr.addErr(field, ErrInvalidValue, "must be an array")
+			return
+		}
+		for i, elem := range elems {
+			checkStrict(verr, fmt.Sprintf("%s[%d]", path, i), elem, t.Elem(), idPrefix)
+		}
+	case reflect.Struct:
+		obj, ok := raw.(map[string]any)
+		if !ok {
+			verr.addErr(field, ErrInvalidValue, "must be an object")
+			return
+		}
+		checkStrictObject(verr, path, obj, t)
+	}
+}
+
+func checkStrictObject(verr *ValidationError, path string, obj map[string]any, t reflect.Type) {
+	prefix := path
+	if prefix != "" {
+		prefix += "."
+	}
+
+	known := make(map[string]bool, t.NumField())
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		name, optional, ok := jsonField(sf)
+		if !ok {
+			continue
+		}
+		known[name] = true
+
+		raw, present := obj[name]
+		if !present {
+			if !optional {
+				verr.addErr(prefix+name, ErrRequired, "is required")
+			}
+			continue
+		}
+		checkStrict(verr, prefix+name, raw, sf.Type, sf.Tag.Get("typeid"))
+	}
+
+	unknown := make([]string, 0)
+	for name := range obj {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	slices.Sort(unknown)
+	for _, name := range unknown {
+		verr.addErr(prefix+name, ErrUnknownField, "unknown field")
+	}
+}
+
+// jsonField returns the JSON name of the struct field and whether it is
+// tagged omitempty or omitzero. ok is false for fields not encoded.
+func jsonField(sf reflect.StructField) (name string, optional, ok bool) {
+	if !sf.IsExported() {
+		return "", false, false
+	}
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = sf.Name
+	}
+	for opt := range strings.SplitSeq(opts, ",") {
+		if opt == "omitempty" || opt == "omitzero" {
+			optional = true
+		}
+	}
+	return name, optional, true
+}
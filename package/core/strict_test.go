@@ -0,0 +1,152 @@
+package core_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeStrictAcceptsEncoded(t *testing.T) {
+	t.Parallel()
+	original := newTestMatch()
+
+	var buf bytes.Buffer
+	require.NoError(t, core.EncodeMatch(&buf, &original))
+
+	decoded, err := core.DecodeStrict[core.Match](&buf)
+	require.NoError(t, err)
+	assert.Equal(t, original.MatchID, decoded.MatchID)
+	assert.Equal(t, original.Scoreboard, decoded.Scoreboard)
+
+	// nil slices are encoded as null
+	buf.Reset()
+	require.NoError(t, core.EncodeMatch(&buf, newValidMatch()))
+	_, err = core.DecodeStrict[core.Match](&buf)
+	require.NoError(t, err)
+}
+
+func TestDecodeStrictRejects(t *testing.T) {
+	t.Parallel()
+	userID := core.NewUserID().String()
+
+	testCases := []struct {
+		name    string
+		decode  func(payload string) error
+		payload string
+		fields  []string
+		err     error
+	}{
+		{
+			name:    "unknown field",
+			decode:  decodeStrict[core.User],
+			payload: `{"user_id":"` + userID + `","username":"alice","bgg_username":"","admin":true}`,
+			fields:  []string{"admin"},
+			err:     core.ErrUnknownField,
+		},
+		{
+			name:    "missing id",
+			decode:  decodeStrict[core.User],
+			payload: `{"username":"alice","bgg_username":""}`,
+			fields:  []string{"user_id"},
+			err:     core.ErrRequired,
+		},
+		{
+			name:    "empty id",
+			decode:  decodeStrict[core.User],
+			payload: `{"user_id":"","username":"alice","bgg_username":""}`,
+			fields:  []string{"user_id"},
+			err:     core.ErrRequired,
+		},
+		{
+			name:    "malformed id",
+			decode:  decodeStrict[core.User],
+			payload: `{"user_id":"alice","username":"alice","bgg_username":""}`,
+			fields:  []string{"user_id"},
+			err:     core.ErrInvalidValue,
+		},
+		{
+			name:    "id of another type",
+			decode:  decodeStrict[core.User],
+			payload: `{"user_id":"` + core.NewEventID().String() + `","username":"alice","bgg_username":""}`,
+			fields:  []string{"user_id"},
+			err:     core.ErrInvalidValue,
+		},
+		{
+			name:    "member id of another type",
+			decode:  decodeStrict[core.Team],
+			payload: `{"team_id":"` + core.NewTeamID().String() + `","name":"","members":["` + userID + `","` + core.NewGameID().String() + `"]}`,
+			fields:  []string{"members[1]"},
+			err:     core.ErrInvalidValue,
+		},
+		{
+			name:    "attendee status",
+			decode:  decodeStrict[core.Attendee],
+			payload: `{"user":{"user_id":"` + userID + `","username":"alice","bgg_username":""},"status":"maybe"}`,
+			fields:  []string{"status"},
+			err:     core.ErrInvalidValue,
+		},
+		{
+			name:    "event status",
+			decode:  decodeStrict[core.Event],
+			payload: `{"event_id":"` + core.NewEventID().String() + `","status":"postponed","title":"","location":"","attendees":[],"matches":[]}`,
+			fields:  []string{"status"},
+			err:     core.ErrInvalidValue,
+		},
+		{
+			name:    "score unit",
+			decode:  decodeStrict[core.Scoreboard],
+			payload: `{"scores":[],"score_unit":"stars"}`,
+			fields:  []string{"score_unit"},
+			err:     core.ErrInvalidValue,
+		},
+		{
+			name:    "wrong type",
+			decode:  decodeStrict[core.Score],
+			payload: `{"user_id":"` + userID + `","value":"61"}`,
+			fields:  []string{"value"},
+			err:     core.ErrInvalidValue,
+		},
+		{
+			name:   "every offending field",
+			decode: decodeStrict[core.Event],
+			payload: `{"event_id":"","status":"maybe","title":"","location":"","starts_at":"tonight",
+				"attendees":[{"user":{"username":"alice","bgg_username":""},"status":"maybe"}],"matches":[],"notes":""}`,
+			fields: []string{"event_id", "status", "starts_at", "attendees[0].user.user_id", "attendees[0].status", "notes"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.decode(tc.payload)
+			assert.Equal(t, tc.fields, fields(t, err))
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func decodeStrict[T any](payload string) error {
+	_, err := core.DecodeStrict[T](strings.NewReader(payload))
+	return err
+}
+
+func TestDecodeStrictValidates(t *testing.T) {
+	t.Parallel()
+	invalid := newTestMatch()
+	invalid.Scoreboard.Scores = invalid.Scoreboard.Scores[:1]
+
+	// encoded without validation, e.g. by another service
+	var buf bytes.Buffer
+	require.NoError(t, core.JSONCodec{}.Encode(&buf, &invalid))
+
+	decoded, err := core.DecodeStrict[core.Match](&buf)
+	assert.Nil(t, decoded)
+	assert.Equal(t, []string{"scoreboard.scores"}, fields(t, err))
+	assert.NotErrorIs(t, err, core.ErrInvalidValue)
+}
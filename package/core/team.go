@@ -0,0 +1,178 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IsTeamMatch reports whether the match is played in teams.
+func (m *Match) IsTeamMatch() bool {
+	return len(m.Teams) > 0
+}
+
+// TeamOf returns the team the player belongs to.
+func (m *Match) TeamOf(userID UserID) (Team, bool) {
+	for _, t := range m.Teams {
+		for _, member := range t.Members {
+			if member == userID {
+				return t, true
+			}
+		}
+	}
+	return Team{}, false
+}
+
+// Placements ranks the match. In team matches every member gets the
+// placement of their team; otherwise players are ranked individually.
+func (m *Match) Placements() []Placement {
+	if !m.IsTeamMatch() {
+		return m.Scoreboard.Rank()
+	}
+
+	placements := make([]Placement, 0, len(m.Players))
+	for _, tp := range m.Scoreboard.RankTeams() {
+		team, ok := m.team(tp.TeamID)
+		if !ok {
+			continue
+		}
+
+		for _, member := range team.Members {
+			p := tp
+			p.UserID = member
+			placements = append(placements, p)
+		}
+	}
+
+	return placements
+}
+
+// Winners returns the players who won the match; in team matches all
+// members of the winning teams.
+func (m *Match) Winners() []UserID {
+	winners := make([]UserID, 0, 1)
+	for _, p := range m.Placements() {
+		if p.Won {
+			winners = append(winners, p.UserID)
+		}
+	}
+	return winners
+}
+
+// RankTeams ranks the team scores like Rank ranks player scores. The
+// returned placements carry the TeamID and no UserID.
+func (s *Scoreboard) RankTeams() []Placement {
+	scores := make([]Score, 0, len(s.TeamScores))
+	for _, ts := range s.TeamScores {
+		scores = append(scores, Score{UserID: ts.TeamID, Value: ts.Value, Tiebreakers: ts.Tiebreakers})
+	}
+
+	placements := s.rank(scores)
+	for i := range placements {
+		placements[i].TeamID = placements[i].UserID
+		placements[i].UserID = UserID{}
+	}
+
+	return placements
+}
+
+func (m *Match) team(teamID TeamID) (Team, bool) {
+	for _, t := range m.Teams {
+		if t.TeamID == teamID {
+			return t, true
+		}
+	}
+	return Team{}, false
+}
+
+// validateTeams checks that the teams partition the players.
+func (m *Match) validateTeams(verr *ValidationError, prefix string) {
+	players := make(map[UserID]bool, len(m.Players))
+	for _, p := range m.Players {
+		players[p.UserID] = true
+	}
+
+	teamIDs := make(map[TeamID]int, len(m.Teams))
+	memberOf := make(map[UserID]int, len(m.Players))
+
+	for i, t := range m.Teams {
+		field := fmt.Sprintf("%steams[%d]", prefix, i)
+
+		if t.TeamID.IsZero() {
+			verr.Add(field+".team_id", "is required")
+		} else if first, ok := teamIDs[t.TeamID]; ok {
+			verr.Add(field+".team_id", "team '%s' is already listed at teams[%d]", t.TeamID, first)
+		} else {
+			teamIDs[t.TeamID] = i
+		}
+
+		if strings.TrimSpace(t.Name) == "" {
+			verr.Add(field+".name", "is required")
+		}
+		if len(t.Members) == 0 {
+			verr.Add(field+".members", "at least one member is required")
+		}
+
+		for j, member := range t.Members {
+			memberField := fmt.Sprintf("%s.members[%d]", field, j)
+			if !players[member] {
+				verr.Add(memberField, "user '%s' is not a player of the match", member)
+				continue
+			}
+			if first, ok := memberOf[member]; ok {
+				verr.Add(memberField, "player '%s' is already a member of teams[%d]", member, first)
+				continue
+			}
+			memberOf[member] = i
+		}
+	}
+
+	for _, p := range m.Players {
+		if _, ok := memberOf[p.UserID]; !ok && !p.UserID.IsZero() {
+			verr.Add(prefix+"teams", "player %s is not a member of any team", describeUser(p))
+		}
+	}
+}
+
+// validateTeamScores checks that every team has exactly one team score,
+// listed in the order of teams.
+func (s *Scoreboard) validateTeamScores(verr *ValidationError, prefix string, teams []Team) {
+	known := make(map[TeamID]bool, len(teams))
+	for _, t := range teams {
+		known[t.TeamID] = true
+	}
+
+	scored := make(map[TeamID]int, len(s.TeamScores))
+	for i, ts := range s.TeamScores {
+		field := fmt.Sprintf("%steam_scores[%d]", prefix, i)
+		s.validateValue(verr, field, ts.Value, ts.Tiebreakers, ts.Breakdown, len(s.TeamScores))
+
+		if !known[ts.TeamID] {
+			verr.Add(field+".team_id", "team '%s' is not a team of the match", ts.TeamID)
+			continue
+		}
+		if first, ok := scored[ts.TeamID]; ok {
+			verr.Add(field+".team_id", "team '%s' is already scored at team_scores[%d]", ts.TeamID, first)
+			continue
+		}
+		scored[ts.TeamID] = i
+
+		if i < len(teams) && teams[i].TeamID != ts.TeamID {
+			verr.Add(field+".team_id", "expected the score of team '%s', got '%s'; team scores must follow the order of teams",
+				teams[i].TeamID, ts.TeamID)
+		}
+	}
+
+	for _, t := range teams {
+		if _, ok := scored[t.TeamID]; !ok && !t.TeamID.IsZero() {
+			verr.Add(prefix+"team_scores", "team '%s' has no score", t.Name)
+		}
+	}
+
+	if s.ScoreUnit == ScoreUnitCooperative {
+		for i, ts := range s.TeamScores {
+			if ts.Value != s.TeamScores[0].Value {
+				verr.Add(fmt.Sprintf("%steam_scores[%d].value", prefix, i), "cooperative games are won or lost by all teams alike")
+			}
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package core_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTeamMatch returns a valid 2v2 match; the first team wins 10 to 7.
+func newTeamMatch() *core.Match {
+	alice, bob, carol, dave := newPlayer("alice"), newPlayer("bob"), newPlayer("carol"), newPlayer("dave")
+	red := core.Team{TeamID: core.NewTeamID(), Name: "Red", Members: []core.UserID{alice.UserID, bob.UserID}}
+	blue := core.Team{TeamID: core.NewTeamID(), Name: "Blue", Members: []core.UserID{carol.UserID, dave.UserID}}
+
+	return &core.Match{
+		MatchID: core.NewMatchID(),
+		Game:    core.Game{GameID: core.NewGameID(), Name: "Codenames"},
+		Players: []core.User{alice, bob, carol, dave},
+		Teams:   []core.Team{red, blue},
+		Scoreboard: core.Scoreboard{
+			Scores: []core.Score{},
+			TeamScores: []core.TeamScore{
+				{TeamID: red.TeamID, Value: 10},
+				{TeamID: blue.TeamID, Value: 7},
+			},
+			ScoreUnit: core.ScoreUnitPoints,
+		},
+	}
+}
+
+func TestTeamMatchValidate(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		modify func(m *core.Match)
+		fields []string
+	}{
+		{name: "valid", modify: func(*core.Match) {}},
+		{
+			name: "valid with player scores",
+			modify: func(m *core.Match) {
+				for _, p := range m.Players {
+					m.Scoreboard.Scores = append(m.Scoreboard.Scores, core.Score{UserID: p.UserID, Value: 3})
+				}
+			},
+		},
+		{
+			name:   "missing team id",
+			modify: func(m *core.Match) { m.Teams[0].TeamID = core.TeamID{} },
+			fields: []string{"teams[0].team_id", "scoreboard.team_scores[0].team_id"},
+		},
+		{
+			name:   "duplicate team id",
+			modify: func(m *core.Match) { m.Teams[1].TeamID = m.Teams[0].TeamID },
+			fields: []string{"teams[1].team_id", "scoreboard.team_scores[1].team_id"},
+		},
+		{
+			name:   "missing name",
+			modify: func(m *core.Match) { m.Teams[1].Name = " " },
+			fields: []string{"teams[1].name"},
+		},
+		{
+			name: "empty team",
+			modify: func(m *core.Match) {
+				m.Teams[1].Members = append(m.Teams[0].Members, m.Teams[1].Members...)
+				m.Teams[0].Members = nil
+			},
+			fields: []string{"teams[0].members"},
+		},
+		{
+			name:   "member is not a player",
+			modify: func(m *core.Match) { m.Teams[0].Members[1] = core.NewUserID() },
+			fields: []string{"teams[0].members[1]", "teams"},
+		},
+		{
+			name:   "player in two teams",
+			modify: func(m *core.Match) { m.Teams[1].Members = append(m.Teams[1].Members, m.Teams[0].Members[0]) },
+			fields: []string{"teams[1].members[2]"},
+		},
+		{
+			name:   "player without team",
+			modify: func(m *core.Match) { m.Teams[1].Members = m.Teams[1].Members[:1] },
+			fields: []string{"teams"},
+		},
+		{
+			name:   "missing team score",
+			modify: func(m *core.Match) { m.Scoreboard.TeamScores = m.Scoreboard.TeamScores[:1] },
+			fields: []string{"scoreboard.team_scores"},
+		},
+		{
+			name: "team scores out of order",
+			modify: func(m *core.Match) {
+				ts := m.Scoreboard.TeamScores
+				ts[0], ts[1] = ts[1], ts[0]
+			},
+			fields: []string{"scoreboard.team_scores[0].team_id", "scoreboard.team_scores[1].team_id"},
+		},
+		{
+			name:   "unknown team scored",
+			modify: func(m *core.Match) { m.Scoreboard.TeamScores[1].TeamID = core.NewTeamID() },
+			fields: []string{"scoreboard.team_scores[1].team_id", "scoreboard.team_scores"},
+		},
+		{
+			name: "partial player scores",
+			modify: func(m *core.Match) {
+				m.Scoreboard.Scores = []core.Score{{UserID: m.Players[0].UserID, Value: 3}}
+			},
+			fields: []string{"scoreboard.scores", "scoreboard.scores", "scoreboard.scores"},
+		},
+		{
+			name: "team scores without teams",
+			modify: func(m *core.Match) {
+				m.Teams = nil
+				for _, p := range m.Players {
+					m.Scoreboard.Scores = append(m.Scoreboard.Scores, core.Score{UserID: p.UserID, Value: 3})
+				}
+			},
+			fields: []string{"scoreboard.team_scores"},
+		},
+		{
+			name: "cooperative teams disagree",
+			modify: func(m *core.Match) {
+				m.Scoreboard.ScoreUnit = core.ScoreUnitCooperative
+				m.Scoreboard.TeamScores[0].Value = core.ScoreWin
+				m.Scoreboard.TeamScores[1].Value = core.ScoreLoss
+			},
+			fields: []string{"scoreboard.team_scores[1].value"},
+		},
+		{
+			name: "rank beyond team count",
+			modify: func(m *core.Match) {
+				m.Scoreboard.ScoreUnit = core.ScoreUnitRank
+				m.Scoreboard.TeamScores[0].Value = 1
+				m.Scoreboard.TeamScores[1].Value = 3
+			},
+			fields: []string{"scoreboard.team_scores[1].value"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			m := newTeamMatch()
+			tc.modify(m)
+
+			err := m.Validate()
+			if len(tc.fields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var verr *core.ValidationError
+			require.ErrorAs(t, err, &verr)
+
+			fields := make([]string, 0, len(verr.Errors))
+			for _, fe := range verr.Errors {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, tc.fields, fields)
+		})
+	}
+}
+
+func TestTeamMatchPlacements(t *testing.T) {
+	t.Parallel()
+	m := newTeamMatch()
+	red, blue := m.Teams[0], m.Teams[1]
+
+	placements := m.Placements()
+	require.Len(t, placements, 4)
+	for i, member := range red.Members {
+		assert.Equal(t, core.Placement{UserID: member, TeamID: red.TeamID, Score: 10, Place: 1, Won: true}, placements[i])
+	}
+	for i, member := range blue.Members {
+		assert.Equal(t, core.Placement{UserID: member, TeamID: blue.TeamID, Score: 7, Place: 2}, placements[2+i])
+	}
+	assert.Equal(t, red.Members, m.Winners())
+
+	team, ok := m.TeamOf(blue.Members[1])
+	require.True(t, ok)
+	assert.Equal(t, blue.TeamID, team.TeamID)
+
+	_, ok = m.TeamOf(core.NewUserID())
+	assert.False(t, ok)
+}
+
+func TestTeamMatchPlacementsTied(t *testing.T) {
+	t.Parallel()
+	m := newTeamMatch()
+	m.Scoreboard.TeamScores[1].Value = 10
+
+	placements := m.Placements()
+	require.Len(t, placements, 4)
+	for _, p := range placements {
+		assert.Equal(t, 1, p.Place)
+		assert.True(t, p.Tied)
+		assert.True(t, p.Won)
+	}
+	assert.Len(t, m.Winners(), 4)
+}
+
+func TestMatchPlacementsWithoutTeams(t *testing.T) {
+	t.Parallel()
+	m := newMatchWithScores(core.ScoreUnitPoints, "", 5, 8)
+
+	assert.False(t, m.IsTeamMatch())
+	assert.Equal(t, m.Scoreboard.Rank(), m.Placements())
+	assert.Equal(t, []core.UserID{m.Players[1].UserID}, m.Winners())
+}
+
+func TestEncodeDecodeTeamMatch(t *testing.T) {
+	t.Parallel()
+	original := newTeamMatch()
+
+	var buf bytes.Buffer
+	require.NoError(t, core.EncodeMatch(&buf, original))
+
+	decoded, err := core.DecodeMatch(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, original, decoded)
+}
+
+func TestEncodeMatchWithoutTeamsIsUnchanged(t *testing.T) {
+	t.Parallel()
+	m := newMatchWithScores(core.ScoreUnitPoints, "", 5, 8)
+
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+
+	var raw map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.NotContains(t, raw, "teams")
+
+	var scoreboard map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(raw["scoreboard"], &scoreboard))
+	assert.NotContains(t, scoreboard, "team_scores")
+}
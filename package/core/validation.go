@@ -0,0 +1,67 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError describes a single invalid field, addressed by its JSON path.
+type FieldError struct {
+	Field   string
+	Message string
+	// Err optionally classifies the error, e.g. as ErrUnknownField.
+	Err error
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError collects every FieldError found while validating a value.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fe.Error())
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the field errors, so errors.Is finds the errors they
+// are classified as.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		errs = append(errs, fe)
+	}
+	return errs
+}
+
+// Add records the field as invalid with the formatted message.
+func (e *ValidationError) Add(field, format string, args ...any) {
+	e.addErr(field, nil, format, args...)
+}
+
+func (e *ValidationError) addErr(field string, err error, format string, args ...any) {
+	e.Errors = append(e.Errors, FieldError{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+		Err:     err,
+	})
+}
+
+// Err returns nil if no field errors were collected, so callers can return it
+// directly.
+func (e *ValidationError) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
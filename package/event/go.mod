@@ -0,0 +1,10 @@
+module github.com/ngoldack/dicetrace/package/event
+
+go 1.25.3
+
+require (
+	github.com/jackc/pgx/v5 v5.7.4
+	github.com/stretchr/testify v1.11.1
+	github.com/testcontainers/testcontainers-go/modules/postgres v0.39.0
+	go.jetify.com/typeid/v2 v2.0.0-alpha.3
+)
@@ -0,0 +1,73 @@
+package event
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// migrationLockID is the advisory lock key guarding concurrent migration runs.
+const migrationLockID = 0x65766e74 // "evnt"
+
+// Migrate applies all pending schema migrations in lexical order. Applied
+// migrations are tracked in the schema_migrations table, so calling Migrate
+// on every startup is safe.
+func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	_, err := pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		name       TEXT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	entries, err := fs.ReadDir(migrations, "migrations")
+	if err != nil {
+		return fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	for _, entry := range entries {
+		file := path.Join("migrations", entry.Name())
+		name := path.Join("event", entry.Name())
+
+		err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+				return fmt.Errorf("failed to acquire migration lock: %w", err)
+			}
+
+			var applied bool
+			err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE name = $1)`, name).Scan(&applied)
+			if err != nil {
+				return fmt.Errorf("failed to check migration state: %w", err)
+			}
+			if applied {
+				return nil
+			}
+
+			sql, err := migrations.ReadFile(file)
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.Exec(ctx, string(sql)); err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(ctx, `INSERT INTO schema_migrations (name) VALUES ($1)`, name)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
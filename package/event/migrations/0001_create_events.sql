@@ -0,0 +1,58 @@
+CREATE TABLE IF NOT EXISTS users (
+    user_id      TEXT PRIMARY KEY,
+    username     TEXT NOT NULL,
+    bgg_username TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS games (
+    game_id    TEXT PRIMARY KEY,
+    bgg_id     INTEGER NOT NULL,
+    name       TEXT NOT NULL,
+    rating     DOUBLE PRECISION NOT NULL DEFAULT 0,
+    categories TEXT[] NOT NULL DEFAULT '{}'
+);
+
+CREATE TABLE events (
+    event_id   TEXT PRIMARY KEY,
+    status     TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX events_status_created_at_idx ON events (status, created_at);
+
+CREATE TABLE event_attendees (
+    event_id TEXT NOT NULL REFERENCES events (event_id) ON DELETE CASCADE,
+    user_id  TEXT NOT NULL REFERENCES users (user_id),
+    status   TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX event_attendees_user_id_idx ON event_attendees (user_id);
+
+CREATE TABLE matches (
+    match_id   TEXT PRIMARY KEY,
+    event_id   TEXT NOT NULL REFERENCES events (event_id) ON DELETE CASCADE,
+    game_id    TEXT NOT NULL REFERENCES games (game_id),
+    score_unit TEXT NOT NULL,
+    position   INTEGER NOT NULL
+);
+
+CREATE INDEX matches_event_id_idx ON matches (event_id);
+
+CREATE TABLE match_players (
+    match_id TEXT NOT NULL REFERENCES matches (match_id) ON DELETE CASCADE,
+    user_id  TEXT NOT NULL REFERENCES users (user_id),
+    position INTEGER NOT NULL,
+    PRIMARY KEY (match_id, user_id)
+);
+
+-- Scores reference the match only: a score's user is expected, but not
+-- enforced, to be one of the match players.
+CREATE TABLE match_scores (
+    match_id TEXT NOT NULL REFERENCES matches (match_id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    user_id  TEXT NOT NULL,
+    value    DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (match_id, position)
+);
@@ -0,0 +1,10 @@
+ALTER TABLE events
+    ADD COLUMN title        TEXT NOT NULL DEFAULT '',
+    ADD COLUMN location     TEXT NOT NULL DEFAULT '',
+    ADD COLUMN starts_at    TIMESTAMPTZ,
+    ADD COLUMN ends_at      TIMESTAMPTZ,
+    ADD COLUMN timezone     TEXT NOT NULL DEFAULT '',
+    ADD COLUMN host_user_id TEXT REFERENCES users (user_id),
+    ADD COLUMN capacity     INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX events_status_starts_at_idx ON events (status, starts_at);
@@ -0,0 +1,5 @@
+ALTER TABLE matches
+    ADD COLUMN score_direction TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE match_scores
+    ADD COLUMN tiebreakers DOUBLE PRECISION[];
@@ -0,0 +1,3 @@
+ALTER TABLE games
+    ADD COLUMN IF NOT EXISTS scoring_unit      TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS scoring_direction TEXT NOT NULL DEFAULT '';
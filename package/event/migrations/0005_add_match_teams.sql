@@ -0,0 +1,25 @@
+CREATE TABLE match_teams (
+    match_id TEXT NOT NULL REFERENCES matches (match_id) ON DELETE CASCADE,
+    team_id  TEXT NOT NULL,
+    name     TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (match_id, team_id)
+);
+
+CREATE TABLE match_team_members (
+    match_id TEXT NOT NULL,
+    team_id  TEXT NOT NULL,
+    user_id  TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (match_id, user_id),
+    FOREIGN KEY (match_id, team_id) REFERENCES match_teams (match_id, team_id) ON DELETE CASCADE
+);
+
+CREATE TABLE match_team_scores (
+    match_id    TEXT NOT NULL REFERENCES matches (match_id) ON DELETE CASCADE,
+    position    INTEGER NOT NULL,
+    team_id     TEXT NOT NULL,
+    value       DOUBLE PRECISION NOT NULL,
+    tiebreakers DOUBLE PRECISION[],
+    PRIMARY KEY (match_id, position)
+);
@@ -0,0 +1,11 @@
+ALTER TABLE matches
+    ADD COLUMN started_at TIMESTAMPTZ,
+    ADD COLUMN ended_at   TIMESTAMPTZ;
+
+ALTER TABLE match_scores
+    ADD COLUMN breakdown_labels TEXT[],
+    ADD COLUMN breakdown_values DOUBLE PRECISION[];
+
+ALTER TABLE match_team_scores
+    ADD COLUMN breakdown_labels TEXT[],
+    ADD COLUMN breakdown_values DOUBLE PRECISION[];
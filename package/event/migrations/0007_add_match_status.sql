@@ -0,0 +1,2 @@
+ALTER TABLE matches
+    ADD COLUMN status TEXT NOT NULL DEFAULT '';
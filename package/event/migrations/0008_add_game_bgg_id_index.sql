@@ -0,0 +1,17 @@
+-- Games were stored under a fresh game ID for every match. Keep the oldest
+-- game per BoardGameGeek ID and move the matches of the others onto it.
+WITH canonical AS (
+    SELECT game_id, min(game_id) OVER (PARTITION BY bgg_id) AS keep
+    FROM games
+    WHERE bgg_id <> 0
+)
+UPDATE matches m
+SET game_id = c.keep
+FROM canonical c
+WHERE m.game_id = c.game_id AND c.game_id <> c.keep;
+
+DELETE FROM games g
+WHERE g.bgg_id <> 0
+  AND EXISTS (SELECT 1 FROM games o WHERE o.bgg_id = g.bgg_id AND o.game_id < g.game_id);
+
+CREATE UNIQUE INDEX games_bgg_id_idx ON games (bgg_id) WHERE bgg_id <> 0;
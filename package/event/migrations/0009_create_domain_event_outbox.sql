@@ -0,0 +1,11 @@
+-- Domain events are stored in the transaction of the change they announce
+-- and removed by the outbox relay once published, in order of position.
+CREATE TABLE domain_event_outbox (
+    position     BIGSERIAL PRIMARY KEY,
+    event_id     TEXT NOT NULL UNIQUE,
+    type         TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    version      INTEGER NOT NULL,
+    occurred_at  TIMESTAMPTZ NOT NULL,
+    payload      JSONB NOT NULL
+);
@@ -0,0 +1,16 @@
+-- An event runs at most one tournament, whose duels are matches of the
+-- event. Rounds only reference their matches, so they are stored as JSON.
+CREATE TABLE tournaments (
+    event_id     TEXT PRIMARY KEY REFERENCES events (event_id) ON DELETE CASCADE,
+    format       TEXT NOT NULL,
+    game_id      TEXT NOT NULL REFERENCES games (game_id),
+    swiss_rounds INTEGER NOT NULL DEFAULT 0,
+    rounds       JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE TABLE tournament_players (
+    event_id TEXT NOT NULL REFERENCES tournaments (event_id) ON DELETE CASCADE,
+    user_id  TEXT NOT NULL REFERENCES users (user_id),
+    position INTEGER NOT NULL,
+    PRIMARY KEY (event_id, user_id)
+);
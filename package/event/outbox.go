@@ -0,0 +1,186 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ngoldack/dicetrace/package/core"
+	"go.jetify.com/typeid/v2"
+)
+
+const (
+	// outboxLockID is the advisory lock key letting a single relay publish
+	// at a time, so events are published in order.
+	outboxLockID = 0x6f626f78 // "obox"
+	// outboxBatchSize caps the events published per transaction.
+	outboxBatchSize = 100
+)
+
+// announce stores the domain event of a change in the outbox within the
+// change's transaction, so the event is published if and only if the
+// change is stored, see OutboxRelay.
+func announce(ctx context.Context, q querier, eventType core.DomainEventType, aggregateID typeid.TypeID, payload any) error {
+	evt, err := core.NewDomainEvent(eventType, aggregateID, time.Now().UTC(), payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(ctx, `
+		INSERT INTO domain_event_outbox (event_id, type, aggregate_id, version, occurred_at, payload)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		evt.ID.String(), string(evt.Type), evt.AggregateID.String(), evt.Version, evt.OccurredAt, []byte(evt.Payload),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store domain event %s of '%s': %w", eventType, aggregateID, err)
+	}
+
+	return nil
+}
+
+// announceNewMatch announces a match started in progress, or recorded in
+// one go and thus final right away.
+func announceNewMatch(ctx context.Context, q querier, eventID core.EventID, match *core.Match) error {
+	if !match.Finalized() {
+		return announce(ctx, q, core.DomainEventMatchStarted, match.MatchID, match)
+	}
+
+	if err := announce(ctx, q, core.DomainEventMatchRecorded, match.MatchID, match); err != nil {
+		return err
+	}
+	return announceResult(ctx, q, core.DomainEventMatchFinalized, eventID, match)
+}
+
+// announceResult announces the result of the finalized match.
+func announceResult(ctx context.Context, q querier, eventType core.DomainEventType, eventID core.EventID, match *core.Match) error {
+	return announce(ctx, q, eventType, match.MatchID, core.MatchFinalized{
+		EventID:    eventID,
+		Match:      *match,
+		Placements: match.Placements(),
+	})
+}
+
+// OutboxRelay publishes the domain events the PostgreSQLEventRepository
+// stored along with its changes. Events are published in the order they
+// were stored and removed from the outbox once published. An event may be
+// published again if removing it fails; JetStream drops such duplicates
+// within its duplicate window and consumers by event ID.
+type OutboxRelay struct {
+	pool      *pgxpool.Pool
+	publisher core.DomainEventPublisher
+}
+
+func NewOutboxRelay(pool *pgxpool.Pool, publisher core.DomainEventPublisher) *OutboxRelay {
+	return &OutboxRelay{
+		pool:      pool,
+		publisher: publisher,
+	}
+}
+
+// Run relays the stored events every interval until the context is done.
+func (r *OutboxRelay) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := r.Relay(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to relay domain events", slog.Any("error", err))
+			}
+			if err != nil || n < outboxBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Relay publishes the oldest stored events and returns how many were
+// published. Publishing stops at the first failing event, so later events
+// wait for it. Nothing is published while another relay is publishing.
+func (r *OutboxRelay) Relay(ctx context.Context) (int, error) {
+	var published int
+	var publishErr error
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var locked bool
+		if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, outboxLockID).Scan(&locked); err != nil {
+			return fmt.Errorf("failed to acquire outbox lock: %w", err)
+		}
+		if !locked {
+			return nil
+		}
+
+		rows, err := tx.Query(ctx, `
+			SELECT position, event_id, type, aggregate_id, version, occurred_at, payload
+			FROM domain_event_outbox
+			ORDER BY position
+			LIMIT $1`,
+			outboxBatchSize,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to query outbox: %w", err)
+		}
+		pending, err := pgx.CollectRows(rows, scanOutboxEvent)
+		if err != nil {
+			return fmt.Errorf("failed to scan outbox: %w", err)
+		}
+
+		positions := make([]int64, 0, len(pending))
+		for _, p := range pending {
+			if err := r.publisher.PublishDomainEvent(ctx, p.evt); err != nil {
+				publishErr = err
+				break
+			}
+			positions = append(positions, p.position)
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM domain_event_outbox WHERE position = ANY($1)`, positions); err != nil {
+			return fmt.Errorf("failed to remove published domain events: %w", err)
+		}
+		published = len(positions)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return published, publishErr
+}
+
+type outboxEvent struct {
+	position int64
+	evt      *core.DomainEvent
+}
+
+func scanOutboxEvent(row pgx.CollectableRow) (outboxEvent, error) {
+	var position int64
+	var eventID, eventType, aggregateID string
+	var evt core.DomainEvent
+	var payload []byte
+	if err := row.Scan(&position, &eventID, &eventType, &aggregateID, &evt.Version, &evt.OccurredAt, &payload); err != nil {
+		return outboxEvent{}, err
+	}
+
+	var err error
+	if evt.ID, err = typeid.Parse(eventID); err != nil {
+		return outboxEvent{}, fmt.Errorf("invalid domain event id '%s': %w", eventID, err)
+	}
+	if evt.AggregateID, err = typeid.Parse(aggregateID); err != nil {
+		return outboxEvent{}, fmt.Errorf("invalid aggregate id '%s' of domain event '%s': %w", aggregateID, eventID, err)
+	}
+	evt.Type = core.DomainEventType(eventType)
+	evt.OccurredAt = evt.OccurredAt.UTC()
+	evt.Payload = payload
+
+	return outboxEvent{position: position, evt: &evt}, nil
+}
@@ -0,0 +1,123 @@
+//go:build integration
+
+package event_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.jetify.com/typeid/v2"
+)
+
+// mockPublisher records the published domain events
+type mockPublisher struct {
+	mu     sync.Mutex
+	events []*core.DomainEvent
+	err    error
+}
+
+func (p *mockPublisher) PublishDomainEvent(ctx context.Context, evt *core.DomainEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	p.events = append(p.events, evt)
+	return nil
+}
+
+// of returns the published events of the aggregates in publishing order.
+func (p *mockPublisher) of(aggregateIDs ...typeid.TypeID) []*core.DomainEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var events []*core.DomainEvent
+	for _, evt := range p.events {
+		if slices.Contains(aggregateIDs, evt.AggregateID) {
+			events = append(events, evt)
+		}
+	}
+	return events
+}
+
+func types(events []*core.DomainEvent) []core.DomainEventType {
+	types := make([]core.DomainEventType, 0, len(events))
+	for _, evt := range events {
+		types = append(types, evt.Type)
+	}
+	return types
+}
+
+// relayAll relays until the outbox is empty; other tests keep adding events,
+// so it stops once the events of the aggregates were published.
+func relayAll(t *testing.T, relay *event.OutboxRelay, publisher *mockPublisher, want int, aggregateIDs ...typeid.TypeID) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		_, err := relay.Relay(t.Context())
+		require.NoError(t, err)
+		return len(publisher.of(aggregateIDs...)) >= want
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestOutboxRelay(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	alice := evt.Attendees[0].User
+	match := core.StartMatch(newTestMatch().Game, []core.User{alice}, evt.StartsAt)
+	require.NoError(t, repo.CreateMatch(ctx, evt.EventID, match))
+	_, err := repo.UpdateMatch(ctx, match.MatchID, func(_ *core.Event, m *core.Match) error {
+		return m.Finalize(evt.StartsAt.Add(time.Hour))
+	})
+	require.NoError(t, err)
+	_, err = repo.UpdateMatch(ctx, match.MatchID, func(_ *core.Event, m *core.Match) error {
+		return m.CorrectScore(core.Score{UserID: alice.UserID, Value: 42})
+	})
+	require.NoError(t, err)
+
+	// changes that are not stored are not announced
+	assert.ErrorIs(t, repo.CreateMatch(ctx, evt.EventID, match), event.ErrMatchExists)
+
+	// publishing failures keep the events in the outbox
+	publisher := &mockPublisher{err: errors.New("nats unavailable")}
+	relay := event.NewOutboxRelay(sharedPool, publisher)
+	require.Eventually(t, func() bool {
+		_, err := relay.Relay(ctx)
+		return err != nil
+	}, 10*time.Second, 10*time.Millisecond)
+	publisher.err = nil
+
+	ids := []typeid.TypeID{alice.UserID, evt.Attendees[1].User.UserID, evt.EventID, match.MatchID}
+	relayAll(t, relay, publisher, 6, ids...)
+	published := publisher.of(ids...)
+	assert.Equal(t, []core.DomainEventType{
+		core.DomainEventUserSaved,
+		core.DomainEventUserSaved,
+		core.DomainEventEventCreated,
+		core.DomainEventMatchStarted,
+		core.DomainEventMatchFinalized,
+		core.DomainEventMatchCorrected,
+	}, types(published))
+
+	var corrected core.MatchFinalized
+	require.NoError(t, published[5].DecodePayload(&corrected))
+	assert.Equal(t, evt.EventID, corrected.EventID)
+	require.Len(t, corrected.Placements, 1)
+	assert.Equal(t, 42.0, corrected.Placements[0].Score)
+
+	// published events are removed from the outbox
+	_, err = relay.Relay(ctx)
+	require.NoError(t, err)
+	assert.Len(t, publisher.of(ids...), len(published))
+}
@@ -0,0 +1,1139 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ngoldack/dicetrace/package/core"
+	"go.jetify.com/typeid/v2"
+)
+
+const pgUniqueViolation = "23505"
+
+// querier is satisfied by both *pgxpool.Pool and pgx.Tx.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// PostgreSQLEventRepository stores events in PostgreSQL. Every change is
+// announced by a core.DomainEvent stored in the change's transaction; an
+// OutboxRelay publishes them.
+type PostgreSQLEventRepository struct {
+	pool *pgxpool.Pool
+}
+
+var (
+	_ EventRepository = (*PostgreSQLEventRepository)(nil)
+	_ MatchRepository = (*PostgreSQLEventRepository)(nil)
+	_ GameRepository  = (*PostgreSQLEventRepository)(nil)
+)
+
+func NewPostgreSQLEventRepository(pool *pgxpool.Pool) *PostgreSQLEventRepository {
+	return &PostgreSQLEventRepository{
+		pool: pool,
+	}
+}
+
+func (r *PostgreSQLEventRepository) CreateEvent(ctx context.Context, evt *core.Event) error {
+	if err := evt.CheckNew(); err != nil {
+		return err
+	}
+
+	if err := evt.Validate(); err != nil {
+		return err
+	}
+
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		eventID := evt.EventID.String()
+
+		var hostID *string
+		if evt.Host != nil {
+			if err := insertUser(ctx, tx, evt.Host); err != nil {
+				return err
+			}
+			id := evt.Host.UserID.String()
+			hostID = &id
+		}
+
+		_, err := tx.Exec(ctx, `
+			INSERT INTO events (event_id, status, title, location, starts_at, ends_at, timezone, host_user_id, capacity)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			eventID, string(evt.Status), evt.Title, evt.Location,
+			nullTime(evt.StartsAt), nullTime(evt.EndsAt), evt.Timezone, hostID, evt.Capacity,
+		)
+		if isPgError(err, pgUniqueViolation) {
+			return fmt.Errorf("event '%s': %w", eventID, ErrEventExists)
+		} else if err != nil {
+			return fmt.Errorf("failed to insert event: %w", err)
+		}
+
+		for i := range evt.Attendees {
+			if err := insertAttendee(ctx, tx, eventID, &evt.Attendees[i], i); err != nil {
+				return err
+			}
+		}
+
+		for i := range evt.Matches {
+			if err := insertMatch(ctx, tx, eventID, &evt.Matches[i], i); err != nil {
+				return err
+			}
+		}
+
+		return announce(ctx, tx, core.DomainEventEventCreated, evt.EventID, evt)
+	})
+}
+
+func (r *PostgreSQLEventRepository) GetEvent(ctx context.Context, eventID core.EventID) (*core.Event, error) {
+	events, err := queryEvents(ctx, r.pool, selectEvents+` WHERE e.event_id = $1`, eventID.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("event '%s': %w", eventID, ErrEventNotFound)
+	}
+
+	return events[0], nil
+}
+
+func (r *PostgreSQLEventRepository) ListEventsByAttendee(ctx context.Context, userID core.UserID) ([]*core.Event, error) {
+	return queryEvents(ctx, r.pool, selectEvents+`
+		JOIN event_attendees a ON a.event_id = e.event_id
+		WHERE a.user_id = $1
+		ORDER BY e.starts_at NULLS LAST, e.created_at, e.event_id`,
+		userID.String(),
+	)
+}
+
+func (r *PostgreSQLEventRepository) ListEvents(ctx context.Context, filter EventFilter) ([]*core.Event, error) {
+	return queryEvents(ctx, r.pool, selectEvents+`
+		WHERE ($1 = '' OR e.status = $1)
+		  AND ($2::timestamptz IS NULL OR e.starts_at >= $2)
+		  AND ($3::timestamptz IS NULL OR e.starts_at < $3)
+		ORDER BY e.starts_at NULLS LAST, e.created_at, e.event_id`,
+		string(filter.Status), nullTime(filter.From), nullTime(filter.To),
+	)
+}
+
+// TransitionEvent applies the status transition to the stored event and
+// returns the updated event. Rejected transitions return a
+// *core.TransitionError.
+func (r *PostgreSQLEventRepository) TransitionEvent(ctx context.Context, eventID core.EventID, transition core.EventTransition) (*core.Event, error) {
+	var evt *core.Event
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var err error
+		evt, err = loadEventForUpdate(ctx, tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		if err := evt.Transition(transition); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `UPDATE events SET status = $2 WHERE event_id = $1`, eventID.String(), string(evt.Status))
+		if err != nil {
+			return fmt.Errorf("failed to update event status: %w", err)
+		}
+
+		return announce(ctx, tx, core.DomainEventEventTransitioned, eventID, evt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return evt, nil
+}
+
+func (r *PostgreSQLEventRepository) RescheduleEvent(ctx context.Context, eventID core.EventID, startsAt, endsAt time.Time) (*core.Event, error) {
+	var evt *core.Event
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var err error
+		evt, err = loadEventForUpdate(ctx, tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		if err := evt.Reschedule(startsAt, endsAt); err != nil {
+			return err
+		}
+
+		if err := evt.Validate(); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `UPDATE events SET starts_at = $2, ends_at = $3 WHERE event_id = $1`,
+			eventID.String(), nullTime(evt.StartsAt), nullTime(evt.EndsAt),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update event schedule: %w", err)
+		}
+
+		return announce(ctx, tx, core.DomainEventEventRescheduled, eventID, evt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return evt, nil
+}
+
+// AddAttendee appends the attendee to the event. Adding a user that already
+// attends the event updates their status instead.
+func (r *PostgreSQLEventRepository) AddAttendee(ctx context.Context, eventID core.EventID, attendee *core.Attendee) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		evt, err := loadEventForUpdate(ctx, tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		if err := evt.CheckMutable(); err != nil {
+			return err
+		}
+
+		idx := slices.IndexFunc(evt.Attendees, func(a core.Attendee) bool {
+			return a.User.UserID == attendee.User.UserID
+		})
+		if idx >= 0 {
+			evt.Attendees[idx] = *attendee
+		} else {
+			evt.Attendees = append(evt.Attendees, *attendee)
+		}
+
+		if err := evt.Validate(); err != nil {
+			return err
+		}
+
+		if err := insertAttendee(ctx, tx, eventID.String(), attendee, -1); err != nil {
+			return err
+		}
+
+		return announce(ctx, tx, core.DomainEventAttendeeAdded, eventID, attendee)
+	})
+}
+
+func (r *PostgreSQLEventRepository) RemoveAttendee(ctx context.Context, eventID core.EventID, userID core.UserID) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		evt, err := loadEventForUpdate(ctx, tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		if err := evt.CheckMutable(); err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx, `DELETE FROM event_attendees WHERE event_id = $1 AND user_id = $2`, eventID.String(), userID.String())
+		if err != nil {
+			return fmt.Errorf("failed to remove attendee: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("user '%s' in event '%s': %w", userID, eventID, ErrAttendeeNotFound)
+		}
+
+		return announce(ctx, tx, core.DomainEventAttendeeRemoved, eventID, core.User{UserID: userID})
+	})
+}
+
+func (r *PostgreSQLEventRepository) UpdateAttendees(ctx context.Context, eventID core.EventID, fn func(evt *core.Event) error) (*core.Event, error) {
+	var evt *core.Event
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var err error
+		evt, err = loadEventForUpdate(ctx, tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(evt); err != nil {
+			return err
+		}
+
+		if err := evt.Validate(); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `DELETE FROM event_attendees WHERE event_id = $1`, eventID.String())
+		if err != nil {
+			return fmt.Errorf("failed to clear attendees: %w", err)
+		}
+
+		for i := range evt.Attendees {
+			if err := insertAttendee(ctx, tx, eventID.String(), &evt.Attendees[i], i); err != nil {
+				return err
+			}
+		}
+
+		return announce(ctx, tx, core.DomainEventAttendeesUpdated, eventID, evt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return evt, nil
+}
+
+func (r *PostgreSQLEventRepository) AppendMatch(ctx context.Context, eventID core.EventID, match *core.Match) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		evt, err := loadEventForUpdate(ctx, tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		if err := evt.CheckMutable(); err != nil {
+			return err
+		}
+
+		if err := match.Validate(); err != nil {
+			return err
+		}
+
+		if err := insertMatch(ctx, tx, eventID.String(), match, -1); err != nil {
+			return err
+		}
+
+		return announceNewMatch(ctx, tx, eventID, match)
+	})
+}
+
+func (r *PostgreSQLEventRepository) CreateMatch(ctx context.Context, eventID core.EventID, match *core.Match) error {
+	return r.AppendMatch(ctx, eventID, match)
+}
+
+func (r *PostgreSQLEventRepository) GetMatch(ctx context.Context, matchID core.MatchID) (*core.Match, error) {
+	matches, err := queryMatches(ctx, r.pool, selectMatches+` WHERE m.match_id = $1`, matchID.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("match '%s': %w", matchID, ErrMatchNotFound)
+	}
+
+	return matches[0].match, nil
+}
+
+// ListFinalizedMatches queries the matches themselves, their events are not
+// needed. Matches recorded without a status are complete, see
+// core.Match.Finalized.
+func (r *PostgreSQLEventRepository) ListFinalizedMatches(ctx context.Context, since time.Time) ([]core.Match, error) {
+	found, err := queryMatches(ctx, r.pool, selectMatches+`
+		WHERE m.status <> $1
+		  AND ($2::timestamptz IS NULL OR COALESCE(m.ended_at, m.started_at) >= $2)`,
+		string(core.MatchStatusInProgress), nullTime(since),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]core.Match, 0, len(found))
+	for _, m := range found {
+		matches = append(matches, *m.match)
+	}
+
+	return core.Chronological(matches), nil
+}
+
+func (r *PostgreSQLEventRepository) ListFinalizedMatchesOf(ctx context.Context, userIDs []core.UserID) ([]core.Match, error) {
+	ids := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		ids = append(ids, id.String())
+	}
+
+	found, err := queryMatches(ctx, r.pool, selectMatches+`
+		WHERE m.status <> $1
+		  AND m.match_id IN (SELECT match_id FROM match_players WHERE user_id = ANY($2))`,
+		string(core.MatchStatusInProgress), ids,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]core.Match, 0, len(found))
+	for _, m := range found {
+		matches = append(matches, *m.match)
+	}
+
+	return core.Chronological(matches), nil
+}
+
+func (r *PostgreSQLEventRepository) GetGameByBGGID(ctx context.Context, bggID int) (*core.Game, error) {
+	var (
+		gameID, name, scoringUnit, scoringDirection string
+		rating                                      float64
+		categories                                  []string
+	)
+	err := r.pool.QueryRow(ctx, `
+		SELECT game_id, name, rating, categories, scoring_unit, scoring_direction
+		FROM games WHERE bgg_id = $1 AND bgg_id <> 0`,
+		bggID,
+	).Scan(&gameID, &name, &rating, &categories, &scoringUnit, &scoringDirection)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("bgg game %d: %w", bggID, ErrGameNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to look up bgg game %d: %w", bggID, err)
+	}
+
+	id, err := typeid.Parse(gameID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid game id '%s': %w", gameID, err)
+	}
+
+	return &core.Game{
+		GameID:     id,
+		BGGID:      bggID,
+		Rating:     rating,
+		Name:       name,
+		Categories: categories,
+		Scoring: core.Scoring{
+			Unit:      core.ScoreUnit(scoringUnit),
+			Direction: core.ScoreDirection(scoringDirection),
+		},
+	}, nil
+}
+
+func (r *PostgreSQLEventRepository) UpdateMatch(ctx context.Context, matchID core.MatchID, fn func(evt *core.Event, match *core.Match) error) (*core.Match, error) {
+	var match *core.Match
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var eventID string
+		err := tx.QueryRow(ctx, `SELECT event_id FROM matches WHERE match_id = $1`, matchID.String()).Scan(&eventID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("match '%s': %w", matchID, ErrMatchNotFound)
+		} else if err != nil {
+			return fmt.Errorf("failed to look up match: %w", err)
+		}
+
+		id, err := typeid.Parse(eventID)
+		if err != nil {
+			return fmt.Errorf("invalid event id '%s': %w", eventID, err)
+		}
+
+		evt, err := loadEventForUpdate(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := evt.CheckMutable(); err != nil {
+			return err
+		}
+
+		position, current, ok := findMatch(evt, matchID)
+		if !ok {
+			// the match was deleted before the event was locked
+			return fmt.Errorf("match '%s': %w", matchID, ErrMatchNotFound)
+		}
+
+		wasFinalized := current.Finalized()
+		if err := fn(evt, current); err != nil {
+			return err
+		}
+		current.MatchID = matchID
+
+		if err := current.Validate(); err != nil {
+			return err
+		}
+
+		// Players, scores and teams are removed along with the match.
+		if _, err := tx.Exec(ctx, `DELETE FROM matches WHERE match_id = $1`, matchID.String()); err != nil {
+			return fmt.Errorf("failed to clear match: %w", err)
+		}
+
+		if err := insertMatch(ctx, tx, eventID, current, position); err != nil {
+			return err
+		}
+
+		switch {
+		case wasFinalized:
+			err = announceResult(ctx, tx, core.DomainEventMatchCorrected, evt.EventID, current)
+		case current.Finalized():
+			err = announceResult(ctx, tx, core.DomainEventMatchFinalized, evt.EventID, current)
+		default:
+			err = announce(ctx, tx, core.DomainEventMatchUpdated, matchID, current)
+		}
+		if err != nil {
+			return err
+		}
+
+		match = current
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return match, nil
+}
+
+// findMatch returns the position of the match in the event and a copy of it.
+func findMatch(evt *core.Event, matchID core.MatchID) (int, *core.Match, bool) {
+	for i := range evt.Matches {
+		if evt.Matches[i].MatchID == matchID {
+			match := evt.Matches[i]
+			return i, &match, true
+		}
+	}
+	return 0, nil, false
+}
+
+// loadEventForUpdate locks the event row for the rest of the transaction, so
+// that concurrent mutations are serialized, and loads the event.
+func loadEventForUpdate(ctx context.Context, tx pgx.Tx, eventID core.EventID) (*core.Event, error) {
+	var id string
+	err := tx.QueryRow(ctx, `SELECT event_id FROM events WHERE event_id = $1 FOR UPDATE`, eventID.String()).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("event '%s': %w", eventID, ErrEventNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to lock event: %w", err)
+	}
+
+	events, err := queryEvents(ctx, tx, selectEvents+` WHERE e.event_id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return events[0], nil
+}
+
+// insertUser stores and announces users not stored yet. Events only
+// reference users, so the profiles of stored users are never overwritten
+// with what an event, e.g. an imported calendar, claims about them.
+func insertUser(ctx context.Context, q querier, usr *core.User) error {
+	tag, err := q.Exec(ctx, `
+		INSERT INTO users (user_id, username, bgg_username)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO NOTHING`,
+		usr.UserID.String(), usr.Username, usr.BGGUsername,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert user '%s': %w", usr.UserID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	return announce(ctx, q, core.DomainEventUserSaved, usr.UserID, usr)
+}
+
+// upsertGame stores the game. A BoardGameGeek game is stored once: if it is
+// stored under another game ID, that one is kept and set on the game.
+func upsertGame(ctx context.Context, q querier, game *core.Game) error {
+	categories := game.Categories
+	if categories == nil {
+		categories = []string{}
+	}
+
+	conflict := `ON CONFLICT (game_id) DO UPDATE SET bgg_id = EXCLUDED.bgg_id,`
+	if game.BGGID != 0 {
+		conflict = `ON CONFLICT (bgg_id) WHERE bgg_id <> 0 DO UPDATE SET`
+	}
+
+	var gameID string
+	err := q.QueryRow(ctx, `
+		INSERT INTO games (game_id, bgg_id, name, rating, categories, scoring_unit, scoring_direction)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`+conflict+` name = EXCLUDED.name, rating = EXCLUDED.rating, categories = EXCLUDED.categories,
+			scoring_unit = EXCLUDED.scoring_unit, scoring_direction = EXCLUDED.scoring_direction
+		RETURNING game_id`,
+		game.GameID.String(), game.BGGID, game.Name, game.Rating, categories,
+		string(game.Scoring.Unit), string(game.Scoring.Direction),
+	).Scan(&gameID)
+	if err != nil {
+		return fmt.Errorf("failed to upsert game '%s': %w", game.GameID, err)
+	}
+
+	id, err := typeid.Parse(gameID)
+	if err != nil {
+		return fmt.Errorf("invalid game id '%s': %w", gameID, err)
+	}
+	game.GameID = id
+
+	return nil
+}
+
+// insertAttendee stores the attendee at the given position; a negative
+// position appends it after the existing attendees.
+func insertAttendee(ctx context.Context, q querier, eventID string, attendee *core.Attendee, position int) error {
+	if err := insertUser(ctx, q, &attendee.User); err != nil {
+		return err
+	}
+
+	_, err := q.Exec(ctx, `
+		INSERT INTO event_attendees (event_id, user_id, status, position)
+		SELECT $1, $2, $3, CASE WHEN $4 >= 0 THEN $4 ELSE COALESCE(MAX(position) + 1, 0) END
+		FROM event_attendees WHERE event_id = $1
+		ON CONFLICT (event_id, user_id) DO UPDATE SET status = EXCLUDED.status`,
+		eventID, attendee.User.UserID.String(), string(attendee.Status), position,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert attendee '%s': %w", attendee.User.UserID, err)
+	}
+
+	return nil
+}
+
+// insertMatch stores the match with its game, players and scores at the given
+// position; a negative position appends it after the existing matches.
+func insertMatch(ctx context.Context, q querier, eventID string, match *core.Match, position int) error {
+	if err := upsertGame(ctx, q, &match.Game); err != nil {
+		return err
+	}
+
+	matchID := match.MatchID.String()
+
+	_, err := q.Exec(ctx, `
+		INSERT INTO matches (match_id, event_id, game_id, status, score_unit, score_direction, started_at, ended_at, position)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, CASE WHEN $9 >= 0 THEN $9 ELSE COALESCE(MAX(position) + 1, 0) END
+		FROM matches WHERE event_id = $2`,
+		matchID, eventID, match.Game.GameID.String(), string(match.Status), string(match.Scoreboard.ScoreUnit),
+		string(match.Scoreboard.Direction), nullTime(match.StartedAt), nullTime(match.EndedAt), position,
+	)
+	if isPgError(err, pgUniqueViolation) {
+		return fmt.Errorf("match '%s': %w", matchID, ErrMatchExists)
+	} else if err != nil {
+		return fmt.Errorf("failed to insert match '%s': %w", matchID, err)
+	}
+
+	for i := range match.Players {
+		if err := insertUser(ctx, q, &match.Players[i]); err != nil {
+			return err
+		}
+
+		_, err := q.Exec(ctx, `INSERT INTO match_players (match_id, user_id, position) VALUES ($1, $2, $3)`,
+			matchID, match.Players[i].UserID.String(), i,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert player '%s': %w", match.Players[i].UserID, err)
+		}
+	}
+
+	for i, score := range match.Scoreboard.Scores {
+		labels, values := splitBreakdown(score.Breakdown)
+		_, err := q.Exec(ctx, `
+			INSERT INTO match_scores (match_id, position, user_id, value, tiebreakers, breakdown_labels, breakdown_values)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			matchID, i, score.UserID.String(), score.Value, score.Tiebreakers, labels, values,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert score for '%s': %w", score.UserID, err)
+		}
+	}
+
+	for i, team := range match.Teams {
+		_, err := q.Exec(ctx, `INSERT INTO match_teams (match_id, team_id, name, position) VALUES ($1, $2, $3, $4)`,
+			matchID, team.TeamID.String(), team.Name, i,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert team '%s': %w", team.TeamID, err)
+		}
+
+		for j, member := range team.Members {
+			_, err := q.Exec(ctx, `INSERT INTO match_team_members (match_id, team_id, user_id, position) VALUES ($1, $2, $3, $4)`,
+				matchID, team.TeamID.String(), member.String(), j,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert member '%s' of team '%s': %w", member, team.TeamID, err)
+			}
+		}
+	}
+
+	for i, score := range match.Scoreboard.TeamScores {
+		labels, values := splitBreakdown(score.Breakdown)
+		_, err := q.Exec(ctx, `
+			INSERT INTO match_team_scores (match_id, position, team_id, value, tiebreakers, breakdown_labels, breakdown_values)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			matchID, i, score.TeamID.String(), score.Value, score.Tiebreakers, labels, values,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert score for team '%s': %w", score.TeamID, err)
+		}
+	}
+
+	return nil
+}
+
+// selectEvents selects the columns scanned by queryEvents. Callers append
+// their own joins, conditions and ordering.
+const selectEvents = `
+	SELECT e.event_id, e.status, e.title, e.location, e.starts_at, e.ends_at, e.timezone, e.capacity,
+	       h.user_id, h.username, h.bgg_username
+	FROM events e
+	LEFT JOIN users h ON h.user_id = e.host_user_id`
+
+// queryEvents runs a selectEvents query and loads the attendees and matches
+// of every returned event.
+func queryEvents(ctx context.Context, q querier, sql string, args ...any) ([]*core.Event, error) {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+
+	events := make([]*core.Event, 0)
+	byID := make(map[string]*core.Event)
+
+	var (
+		eventID, status, title, location, timezone string
+		startsAt, endsAt                           *time.Time
+		capacity                                   int
+		hostID, hostUsername, hostBGGUsername      *string
+	)
+	scans := []any{&eventID, &status, &title, &location, &startsAt, &endsAt, &timezone, &capacity, &hostID, &hostUsername, &hostBGGUsername}
+	_, err = pgx.ForEachRow(rows, scans, func() error {
+		id, err := typeid.Parse(eventID)
+		if err != nil {
+			return fmt.Errorf("invalid event id '%s': %w", eventID, err)
+		}
+
+		evt := &core.Event{
+			EventID:   id,
+			Status:    core.EventStatus(status),
+			Title:     title,
+			Location:  location,
+			Timezone:  timezone,
+			Capacity:  capacity,
+			Attendees: []core.Attendee{},
+			Matches:   []core.Match{},
+		}
+		if startsAt != nil {
+			evt.StartsAt = *startsAt
+		}
+		if endsAt != nil {
+			evt.EndsAt = *endsAt
+		}
+
+		if hostID != nil {
+			host, err := newUser(*hostID, *hostUsername, *hostBGGUsername)
+			if err != nil {
+				return err
+			}
+			evt.Host = &host
+		}
+
+		events = append(events, evt)
+		byID[eventID] = evt
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan events: %w", err)
+	}
+
+	if len(events) == 0 {
+		return events, nil
+	}
+
+	ids := make([]string, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+
+	if err := loadAttendees(ctx, q, ids, byID); err != nil {
+		return nil, err
+	}
+
+	if err := loadMatches(ctx, q, ids, byID); err != nil {
+		return nil, err
+	}
+
+	// Localize once the matches are loaded so their times are converted too.
+	for _, evt := range events {
+		if err := evt.Localize(); err != nil {
+			return nil, err
+		}
+	}
+
+	return events, nil
+}
+
+func loadAttendees(ctx context.Context, q querier, eventIDs []string, byID map[string]*core.Event) error {
+	rows, err := q.Query(ctx, `
+		SELECT a.event_id, u.user_id, u.username, u.bgg_username, a.status
+		FROM event_attendees a
+		JOIN users u ON u.user_id = a.user_id
+		WHERE a.event_id = ANY($1)
+		ORDER BY a.event_id, a.position`,
+		eventIDs,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to query attendees: %w", err)
+	}
+
+	var eventID, userID, username, bggUsername, status string
+	_, err = pgx.ForEachRow(rows, []any{&eventID, &userID, &username, &bggUsername, &status}, func() error {
+		usr, err := newUser(userID, username, bggUsername)
+		if err != nil {
+			return err
+		}
+
+		evt := byID[eventID]
+		evt.Attendees = append(evt.Attendees, core.Attendee{
+			User:   usr,
+			Status: core.AttendeeStatus(status),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan attendees: %w", err)
+	}
+
+	return nil
+}
+
+func loadMatches(ctx context.Context, q querier, eventIDs []string, byID map[string]*core.Event) error {
+	matches, err := queryMatches(ctx, q, selectMatches+`
+		WHERE m.event_id = ANY($1)
+		ORDER BY m.event_id, m.position`,
+		eventIDs,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Matches are only copied into their events once fully loaded, since
+	// appending to Event.Matches may move the underlying array.
+	for _, m := range matches {
+		evt := byID[m.eventID]
+		evt.Matches = append(evt.Matches, *m.match)
+	}
+
+	return nil
+}
+
+// selectMatches selects the columns scanned by queryMatches. Callers append
+// their own conditions and ordering.
+const selectMatches = `
+	SELECT m.event_id, m.match_id, m.status, m.score_unit, m.score_direction, m.started_at, m.ended_at,
+		g.game_id, g.bgg_id, g.name, g.rating, g.categories, g.scoring_unit, g.scoring_direction
+	FROM matches m
+	JOIN games g ON g.game_id = m.game_id`
+
+// eventMatch is a match loaded by queryMatches along with its event.
+type eventMatch struct {
+	eventID string
+	match   *core.Match
+}
+
+// queryMatches runs a selectMatches query and loads the players, scores and
+// teams of every returned match. Matches are returned in query order.
+func queryMatches(ctx context.Context, q querier, sql string, args ...any) ([]eventMatch, error) {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query matches: %w", err)
+	}
+
+	matches := make([]eventMatch, 0)
+	byMatchID := make(map[string]*core.Match)
+
+	var (
+		eventID, matchID, status, scoreUnit, direction, gameID, name string
+		scoringUnit, scoringDirection                                string
+		bggID                                                        int
+		rating                                                       float64
+		categories                                                   []string
+		startedAt, endedAt                                           *time.Time
+	)
+	dest := []any{&eventID, &matchID, &status, &scoreUnit, &direction, &startedAt, &endedAt, &gameID, &bggID, &name, &rating, &categories, &scoringUnit, &scoringDirection}
+	_, err = pgx.ForEachRow(rows, dest, func() error {
+		mID, err := typeid.Parse(matchID)
+		if err != nil {
+			return fmt.Errorf("invalid match id '%s': %w", matchID, err)
+		}
+
+		gID, err := typeid.Parse(gameID)
+		if err != nil {
+			return fmt.Errorf("invalid game id '%s': %w", gameID, err)
+		}
+
+		match := &core.Match{
+			MatchID: mID,
+			Status:  core.MatchStatus(status),
+			Game: core.Game{
+				GameID:     gID,
+				BGGID:      bggID,
+				Rating:     rating,
+				Name:       name,
+				Categories: categories,
+				Scoring: core.Scoring{
+					Unit:      core.ScoreUnit(scoringUnit),
+					Direction: core.ScoreDirection(scoringDirection),
+				},
+			},
+			Players: []core.User{},
+			Scoreboard: core.Scoreboard{
+				Scores:    []core.Score{},
+				ScoreUnit: core.ScoreUnit(scoreUnit),
+				Direction: core.ScoreDirection(direction),
+			},
+		}
+		if startedAt != nil {
+			match.StartedAt = *startedAt
+		}
+		if endedAt != nil {
+			match.EndedAt = *endedAt
+		}
+		matches = append(matches, eventMatch{eventID: eventID, match: match})
+		byMatchID[matchID] = match
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan matches: %w", err)
+	}
+
+	if len(matches) == 0 {
+		return matches, nil
+	}
+
+	matchIDs := make([]string, 0, len(byMatchID))
+	for id := range byMatchID {
+		matchIDs = append(matchIDs, id)
+	}
+
+	if err := loadPlayers(ctx, q, matchIDs, byMatchID); err != nil {
+		return nil, err
+	}
+
+	if err := loadScores(ctx, q, matchIDs, byMatchID); err != nil {
+		return nil, err
+	}
+
+	if err := loadTeams(ctx, q, matchIDs, byMatchID); err != nil {
+		return nil, err
+	}
+
+	if err := loadTeamScores(ctx, q, matchIDs, byMatchID); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func loadPlayers(ctx context.Context, q querier, matchIDs []string, byMatchID map[string]*core.Match) error {
+	rows, err := q.Query(ctx, `
+		SELECT p.match_id, u.user_id, u.username, u.bgg_username
+		FROM match_players p
+		JOIN users u ON u.user_id = p.user_id
+		WHERE p.match_id = ANY($1)
+		ORDER BY p.match_id, p.position`,
+		matchIDs,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to query players: %w", err)
+	}
+
+	var matchID, userID, username, bggUsername string
+	_, err = pgx.ForEachRow(rows, []any{&matchID, &userID, &username, &bggUsername}, func() error {
+		usr, err := newUser(userID, username, bggUsername)
+		if err != nil {
+			return err
+		}
+
+		match := byMatchID[matchID]
+		match.Players = append(match.Players, usr)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan players: %w", err)
+	}
+
+	return nil
+}
+
+func loadScores(ctx context.Context, q querier, matchIDs []string, byMatchID map[string]*core.Match) error {
+	rows, err := q.Query(ctx, `
+		SELECT match_id, user_id, value, tiebreakers, breakdown_labels, breakdown_values
+		FROM match_scores
+		WHERE match_id = ANY($1)
+		ORDER BY match_id, position`,
+		matchIDs,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to query scores: %w", err)
+	}
+
+	var (
+		matchID, userID string
+		value           float64
+		tiebreakers     []float64
+		labels          []string
+		values          []float64
+	)
+	_, err = pgx.ForEachRow(rows, []any{&matchID, &userID, &value, &tiebreakers, &labels, &values}, func() error {
+		uID, err := typeid.Parse(userID)
+		if err != nil {
+			return fmt.Errorf("invalid user id '%s': %w", userID, err)
+		}
+
+		match := byMatchID[matchID]
+		match.Scoreboard.Scores = append(match.Scoreboard.Scores, core.Score{
+			UserID:      uID,
+			Value:       value,
+			Tiebreakers: slices.Clone(tiebreakers),
+			Breakdown:   joinBreakdown(labels, values),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan scores: %w", err)
+	}
+
+	return nil
+}
+
+// loadTeams loads the teams and their members. Matches without teams keep
+// a nil Teams slice.
+func loadTeams(ctx context.Context, q querier, matchIDs []string, byMatchID map[string]*core.Match) error {
+	rows, err := q.Query(ctx, `
+		SELECT t.match_id, t.team_id, t.name, m.user_id
+		FROM match_teams t
+		LEFT JOIN match_team_members m ON m.match_id = t.match_id AND m.team_id = t.team_id
+		WHERE t.match_id = ANY($1)
+		ORDER BY t.match_id, t.position, m.position`,
+		matchIDs,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to query teams: %w", err)
+	}
+
+	var (
+		matchID, teamID, name string
+		userID                *string
+	)
+	_, err = pgx.ForEachRow(rows, []any{&matchID, &teamID, &name, &userID}, func() error {
+		tID, err := typeid.Parse(teamID)
+		if err != nil {
+			return fmt.Errorf("invalid team id '%s': %w", teamID, err)
+		}
+
+		match := byMatchID[matchID]
+		if n := len(match.Teams); n == 0 || match.Teams[n-1].TeamID != tID {
+			match.Teams = append(match.Teams, core.Team{TeamID: tID, Name: name, Members: []core.UserID{}})
+		}
+
+		if userID == nil {
+			return nil
+		}
+
+		uID, err := typeid.Parse(*userID)
+		if err != nil {
+			return fmt.Errorf("invalid user id '%s': %w", *userID, err)
+		}
+
+		team := &match.Teams[len(match.Teams)-1]
+		team.Members = append(team.Members, uID)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan teams: %w", err)
+	}
+
+	return nil
+}
+
+func loadTeamScores(ctx context.Context, q querier, matchIDs []string, byMatchID map[string]*core.Match) error {
+	rows, err := q.Query(ctx, `
+		SELECT match_id, team_id, value, tiebreakers, breakdown_labels, breakdown_values
+		FROM match_team_scores
+		WHERE match_id = ANY($1)
+		ORDER BY match_id, position`,
+		matchIDs,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to query team scores: %w", err)
+	}
+
+	var (
+		matchID, teamID string
+		value           float64
+		tiebreakers     []float64
+		labels          []string
+		values          []float64
+	)
+	_, err = pgx.ForEachRow(rows, []any{&matchID, &teamID, &value, &tiebreakers, &labels, &values}, func() error {
+		tID, err := typeid.Parse(teamID)
+		if err != nil {
+			return fmt.Errorf("invalid team id '%s': %w", teamID, err)
+		}
+
+		match := byMatchID[matchID]
+		match.Scoreboard.TeamScores = append(match.Scoreboard.TeamScores, core.TeamScore{
+			TeamID:      tID,
+			Value:       value,
+			Tiebreakers: slices.Clone(tiebreakers),
+			Breakdown:   joinBreakdown(labels, values),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan team scores: %w", err)
+	}
+
+	return nil
+}
+
+// splitBreakdown stores a score sheet as parallel label and value arrays,
+// both NULL for scores without a breakdown.
+func splitBreakdown(breakdown []core.ScoreLine) ([]string, []float64) {
+	if len(breakdown) == 0 {
+		return nil, nil
+	}
+
+	labels := make([]string, 0, len(breakdown))
+	values := make([]float64, 0, len(breakdown))
+	for _, line := range breakdown {
+		labels = append(labels, line.Label)
+		values = append(values, line.Value)
+	}
+	return labels, values
+}
+
+func joinBreakdown(labels []string, values []float64) []core.ScoreLine {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	breakdown := make([]core.ScoreLine, 0, len(labels))
+	for i, label := range labels {
+		line := core.ScoreLine{Label: label}
+		if i < len(values) {
+			line.Value = values[i]
+		}
+		breakdown = append(breakdown, line)
+	}
+	return breakdown
+}
+
+func newUser(userID, username, bggUsername string) (core.User, error) {
+	id, err := typeid.Parse(userID)
+	if err != nil {
+		return core.User{}, fmt.Errorf("invalid user id '%s': %w", userID, err)
+	}
+
+	return core.User{
+		UserID:      id,
+		Username:    username,
+		BGGUsername: bggUsername,
+	}, nil
+}
+
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
+
+// nullTime maps the zero time to NULL.
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,107 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/tournament"
+)
+
+var (
+	ErrEventNotFound    = errors.New("event not found")
+	ErrEventExists      = errors.New("event already exists")
+	ErrAttendeeNotFound = errors.New("attendee not found")
+	ErrMatchNotFound    = errors.New("match not found")
+	ErrMatchExists      = errors.New("match already exists")
+	ErrGameNotFound     = errors.New("game not found")
+
+	ErrTournamentNotFound = errors.New("tournament not found")
+	ErrTournamentExists   = errors.New("tournament already exists")
+)
+
+// Repository is implemented by repositories storing both events and their
+// matches, like PostgreSQLEventRepository.
+type Repository interface {
+	EventRepository
+	MatchRepository
+}
+
+// EventRepository persists core.Event aggregates including their attendees
+// and matches. Mutations respect the event lifecycle: new events must start
+// unscheduled or scheduled, and closed events reject attendee and match
+// changes with core.ErrEventClosed.
+type EventRepository interface {
+	CreateEvent(ctx context.Context, evt *core.Event) error
+	GetEvent(ctx context.Context, eventID core.EventID) (*core.Event, error)
+
+	ListEventsByAttendee(ctx context.Context, userID core.UserID) ([]*core.Event, error)
+	ListEvents(ctx context.Context, filter EventFilter) ([]*core.Event, error)
+
+	// TransitionEvent changes the event's status through the core event state
+	// machine and returns the updated event.
+	TransitionEvent(ctx context.Context, eventID core.EventID, transition core.EventTransition) (*core.Event, error)
+	// RescheduleEvent moves a not yet started event to new start and end times
+	// and returns the updated event.
+	RescheduleEvent(ctx context.Context, eventID core.EventID, startsAt, endsAt time.Time) (*core.Event, error)
+
+	AddAttendee(ctx context.Context, eventID core.EventID, attendee *core.Attendee) error
+	RemoveAttendee(ctx context.Context, eventID core.EventID, userID core.UserID) error
+	// UpdateAttendees applies fn to the locked event and stores the resulting
+	// attendees in their new order. It is used for the RSVP workflow, where
+	// a single response may change several attendees.
+	UpdateAttendees(ctx context.Context, eventID core.EventID, fn func(evt *core.Event) error) (*core.Event, error)
+
+	AppendMatch(ctx context.Context, eventID core.EventID, match *core.Match) error
+}
+
+// MatchRepository records single matches of events while they are played.
+// Like EventRepository.AppendMatch, closed events reject match changes with
+// core.ErrEventClosed.
+type MatchRepository interface {
+	// CreateMatch appends the match to the event's matches.
+	CreateMatch(ctx context.Context, eventID core.EventID, match *core.Match) error
+	GetMatch(ctx context.Context, matchID core.MatchID) (*core.Match, error)
+	// UpdateMatch applies fn to the match and its locked event and stores the
+	// resulting match in place. Changes fn makes to the event are discarded.
+	UpdateMatch(ctx context.Context, matchID core.MatchID, fn func(evt *core.Event, match *core.Match) error) (*core.Match, error)
+	// ListFinalizedMatches returns the finalized matches of all events in
+	// chronological order, see core.Chronological. A non-zero since skips
+	// matches played before it.
+	ListFinalizedMatches(ctx context.Context, since time.Time) ([]core.Match, error)
+	// ListFinalizedMatchesOf returns the finalized matches any of the users
+	// played in chronological order.
+	ListFinalizedMatchesOf(ctx context.Context, userIDs []core.UserID) ([]core.Match, error)
+}
+
+// TournamentRepository persists the tournaments events run as. An event
+// runs at most one tournament, whose rounds are played as matches of the
+// event. Like matches, tournaments of closed events reject changes with
+// core.ErrEventClosed.
+type TournamentRepository interface {
+	CreateTournament(ctx context.Context, t *tournament.Tournament) error
+	// GetTournament returns the tournament the event runs as.
+	GetTournament(ctx context.Context, eventID core.EventID) (*tournament.Tournament, error)
+	// StartTournamentRound pairs the next round of the event's tournament
+	// from the event's matches, see tournament.Tournament.NextRound, and
+	// appends the round's matches to the event. It returns the tournament
+	// with the new round and the round's matches.
+	StartTournamentRound(ctx context.Context, eventID core.EventID, startedAt time.Time) (*tournament.Tournament, []core.Match, error)
+}
+
+// GameRepository looks up the games matches are played in. A BoardGameGeek
+// game is stored once, so its game ID stays the same across matches.
+type GameRepository interface {
+	GetGameByBGGID(ctx context.Context, bggID int) (*core.Game, error)
+}
+
+// EventFilter narrows down ListEvents. Zero values are ignored.
+type EventFilter struct {
+	Status core.EventStatus
+
+	// From and To bound the event's start time: From is inclusive, To is
+	// exclusive. Unscheduled events never match a bounded range.
+	From time.Time
+	To   time.Time
+}
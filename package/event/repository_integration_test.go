@@ -0,0 +1,719 @@
+//go:build integration
+
+package event_test
+
+import (
+	"context"
+	"os"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+var sharedPool *pgxpool.Pool
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	// Start a single Postgres container for all tests
+	pgContainer, err := postgres.Run(ctx,
+		"postgres:17-alpine",
+		postgres.WithDatabase("dicetrace"),
+		postgres.WithUsername("dicetrace"),
+		postgres.WithPassword("dicetrace"),
+		postgres.BasicWaitStrategies(),
+	)
+	if err != nil {
+		panic("failed to start postgres container: " + err.Error())
+	}
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		panic("failed to get postgres connection string: " + err.Error())
+	}
+
+	sharedPool, err = pgxpool.New(ctx, connStr)
+	if err != nil {
+		panic("failed to create postgres pool: " + err.Error())
+	}
+
+	// Migrations must be idempotent, so apply them twice
+	for range 2 {
+		if err := event.Migrate(ctx, sharedPool); err != nil {
+			panic("failed to migrate database: " + err.Error())
+		}
+	}
+
+	// Run tests
+	code := m.Run()
+
+	// Cleanup
+	sharedPool.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = pgContainer.Terminate(ctx)
+
+	os.Exit(code)
+}
+
+func newTestUser(name string) core.User {
+	return core.User{
+		UserID:      core.NewUserID(),
+		Username:    name,
+		BGGUsername: "bgg" + name,
+	}
+}
+
+func newTestEvent() *core.Event {
+	return &core.Event{
+		EventID:  core.NewEventID(),
+		Status:   core.EventStatusScheduled,
+		StartsAt: time.Now().UTC().Truncate(time.Second),
+		Timezone: "UTC",
+		Attendees: []core.Attendee{
+			{User: newTestUser("user1"), Status: core.AttendeeStatusConfirmed},
+			{User: newTestUser("user2"), Status: core.AttendeeStatusPending},
+		},
+		Matches: []core.Match{},
+	}
+}
+
+// testBGGID hands out a BoardGameGeek ID per test match, as matches of the
+// same BGG game share their stored game.
+var testBGGID atomic.Int64
+
+func newTestMatch(players ...core.User) *core.Match {
+	scores := make([]core.Score, 0, len(players))
+	for i, p := range players {
+		scores = append(scores, core.Score{UserID: p.UserID, Value: float64(100 - i)})
+	}
+
+	return &core.Match{
+		MatchID: core.NewMatchID(),
+		Game: core.Game{
+			GameID:     core.NewGameID(),
+			BGGID:      int(testBGGID.Add(1)),
+			Rating:     8.5,
+			Name:       "Gloomhaven",
+			Categories: []string{"Adventure", "Fantasy"},
+		},
+		Players: players,
+		Scoreboard: core.Scoreboard{
+			Scores:    scores,
+			ScoreUnit: core.ScoreUnitPoints,
+		},
+	}
+}
+
+func TestPostgreSQLEventRepository_CreateAndGet(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	evt.Matches = append(evt.Matches, *newTestMatch(evt.Attendees[0].User, evt.Attendees[1].User))
+
+	err := repo.CreateEvent(ctx, evt)
+	require.NoError(t, err)
+
+	retrieved, err := repo.GetEvent(ctx, evt.EventID)
+	require.NoError(t, err)
+	assert.Equal(t, evt, retrieved)
+}
+
+func TestPostgreSQLEventRepository_CreateDuplicate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	err := repo.CreateEvent(ctx, evt)
+	assert.ErrorIs(t, err, event.ErrEventExists)
+}
+
+func TestPostgreSQLEventRepository_KeepsStoredUsers(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	// e.g. an imported calendar claiming another name for the attendee
+	imported := newTestEvent()
+	imported.Attendees[0].User.UserID = evt.Attendees[0].User.UserID
+	imported.Attendees[0].User.Username = "mallory"
+	require.NoError(t, repo.CreateEvent(ctx, imported))
+
+	retrieved, err := repo.GetEvent(ctx, imported.EventID)
+	require.NoError(t, err)
+	assert.Equal(t, evt.Attendees[0].User, retrieved.Attendees[0].User)
+}
+
+func TestPostgreSQLEventRepository_GetNotFound(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	retrieved, err := repo.GetEvent(ctx, core.NewEventID())
+	assert.ErrorIs(t, err, event.ErrEventNotFound)
+	assert.Nil(t, retrieved)
+}
+
+func TestPostgreSQLEventRepository_ListEventsByAttendee(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	usr := newTestUser("regular")
+
+	first := newTestEvent()
+	first.Attendees = append(first.Attendees, core.Attendee{User: usr, Status: core.AttendeeStatusConfirmed})
+	require.NoError(t, repo.CreateEvent(ctx, first))
+
+	second := newTestEvent()
+	require.NoError(t, repo.CreateEvent(ctx, second))
+	require.NoError(t, repo.AddAttendee(ctx, second.EventID, &core.Attendee{User: usr, Status: core.AttendeeStatusPending}))
+
+	// An event the user does not attend
+	require.NoError(t, repo.CreateEvent(ctx, newTestEvent()))
+
+	events, err := repo.ListEventsByAttendee(ctx, usr.UserID)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, first.EventID, events[0].EventID)
+	assert.Equal(t, second.EventID, events[1].EventID)
+}
+
+func TestPostgreSQLEventRepository_ListEvents(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	// No other test schedules events in this week
+	from := time.Date(2100, time.January, 4, 0, 0, 0, 0, time.UTC)
+	to := from.Add(7 * 24 * time.Hour)
+
+	completed := newTestEvent()
+	completed.StartsAt = from.Add(time.Hour)
+	require.NoError(t, repo.CreateEvent(ctx, completed))
+	completeEvent(t, repo, completed.EventID)
+
+	scheduled := newTestEvent()
+	scheduled.StartsAt = from.Add(2 * time.Hour)
+	require.NoError(t, repo.CreateEvent(ctx, scheduled))
+
+	later := newTestEvent()
+	later.StartsAt = to
+	require.NoError(t, repo.CreateEvent(ctx, later))
+	completeEvent(t, repo, later.EventID)
+
+	events, err := repo.ListEvents(ctx, event.EventFilter{
+		Status: core.EventStatusCompleted,
+		From:   from,
+		To:     to,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, completed.EventID, events[0].EventID)
+
+	events, err = repo.ListEvents(ctx, event.EventFilter{From: from, To: to})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, completed.EventID, events[0].EventID)
+	assert.Equal(t, scheduled.EventID, events[1].EventID)
+}
+
+func TestPostgreSQLEventRepository_Schedule(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	evt := newTestEvent()
+	evt.Title = "Thursday game night"
+	evt.Location = "Kitchen table"
+	evt.StartsAt = time.Date(2025, time.March, 6, 19, 0, 0, 0, berlin)
+	evt.EndsAt = time.Date(2025, time.March, 6, 23, 0, 0, 0, berlin)
+	evt.Timezone = "Europe/Berlin"
+	evt.Host = &evt.Attendees[0].User
+	evt.Capacity = 4
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	retrieved, err := repo.GetEvent(ctx, evt.EventID)
+	require.NoError(t, err)
+	assert.Equal(t, evt.Title, retrieved.Title)
+	assert.Equal(t, evt.Location, retrieved.Location)
+	assert.Equal(t, evt.Host, retrieved.Host)
+	assert.Equal(t, evt.Capacity, retrieved.Capacity)
+	assert.True(t, evt.StartsAt.Equal(retrieved.StartsAt))
+	assert.True(t, evt.EndsAt.Equal(retrieved.EndsAt))
+	assert.Equal(t, "Europe/Berlin", retrieved.StartsAt.Location().String())
+}
+
+func TestPostgreSQLEventRepository_CreateInvalid(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	evt.StartsAt = time.Now()
+	evt.EndsAt = evt.StartsAt.Add(-time.Hour)
+
+	err := repo.CreateEvent(ctx, evt)
+	var verr *core.ValidationError
+	assert.ErrorAs(t, err, &verr)
+}
+
+func completeEvent(t *testing.T, repo event.EventRepository, eventID core.EventID) {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := repo.TransitionEvent(ctx, eventID, core.EventTransitionStart)
+	require.NoError(t, err)
+	_, err = repo.TransitionEvent(ctx, eventID, core.EventTransitionComplete)
+	require.NoError(t, err)
+}
+
+func TestPostgreSQLEventRepository_CreateRequiresInitialStatus(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	evt.Status = core.EventStatusCompleted
+
+	err := repo.CreateEvent(ctx, evt)
+	assert.ErrorIs(t, err, core.ErrInvalidTransition)
+
+	evt = newTestEvent()
+	evt.StartsAt = time.Time{}
+
+	err = repo.CreateEvent(ctx, evt)
+	assert.ErrorIs(t, err, core.ErrTransitionGuard)
+}
+
+func TestPostgreSQLEventRepository_TransitionEvent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	updated, err := repo.TransitionEvent(ctx, evt.EventID, core.EventTransitionStart)
+	require.NoError(t, err)
+	assert.Equal(t, core.EventStatusOngoing, updated.Status)
+
+	retrieved, err := repo.GetEvent(ctx, evt.EventID)
+	require.NoError(t, err)
+	assert.Equal(t, core.EventStatusOngoing, retrieved.Status)
+
+	_, err = repo.TransitionEvent(ctx, evt.EventID, core.EventTransitionSchedule)
+	assert.ErrorIs(t, err, core.ErrInvalidTransition)
+
+	retrieved, err = repo.GetEvent(ctx, evt.EventID)
+	require.NoError(t, err)
+	assert.Equal(t, core.EventStatusOngoing, retrieved.Status)
+
+	_, err = repo.TransitionEvent(ctx, core.NewEventID(), core.EventTransitionStart)
+	assert.ErrorIs(t, err, event.ErrEventNotFound)
+}
+
+func TestPostgreSQLEventRepository_RescheduleEvent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	startsAt := evt.StartsAt.Add(24 * time.Hour)
+	endsAt := startsAt.Add(3 * time.Hour)
+	updated, err := repo.RescheduleEvent(ctx, evt.EventID, startsAt, endsAt)
+	require.NoError(t, err)
+	assert.True(t, startsAt.Equal(updated.StartsAt))
+
+	retrieved, err := repo.GetEvent(ctx, evt.EventID)
+	require.NoError(t, err)
+	assert.True(t, startsAt.Equal(retrieved.StartsAt))
+	assert.True(t, endsAt.Equal(retrieved.EndsAt))
+
+	_, err = repo.RescheduleEvent(ctx, evt.EventID, startsAt, startsAt.Add(-time.Hour))
+	var verr *core.ValidationError
+	assert.ErrorAs(t, err, &verr)
+
+	_, err = repo.TransitionEvent(ctx, evt.EventID, core.EventTransitionStart)
+	require.NoError(t, err)
+	_, err = repo.RescheduleEvent(ctx, evt.EventID, startsAt, endsAt)
+	assert.ErrorIs(t, err, core.ErrInvalidTransition)
+}
+
+func TestPostgreSQLEventRepository_ClosedEventRejectsMutations(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+	_, err := repo.TransitionEvent(ctx, evt.EventID, core.EventTransitionCancel)
+	require.NoError(t, err)
+
+	err = repo.AddAttendee(ctx, evt.EventID, &core.Attendee{User: newTestUser("late"), Status: core.AttendeeStatusPending})
+	assert.ErrorIs(t, err, core.ErrEventClosed)
+
+	err = repo.RemoveAttendee(ctx, evt.EventID, evt.Attendees[0].User.UserID)
+	assert.ErrorIs(t, err, core.ErrEventClosed)
+
+	err = repo.AppendMatch(ctx, evt.EventID, newTestMatch(evt.Attendees[0].User))
+	assert.ErrorIs(t, err, core.ErrEventClosed)
+}
+
+func TestPostgreSQLEventRepository_AddAttendeeOverCapacity(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	evt.Capacity = 1
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	err := repo.AddAttendee(ctx, evt.EventID, &core.Attendee{User: newTestUser("extra"), Status: core.AttendeeStatusConfirmed})
+	var verr *core.ValidationError
+	assert.ErrorAs(t, err, &verr)
+}
+
+func TestPostgreSQLEventRepository_AddAndRemoveAttendee(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	added := core.Attendee{User: newTestUser("user3"), Status: core.AttendeeStatusPending}
+	require.NoError(t, repo.AddAttendee(ctx, evt.EventID, &added))
+
+	// Adding an existing attendee updates their status
+	added.Status = core.AttendeeStatusConfirmed
+	require.NoError(t, repo.AddAttendee(ctx, evt.EventID, &added))
+
+	retrieved, err := repo.GetEvent(ctx, evt.EventID)
+	require.NoError(t, err)
+	require.Len(t, retrieved.Attendees, 3)
+	assert.Equal(t, added, retrieved.Attendees[2])
+
+	require.NoError(t, repo.RemoveAttendee(ctx, evt.EventID, evt.Attendees[0].User.UserID))
+
+	retrieved, err = repo.GetEvent(ctx, evt.EventID)
+	require.NoError(t, err)
+	assert.Equal(t, []core.Attendee{evt.Attendees[1], added}, retrieved.Attendees)
+
+	err = repo.RemoveAttendee(ctx, evt.EventID, evt.Attendees[0].User.UserID)
+	assert.ErrorIs(t, err, event.ErrAttendeeNotFound)
+
+	err = repo.AddAttendee(ctx, core.NewEventID(), &added)
+	assert.ErrorIs(t, err, event.ErrEventNotFound)
+}
+
+func TestPostgreSQLEventRepository_UpdateAttendees(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	evt.Capacity = 1
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	confirmed, pending := evt.Attendees[0].User, evt.Attendees[1].User
+	invited := newTestUser("user3")
+
+	// accepting at capacity waitlists the attendee behind everyone else
+	var changes []core.RSVPChange
+	updated, err := repo.UpdateAttendees(ctx, evt.EventID, func(evt *core.Event) error {
+		invite, err := evt.Invite(invited)
+		if err != nil {
+			return err
+		}
+		accept, err := evt.Accept(pending.UserID)
+		changes = append(invite, accept...)
+		return err
+	})
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, core.RSVPWaitlisted, changes[1].Kind)
+
+	retrieved, err := repo.GetEvent(ctx, evt.EventID)
+	require.NoError(t, err)
+	assert.Equal(t, updated.Attendees, retrieved.Attendees)
+	assert.Equal(t, []core.Attendee{
+		{User: confirmed, Status: core.AttendeeStatusConfirmed},
+		{User: invited, Status: core.AttendeeStatusPending},
+		{User: pending, Status: core.AttendeeStatusWaitlisted},
+	}, retrieved.Attendees)
+
+	// declining promotes the waitlisted attendee
+	_, err = repo.UpdateAttendees(ctx, evt.EventID, func(evt *core.Event) error {
+		_, err := evt.Decline(confirmed.UserID)
+		return err
+	})
+	require.NoError(t, err)
+
+	retrieved, err = repo.GetEvent(ctx, evt.EventID)
+	require.NoError(t, err)
+	assert.Equal(t, core.AttendeeStatusDeclined, retrieved.Attendees[0].Status)
+	assert.Equal(t, core.AttendeeStatusConfirmed, retrieved.Attendees[2].Status)
+
+	// failing updates are rolled back
+	_, err = repo.UpdateAttendees(ctx, evt.EventID, func(evt *core.Event) error {
+		_, err := evt.Invite(invited)
+		return err
+	})
+	assert.ErrorIs(t, err, core.ErrAlreadyInvited)
+
+	_, err = repo.UpdateAttendees(ctx, core.NewEventID(), func(evt *core.Event) error { return nil })
+	assert.ErrorIs(t, err, event.ErrEventNotFound)
+}
+
+func TestPostgreSQLEventRepository_AppendMatch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	first := newTestMatch(evt.Attendees[0].User, evt.Attendees[1].User)
+	first.Scoreboard.Direction = core.ScoreDirectionLowerWins
+	first.Scoreboard.Scores[1].Tiebreakers = []float64{2, 0.5}
+	first.Scoreboard.Scores[0].Breakdown = []core.ScoreLine{
+		{Label: "Military", Value: 60},
+		{Label: "Science", Value: 40},
+	}
+	first.StartedAt = evt.StartsAt.Add(30 * time.Minute)
+	first.EndedAt = first.StartedAt.Add(50 * time.Minute)
+	second := newTestMatch(evt.Attendees[1].User)
+	second.Game.Scoring = core.Scoring{Unit: core.ScoreUnitCooperative}
+	second.Scoreboard.ScoreUnit = core.ScoreUnitCooperative
+	second.Scoreboard.Scores[0].Value = core.ScoreWin
+	require.NoError(t, repo.AppendMatch(ctx, evt.EventID, first))
+	require.NoError(t, repo.AppendMatch(ctx, evt.EventID, second))
+
+	retrieved, err := repo.GetEvent(ctx, evt.EventID)
+	require.NoError(t, err)
+	assert.Equal(t, []core.Match{*first, *second}, retrieved.Matches)
+	assert.Equal(t, 50*time.Minute, retrieved.Matches[0].Duration())
+
+	err = repo.AppendMatch(ctx, core.NewEventID(), newTestMatch())
+	assert.ErrorIs(t, err, event.ErrEventNotFound)
+}
+
+func TestPostgreSQLEventRepository_AppendTeamMatch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	evt.Attendees = append(evt.Attendees,
+		core.Attendee{User: newTestUser("user3"), Status: core.AttendeeStatusConfirmed},
+		core.Attendee{User: newTestUser("user4"), Status: core.AttendeeStatusConfirmed},
+	)
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	players := make([]core.User, 0, len(evt.Attendees))
+	for _, a := range evt.Attendees {
+		players = append(players, a.User)
+	}
+
+	match := newTestMatch(players...)
+	red := core.Team{TeamID: core.NewTeamID(), Name: "Red", Members: []core.UserID{players[0].UserID, players[2].UserID}}
+	blue := core.Team{TeamID: core.NewTeamID(), Name: "Blue", Members: []core.UserID{players[1].UserID, players[3].UserID}}
+	match.Teams = []core.Team{red, blue}
+	match.Scoreboard.TeamScores = []core.TeamScore{
+		{TeamID: red.TeamID, Value: 12, Tiebreakers: []float64{3}},
+		{TeamID: blue.TeamID, Value: 12},
+	}
+	require.NoError(t, repo.AppendMatch(ctx, evt.EventID, match))
+
+	retrieved, err := repo.GetEvent(ctx, evt.EventID)
+	require.NoError(t, err)
+	require.Len(t, retrieved.Matches, 1)
+	assert.Equal(t, *match, retrieved.Matches[0])
+	assert.Equal(t, red.Members, retrieved.Matches[0].Winners())
+}
+
+func TestPostgreSQLEventRepository_AppendInvalidMatch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	// the second player has no score
+	match := newTestMatch(evt.Attendees[0].User, evt.Attendees[1].User)
+	match.Scoreboard.Scores = match.Scoreboard.Scores[:1]
+
+	err := repo.AppendMatch(ctx, evt.EventID, match)
+	var verr *core.ValidationError
+	require.ErrorAs(t, err, &verr)
+
+	retrieved, err := repo.GetEvent(ctx, evt.EventID)
+	require.NoError(t, err)
+	assert.Empty(t, retrieved.Matches)
+}
+
+func TestPostgreSQLEventRepository_RecordMatch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+	require.NoError(t, repo.AppendMatch(ctx, evt.EventID, newTestMatch(evt.Attendees[0].User)))
+
+	game := newTestMatch().Game
+	match := core.StartMatch(game, nil, evt.StartsAt)
+	require.NoError(t, repo.CreateMatch(ctx, evt.EventID, match))
+	assert.ErrorIs(t, repo.CreateMatch(ctx, evt.EventID, match), event.ErrMatchExists)
+
+	retrieved, err := repo.GetMatch(ctx, match.MatchID)
+	require.NoError(t, err)
+	assert.Equal(t, match, retrieved)
+
+	updated, err := repo.UpdateMatch(ctx, match.MatchID, func(e *core.Event, m *core.Match) error {
+		assert.Equal(t, evt.EventID, e.EventID)
+		if err := m.AddPlayer(evt.Attendees[1].User); err != nil {
+			return err
+		}
+		return m.Finalize(evt.StartsAt.Add(time.Hour))
+	})
+	require.NoError(t, err)
+	assert.Equal(t, core.MatchStatusFinalized, updated.Status)
+
+	retrievedEvent, err := repo.GetEvent(ctx, evt.EventID)
+	require.NoError(t, err)
+	require.Len(t, retrievedEvent.Matches, 2)
+	assert.Equal(t, *updated, retrievedEvent.Matches[1])
+
+	_, err = repo.UpdateMatch(ctx, match.MatchID, func(_ *core.Event, m *core.Match) error {
+		return m.AddPlayer(evt.Attendees[0].User)
+	})
+	assert.ErrorIs(t, err, core.ErrMatchFinalized)
+
+	_, err = repo.GetMatch(ctx, core.NewMatchID())
+	assert.ErrorIs(t, err, event.ErrMatchNotFound)
+
+	_, err = repo.UpdateMatch(ctx, core.NewMatchID(), func(*core.Event, *core.Match) error { return nil })
+	assert.ErrorIs(t, err, event.ErrMatchNotFound)
+}
+
+func TestPostgreSQLEventRepository_UpdateMatchOfClosedEvent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	match := core.StartMatch(newTestMatch().Game, []core.User{evt.Attendees[0].User}, evt.StartsAt)
+	require.NoError(t, repo.CreateMatch(ctx, evt.EventID, match))
+
+	_, err := repo.TransitionEvent(ctx, evt.EventID, core.EventTransitionCancel)
+	require.NoError(t, err)
+
+	_, err = repo.UpdateMatch(ctx, match.MatchID, func(_ *core.Event, m *core.Match) error {
+		return m.Finalize(evt.StartsAt.Add(time.Hour))
+	})
+	assert.ErrorIs(t, err, core.ErrEventClosed)
+}
+
+func TestPostgreSQLEventRepository_GamesStoredOncePerBGGID(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	first := newTestMatch(evt.Attendees[0].User)
+	_, err := repo.GetGameByBGGID(ctx, first.Game.BGGID)
+	require.ErrorIs(t, err, event.ErrGameNotFound)
+	require.NoError(t, repo.AppendMatch(ctx, evt.EventID, first))
+
+	// resolved again under a fresh game ID
+	second := newTestMatch(evt.Attendees[0].User)
+	second.Game.BGGID = first.Game.BGGID
+	require.NoError(t, repo.AppendMatch(ctx, evt.EventID, second))
+	assert.Equal(t, first.Game.GameID, second.Game.GameID)
+
+	game, err := repo.GetGameByBGGID(ctx, first.Game.BGGID)
+	require.NoError(t, err)
+	assert.Equal(t, first.Game, *game)
+
+	retrieved, err := repo.GetEvent(ctx, evt.EventID)
+	require.NoError(t, err)
+	assert.Equal(t, retrieved.Matches[0].Game.GameID, retrieved.Matches[1].Game.GameID)
+}
+
+func TestPostgreSQLEventRepository_ListFinalizedMatches(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	early := newTestMatch(evt.Attendees[0].User)
+	early.EndedAt = evt.StartsAt.Add(time.Hour)
+	late := newTestMatch(evt.Attendees[0].User)
+	late.StartedAt = evt.StartsAt.Add(2 * time.Hour)
+	playing := newTestMatch(evt.Attendees[0].User)
+	playing.Status = core.MatchStatusInProgress
+	playing.StartedAt = evt.StartsAt.Add(3 * time.Hour)
+	for _, m := range []*core.Match{late, playing, early} {
+		require.NoError(t, repo.AppendMatch(ctx, evt.EventID, m))
+	}
+
+	// other tests store matches concurrently
+	ours := func(matches []core.Match) []core.MatchID {
+		ids := make([]core.MatchID, 0)
+		for _, m := range matches {
+			if m.MatchID == early.MatchID || m.MatchID == late.MatchID || m.MatchID == playing.MatchID {
+				ids = append(ids, m.MatchID)
+			}
+		}
+		return ids
+	}
+
+	all, err := repo.ListFinalizedMatches(ctx, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []core.MatchID{early.MatchID, late.MatchID}, ours(all))
+	i := slices.IndexFunc(all, func(m core.Match) bool { return m.MatchID == early.MatchID })
+	assert.Equal(t, early.Players, all[i].Players, "matches are loaded with their players")
+	assert.Equal(t, early.Scoreboard.Scores, all[i].Scoreboard.Scores)
+
+	since, err := repo.ListFinalizedMatches(ctx, evt.StartsAt.Add(90*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []core.MatchID{late.MatchID}, ours(since))
+
+	of, err := repo.ListFinalizedMatchesOf(ctx, []core.UserID{evt.Attendees[0].User.UserID})
+	require.NoError(t, err)
+	assert.Equal(t, []core.MatchID{early.MatchID, late.MatchID}, ours(of))
+
+	none, err := repo.ListFinalizedMatchesOf(ctx, []core.UserID{evt.Attendees[1].User.UserID})
+	require.NoError(t, err)
+	assert.Empty(t, ours(none))
+}
@@ -0,0 +1,218 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/tournament"
+	"go.jetify.com/typeid/v2"
+)
+
+var _ TournamentRepository = (*PostgreSQLEventRepository)(nil)
+
+func (r *PostgreSQLEventRepository) CreateTournament(ctx context.Context, t *tournament.Tournament) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		evt, err := loadEventForUpdate(ctx, tx, t.EventID)
+		if err != nil {
+			return err
+		}
+
+		if err := evt.CheckMutable(); err != nil {
+			return err
+		}
+
+		if err := upsertGame(ctx, tx, &t.Game); err != nil {
+			return err
+		}
+
+		rounds, err := marshalRounds(t.Rounds)
+		if err != nil {
+			return err
+		}
+
+		eventID := t.EventID.String()
+		_, err = tx.Exec(ctx, `
+			INSERT INTO tournaments (event_id, format, game_id, swiss_rounds, rounds)
+			VALUES ($1, $2, $3, $4, $5)`,
+			eventID, string(t.Format), t.Game.GameID.String(), t.SwissRounds, rounds,
+		)
+		if isPgError(err, pgUniqueViolation) {
+			return fmt.Errorf("tournament of event '%s': %w", eventID, ErrTournamentExists)
+		} else if err != nil {
+			return fmt.Errorf("failed to insert tournament: %w", err)
+		}
+
+		for i := range t.Players {
+			if err := insertUser(ctx, tx, &t.Players[i]); err != nil {
+				return err
+			}
+
+			_, err := tx.Exec(ctx, `INSERT INTO tournament_players (event_id, user_id, position) VALUES ($1, $2, $3)`,
+				eventID, t.Players[i].UserID.String(), i,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert tournament player '%s': %w", t.Players[i].UserID, err)
+			}
+		}
+
+		return announce(ctx, tx, core.DomainEventTournamentCreated, t.EventID, t)
+	})
+}
+
+func (r *PostgreSQLEventRepository) GetTournament(ctx context.Context, eventID core.EventID) (*tournament.Tournament, error) {
+	return loadTournament(ctx, r.pool, eventID)
+}
+
+func (r *PostgreSQLEventRepository) StartTournamentRound(ctx context.Context, eventID core.EventID, startedAt time.Time) (*tournament.Tournament, []core.Match, error) {
+	var (
+		t       *tournament.Tournament
+		matches []core.Match
+	)
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		// Tournaments are only changed with their event locked.
+		evt, err := loadEventForUpdate(ctx, tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		if err := evt.CheckMutable(); err != nil {
+			return err
+		}
+
+		t, err = loadTournament(ctx, tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		matches, err = t.NextRound(evt.Matches, startedAt)
+		if err != nil {
+			return err
+		}
+
+		for i := range matches {
+			if err := insertMatch(ctx, tx, eventID.String(), &matches[i], -1); err != nil {
+				return err
+			}
+
+			if err := announceNewMatch(ctx, tx, eventID, &matches[i]); err != nil {
+				return err
+			}
+		}
+
+		rounds, err := marshalRounds(t.Rounds)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `UPDATE tournaments SET rounds = $2 WHERE event_id = $1`, eventID.String(), rounds)
+		if err != nil {
+			return fmt.Errorf("failed to update tournament rounds: %w", err)
+		}
+
+		return announce(ctx, tx, core.DomainEventTournamentRoundStarted, eventID, t)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return t, matches, nil
+}
+
+// loadTournament loads the tournament the event runs as with its game and
+// players in seed order.
+func loadTournament(ctx context.Context, q querier, eventID core.EventID) (*tournament.Tournament, error) {
+	var (
+		format, gameID, name, scoringUnit, scoringDirection string
+		swissRounds, bggID                                  int
+		rating                                              float64
+		categories                                          []string
+		rounds                                              []byte
+	)
+	err := q.QueryRow(ctx, `
+		SELECT t.format, t.swiss_rounds, t.rounds,
+			g.game_id, g.bgg_id, g.name, g.rating, g.categories, g.scoring_unit, g.scoring_direction
+		FROM tournaments t
+		JOIN games g ON g.game_id = t.game_id
+		WHERE t.event_id = $1`,
+		eventID.String(),
+	).Scan(&format, &swissRounds, &rounds, &gameID, &bggID, &name, &rating, &categories, &scoringUnit, &scoringDirection)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("tournament of event '%s': %w", eventID, ErrTournamentNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load tournament: %w", err)
+	}
+
+	gID, err := typeid.Parse(gameID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid game id '%s': %w", gameID, err)
+	}
+
+	t := &tournament.Tournament{
+		EventID: eventID,
+		Format:  tournament.Format(format),
+		Game: core.Game{
+			GameID:     gID,
+			BGGID:      bggID,
+			Rating:     rating,
+			Name:       name,
+			Categories: categories,
+			Scoring: core.Scoring{
+				Unit:      core.ScoreUnit(scoringUnit),
+				Direction: core.ScoreDirection(scoringDirection),
+			},
+		},
+		Players:     []core.User{},
+		SwissRounds: swissRounds,
+	}
+	if err := json.Unmarshal(rounds, &t.Rounds); err != nil {
+		return nil, fmt.Errorf("invalid rounds of tournament '%s': %w", eventID, err)
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT u.user_id, u.username, u.bgg_username
+		FROM tournament_players p
+		JOIN users u ON u.user_id = p.user_id
+		WHERE p.event_id = $1
+		ORDER BY p.position`,
+		eventID.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tournament players: %w", err)
+	}
+
+	var userID, username, bggUsername string
+	_, err = pgx.ForEachRow(rows, []any{&userID, &username, &bggUsername}, func() error {
+		usr, err := newUser(userID, username, bggUsername)
+		if err != nil {
+			return err
+		}
+		t.Players = append(t.Players, usr)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan tournament players: %w", err)
+	}
+
+	return t, nil
+}
+
+func marshalRounds(rounds []tournament.Round) ([]byte, error) {
+	if rounds == nil {
+		rounds = []tournament.Round{}
+	}
+
+	data, err := json.Marshal(rounds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tournament rounds: %w", err)
+	}
+	return data, nil
+}
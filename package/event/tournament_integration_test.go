@@ -0,0 +1,85 @@
+//go:build integration
+
+package event_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/event"
+	"github.com/ngoldack/dicetrace/package/tournament"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgreSQLEventRepository_Tournament(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	players := []core.User{evt.Attendees[0].User, evt.Attendees[1].User, newTestUser("user3")}
+	tr, err := tournament.New(evt.EventID, tournament.FormatRoundRobin, newTestMatch().Game, players)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateTournament(ctx, tr))
+	assert.ErrorIs(t, repo.CreateTournament(ctx, tr), event.ErrTournamentExists)
+
+	retrieved, err := repo.GetTournament(ctx, evt.EventID)
+	require.NoError(t, err)
+	assert.Equal(t, tr, retrieved)
+
+	started, matches, err := repo.StartTournamentRound(ctx, evt.EventID, evt.StartsAt)
+	require.NoError(t, err)
+	require.Len(t, started.Rounds, 1)
+	require.Len(t, matches, 1, "one of three players has a bye")
+
+	_, _, err = repo.StartTournamentRound(ctx, evt.EventID, evt.StartsAt)
+	assert.ErrorIs(t, err, tournament.ErrRoundIncomplete)
+
+	_, err = repo.UpdateMatch(ctx, matches[0].MatchID, func(_ *core.Event, m *core.Match) error {
+		m.Scoreboard.Scores[0].Value = 1
+		return m.Finalize(evt.StartsAt.Add(time.Hour))
+	})
+	require.NoError(t, err)
+
+	started, matches, err = repo.StartTournamentRound(ctx, evt.EventID, evt.StartsAt.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, started.Rounds, 2)
+	require.Len(t, matches, 1)
+
+	retrieved, err = repo.GetTournament(ctx, evt.EventID)
+	require.NoError(t, err)
+	assert.Equal(t, started, retrieved)
+
+	retrievedEvent, err := repo.GetEvent(ctx, evt.EventID)
+	require.NoError(t, err)
+	require.Len(t, retrievedEvent.Matches, 2)
+	assert.Equal(t, matches[0], retrievedEvent.Matches[1])
+
+	_, err = repo.GetTournament(ctx, core.NewEventID())
+	assert.ErrorIs(t, err, event.ErrTournamentNotFound)
+}
+
+func TestPostgreSQLEventRepository_TournamentOfClosedEvent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := event.NewPostgreSQLEventRepository(sharedPool)
+
+	evt := newTestEvent()
+	require.NoError(t, repo.CreateEvent(ctx, evt))
+
+	players := []core.User{evt.Attendees[0].User, evt.Attendees[1].User}
+	tr, err := tournament.New(evt.EventID, tournament.FormatSingleElimination, newTestMatch().Game, players)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateTournament(ctx, tr))
+
+	_, err = repo.TransitionEvent(ctx, evt.EventID, core.EventTransitionCancel)
+	require.NoError(t, err)
+
+	_, _, err = repo.StartTournamentRound(ctx, evt.EventID, evt.StartsAt)
+	assert.ErrorIs(t, err, core.ErrEventClosed)
+}
@@ -0,0 +1,77 @@
+// Package bggcollection sources planner candidates from BoardGameGeek
+// collections.
+package bggcollection
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kkjdaniel/gogeek/collection"
+	"github.com/kkjdaniel/gogeek/thing"
+	"github.com/ngoldack/dicetrace/package/bgg"
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/planner"
+)
+
+type Source struct {
+	bgg bgg.BGGService
+}
+
+var _ planner.CollectionSource = (*Source)(nil)
+
+func New(svc bgg.BGGService) *Source {
+	return &Source{
+		bgg: svc,
+	}
+}
+
+// Collection returns the board games the user owns. Expansions and games
+// the user only wants or previously owned are left out. Collections do not
+// list categories, so they are taken from the games' BGG things, which the
+// BGG service caches.
+func (s *Source) Collection(ctx context.Context, user core.User) ([]planner.Candidate, error) {
+	col, err := s.bgg.FetchCollection(ctx, user.BGGUsername)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]planner.Candidate, 0, len(col.Items))
+	for _, item := range col.Items {
+		if item.Subtype != "boardgame" || item.Status.Own != 1 {
+			continue
+		}
+
+		game, err := s.bgg.FetchThing(ctx, item.ObjectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch BGG game %d: %w", item.ObjectID, err)
+		}
+		candidates = append(candidates, candidate(item, game))
+	}
+
+	return candidates, nil
+}
+
+// candidate is identified by its BGG ID only: the game may not be stored,
+// and the planner keys games by BGG ID, see core.Game.Key.
+func candidate(item collection.CollectionItem, game *thing.Item) planner.Candidate {
+	categories := make([]string, 0)
+	for _, link := range game.Links {
+		if link.Type == "boardgamecategory" {
+			categories = append(categories, link.Value)
+		}
+	}
+
+	return planner.Candidate{
+		Game: core.Game{
+			BGGID:      item.ObjectID,
+			Name:       item.Name,
+			Rating:     item.Stats.Rating.Average.Value,
+			Categories: categories,
+		},
+		MinPlayers:  item.Stats.MinPlayers,
+		MaxPlayers:  item.Stats.MaxPlayers,
+		MinPlaytime: time.Duration(item.Stats.MinPlaytime) * time.Minute,
+		MaxPlaytime: time.Duration(item.Stats.MaxPlaytime) * time.Minute,
+	}
+}
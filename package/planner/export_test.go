@@ -0,0 +1,7 @@
+package planner
+
+import "time"
+
+func SetRecommenderClock(r *Recommender, now func() time.Time) {
+	r.now = now
+}
@@ -0,0 +1,8 @@
+module github.com/ngoldack/dicetrace/package/planner
+
+go 1.25.3
+
+require (
+	github.com/kkjdaniel/gogeek v1.5.1
+	github.com/stretchr/testify v1.11.1
+)
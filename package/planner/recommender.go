@@ -0,0 +1,294 @@
+// Package planner helps hosts plan game nights: which games fit the
+// attendees and the time available.
+package planner
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+)
+
+// DefaultNoveltyWindow is how long after being played a game counts as
+// played recently.
+const DefaultNoveltyWindow = 30 * 24 * time.Hour
+
+// Candidate is a game from an attendee's collection.
+type Candidate struct {
+	Game core.Game
+
+	// MinPlayers and MaxPlayers bound the supported player count; zero
+	// means unknown.
+	MinPlayers int
+	MaxPlayers int
+	// MinPlaytime and MaxPlaytime bound how long a match takes; zero means
+	// unknown.
+	MinPlaytime time.Duration
+	MaxPlaytime time.Duration
+
+	// Owners are the attendees owning the game.
+	Owners []core.UserID
+}
+
+// playtime is how long a match of the candidate is expected to take at
+// most.
+func (c Candidate) playtime() time.Duration {
+	if c.MaxPlaytime > 0 {
+		return c.MaxPlaytime
+	}
+	return c.MinPlaytime
+}
+
+// CollectionSource returns the games in a user's collection.
+type CollectionSource interface {
+	Collection(ctx context.Context, user core.User) ([]Candidate, error)
+}
+
+// Preference is what an attendee likes to play. Games are identified by
+// their BoardGameGeek ID.
+type Preference struct {
+	UserID     core.UserID `json:"user_id"`
+	Likes      []int       `json:"likes,omitempty"`
+	Dislikes   []int       `json:"dislikes,omitempty"`
+	Categories []string    `json:"categories,omitempty"`
+}
+
+// score is 1 if the user likes the game and -1 if they dislike it. A
+// favourite category counts half as much as liking the game itself.
+func (p Preference) score(game core.Game) float64 {
+	switch {
+	case slices.Contains(p.Dislikes, game.BGGID):
+		return -1
+	case slices.Contains(p.Likes, game.BGGID):
+		return 1
+	case slices.ContainsFunc(game.Categories, func(c string) bool { return slices.Contains(p.Categories, c) }):
+		return 0.5
+	default:
+		return 0
+	}
+}
+
+// Weights balance the ranking criteria. Each criterion scores a game
+// between 0 and 1, or -1 and 1 for preferences, and is multiplied by its
+// weight.
+type Weights struct {
+	// Rating favours games rated highly on BoardGameGeek.
+	Rating float64
+	// Novelty favours games the group has not played recently.
+	Novelty float64
+	// Preference favours games the attendees like.
+	Preference float64
+}
+
+// DefaultWeights weigh every criterion equally.
+var DefaultWeights = Weights{Rating: 1, Novelty: 1, Preference: 1}
+
+// Request describes the game night to recommend games for.
+type Request struct {
+	Event *core.Event
+
+	// MinPlaytime and MaxPlaytime bound how long a match may take. Without
+	// a maximum the event's duration is used if it is scheduled.
+	MinPlaytime time.Duration
+	MaxPlaytime time.Duration
+
+	// History are the group's previous matches.
+	History     []core.Match
+	Preferences []Preference
+
+	// Weights default to DefaultWeights if zero; NoveltyWindow to
+	// DefaultNoveltyWindow.
+	Weights       Weights
+	NoveltyWindow time.Duration
+}
+
+// Recommender recommends games for game nights from the union of the
+// confirmed attendees' collections.
+type Recommender struct {
+	collections CollectionSource
+	now         func() time.Time
+}
+
+func NewRecommender(collections CollectionSource) *Recommender {
+	return &Recommender{
+		collections: collections,
+		now:         time.Now,
+	}
+}
+
+// Recommend returns the games of the confirmed attendees that support
+// their number and fit the playtime bounds, best first. Games are ranked
+// by the weighted sum of their BoardGameGeek rating, how long ago the
+// group last played them and the attendees' preferences. Attendees without
+// a BoardGameGeek username contribute no games.
+func (r *Recommender) Recommend(ctx context.Context, req Request) ([]core.Game, error) {
+	attendees := confirmedAttendees(req.Event)
+
+	candidates, err := r.union(ctx, attendees)
+	if err != nil {
+		return nil, err
+	}
+
+	minPlaytime, maxPlaytime := req.MinPlaytime, req.MaxPlaytime
+	if maxPlaytime == 0 && !req.Event.StartsAt.IsZero() && !req.Event.EndsAt.IsZero() {
+		maxPlaytime = req.Event.EndsAt.Sub(req.Event.StartsAt)
+	}
+
+	weights := req.Weights
+	if weights == (Weights{}) {
+		weights = DefaultWeights
+	}
+	window := req.NoveltyWindow
+	if window <= 0 {
+		window = DefaultNoveltyWindow
+	}
+
+	lastPlayed := lastPlayed(req.History)
+	preferences := attendeePreferences(req.Preferences, attendees)
+	now := r.now()
+
+	type scored struct {
+		game  core.Game
+		score float64
+	}
+
+	ranked := make([]scored, 0, len(candidates))
+	for _, c := range candidates {
+		if !fitsPlayers(c, len(attendees)) || !fitsPlaytime(c, minPlaytime, maxPlaytime) {
+			continue
+		}
+
+		score := weights.Rating*c.Game.Rating/10 +
+			weights.Novelty*novelty(lastPlayed[c.Game.Key()], now, window) +
+			weights.Preference*preference(c.Game, preferences)
+		ranked = append(ranked, scored{game: c.Game, score: score})
+	}
+
+	slices.SortFunc(ranked, func(a, b scored) int {
+		return cmp.Or(
+			cmp.Compare(b.score, a.score),
+			cmp.Compare(a.game.Name, b.game.Name),
+			cmp.Compare(a.game.Key(), b.game.Key()),
+		)
+	})
+
+	games := make([]core.Game, 0, len(ranked))
+	for _, s := range ranked {
+		games = append(games, s.game)
+	}
+
+	return games, nil
+}
+
+// union merges the attendees' collections, keeping every game once.
+func (r *Recommender) union(ctx context.Context, attendees []core.User) ([]Candidate, error) {
+	var candidates []Candidate
+	index := make(map[string]int)
+
+	for _, user := range attendees {
+		if user.BGGUsername == "" {
+			continue
+		}
+
+		collection, err := r.collections.Collection(ctx, user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch collection of user '%s': %w", user.UserID, err)
+		}
+
+		for _, c := range collection {
+			key := c.Game.Key()
+			i, ok := index[key]
+			if !ok {
+				index[key] = len(candidates)
+				c.Owners = []core.UserID{user.UserID}
+				candidates = append(candidates, c)
+				continue
+			}
+			if !slices.Contains(candidates[i].Owners, user.UserID) {
+				candidates[i].Owners = append(candidates[i].Owners, user.UserID)
+			}
+		}
+	}
+
+	return candidates, nil
+}
+
+func confirmedAttendees(evt *core.Event) []core.User {
+	users := make([]core.User, 0, len(evt.Attendees))
+	for _, a := range evt.Attendees {
+		if a.Status == core.AttendeeStatusConfirmed {
+			users = append(users, a.User)
+		}
+	}
+	return users
+}
+
+// attendeePreferences drops the preferences of users not attending.
+func attendeePreferences(preferences []Preference, attendees []core.User) []Preference {
+	return slices.DeleteFunc(slices.Clone(preferences), func(p Preference) bool {
+		return !slices.ContainsFunc(attendees, func(u core.User) bool { return u.UserID == p.UserID })
+	})
+}
+
+func fitsPlayers(c Candidate, players int) bool {
+	if c.MinPlayers > 0 && players < c.MinPlayers {
+		return false
+	}
+	return c.MaxPlayers == 0 || players <= c.MaxPlayers
+}
+
+// fitsPlaytime reports whether the candidate fits the bounds. Games of
+// unknown playtime always fit.
+func fitsPlaytime(c Candidate, minPlaytime, maxPlaytime time.Duration) bool {
+	playtime := c.playtime()
+	if playtime == 0 {
+		return true
+	}
+	if maxPlaytime > 0 && playtime > maxPlaytime {
+		return false
+	}
+	return playtime >= minPlaytime
+}
+
+// lastPlayed returns when each game was last played.
+func lastPlayed(history []core.Match) map[string]time.Time {
+	last := make(map[string]time.Time, len(history))
+	for _, m := range history {
+		at := m.EndedAt
+		if at.IsZero() {
+			at = m.StartedAt
+		}
+		key := m.Game.Key()
+		if at.After(last[key]) {
+			last[key] = at
+		}
+	}
+	return last
+}
+
+// novelty scores games never played or not played within the window as 1,
+// and games played just now as 0.
+func novelty(lastPlayed, now time.Time, window time.Duration) float64 {
+	if lastPlayed.IsZero() {
+		return 1
+	}
+	return min(1, max(0, float64(now.Sub(lastPlayed))/float64(window)))
+}
+
+// preference scores how much the attendees like the game, between -1 if
+// everyone dislikes it and 1 if everyone likes it.
+func preference(game core.Game, preferences []Preference) float64 {
+	if len(preferences) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, p := range preferences {
+		sum += p.score(game)
+	}
+
+	return sum / float64(len(preferences))
+}
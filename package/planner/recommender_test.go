@@ -0,0 +1,162 @@
+package planner_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/planner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2025, time.March, 6, 18, 0, 0, 0, time.UTC)
+
+var (
+	azul        = core.Game{GameID: core.NewGameID(), BGGID: 230802, Name: "Azul", Rating: 7.7, Categories: []string{"Abstract Strategy"}}
+	brass       = core.Game{GameID: core.NewGameID(), BGGID: 224517, Name: "Brass: Birmingham", Rating: 8.6, Categories: []string{"Economic"}}
+	carcassonne = core.Game{GameID: core.NewGameID(), BGGID: 822, Name: "Carcassonne", Rating: 7.4, Categories: []string{"Medieval"}}
+	twilight    = core.Game{GameID: core.NewGameID(), BGGID: 12333, Name: "Twilight Struggle", Rating: 8.2, Categories: []string{"Wargame"}}
+)
+
+// mockCollections implements planner.CollectionSource by BGG username
+type mockCollections struct {
+	collections map[string][]planner.Candidate
+	err         error
+}
+
+func (m *mockCollections) Collection(ctx context.Context, user core.User) ([]planner.Candidate, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.collections[user.BGGUsername], nil
+}
+
+func newEvent(users ...core.User) *core.Event {
+	evt := &core.Event{EventID: core.NewEventID(), Title: "Game Night"}
+	for _, u := range users {
+		evt.Attendees = append(evt.Attendees, core.Attendee{User: u, Status: core.AttendeeStatusConfirmed})
+	}
+	return evt
+}
+
+func newRecommender(collections planner.CollectionSource) *planner.Recommender {
+	r := planner.NewRecommender(collections)
+	planner.SetRecommenderClock(r, func() time.Time { return now })
+	return r
+}
+
+func gameNames(games []core.Game) []string {
+	names := make([]string, 0, len(games))
+	for _, g := range games {
+		names = append(names, g.Name)
+	}
+	return names
+}
+
+func TestRecommend(t *testing.T) {
+	t.Parallel()
+	alice := core.User{UserID: core.NewUserID(), Username: "alice", BGGUsername: "alice_bgg"}
+	bob := core.User{UserID: core.NewUserID(), Username: "bob", BGGUsername: "bob_bgg"}
+	carol := core.User{UserID: core.NewUserID(), Username: "carol"}
+	dave := core.User{UserID: core.NewUserID(), Username: "dave", BGGUsername: "dave_bgg"}
+
+	collections := &mockCollections{collections: map[string][]planner.Candidate{
+		"alice_bgg": {
+			{Game: azul, MinPlayers: 2, MaxPlayers: 4, MinPlaytime: 30 * time.Minute, MaxPlaytime: 45 * time.Minute},
+			{Game: brass, MinPlayers: 2, MaxPlayers: 4, MinPlaytime: 60 * time.Minute, MaxPlaytime: 120 * time.Minute},
+		},
+		"bob_bgg": {
+			{Game: carcassonne, MinPlayers: 2, MaxPlayers: 5, MaxPlaytime: 45 * time.Minute},
+			{Game: azul, MinPlayers: 2, MaxPlayers: 4, MinPlaytime: 30 * time.Minute, MaxPlaytime: 45 * time.Minute},
+			{Game: twilight, MinPlayers: 2, MaxPlayers: 2, MaxPlaytime: 180 * time.Minute},
+		},
+		"dave_bgg": {{Game: core.Game{GameID: core.NewGameID(), BGGID: 1, Name: "Declined"}}},
+	}}
+
+	evt := newEvent(alice, bob, carol)
+	evt.Attendees = append(evt.Attendees, core.Attendee{User: dave, Status: core.AttendeeStatusDeclined})
+
+	tests := []struct {
+		name string
+		req  planner.Request
+		want []string
+	}{
+		{
+			name: "rating",
+			req:  planner.Request{Event: evt},
+			want: []string{"Brass: Birmingham", "Azul", "Carcassonne"},
+		},
+		{
+			name: "playtime bounds",
+			req:  planner.Request{Event: evt, MinPlaytime: 40 * time.Minute, MaxPlaytime: time.Hour},
+			want: []string{"Azul", "Carcassonne"},
+		},
+		{
+			name: "recently played",
+			req: planner.Request{Event: evt, History: []core.Match{
+				{MatchID: core.NewMatchID(), Game: brass, EndedAt: now.Add(-24 * time.Hour)},
+				{MatchID: core.NewMatchID(), Game: azul, EndedAt: now.Add(-60 * 24 * time.Hour)},
+			}},
+			want: []string{"Azul", "Carcassonne", "Brass: Birmingham"},
+		},
+		{
+			name: "preferences",
+			req: planner.Request{Event: evt, Preferences: []planner.Preference{
+				{UserID: alice.UserID, Dislikes: []int{brass.BGGID}},
+				{UserID: bob.UserID, Categories: []string{"Medieval"}},
+				{UserID: dave.UserID, Likes: []int{brass.BGGID}},
+			}},
+			want: []string{"Carcassonne", "Azul", "Brass: Birmingham"},
+		},
+		{
+			name: "rating only",
+			req: planner.Request{Event: evt, Weights: planner.Weights{Rating: 1}, History: []core.Match{
+				{MatchID: core.NewMatchID(), Game: brass, EndedAt: now},
+			}},
+			want: []string{"Brass: Birmingham", "Azul", "Carcassonne"},
+		},
+	}
+
+	r := newRecommender(collections)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			games, err := r.Recommend(t.Context(), tt.req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, gameNames(games))
+		})
+	}
+}
+
+func TestRecommendEventDuration(t *testing.T) {
+	t.Parallel()
+	alice := core.User{UserID: core.NewUserID(), Username: "alice", BGGUsername: "alice_bgg"}
+	bob := core.User{UserID: core.NewUserID(), Username: "bob"}
+
+	collections := &mockCollections{collections: map[string][]planner.Candidate{
+		"alice_bgg": {
+			{Game: azul, MaxPlaytime: 45 * time.Minute},
+			{Game: brass, MaxPlaytime: 120 * time.Minute},
+			{Game: carcassonne},
+		},
+	}}
+
+	evt := newEvent(alice, bob)
+	evt.StartsAt, evt.EndsAt = now, now.Add(time.Hour)
+
+	games, err := newRecommender(collections).Recommend(t.Context(), planner.Request{Event: evt})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Azul", "Carcassonne"}, gameNames(games), "games of unknown playtime fit")
+}
+
+func TestRecommendCollectionError(t *testing.T) {
+	t.Parallel()
+	alice := core.User{UserID: core.NewUserID(), Username: "alice", BGGUsername: "alice_bgg"}
+	testErr := errors.New("BGG unavailable")
+
+	_, err := newRecommender(&mockCollections{err: testErr}).Recommend(t.Context(), planner.Request{Event: newEvent(alice)})
+	assert.ErrorIs(t, err, testErr)
+}
@@ -0,0 +1,510 @@
+package planner
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/ngoldack/dicetrace/package/core"
+)
+
+// ErrNoSeating is returned if the players cannot be split into tables
+// within the player counts of the games.
+var ErrNoSeating = errors.New("players cannot be seated at the available games")
+
+const (
+	// maxImprovements caps the local search of AssignTables.
+	maxImprovements = 1000
+	// maxPickSteps caps the search for games that can seat the players at a
+	// number of tables.
+	maxPickSteps = 100_000
+)
+
+// TableGame is a game available for a table. Every game is played at most
+// at one table.
+type TableGame struct {
+	Game core.Game `json:"game"`
+	// MinPlayers defaults to 1; a zero MaxPlayers means no upper bound.
+	MinPlayers int `json:"min_players,omitempty"`
+	MaxPlayers int `json:"max_players,omitempty"`
+}
+
+// Table is a game and the players seated to play it.
+type Table struct {
+	Game    core.Game   `json:"game"`
+	Players []core.User `json:"players"`
+}
+
+// TableWeights balance the seating criteria. Each criterion is scored
+// between 0 and 1, or -1 and 1 for preferences, and is multiplied by its
+// weight.
+type TableWeights struct {
+	// Preference favours seating players at games they like.
+	Preference float64
+	// Balance favours tables of similar average skill.
+	Balance float64
+	// Variety favours seating players who rarely played together.
+	Variety float64
+}
+
+// DefaultTableWeights weigh every criterion equally.
+var DefaultTableWeights = TableWeights{Preference: 1, Balance: 1, Variety: 1}
+
+// TablesRequest describes the players to seat.
+type TablesRequest struct {
+	Players []core.User
+	Games   []TableGame
+	// Tables fixes the number of tables. Zero picks the best number.
+	Tables int
+
+	Preferences []Preference
+	// Skill are the players' ratings, e.g. Glicko-2 ratings. Players
+	// without a rating are assumed to be of average skill.
+	Skill map[core.UserID]float64
+	// History are the players' previous matches.
+	History []core.Match
+
+	// Weights default to DefaultTableWeights if zero.
+	Weights TableWeights
+}
+
+// AssignTables splits the players into tables, each playing a different
+// game within its player count. Seatings are found by a deterministic
+// local search from a skill-balanced draft, so the result is a good but not
+// necessarily the best seating. Only table counts the games' player counts
+// allow are tried; the search stops with the context's error once it is
+// done.
+func AssignTables(ctx context.Context, req TablesRequest) ([]Table, error) {
+	if len(req.Players) == 0 {
+		return []Table{}, nil
+	}
+
+	a := newAssigner(req)
+
+	counts := []int{req.Tables}
+	if req.Tables == 0 {
+		counts = counts[:0]
+		lo, hi := a.tableCounts()
+		for k := lo; k <= hi; k++ {
+			counts = append(counts, k)
+		}
+	}
+
+	var best *seating
+	bestCost := math.Inf(1)
+	for _, k := range counts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		s, ok := a.initial(k)
+		if !ok {
+			continue
+		}
+		s, err := a.improve(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		// fewer tables win ties
+		if c := a.cost(s); c < bestCost-costEpsilon {
+			best, bestCost = s, c
+		}
+	}
+	if best == nil {
+		return nil, ErrNoSeating
+	}
+
+	return a.tables(best), nil
+}
+
+const costEpsilon = 1e-12
+
+// seating assigns player indices to tables, each playing a game by index.
+type seating struct {
+	games  []int
+	tables [][]int
+}
+
+func (s *seating) clone() *seating {
+	c := &seating{games: slices.Clone(s.games), tables: make([][]int, len(s.tables))}
+	for i, t := range s.tables {
+		c.tables[i] = slices.Clone(t)
+	}
+	return c
+}
+
+type assigner struct {
+	players []core.User
+	games   []TableGame
+	weights TableWeights
+
+	// preference[p][g] is how much player p likes game g.
+	preference [][]float64
+	skill      []float64
+	// together[p][q] is the share of matches p and q played together, out
+	// of the matches of whichever of them played fewer. Matches of other
+	// players do not dilute it.
+	together [][]float64
+}
+
+func newAssigner(req TablesRequest) *assigner {
+	a := &assigner{
+		players: req.Players,
+		games:   req.Games,
+		weights: req.Weights,
+	}
+	if a.weights == (TableWeights{}) {
+		a.weights = DefaultTableWeights
+	}
+
+	n := len(req.Players)
+	index := make(map[core.UserID]int, n)
+	for i, p := range req.Players {
+		index[p.UserID] = i
+	}
+
+	a.preference = make([][]float64, n)
+	for i := range a.preference {
+		a.preference[i] = make([]float64, len(req.Games))
+	}
+	for _, pref := range req.Preferences {
+		i, ok := index[pref.UserID]
+		if !ok {
+			continue
+		}
+		for g, game := range req.Games {
+			a.preference[i][g] = pref.score(game.Game)
+		}
+	}
+
+	// players without a rating get the average of the rated ones
+	var sum float64
+	var rated int
+	for _, p := range req.Players {
+		if skill, ok := req.Skill[p.UserID]; ok {
+			sum += skill
+			rated++
+		}
+	}
+	a.skill = make([]float64, n)
+	for i, p := range req.Players {
+		skill, ok := req.Skill[p.UserID]
+		if !ok && rated > 0 {
+			skill = sum / float64(rated)
+		}
+		a.skill[i] = skill
+	}
+
+	a.together = make([][]float64, n)
+	for i := range a.together {
+		a.together[i] = make([]float64, n)
+	}
+	played := make([]int, n)
+	for _, m := range req.History {
+		present := make([]int, 0, len(m.Players))
+		for _, p := range m.Players {
+			if i, ok := index[p.UserID]; ok {
+				present = append(present, i)
+				played[i]++
+			}
+		}
+		for x, i := range present {
+			for _, j := range present[x+1:] {
+				a.together[i][j]++
+				a.together[j][i]++
+			}
+		}
+	}
+	for i := range a.together {
+		for j := range a.together[i] {
+			if a.together[i][j] > 0 {
+				a.together[i][j] /= float64(min(played[i], played[j]))
+			}
+		}
+	}
+
+	return a
+}
+
+func (a *assigner) minPlayers(g int) int {
+	return max(1, a.games[g].MinPlayers)
+}
+
+func (a *assigner) maxPlayers(g int) int {
+	if a.games[g].MaxPlayers == 0 {
+		return len(a.players)
+	}
+	return a.games[g].MaxPlayers
+}
+
+// playerBounds returns the smallest minimum and largest maximum player
+// count of the games.
+func (a *assigner) playerBounds() (smallest, largest int) {
+	smallest = len(a.players)
+	for g := range a.games {
+		smallest = min(smallest, a.minPlayers(g))
+		largest = max(largest, a.maxPlayers(g))
+	}
+	return smallest, largest
+}
+
+// tableCounts returns the range of table counts that can seat the players:
+// at least enough tables of the largest game and at most as many tables of
+// the smallest game as there are players for. The range is empty if no
+// count can.
+func (a *assigner) tableCounts() (lo, hi int) {
+	n := len(a.players)
+	smallest, largest := a.playerBounds()
+	if largest == 0 {
+		return 1, 0
+	}
+	return max(1, (n+largest-1)/largest), min(n/smallest, len(a.games))
+}
+
+// initial seats the players at k tables: the best liked games that fit the
+// player count are picked, table sizes are kept as even as possible and
+// players are drafted in skill order, snaking across the tables.
+func (a *assigner) initial(k int) (*seating, bool) {
+	n := len(a.players)
+
+	order := make([]int, len(a.games))
+	for g := range order {
+		order[g] = g
+	}
+	groupPreference := func(g int) float64 {
+		var sum float64
+		for p := range a.players {
+			sum += a.preference[p][g]
+		}
+		return sum
+	}
+	slices.SortStableFunc(order, func(x, y int) int {
+		return cmp.Compare(groupPreference(y), groupPreference(x))
+	})
+
+	games, ok := a.pickGames(order, k, n)
+	if !ok {
+		return nil, false
+	}
+
+	sizes := make([]int, k)
+	seated := 0
+	for t, g := range games {
+		sizes[t] = a.minPlayers(g)
+		seated += sizes[t]
+	}
+	for ; seated < n; seated++ {
+		next := -1
+		for t, g := range games {
+			if sizes[t] < a.maxPlayers(g) && (next == -1 || sizes[t] < sizes[next]) {
+				next = t
+			}
+		}
+		sizes[next]++
+	}
+
+	players := make([]int, n)
+	for p := range players {
+		players[p] = p
+	}
+	slices.SortStableFunc(players, func(x, y int) int {
+		return cmp.Or(cmp.Compare(a.skill[y], a.skill[x]), cmp.Compare(a.players[x].UserID.String(), a.players[y].UserID.String()))
+	})
+
+	s := &seating{games: games, tables: make([][]int, k)}
+	for i, round := 0, 0; i < n; round++ {
+		for t := range k {
+			table := t
+			if round%2 == 1 {
+				table = k - 1 - t
+			}
+			if i < n && len(s.tables[table]) < sizes[table] {
+				s.tables[table] = append(s.tables[table], players[i])
+				i++
+			}
+		}
+	}
+
+	return s, true
+}
+
+// pickGames picks k games in the given order whose player counts can seat
+// n players, preferring earlier games. The search gives up after
+// maxPickSteps games tried.
+func (a *assigner) pickGames(order []int, k, n int) ([]int, bool) {
+	picked := make([]int, 0, k)
+	smallest, largest := a.playerBounds()
+	steps := 0
+
+	var pick func(from, minSum, maxSum int) bool
+	pick = func(from, minSum, maxSum int) bool {
+		if len(picked) == k {
+			return true
+		}
+		left := k - len(picked) - 1
+		for i := from; i <= len(order)-(k-len(picked)); i++ {
+			steps++
+			if steps > maxPickSteps {
+				return false
+			}
+
+			// the games left to pick must still be able to seat the rest
+			g := order[i]
+			lo, hi := minSum+a.minPlayers(g), maxSum+a.maxPlayers(g)
+			if lo+left*smallest > n || hi+left*largest < n {
+				continue
+			}
+
+			picked = append(picked, g)
+			if pick(i+1, lo, hi) {
+				return true
+			}
+			picked = picked[:len(picked)-1]
+		}
+		return false
+	}
+
+	if !pick(0, 0, 0) {
+		return nil, false
+	}
+	return picked, true
+}
+
+// improve applies the first improving move until none is left: swapping
+// two players, moving a player to another table or switching a table's
+// game.
+func (a *assigner) improve(ctx context.Context, s *seating) (*seating, error) {
+	cost := a.cost(s)
+
+	for range maxImprovements {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		next, ok := a.firstImprovement(s, cost)
+		if !ok {
+			break
+		}
+		s, cost = next, a.cost(next)
+	}
+
+	return s, nil
+}
+
+func (a *assigner) firstImprovement(s *seating, cost float64) (*seating, bool) {
+	better := func(c *seating) bool {
+		return a.cost(c) < cost-costEpsilon
+	}
+
+	for t1 := range s.tables {
+		for t2 := t1 + 1; t2 < len(s.tables); t2++ {
+			for i := range s.tables[t1] {
+				for j := range s.tables[t2] {
+					c := s.clone()
+					c.tables[t1][i], c.tables[t2][j] = c.tables[t2][j], c.tables[t1][i]
+					if better(c) {
+						return c, true
+					}
+				}
+			}
+		}
+	}
+
+	for t1 := range s.tables {
+		if len(s.tables[t1]) <= a.minPlayers(s.games[t1]) {
+			continue
+		}
+		for i := range s.tables[t1] {
+			for t2 := range s.tables {
+				if t2 == t1 || len(s.tables[t2]) >= a.maxPlayers(s.games[t2]) {
+					continue
+				}
+				c := s.clone()
+				c.tables[t2] = append(c.tables[t2], c.tables[t1][i])
+				c.tables[t1] = slices.Delete(c.tables[t1], i, i+1)
+				if better(c) {
+					return c, true
+				}
+			}
+		}
+	}
+
+	for t := range s.tables {
+		for g := range a.games {
+			if slices.Contains(s.games, g) || len(s.tables[t]) < a.minPlayers(g) || len(s.tables[t]) > a.maxPlayers(g) {
+				continue
+			}
+			c := s.clone()
+			c.games[t] = g
+			if better(c) {
+				return c, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// cost scores the seating, lower is better.
+func (a *assigner) cost(s *seating) float64 {
+	var preference float64
+	var pairs int
+	var together float64
+	means := make([]float64, len(s.tables))
+
+	for t, players := range s.tables {
+		for x, p := range players {
+			preference += a.preference[p][s.games[t]]
+			means[t] += a.skill[p] / float64(len(players))
+			for _, q := range players[x+1:] {
+				together += a.together[p][q]
+				pairs++
+			}
+		}
+	}
+
+	preference /= float64(len(a.players))
+	if pairs > 0 {
+		together /= float64(pairs)
+	}
+
+	return -a.weights.Preference*preference + a.weights.Balance*imbalance(means) + a.weights.Variety*together
+}
+
+// skillScale is the rating difference at which the stronger player is
+// expected to win ten times as often.
+const skillScale = 400
+
+// imbalance is the standard deviation of the tables' mean skill relative
+// to skillScale, capped at 1.
+func imbalance(means []float64) float64 {
+	if len(means) < 2 {
+		return 0
+	}
+
+	var mean float64
+	for _, m := range means {
+		mean += m / float64(len(means))
+	}
+	var variance float64
+	for _, m := range means {
+		variance += (m - mean) * (m - mean) / float64(len(means))
+	}
+
+	return min(1, math.Sqrt(variance)/skillScale)
+}
+
+func (a *assigner) tables(s *seating) []Table {
+	tables := make([]Table, 0, len(s.tables))
+	for t, players := range s.tables {
+		table := Table{Game: a.games[s.games[t]].Game, Players: make([]core.User, 0, len(players))}
+		for _, p := range players {
+			table.Players = append(table.Players, a.players[p])
+		}
+		slices.SortFunc(table.Players, func(x, y core.User) int {
+			return cmp.Or(cmp.Compare(x.Username, y.Username), cmp.Compare(x.UserID.String(), y.UserID.String()))
+		})
+		tables = append(tables, table)
+	}
+	return tables
+}
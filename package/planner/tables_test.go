@@ -0,0 +1,266 @@
+package planner_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/planner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUsers(names ...string) []core.User {
+	users := make([]core.User, 0, len(names))
+	for _, name := range names {
+		users = append(users, core.User{UserID: core.NewUserID(), Username: name})
+	}
+	return users
+}
+
+func tableGame(game core.Game, minPlayers, maxPlayers int) planner.TableGame {
+	return planner.TableGame{Game: game, MinPlayers: minPlayers, MaxPlayers: maxPlayers}
+}
+
+func usernames(users []core.User) []string {
+	names := make([]string, 0, len(users))
+	for _, u := range users {
+		names = append(names, u.Username)
+	}
+	return names
+}
+
+// tableOf returns the index of the table the user is seated at.
+func tableOf(tables []planner.Table, user core.User) int {
+	for i, t := range tables {
+		for _, p := range t.Players {
+			if p.UserID == user.UserID {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func TestAssignTablesSeatsEveryone(t *testing.T) {
+	t.Parallel()
+	players := newUsers("alice", "bob", "carol", "dave", "erin", "frank", "grace", "heidi", "ivan", "judy", "mallory")
+	codenames := core.Game{GameID: core.NewGameID(), BGGID: 178900, Name: "Codenames"}
+	games := []planner.TableGame{
+		tableGame(azul, 2, 4),
+		tableGame(brass, 2, 4),
+		tableGame(carcassonne, 2, 5),
+		tableGame(codenames, 4, 8),
+		tableGame(twilight, 2, 2),
+	}
+
+	tables, err := planner.AssignTables(t.Context(), planner.TablesRequest{Players: players, Games: games})
+	require.NoError(t, err)
+
+	seated := make(map[core.UserID]bool)
+	played := make(map[int]bool)
+	for _, table := range tables {
+		i := gameIndex(games, table.Game)
+		require.NotEqual(t, -1, i)
+		assert.False(t, played[i], "%s is played at two tables", table.Game.Name)
+		played[i] = true
+		assert.GreaterOrEqual(t, len(table.Players), games[i].MinPlayers)
+		assert.LessOrEqual(t, len(table.Players), games[i].MaxPlayers)
+		for _, p := range table.Players {
+			assert.False(t, seated[p.UserID])
+			seated[p.UserID] = true
+		}
+	}
+	assert.Len(t, seated, len(players))
+}
+
+func gameIndex(games []planner.TableGame, game core.Game) int {
+	for i, g := range games {
+		if g.Game.GameID == game.GameID {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestAssignTablesBalancesSkill(t *testing.T) {
+	t.Parallel()
+	players := newUsers("alice", "bob", "carol", "dave")
+	skill := map[core.UserID]float64{
+		players[0].UserID: 1900,
+		players[1].UserID: 1800,
+		players[2].UserID: 1200,
+		players[3].UserID: 1100,
+	}
+
+	tables, err := planner.AssignTables(t.Context(), planner.TablesRequest{
+		Players: players,
+		Games:   []planner.TableGame{tableGame(azul, 2, 2), tableGame(brass, 2, 2)},
+		Tables:  2,
+		Skill:   skill,
+		Weights: planner.TableWeights{Balance: 1},
+	})
+	require.NoError(t, err)
+	require.Len(t, tables, 2)
+
+	for _, table := range tables {
+		var sum float64
+		for _, p := range table.Players {
+			sum += skill[p.UserID]
+		}
+		assert.InDelta(t, 3000, sum, 1e-9, "table %v", usernames(table.Players))
+	}
+}
+
+func TestAssignTablesPreferences(t *testing.T) {
+	t.Parallel()
+	players := newUsers("alice", "bob", "carol", "dave")
+
+	tables, err := planner.AssignTables(t.Context(), planner.TablesRequest{
+		Players: players,
+		Games:   []planner.TableGame{tableGame(azul, 2, 2), tableGame(brass, 2, 2), tableGame(carcassonne, 2, 2)},
+		Tables:  2,
+		Preferences: []planner.Preference{
+			{UserID: players[0].UserID, Likes: []int{brass.BGGID}},
+			{UserID: players[1].UserID, Likes: []int{brass.BGGID}},
+			{UserID: players[2].UserID, Categories: []string{"Medieval"}},
+			{UserID: players[3].UserID, Likes: []int{carcassonne.BGGID}, Dislikes: []int{brass.BGGID}},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, tables, 2)
+
+	for _, table := range tables {
+		switch table.Game.GameID {
+		case brass.GameID:
+			assert.Equal(t, []string{"alice", "bob"}, usernames(table.Players))
+		case carcassonne.GameID:
+			assert.Equal(t, []string{"carol", "dave"}, usernames(table.Players))
+		default:
+			t.Errorf("unexpected game %s", table.Game.Name)
+		}
+	}
+}
+
+func TestAssignTablesVariety(t *testing.T) {
+	t.Parallel()
+	players := newUsers("alice", "bob", "carol", "dave")
+
+	var history []core.Match
+	for range 3 {
+		history = append(history,
+			core.Match{MatchID: core.NewMatchID(), Game: azul, Players: []core.User{players[0], players[1]}},
+			core.Match{MatchID: core.NewMatchID(), Game: azul, Players: []core.User{players[2], players[3]}},
+		)
+	}
+
+	tables, err := planner.AssignTables(t.Context(), planner.TablesRequest{
+		Players: players,
+		Games:   []planner.TableGame{tableGame(azul, 2, 2), tableGame(brass, 2, 2)},
+		History: history,
+	})
+	require.NoError(t, err)
+	require.Len(t, tables, 2)
+
+	assert.NotEqual(t, tableOf(tables, players[0]), tableOf(tables, players[1]))
+	assert.NotEqual(t, tableOf(tables, players[2]), tableOf(tables, players[3]))
+}
+
+func TestAssignTablesVarietyLongHistory(t *testing.T) {
+	t.Parallel()
+	players := newUsers("alice", "bob", "carol", "dave")
+	others := newUsers("erin", "frank")
+
+	// alice and bob, and carol and dave, always played together; the group
+	// played many more matches without them
+	var history []core.Match
+	for range 3 {
+		history = append(history,
+			core.Match{MatchID: core.NewMatchID(), Game: azul, Players: []core.User{players[0], players[1]}},
+			core.Match{MatchID: core.NewMatchID(), Game: azul, Players: []core.User{players[2], players[3]}},
+		)
+	}
+	for range 1000 {
+		history = append(history, core.Match{MatchID: core.NewMatchID(), Game: brass, Players: others})
+	}
+
+	// keeping the pairs together balances the tables slightly better
+	skill := map[core.UserID]float64{
+		players[0].UserID: 1550,
+		players[1].UserID: 1450,
+		players[2].UserID: 1500,
+		players[3].UserID: 1500,
+	}
+
+	tables, err := planner.AssignTables(t.Context(), planner.TablesRequest{
+		Players: players,
+		Games:   []planner.TableGame{tableGame(azul, 2, 2), tableGame(brass, 2, 2)},
+		Tables:  2,
+		Skill:   skill,
+		History: history,
+	})
+	require.NoError(t, err)
+	require.Len(t, tables, 2)
+
+	assert.NotEqual(t, tableOf(tables, players[0]), tableOf(tables, players[1]))
+	assert.NotEqual(t, tableOf(tables, players[2]), tableOf(tables, players[3]))
+}
+
+func TestAssignTablesNoSeating(t *testing.T) {
+	t.Parallel()
+	players := newUsers("alice", "bob", "carol", "dave", "erin")
+	duels := []planner.TableGame{tableGame(azul, 2, 2), tableGame(twilight, 2, 2)}
+
+	tests := []struct {
+		name string
+		req  planner.TablesRequest
+	}{
+		{name: "too many players", req: planner.TablesRequest{Players: players, Games: duels}},
+		{name: "too many tables", req: planner.TablesRequest{Players: players[:4], Games: duels, Tables: 3}},
+		{name: "no games", req: planner.TablesRequest{Players: players}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := planner.AssignTables(t.Context(), tt.req)
+			assert.ErrorIs(t, err, planner.ErrNoSeating)
+		})
+	}
+
+	tables, err := planner.AssignTables(t.Context(), planner.TablesRequest{Games: duels})
+	require.NoError(t, err)
+	assert.Empty(t, tables)
+}
+
+func TestAssignTablesManyGames(t *testing.T) {
+	t.Parallel()
+	players := newUsers("alice", "bob", "carol", "dave", "erin", "frank", "grace", "heidi", "ivan", "judy", "mallory", "niaj", "olivia")
+
+	// only duels and a single solo game, so most game combinations are dead ends
+	games := []planner.TableGame{tableGame(core.Game{GameID: core.NewGameID(), Name: "Solo"}, 1, 1)}
+	for i := range 60 {
+		games = append(games, tableGame(core.Game{GameID: core.NewGameID(), BGGID: 1000 + i, Name: "Duel"}, 2, 2))
+	}
+
+	tables, err := planner.AssignTables(t.Context(), planner.TablesRequest{Players: players, Games: games})
+	require.NoError(t, err)
+
+	seated := 0
+	for _, table := range tables {
+		seated += len(table.Players)
+	}
+	assert.Equal(t, len(players), seated)
+}
+
+func TestAssignTablesCanceled(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := planner.AssignTables(ctx, planner.TablesRequest{
+		Players: newUsers("alice", "bob"),
+		Games:   []planner.TableGame{tableGame(azul, 2, 4)},
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
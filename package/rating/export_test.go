@@ -0,0 +1,11 @@
+package rating
+
+// Update exposes the Glicko-2 update for a single rating period, scoring
+// results against opponents.
+func Update(r Rating, opponents []Rating, scores []float64, tau float64) Rating {
+	results := make([]result, 0, len(opponents))
+	for i, opp := range opponents {
+		results = append(results, result{opponent: opp, score: scores[i]})
+	}
+	return update(r, results, tau)
+}
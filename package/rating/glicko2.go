@@ -0,0 +1,105 @@
+package rating
+
+import "math"
+
+// glicko2Scale converts between the Glicko and the Glicko-2 scale.
+const glicko2Scale = 173.7178
+
+// convergence is the tolerance of the volatility iteration.
+const convergence = 0.000001
+
+// Rating is a Glicko-2 skill estimate on the familiar Glicko scale.
+type Rating struct {
+	Rating     float64 `json:"rating"`
+	Deviation  float64 `json:"deviation"`
+	Volatility float64 `json:"volatility"`
+}
+
+// Conservative is the rating minus twice the deviation: the player's skill
+// is at least this high with about 95% confidence.
+func (r Rating) Conservative() float64 {
+	return r.Rating - 2*r.Deviation
+}
+
+// result is the outcome of one game against a single opponent, scored 1 for
+// a win, 0.5 for a draw and 0 for a loss.
+type result struct {
+	opponent Rating
+	score    float64
+}
+
+// update computes the rating after a rating period with the given results,
+// following Glickman's "Example of the Glicko-2 system". tau constrains the
+// change in volatility.
+func update(r Rating, results []result, tau float64) Rating {
+	if len(results) == 0 {
+		return r
+	}
+
+	mu := (r.Rating - DefaultRating) / glicko2Scale
+	phi := r.Deviation / glicko2Scale
+
+	var invV, sum float64
+	for _, res := range results {
+		muJ := (res.opponent.Rating - DefaultRating) / glicko2Scale
+		g := gPhi(res.opponent.Deviation / glicko2Scale)
+		e := 1 / (1 + math.Exp(-g*(mu-muJ)))
+
+		invV += g * g * e * (1 - e)
+		sum += g * (res.score - e)
+	}
+	v := 1 / invV
+	delta := v * sum
+
+	sigma := volatility(phi, r.Volatility, v, delta, tau)
+
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phiNew := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	muNew := mu + phiNew*phiNew*sum
+
+	return Rating{
+		Rating:     muNew*glicko2Scale + DefaultRating,
+		Deviation:  phiNew * glicko2Scale,
+		Volatility: sigma,
+	}
+}
+
+func gPhi(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+// volatility finds the new volatility with the Illinois algorithm.
+func volatility(phi, sigma, v, delta, tau float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > convergence {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+
+	return math.Exp(A / 2)
+}
@@ -0,0 +1,39 @@
+package rating_test
+
+import (
+	"testing"
+
+	"github.com/ngoldack/dicetrace/package/rating"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUpdateGlickmanExample reproduces the worked example of Glickman's
+// "Example of the Glicko-2 system".
+func TestUpdateGlickmanExample(t *testing.T) {
+	t.Parallel()
+	player := rating.Rating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	opponents := []rating.Rating{
+		{Rating: 1400, Deviation: 30, Volatility: 0.06},
+		{Rating: 1550, Deviation: 100, Volatility: 0.06},
+		{Rating: 1700, Deviation: 300, Volatility: 0.06},
+	}
+
+	updated := rating.Update(player, opponents, []float64{1, 0, 0}, 0.5)
+	assert.InDelta(t, 1464.06, updated.Rating, 0.01)
+	assert.InDelta(t, 151.52, updated.Deviation, 0.01)
+	assert.InDelta(t, 0.05999, updated.Volatility, 0.00001)
+}
+
+func TestUpdateWithoutResults(t *testing.T) {
+	t.Parallel()
+	player := rating.Rating{Rating: 1620, Deviation: 80, Volatility: 0.06}
+
+	assert.Equal(t, player, rating.Update(player, nil, nil, 0.5))
+}
+
+func TestConservative(t *testing.T) {
+	t.Parallel()
+	r := rating.Rating{Rating: 1600, Deviation: 50}
+
+	assert.InDelta(t, 1500.0, r.Conservative(), 0)
+}
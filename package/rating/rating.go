@@ -0,0 +1,240 @@
+// Package rating maintains Glicko-2 skill ratings of players from finalized
+// matches. Ratings are kept overall and per game, and are deterministic:
+// replaying the same matches always yields the same ratings.
+package rating
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+)
+
+const (
+	DefaultRating     = 1500
+	DefaultDeviation  = 350
+	DefaultVolatility = 0.06
+	// DefaultTau is the system constant constraining volatility changes;
+	// Glickman suggests values between 0.3 and 1.2.
+	DefaultTau = 0.5
+)
+
+// Scope selects the ratings of a game, or the overall ratings across all
+// games.
+type Scope string
+
+// Overall rates players across all games.
+const Overall Scope = ""
+
+// GameScope is the scope of the game, see core.Game.Key.
+func GameScope(game core.Game) Scope {
+	return Scope(game.Key())
+}
+
+// BGGScope is the scope of the BoardGameGeek game.
+func BGGScope(bggID int) Scope {
+	return GameScope(core.Game{BGGID: bggID})
+}
+
+// Config tunes the rating system. Zero values fall back to the defaults.
+type Config struct {
+	InitialRating     float64
+	InitialDeviation  float64
+	InitialVolatility float64
+	Tau               float64
+}
+
+func (c Config) withDefaults() Config {
+	if c.InitialRating == 0 {
+		c.InitialRating = DefaultRating
+	}
+	if c.InitialDeviation == 0 {
+		c.InitialDeviation = DefaultDeviation
+	}
+	if c.InitialVolatility == 0 {
+		c.InitialVolatility = DefaultVolatility
+	}
+	if c.Tau == 0 {
+		c.Tau = DefaultTau
+	}
+	return c
+}
+
+// PlayerRating is a player's rating in a scope.
+type PlayerRating struct {
+	User  core.User `json:"user"`
+	Scope Scope     `json:"scope,omitempty"`
+	Rating
+
+	Matches    int       `json:"matches"`
+	Wins       int       `json:"wins"`
+	LastPlayed time.Time `json:"last_played,omitzero"`
+}
+
+type ratingKey struct {
+	scope  Scope
+	userID core.UserID
+}
+
+// Engine rates finalized matches in chronological order. Every match is a
+// rating period of its own: a multi-player match is decomposed into a
+// pairwise result against every opponent, scored by placement. Teammates
+// are not rated against each other and matches without opponents, such as
+// cooperative games, leave ratings unchanged. The deviation of inactive
+// players is not inflated. An Engine is not safe for concurrent use.
+type Engine struct {
+	cfg     Config
+	ratings map[ratingKey]*PlayerRating
+	matches core.MatchSequence
+}
+
+func NewEngine(cfg Config) *Engine {
+	return &Engine{
+		cfg:     cfg.withDefaults(),
+		ratings: make(map[ratingKey]*PlayerRating),
+	}
+}
+
+// Recompute rates the matches from scratch in chronological order. Matches
+// in progress are skipped.
+func Recompute(cfg Config, matches []core.Match) (*Engine, error) {
+	e := NewEngine(cfg)
+	for _, m := range core.Chronological(matches) {
+		if err := e.Apply(m); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// Apply rates the finalized match. Matches must be applied once each in
+// chronological order, see core.MatchSequence; use Recompute to rate the
+// full history instead.
+func (e *Engine) Apply(match core.Match) error {
+	if err := e.matches.Next(&match); err != nil {
+		return err
+	}
+	at := match.PlayedAt()
+
+	// Players of cooperative games win or lose together against the game.
+	if match.Scoreboard.ScoreUnit != core.ScoreUnitCooperative {
+		placements := match.Placements()
+		users := make(map[core.UserID]core.User, len(match.Players))
+		for _, p := range match.Players {
+			users[p.UserID] = p
+		}
+
+		for _, scope := range []Scope{Overall, GameScope(match.Game)} {
+			e.rate(scope, placements, users, at)
+		}
+	}
+
+	return nil
+}
+
+// rate updates the ratings in the scope from pre-match ratings, so the
+// order of placements does not matter.
+func (e *Engine) rate(scope Scope, placements []core.Placement, users map[core.UserID]core.User, at time.Time) {
+	before := make([]Rating, len(placements))
+	for i, p := range placements {
+		before[i] = e.current(scope, p.UserID)
+	}
+
+	for i, p := range placements {
+		results := make([]result, 0, len(placements)-1)
+		for j, opp := range placements {
+			if i == j || (!p.TeamID.IsZero() && p.TeamID == opp.TeamID) {
+				continue
+			}
+			results = append(results, result{opponent: before[j], score: pairScore(p.Place, opp.Place)})
+		}
+		if len(results) == 0 {
+			continue
+		}
+
+		pr := e.get(scope, p.UserID)
+		pr.User = users[p.UserID]
+		pr.Rating = update(before[i], results, e.cfg.Tau)
+		pr.Matches++
+		if p.Won {
+			pr.Wins++
+		}
+		pr.LastPlayed = at
+	}
+}
+
+// current returns the player's rating in the scope, or the initial rating
+// for unrated players.
+func (e *Engine) current(scope Scope, userID core.UserID) Rating {
+	if pr, ok := e.ratings[ratingKey{scope: scope, userID: userID}]; ok {
+		return pr.Rating
+	}
+	return Rating{
+		Rating:     e.cfg.InitialRating,
+		Deviation:  e.cfg.InitialDeviation,
+		Volatility: e.cfg.InitialVolatility,
+	}
+}
+
+func (e *Engine) get(scope Scope, userID core.UserID) *PlayerRating {
+	key := ratingKey{scope: scope, userID: userID}
+	pr, ok := e.ratings[key]
+	if !ok {
+		pr = &PlayerRating{Scope: scope, Rating: e.current(scope, userID)}
+		e.ratings[key] = pr
+	}
+	return pr
+}
+
+// Player returns the player's rating in the scope, if they were rated.
+func (e *Engine) Player(scope Scope, userID core.UserID) (PlayerRating, bool) {
+	pr, ok := e.ratings[ratingKey{scope: scope, userID: userID}]
+	if !ok {
+		return PlayerRating{}, false
+	}
+	return *pr, true
+}
+
+// Leaderboard returns the rated players of the scope, best first. Players
+// are ordered by conservative rating, so that a few lucky matches do not
+// top established players, then by rating and user ID. A positive limit
+// caps the number of entries.
+func (e *Engine) Leaderboard(scope Scope, limit int) []PlayerRating {
+	board := make([]PlayerRating, 0)
+	for key, pr := range e.ratings {
+		if key.scope == scope {
+			board = append(board, *pr)
+		}
+	}
+
+	slices.SortFunc(board, func(a, b PlayerRating) int {
+		if c := cmp.Compare(b.Conservative(), a.Conservative()); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.Rating.Rating, a.Rating.Rating); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.User.UserID.String(), b.User.UserID.String())
+	})
+
+	if limit > 0 && len(board) > limit {
+		board = board[:limit]
+	}
+
+	return board
+}
+
+// pairScore scores a placement against an opponent's: 1 for finishing
+// ahead, 0.5 for a tie and 0 for finishing behind.
+func pairScore(place, opponent int) float64 {
+	switch {
+	case place < opponent:
+		return 1
+	case place == opponent:
+		return 0.5
+	default:
+		return 0
+	}
+}
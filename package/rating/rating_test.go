@@ -0,0 +1,216 @@
+package rating_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/rating"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	azul        = core.Game{GameID: core.NewGameID(), BGGID: 230802, Name: "Azul"}
+	carcassonne = core.Game{GameID: core.NewGameID(), BGGID: 822, Name: "Carcassonne"}
+	start       = time.Date(2025, time.March, 6, 19, 0, 0, 0, time.UTC)
+)
+
+func newPlayers(names ...string) []core.User {
+	players := make([]core.User, 0, len(names))
+	for _, name := range names {
+		players = append(players, core.User{UserID: core.NewUserID(), Username: name})
+	}
+	return players
+}
+
+// newFinalizedMatch returns a points match of the game that ended at the
+// given time, scoring the players in order.
+func newFinalizedMatch(game core.Game, endedAt time.Time, players []core.User, values ...float64) core.Match {
+	scores := make([]core.Score, 0, len(players))
+	for i, p := range players {
+		scores = append(scores, core.Score{UserID: p.UserID, Value: values[i]})
+	}
+	return core.Match{
+		MatchID:    core.NewMatchID(),
+		Status:     core.MatchStatusFinalized,
+		Game:       game,
+		Players:    players,
+		StartedAt:  endedAt.Add(-time.Hour),
+		EndedAt:    endedAt,
+		Scoreboard: core.Scoreboard{Scores: scores, ScoreUnit: core.ScoreUnitPoints},
+	}
+}
+
+func mustPlayer(t *testing.T, e *rating.Engine, scope rating.Scope, user core.User) rating.PlayerRating {
+	t.Helper()
+	pr, ok := e.Player(scope, user.UserID)
+	require.True(t, ok, "player %s is not rated", user.Username)
+	return pr
+}
+
+func TestEngineHeadToHead(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob")
+	e := rating.NewEngine(rating.Config{})
+
+	require.NoError(t, e.Apply(newFinalizedMatch(azul, start, players, 61, 58)))
+
+	for _, scope := range []rating.Scope{rating.Overall, rating.GameScope(azul)} {
+		alice, bob := mustPlayer(t, e, scope, players[0]), mustPlayer(t, e, scope, players[1])
+		assert.Greater(t, alice.Rating.Rating, float64(rating.DefaultRating))
+		assert.Less(t, bob.Rating.Rating, float64(rating.DefaultRating))
+		assert.InDelta(t, alice.Rating.Rating-rating.DefaultRating, rating.DefaultRating-bob.Rating.Rating, 1e-9)
+		assert.Less(t, alice.Deviation, float64(rating.DefaultDeviation))
+		assert.Equal(t, players[0], alice.User)
+		assert.Equal(t, 1, alice.Matches)
+		assert.Equal(t, 1, alice.Wins)
+		assert.Equal(t, 0, bob.Wins)
+		assert.Equal(t, start, alice.LastPlayed)
+	}
+
+	_, ok := e.Player(rating.GameScope(carcassonne), players[0].UserID)
+	assert.False(t, ok)
+}
+
+func TestEngineMultiplayerDecomposition(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob", "carol")
+	e := rating.NewEngine(rating.Config{})
+
+	require.NoError(t, e.Apply(newFinalizedMatch(azul, start, players, 70, 50, 30)))
+
+	alice := mustPlayer(t, e, rating.Overall, players[0])
+	bob := mustPlayer(t, e, rating.Overall, players[1])
+	carol := mustPlayer(t, e, rating.Overall, players[2])
+
+	// bob won against carol and lost against alice
+	assert.InDelta(t, float64(rating.DefaultRating), bob.Rating.Rating, 1e-9)
+	assert.Greater(t, alice.Rating.Rating, bob.Rating.Rating)
+	assert.Less(t, carol.Rating.Rating, bob.Rating.Rating)
+}
+
+func TestEngineTie(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob")
+	e := rating.NewEngine(rating.Config{})
+
+	require.NoError(t, e.Apply(newFinalizedMatch(azul, start, players, 40, 40)))
+
+	for _, p := range players {
+		pr := mustPlayer(t, e, rating.Overall, p)
+		assert.InDelta(t, float64(rating.DefaultRating), pr.Rating.Rating, 1e-9)
+		assert.Less(t, pr.Deviation, float64(rating.DefaultDeviation))
+		assert.Equal(t, 1, pr.Wins)
+	}
+}
+
+func TestEngineTeams(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob", "carol", "dave")
+	red := core.Team{TeamID: core.NewTeamID(), Name: "Red", Members: []core.UserID{players[0].UserID, players[1].UserID}}
+	blue := core.Team{TeamID: core.NewTeamID(), Name: "Blue", Members: []core.UserID{players[2].UserID, players[3].UserID}}
+
+	match := newFinalizedMatch(azul, start, players, 0, 0, 0, 0)
+	match.Teams = []core.Team{red, blue}
+	match.Scoreboard.Scores = []core.Score{}
+	match.Scoreboard.TeamScores = []core.TeamScore{{TeamID: red.TeamID, Value: 8}, {TeamID: blue.TeamID, Value: 5}}
+	require.NoError(t, match.Validate())
+
+	e := rating.NewEngine(rating.Config{})
+	require.NoError(t, e.Apply(match))
+
+	alice, bob := mustPlayer(t, e, rating.Overall, players[0]), mustPlayer(t, e, rating.Overall, players[1])
+	carol := mustPlayer(t, e, rating.Overall, players[2])
+
+	// teammates are only rated against their opponents
+	assert.Equal(t, alice.Rating, bob.Rating)
+	assert.Greater(t, alice.Rating.Rating, float64(rating.DefaultRating))
+	assert.Less(t, carol.Rating.Rating, float64(rating.DefaultRating))
+}
+
+func TestEngineSkipsUnratedMatches(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob")
+	e := rating.NewEngine(rating.Config{})
+
+	coop := newFinalizedMatch(azul, start, players, core.ScoreWin, core.ScoreWin)
+	coop.Scoreboard.ScoreUnit = core.ScoreUnitCooperative
+	require.NoError(t, e.Apply(coop))
+
+	solo := newFinalizedMatch(azul, start.Add(time.Hour), players[:1], 80)
+	require.NoError(t, e.Apply(solo))
+
+	assert.Empty(t, e.Leaderboard(rating.Overall, 0))
+}
+
+func TestEngineApplyErrors(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob")
+	e := rating.NewEngine(rating.Config{})
+
+	later := newFinalizedMatch(azul, start.Add(time.Hour), players, 3, 2)
+	require.NoError(t, e.Apply(later))
+
+	assert.ErrorIs(t, e.Apply(later), core.ErrMatchApplied)
+	assert.ErrorIs(t, e.Apply(newFinalizedMatch(azul, start, players, 3, 2)), core.ErrMatchOutOfOrder)
+
+	inProgress := newFinalizedMatch(azul, start.Add(2*time.Hour), players, 3, 2)
+	inProgress.Status = core.MatchStatusInProgress
+	assert.ErrorIs(t, e.Apply(inProgress), core.ErrMatchNotFinalized)
+}
+
+func TestRecomputeIsDeterministic(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob", "carol")
+	history := []core.Match{
+		newFinalizedMatch(azul, start, players, 61, 58, 40),
+		newFinalizedMatch(carcassonne, start.Add(time.Hour), players[1:], 90, 75),
+		newFinalizedMatch(azul, start.Add(2*time.Hour), players, 30, 52, 52),
+		newFinalizedMatch(carcassonne, start.Add(3*time.Hour), players[:2], 60, 88),
+	}
+
+	sequential := rating.NewEngine(rating.Config{})
+	for _, m := range history {
+		require.NoError(t, sequential.Apply(m))
+	}
+
+	shuffled := []core.Match{history[2], history[0], history[3], history[1]}
+	inProgress := newFinalizedMatch(azul, start.Add(4*time.Hour), players, 1, 2, 3)
+	inProgress.Status = core.MatchStatusInProgress
+	shuffled = append(shuffled, inProgress)
+
+	recomputed, err := rating.Recompute(rating.Config{}, shuffled)
+	require.NoError(t, err)
+
+	for _, scope := range []rating.Scope{rating.Overall, rating.GameScope(azul), rating.GameScope(carcassonne)} {
+		assert.Equal(t, sequential.Leaderboard(scope, 0), recomputed.Leaderboard(scope, 0))
+	}
+}
+
+func TestLeaderboard(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob", "carol")
+	e := rating.NewEngine(rating.Config{})
+
+	for i := range 3 {
+		require.NoError(t, e.Apply(newFinalizedMatch(azul, start.Add(time.Duration(i)*time.Hour), players, 70, 50, 30)))
+	}
+	require.NoError(t, e.Apply(newFinalizedMatch(carcassonne, start.Add(4*time.Hour), players[1:], 10, 90)))
+
+	board := e.Leaderboard(rating.GameScope(azul), 0)
+	require.Len(t, board, 3)
+	for i, p := range players {
+		assert.Equal(t, p, board[i].User)
+		assert.Equal(t, 3, board[i].Matches)
+	}
+	assert.Equal(t, board[0].Conservative(), board[0].Rating.Rating-2*board[0].Deviation)
+
+	top := e.Leaderboard(rating.Overall, 2)
+	require.Len(t, top, 2)
+	assert.Equal(t, players[0], top[0].User)
+
+	byBGGID := e.Leaderboard(rating.BGGScope(carcassonne.BGGID), 0)
+	require.Len(t, byBGGID, 2)
+	assert.Equal(t, players[2], byBGGID[0].User)
+}
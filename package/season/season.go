@@ -0,0 +1,240 @@
+// Package season ranks the players of a group over a date range by the
+// points they earned in the finalized matches of that range.
+package season
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+)
+
+// DefaultPlacementPoints awards 3 points for first place, 2 for second and
+// 1 for third.
+var DefaultPlacementPoints = []float64{3, 2, 1}
+
+// PointSystem configures the points earned in a match.
+type PointSystem struct {
+	// Placements are the points for first, second, third place and so on.
+	// Later places earn none. Defaults to DefaultPlacementPoints. Tied
+	// players earn the points of the place they share.
+	Placements []float64 `json:"placements,omitempty"`
+	// Participation is earned for every match played.
+	Participation float64 `json:"participation,omitempty"`
+	// WeightByPlayerCount multiplies the placement points by the number of
+	// opponents, so that winning a crowded match is worth more than winning
+	// a duel.
+	WeightByPlayerCount bool `json:"weight_by_player_count,omitempty"`
+}
+
+func (ps PointSystem) withDefaults() PointSystem {
+	if len(ps.Placements) == 0 {
+		ps.Placements = DefaultPlacementPoints
+	}
+	return ps
+}
+
+// Points returns the points earned for the placement in a match of
+// players. Players of cooperative games earn the first place's points if
+// they won and no placement points otherwise.
+func (ps PointSystem) Points(p core.Placement, players int, cooperative bool) float64 {
+	ps = ps.withDefaults()
+
+	place := p.Place
+	if cooperative {
+		place = 0
+		if p.Won {
+			place = 1
+		}
+	}
+
+	var points float64
+	if place >= 1 && place <= len(ps.Placements) {
+		points = ps.Placements[place-1]
+	}
+	if ps.WeightByPlayerCount {
+		points *= float64(players - 1)
+	}
+
+	return points + ps.Participation
+}
+
+func (ps PointSystem) validate(verr *core.ValidationError, prefix string) {
+	for i, points := range ps.Placements {
+		if points < 0 {
+			verr.Add(fmt.Sprintf("%s.placements[%d]", prefix, i), "must not be negative")
+		}
+	}
+	if ps.Participation < 0 {
+		verr.Add(prefix+".participation", "must not be negative")
+	}
+}
+
+// Season is a date range in which a group of players competes.
+type Season struct {
+	Name string `json:"name"`
+
+	// StartsAt is inclusive and EndsAt is exclusive. Matches count towards
+	// the season if they ended in the range.
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+
+	// Participants is the group competing in the season. Matches count for
+	// participants only, though points are earned against everyone in the
+	// match. Without participants everyone who played is ranked.
+	Participants []core.UserID `json:"participants,omitempty"`
+
+	Points PointSystem `json:"points,omitzero"`
+}
+
+// Validate reports every invalid field as a *core.ValidationError.
+func (s *Season) Validate() error {
+	verr := &core.ValidationError{}
+
+	if s.Name == "" {
+		verr.Add("name", "must not be empty")
+	}
+	if s.StartsAt.IsZero() {
+		verr.Add("starts_at", "must be set")
+	}
+	if s.EndsAt.IsZero() {
+		verr.Add("ends_at", "must be set")
+	} else if !s.StartsAt.IsZero() && !s.EndsAt.After(s.StartsAt) {
+		verr.Add("ends_at", "must be after starts_at")
+	}
+
+	seen := make(map[core.UserID]bool, len(s.Participants))
+	for i, id := range s.Participants {
+		field := fmt.Sprintf("participants[%d]", i)
+		switch {
+		case id.IsZero():
+			verr.Add(field, "must be set")
+		case seen[id]:
+			verr.Add(field, "duplicate participant %s", id)
+		}
+		seen[id] = true
+	}
+
+	s.Points.validate(verr, "points")
+
+	return verr.Err()
+}
+
+// Contains reports whether the finalized match counts towards the season.
+func (s *Season) Contains(m core.Match) bool {
+	if !m.Finalized() {
+		return false
+	}
+
+	at := m.PlayedAt()
+	return !at.Before(s.StartsAt) && at.Before(s.EndsAt)
+}
+
+// Standing is a player's position in the season.
+type Standing struct {
+	User core.User `json:"user"`
+	// Position is the 1-based standing. Players tied on points and all
+	// tiebreakers share a position and the following positions are
+	// skipped, e.g. 1, 2, 2, 4.
+	Position int     `json:"position"`
+	Points   float64 `json:"points"`
+
+	// Wins, AveragePlacement and Matches are the tiebreakers, applied in
+	// this order: more wins, then a better average placement, then fewer
+	// matches played.
+	Wins             int     `json:"wins"`
+	AveragePlacement float64 `json:"average_placement"`
+	Matches          int     `json:"matches"`
+}
+
+func compareStandings(a, b Standing) int {
+	return cmp.Or(
+		cmp.Compare(b.Points, a.Points),
+		cmp.Compare(b.Wins, a.Wins),
+		compareAveragePlacement(a, b),
+		cmp.Compare(a.Matches, b.Matches),
+	)
+}
+
+// compareAveragePlacement ranks players without matches last.
+func compareAveragePlacement(a, b Standing) int {
+	switch {
+	case a.Matches == 0 || b.Matches == 0:
+		return cmp.Compare(b.Matches, a.Matches)
+	default:
+		return cmp.Compare(a.AveragePlacement, b.AveragePlacement)
+	}
+}
+
+// Standings ranks the players by the points earned in the matches of the
+// season, best first. Matches with a single player earn no points. All
+// participants are listed, including those who did not play.
+func (s *Season) Standings(matches []core.Match) []Standing {
+	type tally struct {
+		standing Standing
+		places   int
+	}
+
+	participants := make(map[core.UserID]bool, len(s.Participants))
+	tallies := make(map[core.UserID]*tally, len(s.Participants))
+	for _, id := range s.Participants {
+		participants[id] = true
+		tallies[id] = &tally{standing: Standing{User: core.User{UserID: id}}}
+	}
+
+	for i := range matches {
+		m := &matches[i]
+		if !s.Contains(*m) || len(m.Players) < 2 {
+			continue
+		}
+
+		users := make(map[core.UserID]core.User, len(m.Players))
+		for _, p := range m.Players {
+			users[p.UserID] = p
+		}
+
+		cooperative := m.Scoreboard.ScoreUnit == core.ScoreUnitCooperative
+		for _, p := range m.Placements() {
+			if len(participants) > 0 && !participants[p.UserID] {
+				continue
+			}
+
+			t, ok := tallies[p.UserID]
+			if !ok {
+				t = &tally{}
+				tallies[p.UserID] = t
+			}
+			t.standing.User = users[p.UserID]
+			t.standing.Points += s.Points.Points(p, len(m.Players), cooperative)
+			t.standing.Matches++
+			t.places += p.Place
+			if p.Won {
+				t.standing.Wins++
+			}
+		}
+	}
+
+	standings := make([]Standing, 0, len(tallies))
+	for _, t := range tallies {
+		if t.standing.Matches > 0 {
+			t.standing.AveragePlacement = float64(t.places) / float64(t.standing.Matches)
+		}
+		standings = append(standings, t.standing)
+	}
+
+	slices.SortFunc(standings, func(a, b Standing) int {
+		return cmp.Or(compareStandings(a, b), cmp.Compare(a.User.UserID.String(), b.User.UserID.String()))
+	})
+
+	for i := range standings {
+		if i > 0 && compareStandings(standings[i-1], standings[i]) == 0 {
+			standings[i].Position = standings[i-1].Position
+		} else {
+			standings[i].Position = i + 1
+		}
+	}
+
+	return standings
+}
@@ -0,0 +1,176 @@
+package season_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/season"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	azul   = core.Game{GameID: core.NewGameID(), BGGID: 230802, Name: "Azul"}
+	spring = season.Season{
+		Name:     "Spring 2025",
+		StartsAt: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+		EndsAt:   time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC),
+	}
+)
+
+func newPlayers(names ...string) []core.User {
+	players := make([]core.User, 0, len(names))
+	for _, name := range names {
+		players = append(players, core.User{UserID: core.NewUserID(), Username: name})
+	}
+	return players
+}
+
+// newFinalizedMatch returns a points match that ended the given number of
+// days into the season, scoring the players in order.
+func newFinalizedMatch(day int, players []core.User, values ...float64) core.Match {
+	scores := make([]core.Score, 0, len(players))
+	for i, p := range players {
+		scores = append(scores, core.Score{UserID: p.UserID, Value: values[i]})
+	}
+	endedAt := spring.StartsAt.AddDate(0, 0, day).Add(22 * time.Hour)
+	return core.Match{
+		MatchID:    core.NewMatchID(),
+		Status:     core.MatchStatusFinalized,
+		Game:       azul,
+		Players:    players,
+		StartedAt:  endedAt.Add(-time.Hour),
+		EndedAt:    endedAt,
+		Scoreboard: core.Scoreboard{Scores: scores, ScoreUnit: core.ScoreUnitPoints},
+	}
+}
+
+func TestSeasonValidate(t *testing.T) {
+	t.Parallel()
+	userID := core.NewUserID()
+
+	tests := []struct {
+		name   string
+		modify func(s *season.Season)
+		fields []string
+	}{
+		{name: "valid", modify: func(s *season.Season) {}},
+		{name: "missing name", modify: func(s *season.Season) { s.Name = "" }, fields: []string{"name"}},
+		{name: "missing range", modify: func(s *season.Season) { s.StartsAt, s.EndsAt = time.Time{}, time.Time{} }, fields: []string{"starts_at", "ends_at"}},
+		{name: "ends before start", modify: func(s *season.Season) { s.EndsAt = s.StartsAt }, fields: []string{"ends_at"}},
+		{
+			name:   "duplicate participant",
+			modify: func(s *season.Season) { s.Participants = []core.UserID{userID, {}, userID} },
+			fields: []string{"participants[1]", "participants[2]"},
+		},
+		{
+			name: "negative points",
+			modify: func(s *season.Season) {
+				s.Points = season.PointSystem{Placements: []float64{5, -1}, Participation: -1}
+			},
+			fields: []string{"points.placements[1]", "points.participation"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := spring
+			tt.modify(&s)
+
+			err := s.Validate()
+			if len(tt.fields) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var verr *core.ValidationError
+			require.True(t, errors.As(err, &verr))
+			fields := make([]string, 0, len(verr.Errors))
+			for _, fe := range verr.Errors {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
+
+func TestPointSystemPoints(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		system      season.PointSystem
+		placement   core.Placement
+		players     int
+		cooperative bool
+		want        float64
+	}{
+		{name: "first", placement: core.Placement{Place: 1, Won: true}, players: 4, want: 3},
+		{name: "third", placement: core.Placement{Place: 3}, players: 4, want: 1},
+		{name: "beyond points", placement: core.Placement{Place: 4}, players: 4, want: 0},
+		{name: "custom", system: season.PointSystem{Placements: []float64{10, 6}, Participation: 1}, placement: core.Placement{Place: 2}, players: 3, want: 7},
+		{name: "weighted duel", system: season.PointSystem{WeightByPlayerCount: true}, placement: core.Placement{Place: 1, Won: true}, players: 2, want: 3},
+		{name: "weighted crowd", system: season.PointSystem{WeightByPlayerCount: true}, placement: core.Placement{Place: 1, Won: true}, players: 5, want: 12},
+		{name: "cooperative win", placement: core.Placement{Place: 1, Won: true}, players: 3, cooperative: true, want: 3},
+		{name: "cooperative loss", placement: core.Placement{Place: 1, Tied: true}, players: 3, cooperative: true, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.InDelta(t, tt.want, tt.system.Points(tt.placement, tt.players, tt.cooperative), 1e-9)
+		})
+	}
+}
+
+func TestSeasonStandings(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob", "carol", "dave")
+	alice, bob, carol, dave := players[0], players[1], players[2], players[3]
+
+	before := newFinalizedMatch(-1, players[:2], 1, 9)
+	inProgress := newFinalizedMatch(5, players[:2], 1, 9)
+	inProgress.Status = core.MatchStatusInProgress
+	solo := newFinalizedMatch(6, players[1:2], 100)
+
+	matches := []core.Match{
+		newFinalizedMatch(0, players[:3], 30, 20, 10), // alice 3, bob 2, carol 1
+		newFinalizedMatch(1, players[:3], 20, 30, 10), // bob 3, alice 2, carol 1
+		newFinalizedMatch(2, players[2:], 10, 10),     // carol and dave tied, 3 each
+		before, inProgress, solo,
+		newFinalizedMatch(92, players[:2], 1, 9), // after the season
+	}
+
+	standings := spring.Standings(matches)
+	require.Len(t, standings, 4)
+
+	// alice and bob are tied on points, wins and average placement, carol
+	// has as many points and wins but placed worse on average
+	assert.Equal(t, 1, standings[0].Position)
+	assert.Equal(t, 1, standings[1].Position)
+	assert.ElementsMatch(t, []core.User{alice, bob}, []core.User{standings[0].User, standings[1].User})
+	assert.Equal(t, season.Standing{User: carol, Position: 3, Points: 5, Wins: 1, AveragePlacement: 7.0 / 3, Matches: 3}, standings[2])
+	assert.Equal(t, season.Standing{User: dave, Position: 4, Points: 3, Wins: 1, AveragePlacement: 1, Matches: 1}, standings[3])
+}
+
+func TestSeasonStandingsParticipants(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob", "carol")
+	guest := newPlayers("guest")[0]
+
+	s := spring
+	s.Participants = []core.UserID{players[0].UserID, players[1].UserID, players[2].UserID}
+	s.Points = season.PointSystem{WeightByPlayerCount: true}
+
+	standings := s.Standings([]core.Match{
+		newFinalizedMatch(0, []core.User{guest, players[0], players[1]}, 50, 40, 30),
+	})
+
+	require.Len(t, standings, 3, "guests are not ranked, idle participants are")
+	assert.Equal(t, season.Standing{User: players[0], Position: 1, Points: 4, Wins: 0, AveragePlacement: 2, Matches: 1}, standings[0])
+	assert.Equal(t, season.Standing{User: players[1], Position: 2, Points: 2, Wins: 0, AveragePlacement: 3, Matches: 1}, standings[1])
+	assert.Equal(t, season.Standing{User: core.User{UserID: players[2].UserID}, Position: 3}, standings[2])
+}
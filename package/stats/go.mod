@@ -0,0 +1,5 @@
+module github.com/ngoldack/dicetrace/package/stats
+
+go 1.25.3
+
+require github.com/stretchr/testify v1.11.1
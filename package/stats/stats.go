@@ -0,0 +1,285 @@
+// Package stats aggregates per-player statistics from finalized matches:
+// win rates, placements, streaks, head-to-head records and the games and
+// categories a player plays the most.
+package stats
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+)
+
+// Record summarizes a number of played matches.
+type Record struct {
+	Plays int `json:"plays"`
+	Wins  int `json:"wins"`
+	// WinRate is the share of plays won, between 0 and 1.
+	WinRate float64 `json:"win_rate"`
+	// AveragePlacement is the mean 1-based place finished in.
+	AveragePlacement float64 `json:"average_placement"`
+}
+
+// GameStats is a player's record in a game.
+type GameStats struct {
+	Game core.Game `json:"game"`
+	Record
+}
+
+// CategoryStats is a player's record in the games of a category.
+type CategoryStats struct {
+	Category string `json:"category"`
+	Record
+}
+
+// HeadToHead is a player's record against an opponent in the matches both
+// played on opposing sides.
+type HeadToHead struct {
+	Opponent core.User `json:"opponent"`
+	Matches  int       `json:"matches"`
+	// Wins, Losses and Ties count finishing ahead of, behind or level with
+	// the opponent.
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+	Ties   int `json:"ties"`
+}
+
+// PlayerStats are a player's statistics across all matches. Games,
+// categories and head-to-head records are ordered most played first.
+type PlayerStats struct {
+	User core.User `json:"user"`
+	Record
+
+	// CurrentStreak is the number of matches won in a row up to the last
+	// one played; LongestStreak is the most ever won in a row.
+	CurrentStreak int       `json:"current_streak"`
+	LongestStreak int       `json:"longest_streak"`
+	LastPlayed    time.Time `json:"last_played,omitzero"`
+
+	Games      []GameStats     `json:"games"`
+	Categories []CategoryStats `json:"categories"`
+	HeadToHead []HeadToHead    `json:"head_to_head"`
+}
+
+// Favourite returns the player's most played game.
+func (s PlayerStats) Favourite() (GameStats, bool) {
+	if len(s.Games) == 0 {
+		return GameStats{}, false
+	}
+	return s.Games[0], true
+}
+
+// Nemesis returns the opponent the player lost to the most, ties broken by
+// fewest wins against them. Players who never lost have no nemesis.
+func (s PlayerStats) Nemesis() (HeadToHead, bool) {
+	var nemesis HeadToHead
+	for _, h := range s.HeadToHead {
+		if h.Losses > nemesis.Losses || (h.Losses == nemesis.Losses && h.Losses > 0 && h.Wins < nemesis.Wins) {
+			nemesis = h
+		}
+	}
+	return nemesis, nemesis.Losses > 0
+}
+
+// record accumulates a Record.
+type record struct {
+	plays, wins, places int
+}
+
+func (r *record) add(p core.Placement) {
+	r.plays++
+	r.places += p.Place
+	if p.Won {
+		r.wins++
+	}
+}
+
+func (r record) snapshot() Record {
+	if r.plays == 0 {
+		return Record{}
+	}
+	return Record{
+		Plays:            r.plays,
+		Wins:             r.wins,
+		WinRate:          float64(r.wins) / float64(r.plays),
+		AveragePlacement: float64(r.places) / float64(r.plays),
+	}
+}
+
+type gameRecord struct {
+	game core.Game
+	record
+}
+
+type player struct {
+	user core.User
+	record
+
+	currentStreak, longestStreak int
+	lastPlayed                   time.Time
+
+	games      map[string]*gameRecord
+	categories map[string]*record
+	opponents  map[core.UserID]*HeadToHead
+}
+
+// Aggregator aggregates player statistics from finalized matches in
+// chronological order. Teammates are not recorded against each other and
+// neither are the players of cooperative games, who win or lose together.
+// An Aggregator is not safe for concurrent use.
+type Aggregator struct {
+	players map[core.UserID]*player
+	matches core.MatchSequence
+}
+
+func NewAggregator() *Aggregator {
+	return &Aggregator{
+		players: make(map[core.UserID]*player),
+	}
+}
+
+// Recompute aggregates the matches from scratch in chronological order.
+// Matches in progress are skipped.
+func Recompute(matches []core.Match) (*Aggregator, error) {
+	a := NewAggregator()
+	for _, m := range core.Chronological(matches) {
+		if err := a.Apply(m); err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// Apply adds the finalized match to the statistics. Matches must be applied
+// once each in chronological order, see core.MatchSequence. Streaks depend
+// on the order of matches, so use Recompute to aggregate the full history
+// instead.
+func (a *Aggregator) Apply(match core.Match) error {
+	if err := a.matches.Next(&match); err != nil {
+		return err
+	}
+	at := match.PlayedAt()
+
+	users := make(map[core.UserID]core.User, len(match.Players))
+	for _, p := range match.Players {
+		users[p.UserID] = p
+	}
+
+	placements := match.Placements()
+	cooperative := match.Scoreboard.ScoreUnit == core.ScoreUnitCooperative
+	key := match.Game.Key()
+
+	for _, p := range placements {
+		pl := a.player(users[p.UserID])
+		pl.add(p)
+		pl.lastPlayed = at
+
+		if p.Won {
+			pl.currentStreak++
+			pl.longestStreak = max(pl.longestStreak, pl.currentStreak)
+		} else {
+			pl.currentStreak = 0
+		}
+
+		game, ok := pl.games[key]
+		if !ok {
+			game = &gameRecord{}
+			pl.games[key] = game
+		}
+		game.game = match.Game
+		game.add(p)
+
+		for _, category := range slices.Compact(slices.Sorted(slices.Values(match.Game.Categories))) {
+			c, ok := pl.categories[category]
+			if !ok {
+				c = &record{}
+				pl.categories[category] = c
+			}
+			c.add(p)
+		}
+
+		if cooperative {
+			continue
+		}
+		for _, opp := range placements {
+			if opp.UserID == p.UserID || (!p.TeamID.IsZero() && p.TeamID == opp.TeamID) {
+				continue
+			}
+			h, ok := pl.opponents[opp.UserID]
+			if !ok {
+				h = &HeadToHead{}
+				pl.opponents[opp.UserID] = h
+			}
+			h.Opponent = users[opp.UserID]
+			h.Matches++
+			switch {
+			case p.Place < opp.Place:
+				h.Wins++
+			case p.Place > opp.Place:
+				h.Losses++
+			default:
+				h.Ties++
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *Aggregator) player(user core.User) *player {
+	pl, ok := a.players[user.UserID]
+	if !ok {
+		pl = &player{
+			games:      make(map[string]*gameRecord),
+			categories: make(map[string]*record),
+			opponents:  make(map[core.UserID]*HeadToHead),
+		}
+		a.players[user.UserID] = pl
+	}
+	pl.user = user
+	return pl
+}
+
+// Player returns the player's statistics, if they played any match.
+func (a *Aggregator) Player(userID core.UserID) (PlayerStats, bool) {
+	pl, ok := a.players[userID]
+	if !ok {
+		return PlayerStats{}, false
+	}
+
+	s := PlayerStats{
+		User:          pl.user,
+		Record:        pl.snapshot(),
+		CurrentStreak: pl.currentStreak,
+		LongestStreak: pl.longestStreak,
+		LastPlayed:    pl.lastPlayed,
+		Games:         make([]GameStats, 0, len(pl.games)),
+		Categories:    make([]CategoryStats, 0, len(pl.categories)),
+		HeadToHead:    make([]HeadToHead, 0, len(pl.opponents)),
+	}
+
+	for _, g := range pl.games {
+		s.Games = append(s.Games, GameStats{Game: g.game, Record: g.snapshot()})
+	}
+	slices.SortFunc(s.Games, func(a, b GameStats) int {
+		return cmp.Or(cmp.Compare(b.Plays, a.Plays), cmp.Compare(a.Game.Name, b.Game.Name), cmp.Compare(a.Game.Key(), b.Game.Key()))
+	})
+
+	for category, c := range pl.categories {
+		s.Categories = append(s.Categories, CategoryStats{Category: category, Record: c.snapshot()})
+	}
+	slices.SortFunc(s.Categories, func(a, b CategoryStats) int {
+		return cmp.Or(cmp.Compare(b.Plays, a.Plays), cmp.Compare(a.Category, b.Category))
+	})
+
+	for _, h := range pl.opponents {
+		s.HeadToHead = append(s.HeadToHead, *h)
+	}
+	slices.SortFunc(s.HeadToHead, func(a, b HeadToHead) int {
+		return cmp.Or(cmp.Compare(b.Matches, a.Matches), cmp.Compare(a.Opponent.UserID.String(), b.Opponent.UserID.String()))
+	})
+
+	return s, true
+}
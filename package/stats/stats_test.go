@@ -0,0 +1,176 @@
+package stats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	azul        = core.Game{GameID: core.NewGameID(), BGGID: 230802, Name: "Azul", Categories: []string{"Abstract Strategy", "Puzzle"}}
+	carcassonne = core.Game{GameID: core.NewGameID(), BGGID: 822, Name: "Carcassonne", Categories: []string{"Medieval", "Territory Building"}}
+	pandemic    = core.Game{GameID: core.NewGameID(), BGGID: 30549, Name: "Pandemic", Categories: []string{"Medical"}}
+	start       = time.Date(2025, time.March, 6, 19, 0, 0, 0, time.UTC)
+)
+
+func newPlayers(names ...string) []core.User {
+	players := make([]core.User, 0, len(names))
+	for _, name := range names {
+		players = append(players, core.User{UserID: core.NewUserID(), Username: name})
+	}
+	return players
+}
+
+// newFinalizedMatch returns a points match of the game that ended the given
+// number of hours after start, scoring the players in order.
+func newFinalizedMatch(game core.Game, hour int, players []core.User, values ...float64) core.Match {
+	scores := make([]core.Score, 0, len(players))
+	for i, p := range players {
+		scores = append(scores, core.Score{UserID: p.UserID, Value: values[i]})
+	}
+	endedAt := start.Add(time.Duration(hour) * time.Hour)
+	return core.Match{
+		MatchID:    core.NewMatchID(),
+		Status:     core.MatchStatusFinalized,
+		Game:       game,
+		Players:    players,
+		StartedAt:  endedAt.Add(-time.Hour),
+		EndedAt:    endedAt,
+		Scoreboard: core.Scoreboard{Scores: scores, ScoreUnit: core.ScoreUnitPoints},
+	}
+}
+
+func mustPlayer(t *testing.T, a *stats.Aggregator, user core.User) stats.PlayerStats {
+	t.Helper()
+	s, ok := a.Player(user.UserID)
+	require.True(t, ok, "player %s has no statistics", user.Username)
+	return s
+}
+
+func TestAggregator(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob", "carol")
+	alice, bob, carol := players[0], players[1], players[2]
+
+	a := stats.NewAggregator()
+	for _, m := range []core.Match{
+		newFinalizedMatch(azul, 0, players, 70, 50, 30),
+		newFinalizedMatch(azul, 1, players, 60, 60, 20),
+		newFinalizedMatch(carcassonne, 2, players[:2], 80, 95),
+		newFinalizedMatch(azul, 3, players, 90, 40, 41),
+	} {
+		require.NoError(t, a.Apply(m))
+	}
+
+	s := mustPlayer(t, a, alice)
+	assert.Equal(t, alice, s.User)
+	assert.Equal(t, stats.Record{Plays: 4, Wins: 3, WinRate: 0.75, AveragePlacement: 1.25}, s.Record)
+	assert.Equal(t, 1, s.CurrentStreak)
+	assert.Equal(t, 2, s.LongestStreak)
+	assert.Equal(t, start.Add(3*time.Hour), s.LastPlayed)
+
+	require.Len(t, s.Games, 2)
+	assert.Equal(t, azul, s.Games[0].Game)
+	assert.Equal(t, stats.Record{Plays: 3, Wins: 3, WinRate: 1, AveragePlacement: 1}, s.Games[0].Record)
+	favourite, ok := s.Favourite()
+	require.True(t, ok)
+	assert.Equal(t, azul, favourite.Game)
+
+	require.Len(t, s.Categories, 4)
+	assert.Equal(t, "Abstract Strategy", s.Categories[0].Category)
+	assert.Equal(t, 3, s.Categories[0].Plays)
+	assert.Equal(t, "Medieval", s.Categories[2].Category)
+
+	require.Len(t, s.HeadToHead, 2)
+	assert.Equal(t, stats.HeadToHead{Opponent: bob, Matches: 4, Wins: 2, Losses: 1, Ties: 1}, s.HeadToHead[0])
+	assert.Equal(t, stats.HeadToHead{Opponent: carol, Matches: 3, Wins: 3}, s.HeadToHead[1])
+	nemesis, ok := s.Nemesis()
+	require.True(t, ok)
+	assert.Equal(t, bob, nemesis.Opponent)
+
+	s = mustPlayer(t, a, bob)
+	assert.Equal(t, 2, s.Wins, "tied first places are wins")
+	assert.Equal(t, 0, s.CurrentStreak)
+	assert.Equal(t, 2, s.LongestStreak)
+
+	s = mustPlayer(t, a, carol)
+	assert.InDelta(t, 8.0/3, s.AveragePlacement, 1e-9)
+	_, ok = s.Nemesis()
+	assert.True(t, ok)
+}
+
+func TestAggregatorTeamsAndCooperative(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob", "carol", "dave")
+	red := core.Team{TeamID: core.NewTeamID(), Name: "Red", Members: []core.UserID{players[0].UserID, players[1].UserID}}
+	blue := core.Team{TeamID: core.NewTeamID(), Name: "Blue", Members: []core.UserID{players[2].UserID, players[3].UserID}}
+
+	teams := newFinalizedMatch(carcassonne, 0, players, 0, 0, 0, 0)
+	teams.Teams = []core.Team{red, blue}
+	teams.Scoreboard.Scores = []core.Score{}
+	teams.Scoreboard.TeamScores = []core.TeamScore{{TeamID: red.TeamID, Value: 8}, {TeamID: blue.TeamID, Value: 5}}
+	require.NoError(t, teams.Validate())
+
+	coop := newFinalizedMatch(pandemic, 1, players[:2], core.ScoreWin, core.ScoreWin)
+	coop.Scoreboard.ScoreUnit = core.ScoreUnitCooperative
+
+	a := stats.NewAggregator()
+	require.NoError(t, a.Apply(teams))
+	require.NoError(t, a.Apply(coop))
+
+	s := mustPlayer(t, a, players[0])
+	assert.Equal(t, 2, s.Wins)
+	assert.Equal(t, 2, s.CurrentStreak)
+	require.Len(t, s.HeadToHead, 2, "teammates are not recorded against each other")
+	for _, h := range s.HeadToHead {
+		assert.NotEqual(t, players[1], h.Opponent)
+		assert.Equal(t, 1, h.Wins)
+	}
+
+	s = mustPlayer(t, a, players[2])
+	assert.Equal(t, 0, s.Wins)
+	_, ok := s.Favourite()
+	assert.True(t, ok)
+}
+
+func TestAggregatorApplyErrors(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob")
+	a := stats.NewAggregator()
+
+	later := newFinalizedMatch(azul, 1, players, 3, 2)
+	require.NoError(t, a.Apply(later))
+
+	assert.ErrorIs(t, a.Apply(later), core.ErrMatchApplied)
+	assert.ErrorIs(t, a.Apply(newFinalizedMatch(azul, 0, players, 3, 2)), core.ErrMatchOutOfOrder)
+
+	inProgress := newFinalizedMatch(azul, 2, players, 3, 2)
+	inProgress.Status = core.MatchStatusInProgress
+	assert.ErrorIs(t, a.Apply(inProgress), core.ErrMatchNotFinalized)
+
+	_, ok := a.Player(core.NewUserID())
+	assert.False(t, ok)
+}
+
+func TestRecompute(t *testing.T) {
+	t.Parallel()
+	players := newPlayers("alice", "bob")
+	history := []core.Match{
+		newFinalizedMatch(azul, 0, players, 3, 2),
+		newFinalizedMatch(azul, 1, players, 1, 2),
+		newFinalizedMatch(carcassonne, 2, players, 5, 4),
+		newFinalizedMatch(carcassonne, 3, players, 6, 4),
+	}
+
+	recomputed, err := stats.Recompute([]core.Match{history[3], history[1], history[0], history[2]})
+	require.NoError(t, err)
+
+	s := mustPlayer(t, recomputed, players[0])
+	assert.Equal(t, 3, s.Wins)
+	assert.Equal(t, 2, s.CurrentStreak, "streaks follow the order matches were played in")
+	assert.Equal(t, 2, s.LongestStreak)
+}
@@ -0,0 +1,245 @@
+package tournament
+
+import (
+	"cmp"
+	"math/bits"
+	"slices"
+)
+
+// maxPairingSteps caps the search for a Swiss round without rematches.
+const maxPairingSteps = 100_000
+
+// rounds returns the number of rounds of a Swiss or round-robin tournament.
+func (t *Tournament) rounds() int {
+	n := len(t.Players)
+	switch t.Format {
+	case FormatSwiss:
+		if t.SwissRounds > 0 {
+			return t.SwissRounds
+		}
+		return bits.Len(uint(n - 1))
+	case FormatRoundRobin:
+		// every player sits out one round if the number is odd
+		return n - 1 + n%2
+	default:
+		return 0
+	}
+}
+
+// pairSwiss ranks the players by points, then seed, and pairs each with the
+// highest ranked player they have not played yet, as in the Monrad system.
+// If the number of players is odd, the lowest ranked player without a bye
+// sits out.
+func (t *Tournament) pairSwiss(rounds [][]outcome) [][]int {
+	if len(t.Rounds) >= t.rounds() {
+		return nil
+	}
+
+	records := t.records(rounds)
+	ranked := make([]int, len(t.Players))
+	for p := range ranked {
+		ranked[p] = p
+	}
+	slices.SortStableFunc(ranked, func(a, b int) int {
+		return cmp.Compare(records[b].points, records[a].points)
+	})
+
+	var bye []int
+	if len(ranked)%2 == 1 {
+		i := len(ranked) - 1
+		for j := i; j >= 0; j-- {
+			if records[ranked[j]].byes == 0 {
+				i = j
+				break
+			}
+		}
+		bye = []int{ranked[i]}
+		ranked = slices.Delete(ranked, i, i+1)
+	}
+
+	pairings, ok := pairUnplayed(ranked, records)
+	if !ok {
+		// every pairing has a rematch: pair neighbours in rank
+		pairings = pairings[:0]
+		for i := 0; i < len(ranked); i += 2 {
+			pairings = append(pairings, []int{ranked[i], ranked[i+1]})
+		}
+	}
+	if bye != nil {
+		pairings = append(pairings, bye)
+	}
+
+	return pairings
+}
+
+// pairUnplayed pairs the ranked players by backtracking so no two players
+// meet twice, preferring opponents close in rank.
+func pairUnplayed(ranked []int, records []record) ([][]int, bool) {
+	pairings := make([][]int, 0, len(ranked)/2)
+	paired := make([]bool, len(ranked))
+	steps := 0
+
+	var pair func() bool
+	pair = func() bool {
+		first := slices.Index(paired, false)
+		if first == -1 {
+			return true
+		}
+		steps++
+		if steps > maxPairingSteps {
+			return false
+		}
+
+		a := ranked[first]
+		paired[first] = true
+		for i := first + 1; i < len(ranked); i++ {
+			b := ranked[i]
+			if paired[i] || records[a].played(b) {
+				continue
+			}
+			paired[i] = true
+			pairings = append(pairings, []int{a, b})
+			if pair() {
+				return true
+			}
+			pairings = pairings[:len(pairings)-1]
+			paired[i] = false
+		}
+		paired[first] = false
+		return false
+	}
+
+	return pairings, pair()
+}
+
+// pairRoundRobin pairs the players by the circle method: the first seed
+// stays in place while the others rotate, so every player meets every other
+// once.
+func (t *Tournament) pairRoundRobin() [][]int {
+	round := len(t.Rounds)
+	if round >= t.rounds() {
+		return nil
+	}
+
+	// an odd number of players is evened out by a bye slot
+	slots := make([]int, 0, len(t.Players)+1)
+	for p := range t.Players {
+		slots = append(slots, p)
+	}
+	if len(slots)%2 == 1 {
+		slots = append(slots, -1)
+	}
+
+	rest := slots[1:]
+	shift := round % len(rest)
+	circle := append([]int{slots[0]}, append(slices.Clone(rest[len(rest)-shift:]), rest[:len(rest)-shift]...)...)
+
+	pairings := make([][]int, 0, len(circle)/2)
+	var bye []int
+	for i := range len(circle) / 2 {
+		a, b := circle[i], circle[len(circle)-1-i]
+		switch {
+		case a == -1:
+			bye = []int{b}
+		case b == -1:
+			bye = []int{a}
+		default:
+			pairings = append(pairings, []int{a, b})
+		}
+	}
+	if bye != nil {
+		pairings = append(pairings, bye)
+	}
+
+	return pairings
+}
+
+// lives is the number of losses that eliminate a player.
+func (f Format) lives() int {
+	if f == FormatDoubleElimination {
+		return 2
+	}
+	return 1
+}
+
+// pairElimination seeds the first round into a bracket, giving the top
+// seeds byes up to the next power of two. Later rounds pair the players of
+// each bracket in bracket order: undefeated players in the winners bracket,
+// in double elimination players with one loss in the losers bracket. Once a
+// single player is left in each bracket they meet in the grand final, which
+// is replayed if the losers bracket player wins it.
+func (t *Tournament) pairElimination(rounds [][]outcome) [][]int {
+	if len(rounds) == 0 {
+		return t.seedBracket()
+	}
+
+	losses := make([]int, len(t.Players))
+	for _, round := range rounds {
+		for _, o := range round {
+			if p, ok := o.loser(); ok {
+				losses[p]++
+			}
+		}
+	}
+
+	// every remaining player took part in the last round, bye or not
+	var winners, losers []int
+	for _, o := range rounds[len(rounds)-1] {
+		for _, p := range o.players {
+			switch {
+			case losses[p] >= t.Format.lives():
+			case losses[p] == 0:
+				winners = append(winners, p)
+			default:
+				losers = append(losers, p)
+			}
+		}
+	}
+
+	switch {
+	case len(winners)+len(losers) <= 1:
+		return nil
+	case len(winners) == 1 && len(losers) == 1:
+		return [][]int{{winners[0], losers[0]}}
+	}
+
+	return append(pairAdjacent(winners), pairAdjacent(losers)...)
+}
+
+// pairAdjacent pairs neighbouring players; the last one of an odd number
+// gets a bye.
+func pairAdjacent(players []int) [][]int {
+	pairings := make([][]int, 0, (len(players)+1)/2)
+	for i := 0; i < len(players); i += 2 {
+		if i+1 == len(players) {
+			pairings = append(pairings, []int{players[i]})
+			break
+		}
+		pairings = append(pairings, []int{players[i], players[i+1]})
+	}
+	return pairings
+}
+
+// seedBracket pairs the first round so the top seeds can only meet late,
+// e.g. 1-8, 4-5, 2-7 and 3-6 for eight players.
+func (t *Tournament) seedBracket() [][]int {
+	seeds := []int{1}
+	for len(seeds) < len(t.Players) {
+		next := make([]int, 0, len(seeds)*2)
+		for _, s := range seeds {
+			next = append(next, s, len(seeds)*2+1-s)
+		}
+		seeds = next
+	}
+
+	pairings := make([][]int, 0, len(seeds)/2)
+	for i := 0; i < len(seeds); i += 2 {
+		a, b := seeds[i]-1, seeds[i+1]-1
+		if b >= len(t.Players) {
+			pairings = append(pairings, []int{a})
+			continue
+		}
+		pairings = append(pairings, []int{a, b})
+	}
+	return pairings
+}
@@ -0,0 +1,168 @@
+package tournament
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/ngoldack/dicetrace/package/core"
+)
+
+// Points scored per pairing. A bye scores like a win.
+const (
+	pointsWin  = 1
+	pointsDraw = 0.5
+)
+
+// record is a player's results so far.
+type record struct {
+	points               float64
+	wins, draws, losses  int
+	byes                 int
+	eliminatedIn         int
+	beaten, drawn, faced []int
+}
+
+func (r record) played(p int) bool {
+	return slices.Contains(r.faced, p)
+}
+
+// records tallies the results of the rounds per player index.
+func (t *Tournament) records(rounds [][]outcome) []record {
+	records := make([]record, len(t.Players))
+	losses := make([]int, len(t.Players))
+
+	for r, round := range rounds {
+		for _, o := range round {
+			if len(o.players) == 1 {
+				p := o.players[0]
+				records[p].byes++
+				records[p].points += pointsWin
+				continue
+			}
+
+			a, b := o.players[0], o.players[1]
+			records[a].faced = append(records[a].faced, b)
+			records[b].faced = append(records[b].faced, a)
+
+			loser, ok := o.loser()
+			if !ok {
+				for _, p := range [][2]int{{a, b}, {b, a}} {
+					records[p[0]].draws++
+					records[p[0]].points += pointsDraw
+					records[p[0]].drawn = append(records[p[0]].drawn, p[1])
+				}
+				continue
+			}
+
+			records[o.winner].wins++
+			records[o.winner].points += pointsWin
+			records[o.winner].beaten = append(records[o.winner].beaten, loser)
+			records[loser].losses++
+
+			losses[loser]++
+			if t.Format.elimination() && losses[loser] == t.Format.lives() {
+				records[loser].eliminatedIn = r + 1
+			}
+		}
+	}
+
+	return records
+}
+
+// Standing is a player's position in the tournament.
+type Standing struct {
+	User core.User `json:"user"`
+	// Position is the 1-based standing. Players tied on points and all
+	// tiebreakers share a position and the following positions are
+	// skipped, e.g. 1, 2, 2, 4.
+	Position int     `json:"position"`
+	Points   float64 `json:"points"`
+
+	Wins   int `json:"wins"`
+	Draws  int `json:"draws"`
+	Losses int `json:"losses"`
+	Byes   int `json:"byes"`
+
+	// Buchholz is the sum of the points of the player's opponents.
+	Buchholz float64 `json:"buchholz"`
+	// SonnebornBerger is the sum of the points of the opponents the player
+	// beat, plus half of the points of those the player drew against.
+	SonnebornBerger float64 `json:"sonneborn_berger"`
+
+	// EliminatedIn is the 1-based round the player was knocked out in, or
+	// zero for players still in an elimination tournament.
+	EliminatedIn int `json:"eliminated_in,omitempty"`
+}
+
+// compareStandings orders better standings first. In elimination
+// tournaments players still in come first, then players knocked out later.
+// Ties are broken by points, Buchholz, Sonneborn-Berger and wins, in this
+// order.
+func compareStandings(a, b Standing) int {
+	return cmp.Or(
+		compareElimination(a.EliminatedIn, b.EliminatedIn),
+		cmp.Compare(b.Points, a.Points),
+		cmp.Compare(b.Buchholz, a.Buchholz),
+		cmp.Compare(b.SonnebornBerger, a.SonnebornBerger),
+		cmp.Compare(b.Wins, a.Wins),
+	)
+}
+
+func compareElimination(a, b int) int {
+	switch {
+	case a == b:
+		return 0
+	case a == 0:
+		return -1
+	case b == 0:
+		return 1
+	default:
+		return cmp.Compare(b, a)
+	}
+}
+
+// Standings ranks the players by the results of the rounds completed so
+// far; a round counts once all of its matches are finalized. Players tied
+// on everything are listed in seed order.
+func (t *Tournament) Standings(results []core.Match) ([]Standing, error) {
+	rounds, _, err := t.outcomes(results)
+	if err != nil {
+		return nil, err
+	}
+
+	records := t.records(rounds)
+	standings := make([]Standing, 0, len(t.Players))
+	for p, r := range records {
+		s := Standing{
+			User:         t.Players[p],
+			Points:       r.points,
+			Wins:         r.wins,
+			Draws:        r.draws,
+			Losses:       r.losses,
+			Byes:         r.byes,
+			EliminatedIn: r.eliminatedIn,
+		}
+		for _, o := range r.faced {
+			s.Buchholz += records[o].points
+		}
+		for _, o := range r.beaten {
+			s.SonnebornBerger += records[o].points
+		}
+		for _, o := range r.drawn {
+			s.SonnebornBerger += records[o].points / 2
+		}
+		standings = append(standings, s)
+	}
+
+	// the stable sort keeps tied players in seed order
+	slices.SortStableFunc(standings, compareStandings)
+	for i := range standings {
+		if i > 0 && compareStandings(standings[i-1], standings[i]) == 0 {
+			standings[i].Position = standings[i-1].Position
+		} else {
+			standings[i].Position = i + 1
+		}
+	}
+
+	return standings, nil
+}
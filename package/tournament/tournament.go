@@ -0,0 +1,250 @@
+// Package tournament runs tournaments of one game between players: Swiss
+// rounds, single and double elimination brackets and round-robins. Every
+// round is a set of duels, generated as core.Match records from the results
+// of the previous rounds.
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+)
+
+var (
+	// ErrRoundIncomplete is returned when starting a round before every
+	// match of the previous round is finalized.
+	ErrRoundIncomplete = errors.New("round is not complete")
+	// ErrComplete is returned when starting a round after the last one.
+	ErrComplete = errors.New("tournament is complete")
+	// ErrNoWinner is returned for a drawn elimination match.
+	ErrNoWinner = errors.New("elimination match has no winner")
+)
+
+type Format string
+
+const (
+	FormatSwiss             Format = "swiss"
+	FormatSingleElimination Format = "single_elimination"
+	FormatDoubleElimination Format = "double_elimination"
+	FormatRoundRobin        Format = "round_robin"
+)
+
+func (f Format) Valid() bool {
+	switch f {
+	case FormatSwiss, FormatSingleElimination, FormatDoubleElimination, FormatRoundRobin:
+		return true
+	default:
+		return false
+	}
+}
+
+// elimination reports whether players are knocked out of the tournament.
+func (f Format) elimination() bool {
+	return f == FormatSingleElimination || f == FormatDoubleElimination
+}
+
+// Pairing is a duel of a round. A pairing of a single player is a bye: the
+// player advances, and scores a win, without playing.
+type Pairing struct {
+	MatchID core.MatchID  `json:"match_id,omitzero"`
+	Players []core.UserID `json:"players"`
+}
+
+// Bye reports whether the pairing is a bye.
+func (p Pairing) Bye() bool {
+	return len(p.Players) == 1
+}
+
+type Round struct {
+	Pairings []Pairing `json:"pairings"`
+}
+
+// Tournament is run by an event, whose matches are the tournament's
+// duels. An event runs at most one tournament.
+type Tournament struct {
+	EventID core.EventID `json:"event_id"`
+	Format  Format       `json:"format"`
+	Game    core.Game    `json:"game"`
+	// Players are seeded in order, the strongest first.
+	Players []core.User `json:"players"`
+	// SwissRounds is the number of rounds of a Swiss tournament. Zero plays
+	// as many rounds as needed for a single undefeated player, the binary
+	// logarithm of the number of players rounded up.
+	SwissRounds int `json:"swiss_rounds,omitempty"`
+
+	Rounds []Round `json:"rounds"`
+}
+
+// New returns a tournament of the event that has not started yet.
+func New(eventID core.EventID, format Format, game core.Game, players []core.User) (*Tournament, error) {
+	t := &Tournament{
+		EventID: eventID,
+		Format:  format,
+		Game:    game,
+		Players: players,
+		Rounds:  []Round{},
+	}
+
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Validate reports every invalid field as a *core.ValidationError.
+func (t *Tournament) Validate() error {
+	verr := &core.ValidationError{}
+
+	if t.EventID.IsZero() {
+		verr.Add("event_id", "must be set")
+	}
+
+	if !t.Format.Valid() {
+		verr.Add("format", "unknown format %q", t.Format)
+	}
+
+	if len(t.Players) < 2 {
+		verr.Add("players", "must have at least 2 players")
+	}
+	seen := make(map[core.UserID]bool, len(t.Players))
+	for i, p := range t.Players {
+		field := fmt.Sprintf("players[%d]", i)
+		switch {
+		case p.UserID.IsZero():
+			verr.Add(field+".user_id", "must be set")
+		case seen[p.UserID]:
+			verr.Add(field, "duplicate player %s", p.UserID)
+		}
+		seen[p.UserID] = true
+	}
+
+	if t.SwissRounds < 0 {
+		verr.Add("swiss_rounds", "must not be negative")
+	} else if t.SwissRounds > 0 && t.Format != FormatSwiss {
+		verr.Add("swiss_rounds", "must only be set for the swiss format")
+	}
+
+	return verr.Err()
+}
+
+// NextRound pairs the players of the next round from the results of the
+// previous rounds and returns its matches, started at the given time.
+// Results may contain unrelated matches.
+func (t *Tournament) NextRound(results []core.Match, startedAt time.Time) ([]core.Match, error) {
+	rounds, complete, err := t.outcomes(results)
+	if err != nil {
+		return nil, err
+	}
+	if !complete {
+		return nil, fmt.Errorf("round %d: %w", len(t.Rounds), ErrRoundIncomplete)
+	}
+
+	var pairings [][]int
+	switch t.Format {
+	case FormatSwiss:
+		pairings = t.pairSwiss(rounds)
+	case FormatRoundRobin:
+		pairings = t.pairRoundRobin()
+	default:
+		pairings = t.pairElimination(rounds)
+	}
+	if len(pairings) == 0 {
+		return nil, ErrComplete
+	}
+
+	round := Round{Pairings: make([]Pairing, 0, len(pairings))}
+	matches := make([]core.Match, 0, len(pairings))
+	for _, players := range pairings {
+		pairing := Pairing{Players: make([]core.UserID, 0, len(players))}
+		users := make([]core.User, 0, len(players))
+		for _, p := range players {
+			pairing.Players = append(pairing.Players, t.Players[p].UserID)
+			users = append(users, t.Players[p])
+		}
+
+		if !pairing.Bye() {
+			match := core.StartMatch(t.Game, users, startedAt)
+			pairing.MatchID = match.MatchID
+			matches = append(matches, *match)
+		}
+		round.Pairings = append(round.Pairings, pairing)
+	}
+	t.Rounds = append(t.Rounds, round)
+
+	return matches, nil
+}
+
+// outcome is the result of a pairing: the winner, or -1 for a draw, by
+// player index. Byes are won by their player.
+type outcome struct {
+	players []int
+	winner  int
+}
+
+// loser returns the player who lost the duel, if any.
+func (o outcome) loser() (int, bool) {
+	if len(o.players) < 2 || o.winner == -1 {
+		return 0, false
+	}
+	if o.players[0] == o.winner {
+		return o.players[1], true
+	}
+	return o.players[0], true
+}
+
+// outcomes returns the outcome of every pairing per round, stopping at the
+// first round with a match that is missing or not finalized. complete
+// reports whether every round has finished.
+func (t *Tournament) outcomes(results []core.Match) (rounds [][]outcome, complete bool, err error) {
+	matches := make(map[core.MatchID]*core.Match, len(results))
+	for i := range results {
+		matches[results[i].MatchID] = &results[i]
+	}
+
+	index := make(map[core.UserID]int, len(t.Players))
+	for i, p := range t.Players {
+		index[p.UserID] = i
+	}
+
+	for r, round := range t.Rounds {
+		outcomes := make([]outcome, 0, len(round.Pairings))
+		for _, pairing := range round.Pairings {
+			o := outcome{winner: -1}
+			for _, id := range pairing.Players {
+				o.players = append(o.players, index[id])
+			}
+
+			if pairing.Bye() {
+				o.winner = o.players[0]
+				outcomes = append(outcomes, o)
+				continue
+			}
+
+			m, ok := matches[pairing.MatchID]
+			if !ok || !m.Finalized() {
+				return rounds, false, nil
+			}
+
+			won := make(map[core.UserID]bool, 2)
+			for _, p := range m.Placements() {
+				won[p.UserID] = p.Won
+			}
+			a, b := pairing.Players[0], pairing.Players[1]
+			switch {
+			case won[a] && !won[b]:
+				o.winner = o.players[0]
+			case won[b] && !won[a]:
+				o.winner = o.players[1]
+			case t.Format.elimination():
+				return nil, false, fmt.Errorf("round %d match '%s': %w", r+1, m.MatchID, ErrNoWinner)
+			}
+			outcomes = append(outcomes, o)
+		}
+		rounds = append(rounds, outcomes)
+	}
+
+	return rounds, true, nil
+}
@@ -0,0 +1,384 @@
+package tournament_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ngoldack/dicetrace/package/core"
+	"github.com/ngoldack/dicetrace/package/tournament"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	now   = time.Date(2025, time.April, 12, 14, 0, 0, 0, time.UTC)
+	chess = core.Game{GameID: core.NewGameID(), BGGID: 171, Name: "Chess"}
+)
+
+// newPlayers returns players p1 to pn, in seed order.
+func newPlayers(n int) []core.User {
+	users := make([]core.User, 0, n)
+	for i := range n {
+		users = append(users, core.User{UserID: core.NewUserID(), Username: fmt.Sprintf("p%d", i+1)})
+	}
+	return users
+}
+
+func newTournament(t *testing.T, format tournament.Format, n int) *tournament.Tournament {
+	t.Helper()
+	tr, err := tournament.New(core.NewEventID(), format, chess, newPlayers(n))
+	require.NoError(t, err)
+	return tr
+}
+
+// finish finalizes the duel, won by the player at the given index or drawn
+// if it is -1.
+func finish(t *testing.T, m *core.Match, winner int) {
+	t.Helper()
+	for i := range m.Scoreboard.Scores {
+		if i == winner || winner == -1 {
+			m.Scoreboard.Scores[i].Value = 1
+		}
+	}
+	require.NoError(t, m.Finalize(now.Add(time.Hour)))
+}
+
+// bySeed lets the higher seeded player win.
+func bySeed(tr *tournament.Tournament) func(m *core.Match) int {
+	seeds := make(map[core.UserID]int, len(tr.Players))
+	for i, p := range tr.Players {
+		seeds[p.UserID] = i
+	}
+	return func(m *core.Match) int {
+		if seeds[m.Players[0].UserID] < seeds[m.Players[1].UserID] {
+			return 0
+		}
+		return 1
+	}
+}
+
+// play starts the next round and finishes its matches.
+func play(t *testing.T, tr *tournament.Tournament, results []core.Match, winner func(m *core.Match) int) []core.Match {
+	t.Helper()
+	matches, err := tr.NextRound(results, now)
+	require.NoError(t, err)
+	for i := range matches {
+		finish(t, &matches[i], winner(&matches[i]))
+	}
+	return append(results, matches...)
+}
+
+// pairingNames returns the usernames of a round's pairings.
+func pairingNames(tr *tournament.Tournament, round tournament.Round) [][]string {
+	names := make(map[core.UserID]string, len(tr.Players))
+	for _, p := range tr.Players {
+		names[p.UserID] = p.Username
+	}
+
+	pairings := make([][]string, 0, len(round.Pairings))
+	for _, pairing := range round.Pairings {
+		var players []string
+		for _, id := range pairing.Players {
+			players = append(players, names[id])
+		}
+		pairings = append(pairings, players)
+	}
+	return pairings
+}
+
+func standingNames(standings []tournament.Standing) []string {
+	names := make([]string, 0, len(standings))
+	for _, s := range standings {
+		names = append(names, fmt.Sprintf("%d:%s", s.Position, s.User.Username))
+	}
+	return names
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	players := newPlayers(2)
+	eventID := core.NewEventID()
+
+	tests := []struct {
+		name   string
+		tr     tournament.Tournament
+		fields []string
+	}{
+		{
+			name: "valid",
+			tr:   tournament.Tournament{EventID: eventID, Format: tournament.FormatSwiss, Players: players, SwissRounds: 3},
+		},
+		{
+			name:   "missing event",
+			tr:     tournament.Tournament{Format: tournament.FormatSwiss, Players: players},
+			fields: []string{"event_id"},
+		},
+		{
+			name:   "unknown format",
+			tr:     tournament.Tournament{EventID: eventID, Format: "ladder", Players: players},
+			fields: []string{"format"},
+		},
+		{
+			name:   "too few players",
+			tr:     tournament.Tournament{EventID: eventID, Format: tournament.FormatRoundRobin, Players: players[:1]},
+			fields: []string{"players"},
+		},
+		{
+			name:   "invalid players",
+			tr:     tournament.Tournament{EventID: eventID, Format: tournament.FormatRoundRobin, Players: []core.User{players[0], players[0], {}}},
+			fields: []string{"players[1]", "players[2].user_id"},
+		},
+		{
+			name:   "swiss rounds of elimination",
+			tr:     tournament.Tournament{EventID: eventID, Format: tournament.FormatSingleElimination, Players: players, SwissRounds: 2},
+			fields: []string{"swiss_rounds"},
+		},
+		{
+			name:   "negative swiss rounds",
+			tr:     tournament.Tournament{EventID: eventID, Format: tournament.FormatSwiss, Players: players, SwissRounds: -1},
+			fields: []string{"swiss_rounds"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.tr.Validate()
+			if tt.fields == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var verr *core.ValidationError
+			require.ErrorAs(t, err, &verr)
+			fields := make([]string, 0, len(verr.Errors))
+			for _, fe := range verr.Errors {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	t.Parallel()
+	tr := newTournament(t, tournament.FormatRoundRobin, 5)
+
+	var results []core.Match
+	for range 5 {
+		results = play(t, tr, results, bySeed(tr))
+	}
+	_, err := tr.NextRound(results, now)
+	require.ErrorIs(t, err, tournament.ErrComplete)
+
+	met := make(map[[2]string]int)
+	byes := make(map[string]int)
+	for _, round := range tr.Rounds {
+		for _, pairing := range pairingNames(tr, round) {
+			if len(pairing) == 1 {
+				byes[pairing[0]]++
+				continue
+			}
+			met[[2]string{min(pairing[0], pairing[1]), max(pairing[0], pairing[1])}]++
+		}
+	}
+	assert.Len(t, met, 10, "every pair meets")
+	for pair, n := range met {
+		assert.Equal(t, 1, n, "%v meet once", pair)
+	}
+	assert.Equal(t, map[string]int{"p1": 1, "p2": 1, "p3": 1, "p4": 1, "p5": 1}, byes)
+
+	standings, err := tr.Standings(results)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1:p1", "2:p2", "3:p3", "4:p4", "5:p5"}, standingNames(standings))
+	assert.Equal(t, 5.0, standings[0].Points, "4 wins and a bye")
+	assert.Equal(t, 1, standings[0].Byes)
+}
+
+func TestSwiss(t *testing.T) {
+	t.Parallel()
+	tr := newTournament(t, tournament.FormatSwiss, 8)
+
+	var results []core.Match
+	for range 3 {
+		results = play(t, tr, results, bySeed(tr))
+	}
+	_, err := tr.NextRound(results, now)
+	require.ErrorIs(t, err, tournament.ErrComplete, "3 rounds for 8 players")
+
+	assert.Equal(t, [][]string{{"p1", "p2"}, {"p3", "p4"}, {"p5", "p6"}, {"p7", "p8"}}, pairingNames(tr, tr.Rounds[0]))
+	assert.Equal(t, [][]string{{"p1", "p3"}, {"p5", "p7"}, {"p2", "p4"}, {"p6", "p8"}}, pairingNames(tr, tr.Rounds[1]))
+	assert.Equal(t, [][]string{{"p1", "p5"}, {"p2", "p3"}, {"p6", "p7"}, {"p4", "p8"}}, pairingNames(tr, tr.Rounds[2]))
+
+	standings, err := tr.Standings(results)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1:p1", "2:p5", "3:p2", "4:p6", "5:p3", "6:p7", "7:p4", "8:p8"}, standingNames(standings))
+
+	// p5, p2 and p6 scored 2 points each, against opponents of 6, 5 and 3
+	for i, buchholz := range []float64{6, 5, 3} {
+		assert.Equal(t, 2.0, standings[i+1].Points)
+		assert.Equal(t, buchholz, standings[i+1].Buchholz)
+	}
+}
+
+func TestSwissByes(t *testing.T) {
+	t.Parallel()
+	tr := newTournament(t, tournament.FormatSwiss, 5)
+	tr.SwissRounds = 5
+
+	var results []core.Match
+	for range 5 {
+		results = play(t, tr, results, bySeed(tr))
+	}
+
+	byes := make(map[string]int)
+	for _, round := range tr.Rounds {
+		require.Len(t, round.Pairings, 3)
+		bye := round.Pairings[2]
+		assert.True(t, bye.Bye())
+		assert.True(t, bye.MatchID.IsZero())
+		byes[pairingNames(tr, round)[2][0]]++
+	}
+	assert.Equal(t, map[string]int{"p1": 1, "p2": 1, "p3": 1, "p4": 1, "p5": 1}, byes, "no player sits out twice")
+}
+
+func TestSwissDraws(t *testing.T) {
+	t.Parallel()
+	tr := newTournament(t, tournament.FormatSwiss, 4)
+
+	results := play(t, tr, nil, func(*core.Match) int { return -1 })
+	standings, err := tr.Standings(results)
+	require.NoError(t, err)
+	for _, s := range standings {
+		assert.Equal(t, 1, s.Position, "%s ties", s.User.Username)
+		assert.Equal(t, 0.5, s.Points)
+		assert.Equal(t, 0.5, s.Buchholz)
+		assert.Equal(t, 0.25, s.SonnebornBerger)
+		assert.Equal(t, 1, s.Draws)
+	}
+	assert.Equal(t, []string{"1:p1", "1:p2", "1:p3", "1:p4"}, standingNames(standings), "ties in seed order")
+}
+
+func TestSingleElimination(t *testing.T) {
+	t.Parallel()
+	tr := newTournament(t, tournament.FormatSingleElimination, 6)
+
+	var results []core.Match
+	for range 3 {
+		results = play(t, tr, results, bySeed(tr))
+	}
+	_, err := tr.NextRound(results, now)
+	require.ErrorIs(t, err, tournament.ErrComplete)
+
+	assert.Equal(t, [][]string{{"p1"}, {"p4", "p5"}, {"p2"}, {"p3", "p6"}}, pairingNames(tr, tr.Rounds[0]), "top seeds get byes")
+	assert.Equal(t, [][]string{{"p1", "p4"}, {"p2", "p3"}}, pairingNames(tr, tr.Rounds[1]))
+	assert.Equal(t, [][]string{{"p1", "p2"}}, pairingNames(tr, tr.Rounds[2]))
+
+	standings, err := tr.Standings(results)
+	require.NoError(t, err)
+	// p4 lost to the winner, a stronger opponent than p3's
+	assert.Equal(t, []string{"1:p1", "2:p2", "3:p4", "4:p3", "5:p5", "5:p6"}, standingNames(standings))
+	assert.Equal(t, 0, standings[0].EliminatedIn)
+	assert.Equal(t, 3, standings[1].EliminatedIn)
+	assert.Equal(t, 1, standings[5].EliminatedIn)
+}
+
+func TestDoubleElimination(t *testing.T) {
+	t.Parallel()
+	tr := newTournament(t, tournament.FormatDoubleElimination, 4)
+	p2 := tr.Players[1].UserID
+
+	// the higher seed wins, except p2 who wins every match
+	winner := func(m *core.Match) int {
+		for i, p := range m.Players {
+			if p.UserID == p2 {
+				return i
+			}
+		}
+		return bySeed(tr)(m)
+	}
+
+	var results []core.Match
+	for {
+		matches, err := tr.NextRound(results, now)
+		if err != nil {
+			require.ErrorIs(t, err, tournament.ErrComplete)
+			break
+		}
+		for i := range matches {
+			finish(t, &matches[i], winner(&matches[i]))
+		}
+		results = append(results, matches...)
+	}
+
+	rounds := make([][][]string, 0, len(tr.Rounds))
+	for _, round := range tr.Rounds {
+		rounds = append(rounds, pairingNames(tr, round))
+	}
+	assert.Equal(t, [][][]string{
+		{{"p1", "p4"}, {"p2", "p3"}},
+		{{"p1", "p2"}, {"p4", "p3"}},
+		{{"p2"}, {"p1", "p3"}},
+		{{"p2", "p1"}},
+	}, rounds)
+
+	standings, err := tr.Standings(results)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1:p2", "2:p1", "3:p3", "4:p4"}, standingNames(standings))
+}
+
+func TestDoubleEliminationGrandFinalReset(t *testing.T) {
+	t.Parallel()
+	tr := newTournament(t, tournament.FormatDoubleElimination, 2)
+
+	// p2 drops to the losers bracket, then wins the grand final
+	results := play(t, tr, nil, bySeed(tr))
+	results = play(t, tr, results, func(*core.Match) int { return 1 })
+	results = play(t, tr, results, bySeed(tr))
+
+	_, err := tr.NextRound(results, now)
+	require.ErrorIs(t, err, tournament.ErrComplete)
+	require.Len(t, tr.Rounds, 3)
+
+	standings, err := tr.Standings(results)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1:p1", "2:p2"}, standingNames(standings))
+}
+
+func TestNextRoundIncomplete(t *testing.T) {
+	t.Parallel()
+	tr := newTournament(t, tournament.FormatSwiss, 4)
+
+	matches, err := tr.NextRound(nil, now)
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	for _, m := range matches {
+		assert.Equal(t, core.MatchStatusInProgress, m.Status)
+		assert.Equal(t, chess.GameID, m.Game.GameID)
+		assert.Equal(t, now, m.StartedAt)
+	}
+
+	finish(t, &matches[0], 0)
+	_, err = tr.NextRound(matches, now)
+	require.ErrorIs(t, err, tournament.ErrRoundIncomplete)
+	_, err = tr.NextRound(matches[:1], now)
+	require.ErrorIs(t, err, tournament.ErrRoundIncomplete)
+
+	standings, err := tr.Standings(matches)
+	require.NoError(t, err)
+	for _, s := range standings {
+		assert.Zero(t, s.Points, "the round is not complete")
+	}
+}
+
+func TestEliminationDraw(t *testing.T) {
+	t.Parallel()
+	tr := newTournament(t, tournament.FormatSingleElimination, 2)
+
+	results := play(t, tr, nil, func(*core.Match) int { return -1 })
+	_, err := tr.NextRound(results, now)
+	require.ErrorIs(t, err, tournament.ErrNoWinner)
+	_, err = tr.Standings(results)
+	require.ErrorIs(t, err, tournament.ErrNoWinner)
+}